@@ -31,4 +31,13 @@ type ResourceRef struct {
 
 	// Version is the API version of the Kubernetes resource object's kind.
 	Version string `json:"v"`
+
+	// Revision is the source revision at which the object last changed.
+	// +optional
+	Revision string `json:"rev,omitempty"`
+
+	// Digest is the truncated digest of the last applied manifest of the
+	// object, used to detect its changes. It is not recorded for Secrets.
+	// +optional
+	Digest string `json:"d,omitempty"`
 }
@@ -38,10 +38,255 @@ const (
 	DeletionPolicyDelete             = "Delete"
 	DeletionPolicyWaitForTermination = "WaitForTermination"
 	DeletionPolicyOrphan             = "Orphan"
+
+	ForceStatefulSetPolicyCascade = "Cascade"
+	ForceStatefulSetPolicyOrphan  = "Orphan"
+
+	DriftCorrectionModeInterval  = "Interval"
+	DriftCorrectionModeImmediate = "Immediate"
+
+	SuspendModeAll       = "All"
+	SuspendModeApplyOnly = "ApplyOnly"
+
+	SecretInventoryPolicyPlain = "Plain"
+	SecretInventoryPolicyHash  = "Hash"
+	SecretInventoryPolicyOmit  = "Omit"
+
+	FieldValidationIgnore = "Ignore"
+	FieldValidationWarn   = "Warn"
+	FieldValidationStrict = "Strict"
+
+	LoadRestrictionsNone     = "LoadRestrictionsNone"
+	LoadRestrictionsRootOnly = "LoadRestrictionsRootOnly"
+
+	ReorderNone   = "none"
+	ReorderLegacy = "legacy"
+
+	DeprecatedAPIVersionsWarn    = "Warn"
+	DeprecatedAPIVersionsRewrite = "Rewrite"
+
+	ExpirationPolicyPrune  = "Prune"
+	ExpirationPolicyDelete = "Delete"
+
+	CRDPolicyApply    = "Apply"
+	CRDPolicyDelegate = "Delegate"
+	CRDPolicyOnly     = "Only"
+
+	// DefaultServiceAccountAnnotation is the Namespace annotation holding the
+	// default service account name of the Kustomizations in the namespace.
+	DefaultServiceAccountAnnotation = "kustomize.toolkit.fluxcd.io/default-service-account-name"
+
+	// DefaultIntervalAnnotation is the Namespace annotation holding the
+	// default interval set at admission on the Kustomizations in the namespace.
+	DefaultIntervalAnnotation = "kustomize.toolkit.fluxcd.io/default-interval"
+
+	// DefaultPruneAnnotation is the Namespace annotation holding the
+	// default prune value set at admission on the Kustomizations in the namespace.
+	DefaultPruneAnnotation = "kustomize.toolkit.fluxcd.io/default-prune"
+
+	// TenantConformanceAnnotation is the Namespace annotation which, when set
+	// to enabled, rejects the builds of the Kustomizations in the namespace
+	// that contain cluster-scoped objects.
+	TenantConformanceAnnotation = "kustomize.toolkit.fluxcd.io/tenant-conformance"
+
+	// ReconcileModeAnnotation is the annotation changing the reconciliation
+	// requested with the reconcile.fluxcd.io/requestedAt annotation to one
+	// of the ReconcileModePruneOnly, ReconcileModeHealthOnly,
+	// ReconcileModeSkipHealthChecks, ReconcileModeSnapshot,
+	// ReconcileModeRestore, ReconcileModeObserve or ReconcileModeCompare modes.
+	ReconcileModeAnnotation = "kustomize.toolkit.fluxcd.io/reconcile-mode"
+
+	// ReconcileModePruneOnly deletes the stale objects without applying
+	// the manifests.
+	ReconcileModePruneOnly = "prune-only"
+
+	// ReconcileModeHealthOnly runs the health checks of the last applied
+	// objects without applying the manifests.
+	ReconcileModeHealthOnly = "health-only"
+
+	// ReconcileModeSkipHealthChecks applies the manifests without running
+	// the health checks, as an emergency override.
+	ReconcileModeSkipHealthChecks = "skip-health-checks"
+
+	// ReconcileModeSnapshot exports the live state of the objects of the
+	// inventory to the snapshot Secret, without applying the manifests.
+	ReconcileModeSnapshot = "snapshot"
+
+	// ReconcileModeRestore applies the objects of the last snapshot
+	// instead of the manifests.
+	ReconcileModeRestore = "restore"
+
+	// ReconcileModeObserve lists the objects of the target namespace
+	// matching the ObserveSelectorAnnotation, and reports the ones absent
+	// from the manifests as cleanup candidates and the ones present in the
+	// manifests but not in the inventory as adoption candidates, without
+	// applying the manifests.
+	ReconcileModeObserve = "observe"
+
+	// ObserveSelectorAnnotation is the annotation holding the label
+	// selector of the objects listed by the ReconcileModeObserve mode.
+	ObserveSelectorAnnotation = "kustomize.toolkit.fluxcd.io/observe-selector"
+
+	// ReconcileModeCompare builds the manifests of the latest revision and
+	// of the revision of the CompareSourceAnnotation source, and stores the
+	// summary of their differences in a ConfigMap, without applying the
+	// manifests.
+	ReconcileModeCompare = "compare"
+
+	// CompareSourceAnnotation is the annotation holding the reference of the
+	// source compared by the ReconcileModeCompare mode, in the
+	// '<kind>/<name>' or '<kind>/<namespace>/<name>' format.
+	CompareSourceAnnotation = "kustomize.toolkit.fluxcd.io/compare-source"
+
+	// SnapshotAgeRecipientAnnotation is the annotation holding the age
+	// recipient used to encrypt the data of the Secrets of a snapshot,
+	// which is redacted otherwise.
+	SnapshotAgeRecipientAnnotation = "kustomize.toolkit.fluxcd.io/snapshot-age-recipient"
+
+	// RequestedByAnnotation is the annotation recording who requested
+	// the reconciliation, e.g. for an emergency override.
+	RequestedByAnnotation = "kustomize.toolkit.fluxcd.io/requested-by"
+
+	// PurgeOrphanedAnnotation is the annotation which, when set to enabled,
+	// removes from the inventory the stale objects whose kind is no longer
+	// served by the API server.
+	PurgeOrphanedAnnotation = "kustomize.toolkit.fluxcd.io/purge-orphaned"
+
+	// PruneDryRunAnnotation is the annotation which, when set to true, makes
+	// the garbage collection report the stale objects it would delete
+	// without deleting them.
+	PruneDryRunAnnotation = "kustomize.toolkit.fluxcd.io/prune-dry-run"
+
+	// ConfirmCRDDeletionAnnotation is the annotation which, when set to the
+	// source revision being reconciled, confirms the deletion by the garbage
+	// collection of the CustomResourceDefinitions removed from the manifests
+	// of the revision.
+	ConfirmCRDDeletionAnnotation = "kustomize.toolkit.fluxcd.io/confirm-crd-deletion"
+
+	// ApplyStrategyAnnotation is the annotation which, when set to
+	// ApplyStrategyClientSide on an object of the manifests, applies the
+	// object with a client-side three-way merge instead of server-side apply.
+	ApplyStrategyAnnotation = "kustomize.toolkit.fluxcd.io/apply-strategy"
+
+	// ApplyStrategyClientSide is the value of the ApplyStrategyAnnotation
+	// selecting the client-side three-way merge.
+	ApplyStrategyClientSide = "client-side"
+
+	// DryRunAnnotation is the annotation which, when set to disabled on an
+	// object of the manifests, applies the object with server-side apply
+	// without the server-side dry-run validating it and detecting its drift,
+	// e.g. for the objects of aggregated APIs rejecting dry-run requests.
+	DryRunAnnotation = "kustomize.toolkit.fluxcd.io/dry-run"
+
+	// DeletionGCAnnotation is the annotation which, when set to disabled,
+	// skips the garbage collection on the deletion of the Kustomization,
+	// while still removing its finalizer.
+	DeletionGCAnnotation = "kustomize.toolkit.fluxcd.io/deletion-gc"
+
+	// CRDsDelegatedByLabel is the label set on the Kustomizations generated
+	// to apply the CustomResourceDefinitions delegated by a Kustomization,
+	// holding the name of the delegating Kustomization.
+	CRDsDelegatedByLabel = "kustomize.toolkit.fluxcd.io/crds-delegated-by"
+
+	// PolicyViolationReason signals that the Kustomization spec violates
+	// one or more of the platform validation rules.
+	PolicyViolationReason = "PolicyViolation"
+
+	// TenantConformanceReason signals that the build of the revision contains
+	// cluster-scoped objects, which are not allowed in the namespace.
+	TenantConformanceReason = "TenantConformance"
+
+	// ReconciliationInterruptedReason signals that the reconciliation was
+	// interrupted by the controller shutdown after applying the revision.
+	ReconciliationInterruptedReason = "ReconciliationInterrupted"
+
+	// MutationLoopDetectedCondition indicates that the drift correction of
+	// one or more fields is suspended for the current revision, as the
+	// applied values keep being reverted, e.g. by a mutating admission webhook.
+	MutationLoopDetectedCondition = "MutationLoopDetected"
+
+	// MutationLoopReason signals that the applied values of one or more
+	// fields are reverted on every apply.
+	MutationLoopReason = "MutationLoop"
+
+	// DecryptionKeyExpiringCondition indicates that one or more of the
+	// imported decryption keys or credentials expire soon, or have expired.
+	DecryptionKeyExpiringCondition = "DecryptionKeyExpiring"
+
+	// DecryptionKeyExpiringReason signals that one or more of the imported
+	// decryption keys or credentials expire soon, or have expired.
+	DecryptionKeyExpiringReason = "DecryptionKeyExpiring"
+
+	// DecryptionKeyGroupsUnsatisfiedCondition indicates that the data key of
+	// SOPS data encrypted with multiple key groups can't be recovered, as
+	// fewer key groups than the Shamir threshold can be decrypted with the
+	// imported keys.
+	DecryptionKeyGroupsUnsatisfiedCondition = "DecryptionKeyGroupsUnsatisfied"
+
+	// KeyGroupsUnsatisfiedReason signals that fewer SOPS key groups than the
+	// Shamir threshold can be decrypted with the imported keys.
+	KeyGroupsUnsatisfiedReason = "KeyGroupsUnsatisfied"
+
+	// DecryptionKeysChangedReason signals that the set of imported
+	// decryption keys changed.
+	DecryptionKeysChangedReason = "DecryptionKeysChanged"
+
+	// DecryptionRecipientsMismatchCondition indicates that one or more SOPS
+	// files are encrypted to keys not in the creation rules of the .sops.yaml
+	// files of the source.
+	DecryptionRecipientsMismatchCondition = "DecryptionRecipientsMismatch"
+
+	// RecipientsMismatchReason signals that one or more SOPS files are
+	// encrypted to keys not in the creation rules of the .sops.yaml files.
+	RecipientsMismatchReason = "RecipientsMismatch"
+
+	// BuildWarningsReason signals that the kustomize build of the
+	// revision succeeded with deprecation warnings.
+	BuildWarningsReason = "BuildWarnings"
+
+	// ExpiredCondition indicates that the TTL of the Kustomization expired,
+	// and that the objects of its inventory were deleted.
+	ExpiredCondition = "Expired"
+
+	// TTLExpiredReason signals that the TTL of the Kustomization expired.
+	TTLExpiredReason = "TTLExpired"
+
+	// UpgradeFreezeCondition indicates that the reconciliation is paused
+	// by the cluster upgrade freeze signalled by the controller Lease.
+	UpgradeFreezeCondition = "UpgradeFreeze"
+
+	// UpgradeFreezeReason signals that the reconciliation is paused
+	// during a cluster upgrade.
+	UpgradeFreezeReason = "UpgradeFreeze"
+
+	// DriftDetectedCondition indicates that the managed objects differ from
+	// the manifests of the latest revision while the apply is suspended.
+	DriftDetectedCondition = "DriftDetected"
+
+	// ApplySuspendedReason signals that the apply is suspended with the
+	// 'ApplyOnly' suspend mode, while the health and the drift of the
+	// managed objects are still reported.
+	ApplySuspendedReason = "ApplySuspended"
+
+	// LockWaitReason signals that the apply is waiting for the lock of the
+	// Kustomization held by another Kustomization.
+	LockWaitReason = "LockWait"
+
+	// CRDDeletionBlockedCondition indicates that the garbage collection
+	// skipped the deletion of one or more CustomResourceDefinitions removed
+	// from the manifests, as it was not confirmed.
+	CRDDeletionBlockedCondition = "CRDDeletionBlocked"
+
+	// CRDDeletionNotConfirmedReason signals that the deletion of one or more
+	// CustomResourceDefinitions was not confirmed with AllowCRDDeletion or
+	// the ConfirmCRDDeletionAnnotation.
+	CRDDeletionNotConfirmedReason = "CRDDeletionNotConfirmed"
 )
 
 // KustomizationSpec defines the configuration to calculate the desired state
 // from a Source using Kustomize.
+// +kubebuilder:validation:XValidation:rule="!has(self.kubeConfig) || !has(self.kubeConfigClusterRef)", message="spec.kubeConfig and spec.kubeConfigClusterRef are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.kubeConfigProxy) || has(self.kubeConfig) || has(self.kubeConfigClusterRef)", message="spec.kubeConfigProxy requires spec.kubeConfig or spec.kubeConfigClusterRef to be set"
 type KustomizationSpec struct {
 	// CommonMetadata specifies the common labels and annotations that are
 	// applied to all resources. Any existing label or annotation will be
@@ -49,6 +294,12 @@ type KustomizationSpec struct {
 	// +optional
 	CommonMetadata *CommonMetadata `json:"commonMetadata,omitempty"`
 
+	// NamespaceLabels specifies the labels, e.g. the Pod Security admission
+	// labels, that are set on the Namespaces applied by the Kustomization.
+	// The labels set in the manifests take precedence.
+	// +optional
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
 	// DependsOn may contain a DependencyReference slice
 	// with references to Kustomization resources that must be ready before this
 	// Kustomization can be reconciled.
@@ -62,6 +313,9 @@ type KustomizationSpec struct {
 	// The interval at which to reconcile the Kustomization.
 	// This interval is approximate and may be subject to jitter to ensure
 	// efficient use of resources.
+	// When set to zero, the periodic reconciliation is disabled and the
+	// Kustomization is reconciled only on changes of its source, spec or
+	// watched dependencies, or when a reconciliation is requested.
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
 	// +required
@@ -75,6 +329,15 @@ type KustomizationSpec struct {
 	// +optional
 	RetryInterval *metav1.Duration `json:"retryInterval,omitempty"`
 
+	// IntervalJitterPercentage is the percentage of jitter applied to the
+	// interval between two reconciliations. A value of 10 applies a random
+	// jitter of +/-10% to the interval. When not specified, the controller
+	// applies the jitter set with the --interval-jitter-percentage flag.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	IntervalJitterPercentage *int32 `json:"intervalJitterPercentage,omitempty"`
+
 	// The KubeConfig for reconciling the Kustomization on a remote cluster.
 	// When used in combination with KustomizationSpec.ServiceAccountName,
 	// forces the controller to act on behalf of that Service Account at the
@@ -85,6 +348,20 @@ type KustomizationSpec struct {
 	// +optional
 	KubeConfig *meta.KubeConfigReference `json:"kubeConfig,omitempty"`
 
+	// KubeConfigClusterRef holds the name of a Cluster API Cluster in the
+	// same namespace as the Kustomization, for reconciling the Kustomization
+	// on that cluster. The kubeconfig is read from the '<cluster-name>-kubeconfig'
+	// Secret generated by Cluster API. Mutually exclusive with KubeConfig.
+	// +optional
+	KubeConfigClusterRef *meta.LocalObjectReference `json:"kubeConfigClusterRef,omitempty"`
+
+	// KubeConfigProxy holds the settings of the proxy the connections to the
+	// remote cluster go through, e.g. a Konnectivity server or another tunnel
+	// proxy, for the apply, the health checks and the garbage collection.
+	// Requires KubeConfig or KubeConfigClusterRef to be set.
+	// +optional
+	KubeConfigProxy *KubeConfigProxy `json:"kubeConfigProxy,omitempty"`
+
 	// Path to the directory containing the kustomization.yaml file, or the
 	// set of plain YAMLs a kustomization.yaml should be generated for.
 	// Defaults to 'None', which translates to the root path of the SourceRef.
@@ -100,6 +377,14 @@ type KustomizationSpec struct {
 	// +required
 	Prune bool `json:"prune"`
 
+	// AllowCRDDeletion allows the garbage collection to delete the
+	// CustomResourceDefinitions removed from the manifests, along with all
+	// their custom resources. When false, the CustomResourceDefinitions are
+	// kept until their deletion is confirmed for the revision with the
+	// 'kustomize.toolkit.fluxcd.io/confirm-crd-deletion' annotation.
+	// +optional
+	AllowCRDDeletion bool `json:"allowCRDDeletion,omitempty"`
+
 	// DeletionPolicy can be used to control garbage collection when this
 	// Kustomization is deleted. Valid values are ('MirrorPrune', 'Delete',
 	// 'WaitForTermination', 'Orphan'). 'MirrorPrune' mirrors the Prune field
@@ -108,10 +393,73 @@ type KustomizationSpec struct {
 	// +optional
 	DeletionPolicy string `json:"deletionPolicy,omitempty"`
 
+	// DeletionTimeout is the time after the deletion of this Kustomization
+	// from which the garbage collection is given up if it keeps failing,
+	// e.g. because the impersonated service account was removed first.
+	// The objects left in the cluster are reported in an event and the
+	// finalizer is removed. When not specified, the garbage collection
+	// is retried until it succeeds.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	DeletionTimeout *metav1.Duration `json:"deletionTimeout,omitempty"`
+
+	// TTL is the time after the creation of this Kustomization from which it
+	// expires, e.g. for preview environments. On expiration, the objects of
+	// the inventory are deleted and the reconciliation stops.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// ExpirationPolicy controls what happens to this Kustomization when its
+	// TTL expires. Valid values are ('Prune', 'Delete'). 'Prune' keeps the
+	// Kustomization with the Expired condition, while 'Delete' also deletes
+	// the Kustomization. Defaults to 'Prune'.
+	// +kubebuilder:validation:Enum=Prune;Delete
+	// +optional
+	ExpirationPolicy string `json:"expirationPolicy,omitempty"`
+
+	// SecretInventoryPolicy controls how Secret objects are recorded in the
+	// status inventory. Valid values are ('Plain', 'Hash', 'Omit').
+	// 'Hash' replaces the Secret entries with a digest of their identifier
+	// and 'Omit' leaves them out of the status. For both, the full inventory
+	// is stored in a Secret named '<kustomization-name>-inventory' in the
+	// namespace of the Kustomization. Defaults to 'Plain'.
+	// +kubebuilder:validation:Enum=Plain;Hash;Omit
+	// +optional
+	SecretInventoryPolicy string `json:"secretInventoryPolicy,omitempty"`
+
+	// CRDPolicy controls how the CustomResourceDefinitions of the build are
+	// applied. Valid values are ('Apply', 'Delegate', 'Only'). 'Delegate'
+	// leaves them out of the apply and delegates them to a Kustomization
+	// named '<kustomization-name>-crds', generated by the controller in the
+	// namespace of the Kustomization, which is not deleted with it. 'Only'
+	// applies only the CustomResourceDefinitions, and is set on the generated
+	// Kustomizations. Defaults to 'Apply'.
+	// +kubebuilder:validation:Enum=Apply;Delegate;Only
+	// +optional
+	CRDPolicy string `json:"crdPolicy,omitempty"`
+
+	// Validation sets the server-side field validation directive used when
+	// applying the resources. Valid values are ('Ignore', 'Warn', 'Strict').
+	// 'Ignore' drops unknown and duplicate fields, 'Warn' drops them and
+	// reports a warning, and 'Strict' fails the apply. When not specified,
+	// the Kubernetes API server default is used.
+	// +kubebuilder:validation:Enum=Ignore;Warn;Strict
+	// +optional
+	Validation string `json:"validation,omitempty"`
+
 	// A list of resources to be included in the health assessment.
 	// +optional
 	HealthChecks []meta.NamespacedObjectKindReference `json:"healthChecks,omitempty"`
 
+	// A list of resources not managed by the Kustomization to be included in
+	// the health assessment, e.g. prerequisites of the platform managed
+	// elsewhere. Unlike HealthChecks, they are checked even when Wait is enabled.
+	// +optional
+	ExternalHealthChecks []meta.NamespacedObjectKindReference `json:"externalHealthChecks,omitempty"`
+
 	// NamePrefix will prefix the names of all managed resources.
 	// +kubebuilder:validation:MinLength=1
 	// +kubebuilder:validation:MaxLength=200
@@ -137,6 +485,26 @@ type KustomizationSpec struct {
 	// +optional
 	Images []kustomize.Image `json:"images,omitempty"`
 
+	// ImageDigestResolution resolves the new tags of the listed images
+	// to their digests with the registry API at build time, and applies
+	// the images pinned to the digests.
+	// +optional
+	ImageDigestResolution *ImageDigestResolution `json:"imageDigestResolution,omitempty"`
+
+	// ImagePullSecret generates an image pull secret in the target namespace
+	// from a template Secret, and attaches it to the ServiceAccounts of the
+	// target namespace rendered by the Kustomization.
+	// Requires TargetNamespace to be set.
+	// +optional
+	ImagePullSecret *ImagePullSecret `json:"imagePullSecret,omitempty"`
+
+	// Tenancy adds the guardrail objects of a tenancy profile, e.g. a
+	// default-deny NetworkPolicy, a ResourceQuota and a LimitRange, to the
+	// objects applied in the target namespace.
+	// Requires TargetNamespace to be set.
+	// +optional
+	Tenancy *Tenancy `json:"tenancy,omitempty"`
+
 	// The name of the Kubernetes service account to impersonate
 	// when reconciling this Kustomization.
 	// +optional
@@ -151,6 +519,15 @@ type KustomizationSpec struct {
 	// +optional
 	Suspend bool `json:"suspend,omitempty"`
 
+	// SuspendMode sets what is suspended when Suspend is true. Valid values
+	// are ('All', 'ApplyOnly'). 'All' stops the reconciliation. 'ApplyOnly'
+	// stops the apply and the garbage collection, while the manifests are
+	// still built at every interval to report the drift of the managed
+	// objects, and their health is still checked. Defaults to 'All'.
+	// +kubebuilder:validation:Enum=All;ApplyOnly
+	// +optional
+	SuspendMode string `json:"suspendMode,omitempty"`
+
 	// TargetNamespace sets or overrides the namespace in the
 	// kustomization.yaml file.
 	// +kubebuilder:validation:MinLength=1
@@ -172,11 +549,35 @@ type KustomizationSpec struct {
 	// +optional
 	Force bool `json:"force,omitempty"`
 
+	// ForceStatefulSetPolicy controls how the StatefulSets are recreated
+	// when patching fails due to an immutable field change. Valid values are
+	// ('Cascade', 'Orphan'). 'Cascade' deletes the StatefulSet with its pods.
+	// 'Orphan' deletes the StatefulSet leaving its pods and volume claims in
+	// place, to be adopted by the recreated StatefulSet. Defaults to 'Cascade',
+	// or to 'Orphan' when ForceMaxUnavailable is set.
+	// +kubebuilder:validation:Enum=Cascade;Orphan
+	// +optional
+	ForceStatefulSetPolicy string `json:"forceStatefulSetPolicy,omitempty"`
+
+	// ForceMaxUnavailable enables the gradual recreation of the StatefulSets
+	// replaced due to an immutable field change. Instead of deleting the pods
+	// at once, the StatefulSet is recreated without its pods, which are then
+	// evicted in batches of at most this size, honoring the PodDisruptionBudgets.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ForceMaxUnavailable *int32 `json:"forceMaxUnavailable,omitempty"`
+
 	// Wait instructs the controller to check the health of all the reconciled
 	// resources. When enabled, the HealthChecks are ignored. Defaults to false.
 	// +optional
 	Wait bool `json:"wait,omitempty"`
 
+	// WaitSelector restricts the health checks enabled by Wait to the
+	// reconciled resources matching the label selector, so that the readiness
+	// is gated on the critical workloads only. Ignored when Wait is false.
+	// +optional
+	WaitSelector *metav1.LabelSelector `json:"waitSelector,omitempty"`
+
 	// BuildMetadata specifies which kustomize build metadata should be added
 	// to the built resources. The allowed values are 'originAnnotations' to
 	// annotate resources with their source origin, and 'transformerAnnotations'
@@ -184,6 +585,11 @@ type KustomizationSpec struct {
 	// +optional
 	BuildMetadata []BuildMetadataOption `json:"buildMetadata,omitempty"`
 
+	// BuildOptions configures the kustomize build of the Kustomization.
+	// Enabling Helm or the alpha plugins must be allowed in the controller.
+	// +optional
+	BuildOptions *BuildOptions `json:"buildOptions,omitempty"`
+
 	// Components specifies relative paths to kustomize Components.
 	// +optional
 	Components []string `json:"components,omitempty"`
@@ -194,18 +600,63 @@ type KustomizationSpec struct {
 	// +optional
 	IgnoreMissingComponents bool `json:"ignoreMissingComponents,omitempty"`
 
+	// Include references the Kustomizations whose rendered manifests are
+	// composed into the build as resources, before the patches and the
+	// post-build substitutions are applied. The included Kustomizations
+	// export their last build to a Secret in their namespace.
+	// +optional
+	Include []meta.NamespacedObjectReference `json:"include,omitempty"`
+
+	// Export defines which Kustomizations are allowed to include the build
+	// of this Kustomization. By default, only the Kustomizations in the same
+	// namespace can include it, and the Secrets are removed from the build.
+	// +optional
+	Export *ExportPolicy `json:"export,omitempty"`
+
 	// HealthCheckExprs is a list of healthcheck expressions for evaluating the
 	// health of custom resources using Common Expression Language (CEL).
 	// The expressions are evaluated only when Wait or HealthChecks are specified.
 	// +optional
 	HealthCheckExprs []kustomize.CustomHealthCheck `json:"healthCheckExprs,omitempty"`
 
+	// HealthCheckThresholds is a list of readiness thresholds for relaxing
+	// the health assessment of Deployments. A Deployment matching a threshold
+	// is considered healthy once the ratio of its updated and ready replicas
+	// reaches the configured minimum.
+	// The thresholds are evaluated only when Wait or HealthChecks are specified.
+	// +optional
+	HealthCheckThresholds []HealthCheckThreshold `json:"healthCheckThresholds,omitempty"`
+
 	// Ignore is a list of rules for specifying which changes to ignore
 	// during drift detection. These rules are applied to the resources managed
 	// by the Kustomization and are used to exclude specific JSON pointer paths
 	// from the drift detection and apply process.
 	// +optional
 	Ignore []IgnoreRule `json:"ignore,omitempty"`
+
+	// DriftCorrection configures when the changes made to the managed
+	// resources outside of the controller, e.g. with kubectl edit, are corrected.
+	// +optional
+	DriftCorrection *DriftCorrection `json:"driftCorrection,omitempty"`
+
+	// Lock configures a lock shared with the other Kustomizations managing
+	// overlapping objects, so that they are not applied concurrently.
+	// +optional
+	Lock *Lock `json:"lock,omitempty"`
+
+	// Verify configures the verification of the source revisions, which are
+	// not applied if the verification fails.
+	// +optional
+	Verify *Verification `json:"verify,omitempty"`
+
+	// ReadyMessageTemplate is a Go text/template rendering the message of
+	// the Ready condition at the end of the reconciliation, e.g. to include
+	// the short revision, the labels and the number of changed objects in
+	// the message displayed by dashboards. The default message is kept if
+	// the template fails to render.
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	ReadyMessageTemplate string `json:"readyMessageTemplate,omitempty"`
 }
 
 // BuildMetadataOption defines the supported buildMetadata options.
@@ -248,10 +699,281 @@ type IgnoreRule struct {
 	Target *kustomize.Selector `json:"target,omitempty"`
 }
 
+// DriftCorrection defines when the drift of the managed resources is corrected.
+type DriftCorrection struct {
+	// Mode sets when the drift is corrected. Valid values are ('Interval',
+	// 'Immediate'). 'Interval' corrects the drift at the next reconciliation.
+	// 'Immediate' watches the managed resources and reconciles the
+	// Kustomization as soon as one of them is modified by another field
+	// manager or deleted, which requires the WatchInventoryKinds feature
+	// gate to be enabled in the controller. Defaults to 'Interval'.
+	// +kubebuilder:validation:Enum=Interval;Immediate
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// Debounce is the delay during which the changes made to the managed
+	// resources are coalesced into a single reconciliation in the
+	// 'Immediate' mode. Defaults to '5s'.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m))+$"
+	// +optional
+	Debounce *metav1.Duration `json:"debounce,omitempty"`
+}
+
+// Lock defines the lock held by a Kustomization during its apply,
+// garbage collection and health checks.
+type Lock struct {
+	// Name of the lock shared by the Kustomizations which must not be
+	// applied concurrently. Defaults to the target namespace, or to the
+	// namespace of the Kustomization if no target namespace is set.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([a-z0-9-]*[a-z0-9])?$"
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// LockWait describes the wait for a lock held by another Kustomization.
+type LockWait struct {
+	// Name of the lock.
+	// +required
+	Name string `json:"name"`
+
+	// Holder is the namespace/name of the Kustomization holding the lock,
+	// empty if it could not be determined.
+	// +optional
+	Holder string `json:"holder,omitempty"`
+
+	// Since is the time at which the wait started.
+	// +required
+	Since metav1.Time `json:"since"`
+}
+
+// ApplyProgress reports the progress of an in-flight apply.
+type ApplyProgress struct {
+	// Stage is the apply stage in progress, one of ClusterDefinitions
+	// (CRDs, ClusterRoles and Namespaces), ClassDefinitions, CustomStage,
+	// Resources or ClientSide.
+	// +required
+	Stage string `json:"stage"`
+
+	// Applied is the number of objects processed by the apply so far.
+	// +required
+	Applied int `json:"applied"`
+
+	// Total is the number of objects to apply.
+	// +required
+	Total int `json:"total"`
+
+	// EstimatedCompletion is the estimated time at which the apply
+	// completes, based on the apply rate of the last minute.
+	// +optional
+	EstimatedCompletion *metav1.Time `json:"estimatedCompletion,omitempty"`
+
+	// LastUpdateTime is the time of the last progress update.
+	// +required
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
+// DecryptionKeys describes the Secret the decryption keys were imported from.
+type DecryptionKeys struct {
+	// SecretRef is the reference of the Secret.
+	// +required
+	SecretRef meta.NamespacedObjectReference `json:"secretRef"`
+
+	// ResourceVersion is the resourceVersion of the Secret
+	// the keys were imported from.
+	// +required
+	ResourceVersion string `json:"resourceVersion"`
+
+	// LastChangeTime is the time at which the keys of the
+	// resourceVersion were first imported.
+	// +required
+	LastChangeTime metav1.Time `json:"lastChangeTime"`
+
+	// Cached is true if the Secret couldn't be read due to a transient
+	// error, and the keys were imported from its last version read.
+	// +optional
+	Cached bool `json:"cached,omitempty"`
+}
+
+// DecryptionStatus describes the keys imported for the decryption.
+type DecryptionStatus struct {
+	// KeyHashes are the SHA-256 hashes of the recipients of the imported
+	// age identities and of the fingerprints of the imported PGP keys,
+	// each prefixed with its key type, e.g. 'age:sha256:<hex>'.
+	// +optional
+	KeyHashes []string `json:"keyHashes,omitempty"`
+
+	// LastChangeTime is the time at which the key hashes last changed.
+	// +required
+	LastChangeTime metav1.Time `json:"lastChangeTime"`
+}
+
+// Verification defines the verification of the source revisions.
+type Verification struct {
+	// GitSignatures requires the commit of the revision to be signed by one
+	// of the trusted keys, as verified by the GitRepository source.
+	// +optional
+	GitSignatures *GitSignatureVerification `json:"gitSignatures,omitempty"`
+}
+
+// GitSignatureVerification defines the keys trusted to sign the commits
+// applied by a Kustomization.
+type GitSignatureVerification struct {
+	// SecretRef holds the name of a Secret in the same namespace as the
+	// Kustomization, whose data entries are armored OpenPGP public keys.
+	// +required
+	SecretRef meta.LocalObjectReference `json:"secretRef"`
+}
+
+// BuildOptions defines the options of the kustomize build.
+type BuildOptions struct {
+	// LoadRestrictor restricts the files loaded by the kustomization files.
+	// Valid values are ('LoadRestrictionsNone', 'LoadRestrictionsRootOnly').
+	// 'LoadRestrictionsRootOnly' restricts the files to the directory of
+	// the kustomization file loading them, while 'LoadRestrictionsNone'
+	// allows loading files from the whole source artifact.
+	// Defaults to 'LoadRestrictionsNone'.
+	// +kubebuilder:validation:Enum=LoadRestrictionsNone;LoadRestrictionsRootOnly
+	// +optional
+	LoadRestrictor string `json:"loadRestrictor,omitempty"`
+
+	// Reorder sets the order of the built resources. Valid values are
+	// ('none', 'legacy'). 'none' keeps the order of the kustomization files,
+	// while 'legacy' sorts the resources by kind. Defaults to 'none'.
+	// +kubebuilder:validation:Enum=none;legacy
+	// +optional
+	Reorder string `json:"reorder,omitempty"`
+
+	// EnableHelm enables the inflation of the Helm charts of the
+	// kustomization files, which requires the Helm command to be
+	// configured in the controller. Defaults to false.
+	// +optional
+	EnableHelm bool `json:"enableHelm,omitempty"`
+
+	// EnableAlphaPlugins enables the kustomize plugins which are not
+	// builtin, including the exec KRM functions, which requires the alpha
+	// plugins to be allowed in the controller. Defaults to false.
+	// +optional
+	EnableAlphaPlugins bool `json:"enableAlphaPlugins,omitempty"`
+
+	// TransformerConfigsFrom is a list of references to ConfigMaps in the
+	// same namespace as the Kustomization, whose data entries are kustomize
+	// transformer configurations, e.g. nameReference or varReference, added
+	// to the configurations of the generated kustomization.yaml.
+	// +optional
+	TransformerConfigsFrom []meta.LocalObjectReference `json:"transformerConfigsFrom,omitempty"`
+
+	// OpenAPISchemaFrom is a reference to a ConfigMap in the same namespace
+	// as the Kustomization holding an OpenAPI v2 schema, added to the builtin
+	// Kubernetes schema of the kustomize build. Kustomize uses the schema to merge
+	// the lists of the custom resources in the strategic merge patches,
+	// instead of replacing them.
+	// +optional
+	OpenAPISchemaFrom *OpenAPISchemaReference `json:"openAPISchemaFrom,omitempty"`
+
+	// DeprecatedAPIVersions sets the handling of the objects of the build
+	// using API versions removed from Kubernetes. Valid values are
+	// ('Warn', 'Rewrite'). 'Warn' reports the objects in the build warnings,
+	// while 'Rewrite' also converts the objects of the versions no longer
+	// served by the target cluster to the replacement version, when both
+	// versions share the same schema. Defaults to 'Warn'.
+	// +kubebuilder:validation:Enum=Warn;Rewrite
+	// +optional
+	DeprecatedAPIVersions string `json:"deprecatedAPIVersions,omitempty"`
+}
+
+// ImageDigestResolution contains the images whose new tag is resolved to its
+// digest, and the credentials of the registry used to resolve the digests.
+type ImageDigestResolution struct {
+	// Images is the list of the names of the images of spec.images whose new
+	// tag is resolved to its digest. The images with a digest set are ignored.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Images []string `json:"images"`
+
+	// SecretRef holds the name of a Secret of type 'kubernetes.io/dockerconfigjson'
+	// in the same namespace as the Kustomization, with the credentials of the
+	// registry used to resolve the digests.
+	// +optional
+	SecretRef *meta.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// ImagePullSecret contains the settings of the image pull secret generated
+// in the target namespace.
+type ImagePullSecret struct {
+	// TemplateRef holds the name of a Secret of type 'kubernetes.io/dockerconfigjson'
+	// in the same namespace as the Kustomization, whose data is copied to the
+	// generated Secret.
+	// +required
+	TemplateRef meta.LocalObjectReference `json:"templateRef"`
+
+	// Name of the generated Secret, defaults to the name of the template.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ServiceAccounts is the list of the names of the ServiceAccounts of the
+	// target namespace to attach the Secret to, among the ones rendered by the
+	// Kustomization. Defaults to all the rendered ServiceAccounts.
+	// +optional
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+}
+
+// Tenancy contains the settings of the guardrail objects generated
+// in the target namespace.
+type Tenancy struct {
+	// Profile is the name of the tenancy profile, a key of the ConfigMap set
+	// with the controller flag --tenancy-profiles holding the templates of
+	// the guardrail objects.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	// +required
+	Profile string `json:"profile"`
+}
+
+// OpenAPISchemaReference contains a reference to an OpenAPI schema
+// stored in a ConfigMap.
+type OpenAPISchemaReference struct {
+	// Name of the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +required
+	Name string `json:"name"`
+
+	// Key of the ConfigMap data holding the schema in JSON or YAML format.
+	// Defaults to 'schema.json'.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// HealthCheckThreshold defines the readiness threshold of the Deployments
+// selected by the target.
+type HealthCheckThreshold struct {
+	// MinReadyRatio is the minimum ratio of updated and ready replicas to the
+	// desired replicas for a Deployment to be considered healthy, expressed as
+	// a decimal number between 0 and 1, e.g. '0.9'.
+	// +kubebuilder:validation:Pattern="^(0(\\.[0-9]+)?|1(\\.0+)?)$"
+	// +required
+	MinReadyRatio string `json:"minReadyRatio"`
+
+	// Target is a selector for specifying the Deployments to which this
+	// threshold applies.
+	// If Target is not set, the threshold applies to all the Deployments
+	// within the manifest of the Kustomization.
+	// +optional
+	Target *kustomize.Selector `json:"target,omitempty"`
+}
+
 // Decryption defines how decryption is handled for Kubernetes manifests.
 type Decryption struct {
-	// Provider is the name of the decryption engine.
-	// +kubebuilder:validation:Enum=sops
+	// Provider is the name of the decryption engine, 'sops' to decrypt the
+	// SOPS encrypted manifests, 'placeholder' to resolve the placeholders
+	// of the Secrets from external secret stores, or 'sealed-secrets' to
+	// unseal the Bitnami SealedSecrets.
+	// +kubebuilder:validation:Enum=sops;placeholder;sealed-secrets
 	// +required
 	Provider string `json:"provider"`
 
@@ -269,6 +991,19 @@ type Decryption struct {
 	// field.
 	// +optional
 	SecretRef *meta.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// FailOnUndecrypted fails the build when a resource still holds SOPS
+	// encrypted data after the decryption, instead of applying the encrypted
+	// data to the cluster, e.g. when the decryption keys are missing.
+	// +optional
+	FailOnUndecrypted bool `json:"failOnUndecrypted,omitempty"`
+
+	// ExcludePaths is a list of glob patterns of the paths, relative to the
+	// root of the source artifact, of the files which are not decrypted, e.g.
+	// 'backups/*.enc.yaml'. A pattern matching a directory excludes all the
+	// files of the directory.
+	// +optional
+	ExcludePaths []string `json:"excludePaths,omitempty"`
 }
 
 // SubstituteStrategy defines the strategy for substituting variables in the YAML manifests.
@@ -344,6 +1079,8 @@ type SubstituteReference struct {
 type KustomizationStatus struct {
 	meta.ReconcileRequestStatus `json:",inline"`
 
+	meta.ForceRequestStatus `json:",inline"`
+
 	// ObservedGeneration is the last reconciled generation.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
@@ -373,10 +1110,255 @@ type KustomizationStatus struct {
 	// +optional
 	Inventory *ResourceInventory `json:"inventory,omitempty"`
 
+	// InventorySecretName is the name of the Secret storing the full
+	// inventory, set when the Secret entries are redacted from the status
+	// inventory according to the SecretInventoryPolicy.
+	// +optional
+	InventorySecretName string `json:"inventorySecretName,omitempty"`
+
 	// History contains a set of snapshots of the last reconciliation attempts
 	// tracking the revision, the state and the duration of each attempt.
 	// +optional
 	History meta.History `json:"history,omitempty"`
+
+	// LastHealthCheckOverride records the last reconciliation that applied
+	// a revision without running the health checks.
+	// +optional
+	LastHealthCheckOverride *HealthCheckOverride `json:"lastHealthCheckOverride,omitempty"`
+
+	// OrphanedByAPIRemoval contains the inventory entries of the stale objects
+	// which could not be pruned because their kind is no longer served by
+	// the API server.
+	// +optional
+	OrphanedByAPIRemoval []ResourceRef `json:"orphanedByAPIRemoval,omitempty"`
+
+	// PruneDryRun reports the stale objects that the last garbage collection
+	// would have deleted, as requested with the PruneDryRunAnnotation.
+	// +optional
+	PruneDryRun *PruneDryRunResult `json:"pruneDryRun,omitempty"`
+
+	// LastReconcileAPIRequests contains the number of Kubernetes API requests
+	// issued by the last reconciliation for applying, pruning and health
+	// checking the resources, by request verb.
+	// +optional
+	LastReconcileAPIRequests map[string]int64 `json:"lastReconcileAPIRequests,omitempty"`
+
+	// BuildWarnings contains the deprecation warnings of the kustomization
+	// files reported by the last build, capped to the first ten warnings.
+	// +optional
+	BuildWarnings []string `json:"buildWarnings,omitempty"`
+
+	// APIWarnings contains the distinct warnings returned by the Kubernetes
+	// API server to the requests of the last apply, such as the deprecation
+	// of API versions, capped to the first ten warnings.
+	// +optional
+	APIWarnings []string `json:"apiWarnings,omitempty"`
+
+	// APIVersionRewrites contains the objects of the last build rewritten
+	// from an API version no longer served by the target cluster to its
+	// replacement, as requested with the Rewrite policy of the
+	// DeprecatedAPIVersions build option.
+	// +optional
+	APIVersionRewrites []APIVersionRewrite `json:"apiVersionRewrites,omitempty"`
+
+	// ResolvedImages contains the digests the tags of the images set to
+	// resolve their digest were resolved to by the last build.
+	// +optional
+	ResolvedImages []ResolvedImage `json:"resolvedImages,omitempty"`
+
+	// BuildFingerprint is the digest of the inputs of the last build: the
+	// digest of the source artifact, the spec fields applied to the
+	// manifests, the post build variables and the digests of the builds
+	// exported by the included Kustomizations. Kustomizations with the same
+	// fingerprint render the same manifests.
+	// +optional
+	BuildFingerprint string `json:"buildFingerprint,omitempty"`
+
+	// LastSnapshot describes the last snapshot of the live state of the
+	// objects of the inventory, taken with the snapshot reconcile mode.
+	// +optional
+	LastSnapshot *Snapshot `json:"lastSnapshot,omitempty"`
+
+	// ExportedBuild describes the last build exported for the
+	// Kustomizations including this Kustomization.
+	// +optional
+	ExportedBuild *ExportedBuild `json:"exportedBuild,omitempty"`
+
+	// LastObservation reports the objects of the target namespace found
+	// by the last reconciliation in the observe reconcile mode.
+	// +optional
+	LastObservation *Observation `json:"lastObservation,omitempty"`
+
+	// LockWait reports the lock held by another Kustomization which
+	// the apply of this Kustomization is waiting for.
+	// +optional
+	LockWait *LockWait `json:"lockWait,omitempty"`
+
+	// Progress reports the progress of the apply in flight, updated
+	// periodically during long applies and removed once it completes.
+	// +optional
+	Progress *ApplyProgress `json:"progress,omitempty"`
+
+	// DecryptionKeys describes the Secret the decryption keys of the
+	// last build were imported from.
+	// +optional
+	DecryptionKeys *DecryptionKeys `json:"decryptionKeys,omitempty"`
+
+	// Decryption describes the keys imported for the decryption
+	// of the last build.
+	// +optional
+	Decryption *DecryptionStatus `json:"decryption,omitempty"`
+}
+
+// ExportPolicy defines the access to the build of a Kustomization
+// exported to the Kustomizations including it.
+type ExportPolicy struct {
+	// AllowedNamespaces lists the namespaces of the Kustomizations allowed
+	// to include the build, in addition to the namespace of the Kustomization.
+	// The wildcard '*' allows all namespaces.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// AllowSecrets allows the export of the Secrets of the build, which hold
+	// the decrypted data of the SOPS encrypted Secrets. When false, the
+	// Secrets are removed from the exported build. Defaults to false.
+	// +optional
+	AllowSecrets bool `json:"allowSecrets,omitempty"`
+}
+
+// ExportedBuild describes the rendered manifests of a Kustomization,
+// stored in a Secret in the namespace of the Kustomization to be
+// included in the builds of other Kustomizations.
+type ExportedBuild struct {
+	// SecretName is the name of the Secret holding the manifests.
+	// +required
+	SecretName string `json:"secretName"`
+
+	// Revision is the revision the manifests were built from.
+	// +required
+	Revision string `json:"revision"`
+
+	// Digest is the digest of the manifests.
+	// +required
+	Digest string `json:"digest"`
+}
+
+// Observation reports the existing objects of the target namespace
+// compared to the manifests, before they are managed by the Kustomization.
+type Observation struct {
+	// Revision is the source revision the manifests were built from.
+	// +required
+	Revision string `json:"revision"`
+
+	// Selector is the label selector of the listed objects.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// AdoptionCandidates are the listed objects that are part of the
+	// manifests but not of the inventory.
+	// +optional
+	AdoptionCandidates []ResourceRef `json:"adoptionCandidates,omitempty"`
+
+	// CleanupCandidates are the listed objects that are not part of
+	// the manifests.
+	// +optional
+	CleanupCandidates []ResourceRef `json:"cleanupCandidates,omitempty"`
+
+	// HandledAt is the time at which the objects were listed.
+	// +required
+	HandledAt metav1.Time `json:"handledAt"`
+}
+
+// Snapshot describes a snapshot of the live state of the objects of the
+// inventory, stored in a Secret in the namespace of the Kustomization.
+type Snapshot struct {
+	// SecretName is the name of the Secret holding the snapshot.
+	// +required
+	SecretName string `json:"secretName"`
+
+	// Revision is the last applied revision when the snapshot was taken.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Digest is the digest of the snapshot manifests.
+	// +required
+	Digest string `json:"digest"`
+
+	// Objects is the number of objects in the snapshot.
+	// +required
+	Objects int `json:"objects"`
+
+	// Encrypted is true if the data of the Secrets was encrypted with an
+	// age recipient, and false if it was redacted.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// Time is the time the snapshot was taken.
+	// +required
+	Time metav1.Time `json:"time"`
+}
+
+// ResolvedImage records the digest an image tag was resolved to.
+type ResolvedImage struct {
+	// Image is the image reference in the format '<name>:<tag>'.
+	// +required
+	Image string `json:"image"`
+
+	// Digest is the digest of the image.
+	// +required
+	Digest string `json:"digest"`
+}
+
+// APIVersionRewrite records the rewrite of an object of the build
+// to the replacement of its API version.
+type APIVersionRewrite struct {
+	// Object is the object in the format '<kind>/<namespace>/<name>'.
+	// +required
+	Object string `json:"object"`
+
+	// From is the API version of the object in the manifests.
+	// +required
+	From string `json:"from"`
+
+	// To is the API version the object is applied with.
+	// +required
+	To string `json:"to"`
+}
+
+// PruneDryRunResult records the outcome of a garbage collection dry-run.
+type PruneDryRunResult struct {
+	// Revision is the source revision of the dry-run.
+	// +required
+	Revision string `json:"revision"`
+
+	// Entries of the stale objects that would be deleted.
+	// +optional
+	Entries []ResourceRef `json:"entries,omitempty"`
+
+	// HandledAt is the time at which the dry-run was performed.
+	// +required
+	HandledAt metav1.Time `json:"handledAt"`
+}
+
+// HealthCheckOverride records a reconciliation that skipped the health checks
+// on request of the ReconcileModeSkipHealthChecks mode.
+type HealthCheckOverride struct {
+	// RequestedAt is the reconcile.fluxcd.io/requestedAt value
+	// of the request.
+	// +required
+	RequestedAt string `json:"requestedAt"`
+
+	// RequestedBy is the value of the RequestedByAnnotation of the request.
+	// +optional
+	RequestedBy string `json:"requestedBy,omitempty"`
+
+	// Revision is the source revision applied without health checks.
+	// +required
+	Revision string `json:"revision"`
+
+	// HandledAt is the time at which the request was handled.
+	// +required
+	HandledAt metav1.Time `json:"handledAt"`
 }
 
 // GetTimeout returns the timeout with default.
@@ -391,6 +1373,21 @@ func (in Kustomization) GetTimeout() time.Duration {
 	return duration
 }
 
+// GetLockName returns the name of the lock of the Kustomization,
+// or an empty string if the Kustomization has no lock.
+func (in Kustomization) GetLockName() string {
+	switch {
+	case in.Spec.Lock == nil:
+		return ""
+	case in.Spec.Lock.Name != "":
+		return in.Spec.Lock.Name
+	case in.Spec.TargetNamespace != "":
+		return in.Spec.TargetNamespace
+	default:
+		return in.GetNamespace()
+	}
+}
+
 // GetRetryInterval returns the retry interval
 func (in Kustomization) GetRetryInterval() time.Duration {
 	if in.Spec.RetryInterval != nil {
@@ -405,6 +1402,23 @@ func (in Kustomization) GetRequeueAfter() time.Duration {
 	return in.Spec.Interval.Duration
 }
 
+// GetLoadRestrictor returns the load restrictor of the build with the
+// default value if not specified.
+func (in Kustomization) GetLoadRestrictor() string {
+	if in.Spec.BuildOptions == nil || in.Spec.BuildOptions.LoadRestrictor == "" {
+		return LoadRestrictionsNone
+	}
+	return in.Spec.BuildOptions.LoadRestrictor
+}
+
+// GetKey returns the key of the OpenAPI schema with the default value if not specified.
+func (in OpenAPISchemaReference) GetKey() string {
+	if in.Key == "" {
+		return "schema.json"
+	}
+	return in.Key
+}
+
 // GetDeletionPolicy returns the deletion policy and default value if not specified.
 func (in Kustomization) GetDeletionPolicy() string {
 	if in.Spec.DeletionPolicy == "" {
@@ -413,6 +1427,94 @@ func (in Kustomization) GetDeletionPolicy() string {
 	return in.Spec.DeletionPolicy
 }
 
+// GetDeletionTimeout returns the time after the deletion from which the
+// garbage collection is given up, or zero if not specified.
+func (in Kustomization) GetDeletionTimeout() time.Duration {
+	if in.Spec.DeletionTimeout != nil {
+		return in.Spec.DeletionTimeout.Duration
+	}
+	return 0
+}
+
+// GetExpirationTime returns the time at which the TTL expires,
+// or the zero time if not specified.
+func (in Kustomization) GetExpirationTime() time.Time {
+	if in.Spec.TTL == nil {
+		return time.Time{}
+	}
+	return in.GetCreationTimestamp().Add(in.Spec.TTL.Duration)
+}
+
+// GetExpirationPolicy returns the expiration policy
+// and default value if not specified.
+func (in Kustomization) GetExpirationPolicy() string {
+	if in.Spec.ExpirationPolicy == "" {
+		return ExpirationPolicyPrune
+	}
+	return in.Spec.ExpirationPolicy
+}
+
+// GetForceStatefulSetPolicy returns the StatefulSet recreation policy
+// and default value if not specified.
+func (in Kustomization) GetForceStatefulSetPolicy() string {
+	if in.Spec.ForceStatefulSetPolicy == "" {
+		if in.Spec.ForceMaxUnavailable != nil {
+			return ForceStatefulSetPolicyOrphan
+		}
+		return ForceStatefulSetPolicyCascade
+	}
+	return in.Spec.ForceStatefulSetPolicy
+}
+
+// GetDriftCorrectionMode returns the drift correction mode
+// and default value if not specified.
+func (in Kustomization) GetDriftCorrectionMode() string {
+	if in.Spec.DriftCorrection == nil || in.Spec.DriftCorrection.Mode == "" {
+		return DriftCorrectionModeInterval
+	}
+	return in.Spec.DriftCorrection.Mode
+}
+
+// GetSuspendMode returns the suspend mode
+// and default value if not specified.
+func (in Kustomization) GetSuspendMode() string {
+	if in.Spec.SuspendMode == "" {
+		return SuspendModeAll
+	}
+	return in.Spec.SuspendMode
+}
+
+// IsApplySuspended returns true if the apply is suspended
+// while the health and the drift are still reported.
+func (in Kustomization) IsApplySuspended() bool {
+	return in.Spec.Suspend && in.GetSuspendMode() == SuspendModeApplyOnly
+}
+
+// GetDriftCorrectionDebounce returns the drift correction debounce
+// delay and default value if not specified.
+func (in Kustomization) GetDriftCorrectionDebounce() time.Duration {
+	if in.Spec.DriftCorrection == nil || in.Spec.DriftCorrection.Debounce == nil {
+		return 5 * time.Second
+	}
+	return in.Spec.DriftCorrection.Debounce.Duration
+}
+
+// GetSecretInventoryPolicy returns the secret inventory policy and default value if not specified.
+func (in Kustomization) GetSecretInventoryPolicy() string {
+	if in.Spec.SecretInventoryPolicy == "" {
+		return SecretInventoryPolicyPlain
+	}
+	return in.Spec.SecretInventoryPolicy
+}
+
+// GetCRDPolicy returns the CRD policy and default value if not specified.
+func (in Kustomization) GetCRDPolicy() string {
+	if in.Spec.CRDPolicy == "" {
+		return CRDPolicyApply
+	}
+	return in.Spec.CRDPolicy
+}
+
 // GetDependsOn returns the dependencies as a list of meta.DependencyReference.
 //
 // This function makes the Kustomization type conformant with the meta.ObjectWithDependencies interface
@@ -446,6 +1548,18 @@ func (in *Kustomization) SetConditions(conditions []metav1.Condition) {
 	in.Status.Conditions = conditions
 }
 
+// GetLastHandledReconcileRequest returns the last handled
+// reconcile request value.
+func (in Kustomization) GetLastHandledReconcileRequest() string {
+	return in.Status.GetLastHandledReconcileRequest()
+}
+
+// GetLastHandledForceRequestStatus returns a pointer to the last handled
+// force request value, used to handle the force request only once.
+func (in *Kustomization) GetLastHandledForceRequestStatus() *string {
+	return &in.Status.LastHandledForceAt
+}
+
 // +genclient
 // +kubebuilder:storageversion
 // +kubebuilder:object:root=true
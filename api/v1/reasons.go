@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "github.com/fluxcd/pkg/apis/meta"
+
+// The failure reasons are set as the reason of the Ready condition of a
+// failed Kustomization, and of the Warning events emitted for the failure.
+// The reasons shared with the other Flux controllers keep the values of the
+// meta package.
+const (
+	// ArtifactFailedReason signals that the artifact of the source could not
+	// be fetched, or that the path of the Kustomization does not exist in it.
+	ArtifactFailedReason = meta.ArtifactFailedReason
+
+	// DependencyNotReadyReason signals that one or more of the dependencies
+	// of the Kustomization are not ready.
+	DependencyNotReadyReason = meta.DependencyNotReadyReason
+
+	// VerificationFailedReason signals that the commit of the revision is not
+	// signed by one of the keys trusted by the Kustomization.
+	VerificationFailedReason = "VerificationFailed"
+
+	// DecryptionFailedReason signals that the SOPS encrypted manifests could
+	// not be decrypted, e.g. as the keys could not be imported or the key
+	// management services could not be reached.
+	DecryptionFailedReason = "DecryptionFailed"
+
+	// BuildFailedReason signals that the kustomize build of the revision
+	// failed.
+	BuildFailedReason = meta.BuildFailedReason
+
+	// ValidationFailedReason signals that the API server rejected one or more
+	// objects of the revision as invalid on the dry-run apply.
+	ValidationFailedReason = "ValidationFailed"
+
+	// ReconciliationFailedReason signals that the apply of the revision, or
+	// any other stage without a dedicated reason, failed.
+	ReconciliationFailedReason = meta.ReconciliationFailedReason
+
+	// RemoteUnreachableReason signals that the API server of the remote
+	// cluster targeted with a kubeconfig could not be reached, as opposed
+	// to the apply being rejected.
+	RemoteUnreachableReason = "RemoteUnreachable"
+
+	// PruneFailedReason signals that the garbage collection of the stale
+	// objects failed.
+	PruneFailedReason = meta.PruneFailedReason
+
+	// HealthCheckFailedReason signals that the health checks of the applied
+	// objects failed.
+	HealthCheckFailedReason = meta.HealthCheckFailedReason
+)
+
+// FailureReasons is the list of the reasons of a failed reconciliation.
+var FailureReasons = []string{
+	ArtifactFailedReason,
+	DependencyNotReadyReason,
+	VerificationFailedReason,
+	DecryptionFailedReason,
+	BuildFailedReason,
+	ValidationFailedReason,
+	ReconciliationFailedReason,
+	RemoteUnreachableReason,
+	PruneFailedReason,
+	HealthCheckFailedReason,
+	PolicyViolationReason,
+	TenantConformanceReason,
+	ReconciliationInterruptedReason,
+	TTLExpiredReason,
+	LockWaitReason,
+	meta.InvalidCELExpressionReason,
+	meta.FeatureGateDisabledReason,
+}
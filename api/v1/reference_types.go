@@ -55,3 +55,21 @@ func (s *CrossNamespaceSourceReference) String() string {
 
 // DependencyReference defines a Kustomization dependency on another Kustomization resource.
 type DependencyReference = meta.DependencyReference
+
+// KubeConfigProxy holds the settings of the proxy used to connect
+// to a remote cluster.
+type KubeConfigProxy struct {
+	// URL of the proxy. The 'http', 'https' and 'socks5' schemes are supported.
+	// For the 'http' and 'https' schemes, the connections are tunneled
+	// with HTTP CONNECT.
+	// +kubebuilder:validation:Pattern="^(http|https|socks5)://.+$"
+	// +required
+	URL string `json:"url"`
+
+	// CASecretRef holds the name of a Secret in the same namespace as the
+	// Kustomization, with the 'ca.crt' key containing the PEM-encoded CA
+	// certificate of an 'https' proxy. The certificate is added to the
+	// CAs trusted by the connections to the remote cluster.
+	// +optional
+	CASecretRef *meta.LocalObjectReference `json:"caSecretRef,omitempty"`
+}
@@ -27,6 +27,66 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIVersionRewrite) DeepCopyInto(out *APIVersionRewrite) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIVersionRewrite.
+func (in *APIVersionRewrite) DeepCopy() *APIVersionRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(APIVersionRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplyProgress) DeepCopyInto(out *ApplyProgress) {
+	*out = *in
+	if in.EstimatedCompletion != nil {
+		in, out := &in.EstimatedCompletion, &out.EstimatedCompletion
+		*out = (*in).DeepCopy()
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyProgress.
+func (in *ApplyProgress) DeepCopy() *ApplyProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplyProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildOptions) DeepCopyInto(out *BuildOptions) {
+	*out = *in
+	if in.TransformerConfigsFrom != nil {
+		in, out := &in.TransformerConfigsFrom, &out.TransformerConfigsFrom
+		*out = make([]meta.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.OpenAPISchemaFrom != nil {
+		in, out := &in.OpenAPISchemaFrom, &out.OpenAPISchemaFrom
+		*out = new(OpenAPISchemaReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildOptions.
+func (in *BuildOptions) DeepCopy() *BuildOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonMetadata) DeepCopyInto(out *CommonMetadata) {
 	*out = *in
@@ -79,6 +139,11 @@ func (in *Decryption) DeepCopyInto(out *Decryption) {
 		*out = new(meta.LocalObjectReference)
 		**out = **in
 	}
+	if in.ExcludePaths != nil {
+		in, out := &in.ExcludePaths, &out.ExcludePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Decryption.
@@ -91,6 +156,151 @@ func (in *Decryption) DeepCopy() *Decryption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecryptionKeys) DeepCopyInto(out *DecryptionKeys) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	in.LastChangeTime.DeepCopyInto(&out.LastChangeTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecryptionKeys.
+func (in *DecryptionKeys) DeepCopy() *DecryptionKeys {
+	if in == nil {
+		return nil
+	}
+	out := new(DecryptionKeys)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecryptionStatus) DeepCopyInto(out *DecryptionStatus) {
+	*out = *in
+	if in.KeyHashes != nil {
+		in, out := &in.KeyHashes, &out.KeyHashes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastChangeTime.DeepCopyInto(&out.LastChangeTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecryptionStatus.
+func (in *DecryptionStatus) DeepCopy() *DecryptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DecryptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftCorrection) DeepCopyInto(out *DriftCorrection) {
+	*out = *in
+	if in.Debounce != nil {
+		in, out := &in.Debounce, &out.Debounce
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftCorrection.
+func (in *DriftCorrection) DeepCopy() *DriftCorrection {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftCorrection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportPolicy) DeepCopyInto(out *ExportPolicy) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportPolicy.
+func (in *ExportPolicy) DeepCopy() *ExportPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportedBuild) DeepCopyInto(out *ExportedBuild) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportedBuild.
+func (in *ExportedBuild) DeepCopy() *ExportedBuild {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportedBuild)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitSignatureVerification) DeepCopyInto(out *GitSignatureVerification) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSignatureVerification.
+func (in *GitSignatureVerification) DeepCopy() *GitSignatureVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(GitSignatureVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckOverride) DeepCopyInto(out *HealthCheckOverride) {
+	*out = *in
+	in.HandledAt.DeepCopyInto(&out.HandledAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckOverride.
+func (in *HealthCheckOverride) DeepCopy() *HealthCheckOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckThreshold) DeepCopyInto(out *HealthCheckThreshold) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(kustomize.Selector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckThreshold.
+func (in *HealthCheckThreshold) DeepCopy() *HealthCheckThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IgnoreRule) DeepCopyInto(out *IgnoreRule) {
 	*out = *in
@@ -116,6 +326,72 @@ func (in *IgnoreRule) DeepCopy() *IgnoreRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageDigestResolution) DeepCopyInto(out *ImageDigestResolution) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(meta.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageDigestResolution.
+func (in *ImageDigestResolution) DeepCopy() *ImageDigestResolution {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageDigestResolution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullSecret) DeepCopyInto(out *ImagePullSecret) {
+	*out = *in
+	out.TemplateRef = in.TemplateRef
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullSecret.
+func (in *ImagePullSecret) DeepCopy() *ImagePullSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeConfigProxy) DeepCopyInto(out *KubeConfigProxy) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(meta.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeConfigProxy.
+func (in *KubeConfigProxy) DeepCopy() *KubeConfigProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeConfigProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kustomization) DeepCopyInto(out *Kustomization) {
 	*out = *in
@@ -183,6 +459,13 @@ func (in *KustomizationSpec) DeepCopyInto(out *KustomizationSpec) {
 		*out = new(CommonMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NamespaceLabels != nil {
+		in, out := &in.NamespaceLabels, &out.NamespaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.DependsOn != nil {
 		in, out := &in.DependsOn, &out.DependsOn
 		*out = make([]DependencyReference, len(*in))
@@ -199,21 +482,51 @@ func (in *KustomizationSpec) DeepCopyInto(out *KustomizationSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.IntervalJitterPercentage != nil {
+		in, out := &in.IntervalJitterPercentage, &out.IntervalJitterPercentage
+		*out = new(int32)
+		**out = **in
+	}
 	if in.KubeConfig != nil {
 		in, out := &in.KubeConfig, &out.KubeConfig
 		*out = new(meta.KubeConfigReference)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KubeConfigClusterRef != nil {
+		in, out := &in.KubeConfigClusterRef, &out.KubeConfigClusterRef
+		*out = new(meta.LocalObjectReference)
+		**out = **in
+	}
+	if in.KubeConfigProxy != nil {
+		in, out := &in.KubeConfigProxy, &out.KubeConfigProxy
+		*out = new(KubeConfigProxy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PostBuild != nil {
 		in, out := &in.PostBuild, &out.PostBuild
 		*out = new(PostBuild)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeletionTimeout != nil {
+		in, out := &in.DeletionTimeout, &out.DeletionTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.HealthChecks != nil {
 		in, out := &in.HealthChecks, &out.HealthChecks
 		*out = make([]meta.NamespacedObjectKindReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExternalHealthChecks != nil {
+		in, out := &in.ExternalHealthChecks, &out.ExternalHealthChecks
+		*out = make([]meta.NamespacedObjectKindReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]kustomize.Patch, len(*in))
@@ -226,27 +539,74 @@ func (in *KustomizationSpec) DeepCopyInto(out *KustomizationSpec) {
 		*out = make([]kustomize.Image, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImageDigestResolution != nil {
+		in, out := &in.ImageDigestResolution, &out.ImageDigestResolution
+		*out = new(ImageDigestResolution)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecret != nil {
+		in, out := &in.ImagePullSecret, &out.ImagePullSecret
+		*out = new(ImagePullSecret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tenancy != nil {
+		in, out := &in.Tenancy, &out.Tenancy
+		*out = new(Tenancy)
+		**out = **in
+	}
 	out.SourceRef = in.SourceRef
 	if in.Timeout != nil {
 		in, out := &in.Timeout, &out.Timeout
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.ForceMaxUnavailable != nil {
+		in, out := &in.ForceMaxUnavailable, &out.ForceMaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WaitSelector != nil {
+		in, out := &in.WaitSelector, &out.WaitSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.BuildMetadata != nil {
 		in, out := &in.BuildMetadata, &out.BuildMetadata
 		*out = make([]BuildMetadataOption, len(*in))
 		copy(*out, *in)
 	}
+	if in.BuildOptions != nil {
+		in, out := &in.BuildOptions, &out.BuildOptions
+		*out = new(BuildOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Components != nil {
 		in, out := &in.Components, &out.Components
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]meta.NamespacedObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ExportPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.HealthCheckExprs != nil {
 		in, out := &in.HealthCheckExprs, &out.HealthCheckExprs
 		*out = make([]kustomize.CustomHealthCheck, len(*in))
 		copy(*out, *in)
 	}
+	if in.HealthCheckThresholds != nil {
+		in, out := &in.HealthCheckThresholds, &out.HealthCheckThresholds
+		*out = make([]HealthCheckThreshold, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Ignore != nil {
 		in, out := &in.Ignore, &out.Ignore
 		*out = make([]IgnoreRule, len(*in))
@@ -254,6 +614,21 @@ func (in *KustomizationSpec) DeepCopyInto(out *KustomizationSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DriftCorrection != nil {
+		in, out := &in.DriftCorrection, &out.DriftCorrection
+		*out = new(DriftCorrection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lock != nil {
+		in, out := &in.Lock, &out.Lock
+		*out = new(Lock)
+		**out = **in
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(Verification)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KustomizationSpec.
@@ -270,6 +645,7 @@ func (in *KustomizationSpec) DeepCopy() *KustomizationSpec {
 func (in *KustomizationStatus) DeepCopyInto(out *KustomizationStatus) {
 	*out = *in
 	out.ReconcileRequestStatus = in.ReconcileRequestStatus
+	out.ForceRequestStatus = in.ForceRequestStatus
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -289,6 +665,83 @@ func (in *KustomizationStatus) DeepCopyInto(out *KustomizationStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastHealthCheckOverride != nil {
+		in, out := &in.LastHealthCheckOverride, &out.LastHealthCheckOverride
+		*out = new(HealthCheckOverride)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OrphanedByAPIRemoval != nil {
+		in, out := &in.OrphanedByAPIRemoval, &out.OrphanedByAPIRemoval
+		*out = make([]ResourceRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.PruneDryRun != nil {
+		in, out := &in.PruneDryRun, &out.PruneDryRun
+		*out = new(PruneDryRunResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastReconcileAPIRequests != nil {
+		in, out := &in.LastReconcileAPIRequests, &out.LastReconcileAPIRequests
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BuildWarnings != nil {
+		in, out := &in.BuildWarnings, &out.BuildWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIWarnings != nil {
+		in, out := &in.APIWarnings, &out.APIWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIVersionRewrites != nil {
+		in, out := &in.APIVersionRewrites, &out.APIVersionRewrites
+		*out = make([]APIVersionRewrite, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedImages != nil {
+		in, out := &in.ResolvedImages, &out.ResolvedImages
+		*out = make([]ResolvedImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSnapshot != nil {
+		in, out := &in.LastSnapshot, &out.LastSnapshot
+		*out = new(Snapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExportedBuild != nil {
+		in, out := &in.ExportedBuild, &out.ExportedBuild
+		*out = new(ExportedBuild)
+		**out = **in
+	}
+	if in.LastObservation != nil {
+		in, out := &in.LastObservation, &out.LastObservation
+		*out = new(Observation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LockWait != nil {
+		in, out := &in.LockWait, &out.LockWait
+		*out = new(LockWait)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(ApplyProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DecryptionKeys != nil {
+		in, out := &in.DecryptionKeys, &out.DecryptionKeys
+		*out = new(DecryptionKeys)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Decryption != nil {
+		in, out := &in.Decryption, &out.Decryption
+		*out = new(DecryptionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KustomizationStatus.
@@ -301,6 +754,78 @@ func (in *KustomizationStatus) DeepCopy() *KustomizationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Lock) DeepCopyInto(out *Lock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lock.
+func (in *Lock) DeepCopy() *Lock {
+	if in == nil {
+		return nil
+	}
+	out := new(Lock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockWait) DeepCopyInto(out *LockWait) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockWait.
+func (in *LockWait) DeepCopy() *LockWait {
+	if in == nil {
+		return nil
+	}
+	out := new(LockWait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Observation) DeepCopyInto(out *Observation) {
+	*out = *in
+	if in.AdoptionCandidates != nil {
+		in, out := &in.AdoptionCandidates, &out.AdoptionCandidates
+		*out = make([]ResourceRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.CleanupCandidates != nil {
+		in, out := &in.CleanupCandidates, &out.CleanupCandidates
+		*out = make([]ResourceRef, len(*in))
+		copy(*out, *in)
+	}
+	in.HandledAt.DeepCopyInto(&out.HandledAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observation.
+func (in *Observation) DeepCopy() *Observation {
+	if in == nil {
+		return nil
+	}
+	out := new(Observation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPISchemaReference) DeepCopyInto(out *OpenAPISchemaReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPISchemaReference.
+func (in *OpenAPISchemaReference) DeepCopy() *OpenAPISchemaReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPISchemaReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostBuild) DeepCopyInto(out *PostBuild) {
 	*out = *in
@@ -328,6 +853,42 @@ func (in *PostBuild) DeepCopy() *PostBuild {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PruneDryRunResult) DeepCopyInto(out *PruneDryRunResult) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]ResourceRef, len(*in))
+		copy(*out, *in)
+	}
+	in.HandledAt.DeepCopyInto(&out.HandledAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PruneDryRunResult.
+func (in *PruneDryRunResult) DeepCopy() *PruneDryRunResult {
+	if in == nil {
+		return nil
+	}
+	out := new(PruneDryRunResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedImage) DeepCopyInto(out *ResolvedImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedImage.
+func (in *ResolvedImage) DeepCopy() *ResolvedImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceInventory) DeepCopyInto(out *ResourceInventory) {
 	*out = *in
@@ -363,6 +924,22 @@ func (in *ResourceRef) DeepCopy() *ResourceRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Snapshot) DeepCopyInto(out *Snapshot) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Snapshot.
+func (in *Snapshot) DeepCopy() *Snapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(Snapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubstituteReference) DeepCopyInto(out *SubstituteReference) {
 	*out = *in
@@ -378,6 +955,21 @@ func (in *SubstituteReference) DeepCopy() *SubstituteReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenancy) DeepCopyInto(out *Tenancy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenancy.
+func (in *Tenancy) DeepCopy() *Tenancy {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenancy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultConfig) DeepCopyInto(out *VaultConfig) {
 	*out = *in
@@ -412,3 +1004,23 @@ func (in *VaultInstance) DeepCopy() *VaultInstance {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Verification) DeepCopyInto(out *Verification) {
+	*out = *in
+	if in.GitSignatures != nil {
+		in, out := &in.GitSignatures, &out.GitSignatures
+		*out = new(GitSignatureVerification)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Verification.
+func (in *Verification) DeepCopy() *Verification {
+	if in == nil {
+		return nil
+	}
+	out := new(Verification)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,371 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	"github.com/fluxcd/cli-utils/pkg/object"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+// Path is the path prefix of the endpoint serving the inventory of a
+// Kustomization at '<Path><namespace>/<name>'.
+const Path = "/inventory/"
+
+// requestTimeout bounds the time spent reading the objects of an inventory.
+const requestTimeout = 30 * time.Second
+
+// Health is the health rollup of the objects of an inventory.
+type Health string
+
+const (
+	// HealthHealthy signals that all the objects exist and are ready.
+	HealthHealthy Health = "Healthy"
+	// HealthProgressing signals that some objects are being reconciled
+	// by their controllers, and none has failed.
+	HealthProgressing Health = "Progressing"
+	// HealthDegraded signals that some objects are missing or failed.
+	HealthDegraded Health = "Degraded"
+	// HealthUnknown signals that the status of some objects can't be computed.
+	HealthUnknown Health = "Unknown"
+)
+
+// Entry is the live status of an inventory entry.
+type Entry struct {
+	// ID is the inventory ID of the object.
+	ID string `json:"id"`
+
+	// APIVersion is the API version of the object.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the object, empty if cluster-scoped.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+
+	// Exists is false if the object is not found in the cluster.
+	Exists bool `json:"exists"`
+
+	// Ready is true if the kstatus of the object is Current.
+	Ready bool `json:"ready"`
+
+	// Status is the kstatus of the object.
+	Status string `json:"status"`
+
+	// Message describes the status of the object.
+	Message string `json:"message,omitempty"`
+
+	// Drifted is true if the object was modified by another field
+	// manager after it was last applied by the controller.
+	Drifted bool `json:"drifted"`
+
+	// LastApplied is the time of the last apply of the object
+	// by the controller.
+	LastApplied *metav1.Time `json:"lastApplied,omitempty"`
+}
+
+// Report is the inventory of a Kustomization with the live
+// status of its objects.
+type Report struct {
+	// Namespace is the namespace of the Kustomization.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Kustomization.
+	Name string `json:"name"`
+
+	// LastAppliedRevision is the last applied revision of the Kustomization.
+	LastAppliedRevision string `json:"lastAppliedRevision,omitempty"`
+
+	// Health is the health rollup of the objects.
+	Health Health `json:"health"`
+
+	// Total is the number of inventory entries.
+	Total int `json:"total"`
+
+	// Ready is the number of ready objects.
+	Ready int `json:"ready"`
+
+	// Missing is the number of objects not found in the cluster.
+	Missing int `json:"missing"`
+
+	// Drifted is the number of drifted objects.
+	Drifted int `json:"drifted"`
+
+	// Entries holds the live status of every inventory entry.
+	Entries []Entry `json:"entries"`
+}
+
+// Handler serves the inventory of the Kustomizations with the live status
+// of their objects, so that UIs can render the resource tree of a
+// Kustomization with a single request.
+type Handler struct {
+	// Client reads the Kustomizations.
+	Client client.Reader
+
+	// Objects reads the objects of the inventories, usually from the
+	// cache returned by NewObjectCache.
+	Objects client.Reader
+
+	// APIReader reads the metadata of the Secrets of the inventories,
+	// usually from the API server, so that their data is neither read
+	// nor cached. Defaults to Objects.
+	APIReader client.Reader
+
+	// ControllerName is the field manager of the controller.
+	ControllerName string
+}
+
+// NewObjectCache returns a cache restricted to the objects labeled by the
+// controller, from which the Handler reads the objects of the inventories
+// instead of querying the API server on every request. The Secrets are
+// read with the APIReader of the Handler, and never through the cache.
+func NewObjectCache(mgr ctrl.Manager) (cache.Cache, error) {
+	req, err := labels.NewRequirement(fmt.Sprintf("%s/name", kustomizev1.GroupVersion.Group), selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient:           mgr.GetHTTPClient(),
+		Scheme:               mgr.GetScheme(),
+		Mapper:               mgr.GetRESTMapper(),
+		DefaultLabelSelector: labels.NewSelector().Add(*req),
+	})
+}
+
+// ServeHTTP writes the Report of the Kustomization at the request path
+// in JSON format.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, Path), "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		http.Error(w, fmt.Sprintf("invalid path, expected %s<namespace>/<name>", Path), http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), requestTimeout)
+	defer cancel()
+
+	obj := &kustomizev1.Kustomization{}
+	if err := h.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		code := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			code = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if obj.Spec.KubeConfig != nil || obj.Spec.KubeConfigClusterRef != nil {
+		http.Error(w, "the inventory of Kustomizations targeting remote clusters is not supported",
+			http.StatusNotImplemented)
+		return
+	}
+
+	report, err := h.Report(ctx, obj)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// Report returns the inventory of the Kustomization with the live status
+// of its objects. The Secret entries hashed in the inventory are omitted.
+func (h *Handler) Report(ctx context.Context, obj *kustomizev1.Kustomization) (*Report, error) {
+	report := &Report{
+		Namespace:           obj.GetNamespace(),
+		Name:                obj.GetName(),
+		LastAppliedRevision: obj.Status.LastAppliedRevision,
+		Entries:             []Entry{},
+	}
+	if obj.Status.Inventory == nil {
+		report.Health = HealthHealthy
+		return report, nil
+	}
+
+	objects, err := inventory.List(inventory.WithoutHashedEntries(obj.Status.Inventory))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory: %w", err)
+	}
+
+	for _, u := range objects {
+		entry, err := h.entry(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	report.rollup()
+	return report, nil
+}
+
+// entry returns the live status of the inventory object.
+func (h *Handler) entry(ctx context.Context, u *unstructured.Unstructured) (Entry, error) {
+	entry := Entry{
+		ID:         object.UnstructuredToObjMetadata(u).String(),
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Namespace:  u.GetNamespace(),
+		Name:       u.GetName(),
+	}
+
+	live, err := h.get(ctx, u)
+	if err != nil {
+		if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			entry.Status = status.NotFoundStatus.String()
+			return entry, nil
+		}
+		return entry, fmt.Errorf("failed to get %s: %w", entry.ID, err)
+	}
+	entry.Exists = true
+
+	if res, err := status.Compute(live); err != nil {
+		entry.Status = status.UnknownStatus.String()
+		entry.Message = err.Error()
+	} else {
+		entry.Status = res.Status.String()
+		entry.Message = res.Message
+		entry.Ready = res.Status == status.CurrentStatus
+	}
+
+	entry.LastApplied, entry.Drifted = lastApplied(live, h.ControllerName)
+	return entry, nil
+}
+
+// get returns the live object of the inventory object. Only the metadata
+// of the Secrets is read, with the APIReader.
+func (h *Handler) get(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	gvk := u.GroupVersionKind()
+	if gvk.Group != "" || gvk.Kind != "Secret" {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(gvk)
+		if err := h.Objects.Get(ctx, client.ObjectKeyFromObject(u), live); err != nil {
+			return nil, err
+		}
+		return live, nil
+	}
+
+	reader := h.APIReader
+	if reader == nil {
+		reader = h.Objects
+	}
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(gvk)
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(u), secret); err != nil {
+		return nil, err
+	}
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+	if err != nil {
+		return nil, err
+	}
+	live := &unstructured.Unstructured{Object: data}
+	live.SetGroupVersionKind(gvk)
+	return live, nil
+}
+
+// lastApplied returns the time of the last apply of the object by the
+// controller, and whether the object was updated by another field manager
+// afterwards. The updates of the status subresource are ignored.
+func lastApplied(live *unstructured.Unstructured, controllerName string) (*metav1.Time, bool) {
+	var applied *metav1.Time
+	for _, mf := range live.GetManagedFields() {
+		if mf.Manager == controllerName && mf.Operation == metav1.ManagedFieldsOperationApply &&
+			mf.Subresource == "" && mf.Time != nil {
+			applied = mf.Time
+		}
+	}
+	if applied == nil {
+		return nil, false
+	}
+
+	for _, mf := range live.GetManagedFields() {
+		if mf.Manager != controllerName && mf.Operation == metav1.ManagedFieldsOperationUpdate &&
+			mf.Subresource == "" && mf.Time != nil && mf.Time.After(applied.Time) {
+			return applied, true
+		}
+	}
+	return applied, false
+}
+
+// rollup computes the counters and the health of the report entries.
+func (r *Report) rollup() {
+	var failed, progressing, unknown int
+	r.Total = len(r.Entries)
+	for _, e := range r.Entries {
+		if e.Ready {
+			r.Ready++
+		}
+		if !e.Exists {
+			r.Missing++
+		}
+		if e.Drifted {
+			r.Drifted++
+		}
+		switch e.Status {
+		case status.FailedStatus.String():
+			failed++
+		case status.InProgressStatus.String():
+			progressing++
+		case status.UnknownStatus.String():
+			unknown++
+		}
+	}
+
+	switch {
+	case failed > 0 || r.Missing > 0:
+		r.Health = HealthDegraded
+	case progressing > 0:
+		r.Health = HealthProgressing
+	case unknown > 0:
+		r.Health = HealthUnknown
+	default:
+		r.Health = HealthHealthy
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+
+	ready := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "apps"},
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Status: kustomizev1.KustomizationStatus{
+			LastAppliedRevision: "main@sha1:a1b2c3d4",
+			Inventory: &kustomizev1.ResourceInventory{
+				Entries: []kustomizev1.ResourceRef{
+					{ID: "apps_ready__ConfigMap", Version: "v1"},
+					{ID: "apps_missing__ConfigMap", Version: "v1"},
+				},
+			},
+		},
+	}
+	remote := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			KubeConfigClusterRef: &meta.LocalObjectReference{Name: "staging"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(obj, remote, ready).Build()
+	h := &Handler{
+		Client:         c,
+		Objects:        c,
+		ControllerName: "kustomize-controller",
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path+"apps/app", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+	var report Report
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &report)).To(Succeed())
+	g.Expect(report.LastAppliedRevision).To(Equal("main@sha1:a1b2c3d4"))
+	g.Expect(report.Health).To(Equal(HealthDegraded))
+	g.Expect(report.Total).To(Equal(2))
+	g.Expect(report.Ready).To(Equal(1))
+	g.Expect(report.Missing).To(Equal(1))
+	g.Expect(report.Entries).To(HaveLen(2))
+
+	g.Expect(report.Entries[0].ID).To(Equal("apps_missing__ConfigMap"))
+	g.Expect(report.Entries[0].Exists).To(BeFalse())
+	g.Expect(report.Entries[0].Status).To(Equal("NotFound"))
+
+	g.Expect(report.Entries[1].ID).To(Equal("apps_ready__ConfigMap"))
+	g.Expect(report.Entries[1].APIVersion).To(Equal("v1"))
+	g.Expect(report.Entries[1].Exists).To(BeTrue())
+	g.Expect(report.Entries[1].Ready).To(BeTrue())
+	g.Expect(report.Entries[1].Status).To(Equal("Current"))
+
+	for path, code := range map[string]int{
+		Path + "apps/remote":  http.StatusNotImplemented,
+		Path + "apps/unknown": http.StatusNotFound,
+		Path + "apps":         http.StatusNotFound,
+		Path + "apps/app/x":   http.StatusNotFound,
+	} {
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		g.Expect(rec.Code).To(Equal(code), path)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path+"apps/app", nil))
+	g.Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+}
+
+func TestHandler_SecretMetadata(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+
+	token := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "apps"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Status: kustomizev1.KustomizationStatus{
+			Inventory: &kustomizev1.ResourceInventory{
+				Entries: []kustomizev1.ResourceRef{{ID: "apps_token__Secret", Version: "v1"}},
+			},
+		},
+	}
+
+	var reads []client.Object
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(obj, token).Build()
+	apiReader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(token).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				reads = append(reads, obj)
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+	h := &Handler{
+		Client: c,
+		Objects: interceptor.NewClient(c, interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return fmt.Errorf("unexpected read of %s", key)
+			},
+		}),
+		APIReader:      apiReader,
+		ControllerName: "kustomize-controller",
+	}
+
+	// Only the metadata of the Secrets is read, with the API reader.
+	report, err := h.Report(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(report.Entries).To(HaveLen(1))
+	g.Expect(report.Entries[0].Exists).To(BeTrue())
+	g.Expect(report.Entries[0].Ready).To(BeTrue())
+	g.Expect(reads).To(HaveLen(1))
+	g.Expect(reads[0]).To(BeAssignableToTypeOf(&metav1.PartialObjectMetadata{}))
+}
+
+func TestLastApplied(t *testing.T) {
+	g := NewWithT(t)
+
+	before := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	after := metav1.NewTime(time.Now().Truncate(time.Second))
+	entry := func(manager string, op metav1.ManagedFieldsOperationType, subresource string,
+		ts metav1.Time) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{Manager: manager, Operation: op, Subresource: subresource, Time: &ts}
+	}
+
+	u := &unstructured.Unstructured{}
+	applied, drifted := lastApplied(u, "kustomize-controller")
+	g.Expect(applied).To(BeNil())
+	g.Expect(drifted).To(BeFalse())
+
+	u.SetManagedFields([]metav1.ManagedFieldsEntry{
+		entry("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", before),
+		entry("kustomize-controller", metav1.ManagedFieldsOperationApply, "", before),
+		entry("deployment-controller", metav1.ManagedFieldsOperationUpdate, "status", after),
+	})
+	applied, drifted = lastApplied(u, "kustomize-controller")
+	g.Expect(applied.Time).To(Equal(before.Time))
+	g.Expect(drifted).To(BeFalse())
+
+	u.SetManagedFields(append(u.GetManagedFields(),
+		entry("kubectl-edit", metav1.ManagedFieldsOperationUpdate, "", after)))
+	applied, drifted = lastApplied(u, "kustomize-controller")
+	g.Expect(applied.Time).To(Equal(before.Time))
+	g.Expect(drifted).To(BeTrue())
+}
+
+func TestReport_Rollup(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+		want    Health
+	}{
+		{
+			name: "empty",
+			want: HealthHealthy,
+		},
+		{
+			name: "ready",
+			entries: []Entry{
+				{Exists: true, Ready: true, Status: "Current"},
+			},
+			want: HealthHealthy,
+		},
+		{
+			name: "progressing",
+			entries: []Entry{
+				{Exists: true, Ready: true, Status: "Current"},
+				{Exists: true, Status: "InProgress"},
+			},
+			want: HealthProgressing,
+		},
+		{
+			name: "failed",
+			entries: []Entry{
+				{Exists: true, Status: "InProgress"},
+				{Exists: true, Status: "Failed"},
+			},
+			want: HealthDegraded,
+		},
+		{
+			name: "unknown",
+			entries: []Entry{
+				{Exists: true, Ready: true, Status: "Current"},
+				{Exists: true, Status: "Unknown"},
+			},
+			want: HealthUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := &Report{Entries: tt.entries}
+			r.rollup()
+			g.Expect(r.Health).To(Equal(tt.want))
+		})
+	}
+}
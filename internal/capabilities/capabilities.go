@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capabilities
+
+import (
+	"encoding/json"
+	"net/http"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// Path is the path of the endpoint serving the capabilities of the controller.
+const Path = "/capabilities"
+
+// Capabilities describes the features and limits of the controller, so that
+// tooling can adapt to the controller configuration without parsing its flags.
+type Capabilities struct {
+	// FeatureGates holds the state of every feature gate by name.
+	FeatureGates map[string]bool `json:"featureGates"`
+
+	// DecryptionProviders are the names of the supported values of
+	// spec.decryption.provider.
+	DecryptionProviders []string `json:"decryptionProviders"`
+
+	// ReconcileModes are the supported values of the reconcile mode annotation.
+	ReconcileModes []string `json:"reconcileModes"`
+
+	// BuildOptions describes the build features allowed by the controller.
+	BuildOptions BuildOptions `json:"buildOptions"`
+
+	// Concurrency describes the concurrency limits of the controller.
+	Concurrency Concurrency `json:"concurrency"`
+}
+
+// BuildOptions describes the build features allowed by the controller.
+type BuildOptions struct {
+	// RemoteBases is false if the remote bases are disallowed in the
+	// kustomization files.
+	RemoteBases bool `json:"remoteBases"`
+
+	// AlphaPlugins is true if the Kustomizations can enable the kustomize
+	// alpha plugins with spec.buildOptions.enableAlphaPlugins.
+	AlphaPlugins bool `json:"alphaPlugins"`
+
+	// Helm is true if the Kustomizations can inflate Helm charts with
+	// spec.buildOptions.enableHelm.
+	Helm bool `json:"helm"`
+}
+
+// Concurrency describes the concurrency limits of the controller.
+type Concurrency struct {
+	// Reconciles is the number of concurrent reconciliations.
+	Reconciles int `json:"reconciles"`
+
+	// ServerSideApplies is the number of concurrent server-side apply
+	// operations of a reconciliation.
+	ServerSideApplies int `json:"serverSideApplies"`
+}
+
+// ReconcileModes returns the supported values of the reconcile mode annotation.
+func ReconcileModes() []string {
+	return []string{
+		kustomizev1.ReconcileModePruneOnly,
+		kustomizev1.ReconcileModeHealthOnly,
+		kustomizev1.ReconcileModeSkipHealthChecks,
+		kustomizev1.ReconcileModeSnapshot,
+		kustomizev1.ReconcileModeRestore,
+		kustomizev1.ReconcileModeObserve,
+		kustomizev1.ReconcileModeCompare,
+	}
+}
+
+// Handler serves the capabilities of the controller in JSON format.
+type Handler struct {
+	Capabilities Capabilities
+}
+
+// ServeHTTP writes the capabilities in JSON format.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	b, err := json.MarshalIndent(h.Capabilities, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capabilities
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	g := NewWithT(t)
+
+	h := &Handler{
+		Capabilities: Capabilities{
+			FeatureGates:        map[string]bool{"CacheApplyReads": true},
+			DecryptionProviders: []string{"placeholder", "sops"},
+			ReconcileModes:      ReconcileModes(),
+			BuildOptions:        BuildOptions{RemoteBases: true, Helm: true},
+			Concurrency:         Concurrency{Reconciles: 10, ServerSideApplies: 4},
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+	var got map[string]any
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &got)).To(Succeed())
+	g.Expect(got).To(HaveKeyWithValue("featureGates", HaveKeyWithValue("CacheApplyReads", true)))
+	g.Expect(got).To(HaveKeyWithValue("decryptionProviders", ConsistOf("placeholder", "sops")))
+	g.Expect(got).To(HaveKeyWithValue("reconcileModes", ContainElement("health-only")))
+	g.Expect(got).To(HaveKeyWithValue("buildOptions", And(
+		HaveKeyWithValue("remoteBases", true),
+		HaveKeyWithValue("alphaPlugins", false),
+		HaveKeyWithValue("helm", true))))
+	g.Expect(got).To(HaveKeyWithValue("concurrency", HaveKeyWithValue("reconciles", BeNumerically("==", 10))))
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, nil))
+	g.Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/client-go/transport"
+)
+
+const (
+	// apiOperationApply is the operation of the requests issued by
+	// the reconciliations, other than the drift checks and the health checks.
+	apiOperationApply = "apply"
+	// apiOperationDriftCheck is the operation of the server-side apply
+	// dry-run requests, issued to detect the drift of the objects.
+	apiOperationDriftCheck = "drift-check"
+	// apiOperationHealth is the operation of the requests
+	// issued by the health checks.
+	apiOperationHealth = "health"
+)
+
+// apiOperationKey is the context key of the apiOperation.
+type apiOperationKey struct{}
+
+// apiOperation is the type of the operation issuing API requests.
+type apiOperation struct {
+	name string
+	// background is true for the reconciliations of the
+	// last applied revision, e.g. the periodic drift correction.
+	background bool
+}
+
+// withAPIOperation returns a context tagging the requests issued with it
+// with the given operation.
+func withAPIOperation(ctx context.Context, name string, background bool) context.Context {
+	return context.WithValue(ctx, apiOperationKey{}, apiOperation{name: name, background: background})
+}
+
+// apiOperationFor returns the operation of the given request, and false
+// if the request is not issued by a reconciliation.
+func apiOperationFor(req *http.Request) (apiOperation, bool) {
+	op, ok := req.Context().Value(apiOperationKey{}).(apiOperation)
+	if !ok {
+		return op, false
+	}
+	if op.name == apiOperationApply && req.URL.Query().Get("dryRun") == "All" {
+		op.name = apiOperationDriftCheck
+	}
+	return op, true
+}
+
+// NewAPIOperationWrapper returns a transport wrapper setting the user agent
+// of the requests issued by the reconciliations to '<name>/<operation>',
+// where the operation is one of 'apply', 'drift-check' or 'health', so that
+// the requests can be told apart in the audit logs of the API server.
+// When driftCheckUser is set, the drift check requests of the background
+// reconciliations impersonate it, unless the client already impersonates a
+// user, so that they can be assigned a lower priority level by a FlowSchema.
+func NewAPIOperationWrapper(name, driftCheckUser string) transport.WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &apiOperationRoundTripper{
+			name:           name,
+			driftCheckUser: driftCheckUser,
+			delegate:       rt,
+		}
+	}
+}
+
+type apiOperationRoundTripper struct {
+	name           string
+	driftCheckUser string
+	delegate       http.RoundTripper
+}
+
+func (rt *apiOperationRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	op, ok := apiOperationFor(req)
+	if !ok {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", fmt.Sprintf("%s/%s", rt.name, op.name))
+	if rt.driftCheckUser != "" && op.background && op.name == apiOperationDriftCheck &&
+		req.Header.Get(transport.ImpersonateUserHeader) == "" {
+		req.Header.Set(transport.ImpersonateUserHeader, rt.driftCheckUser)
+	}
+	return rt.delegate.RoundTrip(req)
+}
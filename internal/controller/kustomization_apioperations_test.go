@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/transport"
+)
+
+func TestNewAPIOperationWrapper(t *testing.T) {
+	var gotUserAgent, gotImpersonate string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		gotImpersonate = r.Header.Get(transport.ImpersonateUserHeader)
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		query           string
+		impersonate     string
+		wantUserAgent   string
+		wantImpersonate string
+	}{
+		{
+			name:          "untagged request",
+			ctx:           context.Background(),
+			wantUserAgent: "default",
+		},
+		{
+			name:          "apply",
+			ctx:           withAPIOperation(context.Background(), apiOperationApply, true),
+			wantUserAgent: "kustomize-controller/apply",
+		},
+		{
+			name:          "drift check of a new revision",
+			ctx:           withAPIOperation(context.Background(), apiOperationApply, false),
+			query:         "?dryRun=All",
+			wantUserAgent: "kustomize-controller/drift-check",
+		},
+		{
+			name:            "background drift check",
+			ctx:             withAPIOperation(context.Background(), apiOperationApply, true),
+			query:           "?dryRun=All&fieldManager=kustomize-controller",
+			wantUserAgent:   "kustomize-controller/drift-check",
+			wantImpersonate: "drift-checker",
+		},
+		{
+			name:            "background drift check of an impersonated client",
+			ctx:             withAPIOperation(context.Background(), apiOperationApply, true),
+			query:           "?dryRun=All",
+			impersonate:     "system:serviceaccount:apps:tenant",
+			wantUserAgent:   "kustomize-controller/drift-check",
+			wantImpersonate: "system:serviceaccount:apps:tenant",
+		},
+		{
+			name:          "health check",
+			ctx:           withAPIOperation(context.Background(), apiOperationHealth, false),
+			wantUserAgent: "kustomize-controller/health",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rt := NewAPIOperationWrapper("kustomize-controller", "drift-checker")(http.DefaultTransport)
+			req, err := http.NewRequestWithContext(tt.ctx, http.MethodPatch, server.URL+tt.query, nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("User-Agent", "default")
+			if tt.impersonate != "" {
+				req.Header.Set(transport.ImpersonateUserHeader, tt.impersonate)
+			}
+
+			resp, err := rt.RoundTrip(req)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(resp.Body.Close()).To(Succeed())
+			g.Expect(gotUserAgent).To(Equal(tt.wantUserAgent))
+			g.Expect(gotImpersonate).To(Equal(tt.wantImpersonate))
+			// The request of the caller is not modified.
+			g.Expect(req.Header.Get("User-Agent")).To(Equal("default"))
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// apiRequestsTotal counts the Kubernetes API requests issued
+// by the reconciliations of each Kustomization.
+var apiRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gotk_kustomization_api_requests_total",
+	Help: "The total number of Kubernetes API requests issued by the reconciliations of a Kustomization.",
+}, []string{"verb", "name", "namespace"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(apiRequestsTotal)
+}
+
+// deleteAPIRequestsMetrics removes the API requests metrics
+// of the given Kustomization.
+func deleteAPIRequestsMetrics(obj *kustomizev1.Kustomization) {
+	apiRequestsTotal.DeletePartialMatch(prometheus.Labels{
+		"name":      obj.GetName(),
+		"namespace": obj.GetNamespace(),
+	})
+}
+
+// apiRequestCounter counts by verb the API requests issued
+// by the clients it wraps.
+type apiRequestCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func newAPIRequestCounter() *apiRequestCounter {
+	return &apiRequestCounter{counts: make(map[string]int64)}
+}
+
+func (c *apiRequestCounter) inc(verb string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[verb]++
+}
+
+// summary returns a copy of the request counts by verb.
+func (c *apiRequestCounter) summary() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.counts)
+}
+
+// record exports the request counts to the Prometheus metrics
+// and to the status of the given Kustomization.
+func (c *apiRequestCounter) record(obj *kustomizev1.Kustomization) {
+	counts := c.summary()
+	for verb, n := range counts {
+		apiRequestsTotal.WithLabelValues(verb, obj.GetName(), obj.GetNamespace()).Add(float64(n))
+	}
+	obj.Status.LastReconcileAPIRequests = counts
+}
+
+// wrap returns a client counting the requests issued by the given client.
+func (c *apiRequestCounter) wrap(kubeClient client.Client) client.Client {
+	return &countingClient{Client: kubeClient, counter: c}
+}
+
+// countingClient is a client.Client which counts the issued requests.
+type countingClient struct {
+	client.Client
+	counter *apiRequestCounter
+}
+
+func (c *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.counter.inc("get")
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *countingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.counter.inc("list")
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *countingClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	c.counter.inc("patch")
+	return c.Client.Apply(ctx, obj, opts...)
+}
+
+func (c *countingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.counter.inc("create")
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *countingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.counter.inc("delete")
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *countingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.counter.inc("update")
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *countingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.counter.inc("patch")
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *countingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	c.counter.inc("deletecollection")
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *countingClient) Status() client.SubResourceWriter {
+	return &countingSubResourceClient{SubResourceClient: c.Client.SubResource("status"), counter: c.counter}
+}
+
+func (c *countingClient) SubResource(subResource string) client.SubResourceClient {
+	return &countingSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), counter: c.counter}
+}
+
+// countingSubResourceClient is a client.SubResourceClient
+// which counts the issued requests.
+type countingSubResourceClient struct {
+	client.SubResourceClient
+	counter *apiRequestCounter
+}
+
+func (c *countingSubResourceClient) Get(ctx context.Context, obj, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	c.counter.inc("get")
+	return c.SubResourceClient.Get(ctx, obj, subResource, opts...)
+}
+
+func (c *countingSubResourceClient) Create(ctx context.Context, obj, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	c.counter.inc("create")
+	return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *countingSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	c.counter.inc("update")
+	return c.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (c *countingSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	c.counter.inc("patch")
+	return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *countingSubResourceClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+	c.counter.inc("patch")
+	return c.SubResourceClient.Apply(ctx, obj, opts...)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestAPIRequestCounter(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	counter := newAPIRequestCounter()
+	kubeClient := counter.wrap(fake.NewClientBuilder().Build())
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+	}
+	g.Expect(kubeClient.Create(ctx, cm)).To(Succeed())
+	g.Expect(kubeClient.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+	g.Expect(kubeClient.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+	g.Expect(kubeClient.List(ctx, &corev1.ConfigMapList{})).To(Succeed())
+	g.Expect(kubeClient.Delete(ctx, cm)).To(Succeed())
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apirequests"},
+	}
+	counter.record(obj)
+
+	g.Expect(obj.Status.LastReconcileAPIRequests).To(Equal(map[string]int64{
+		"create": 1,
+		"delete": 1,
+		"get":    2,
+		"list":   1,
+	}))
+	g.Expect(testutil.ToFloat64(apiRequestsTotal.WithLabelValues("get", "app", "apirequests"))).To(Equal(float64(2)))
+
+	deleteAPIRequestsMetrics(obj)
+	g.Expect(apiRequestsTotal.DeleteLabelValues("get", "app", "apirequests")).To(BeFalse())
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// maxAPIWarnings is the maximum number of API warnings
+// recorded in the Kustomization status.
+const maxAPIWarnings = 10
+
+// apiWarningsTotal counts the warnings returned by the Kubernetes API
+// server to the apply requests of each Kustomization.
+var apiWarningsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gotk_kustomization_api_warnings_total",
+	Help: "The total number of warnings returned by the Kubernetes API server to the apply requests of a Kustomization.",
+}, []string{"name", "namespace"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(apiWarningsTotal)
+}
+
+// deleteAPIWarningsMetrics removes the API warnings metrics
+// of the given Kustomization.
+func deleteAPIWarningsMetrics(obj *kustomizev1.Kustomization) {
+	apiWarningsTotal.DeletePartialMatch(prometheus.Labels{
+		"name":      obj.GetName(),
+		"namespace": obj.GetNamespace(),
+	})
+}
+
+// apiWarningsKey is the context key of the apiWarningCollector.
+type apiWarningsKey struct{}
+
+// apiWarningCollector collects the distinct warnings returned by the
+// API server to the requests issued with its context.
+type apiWarningCollector struct {
+	mu       sync.Mutex
+	warnings []string
+	total    int
+}
+
+// withAPIWarningCollector returns a context collecting the warnings
+// returned to the requests issued with it, and the collector.
+func withAPIWarningCollector(ctx context.Context) (context.Context, *apiWarningCollector) {
+	c := &apiWarningCollector{}
+	return context.WithValue(ctx, apiWarningsKey{}, c), c
+}
+
+func (c *apiWarningCollector) add(message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total++
+	if !slices.Contains(c.warnings, message) {
+		c.warnings = append(c.warnings, message)
+	}
+}
+
+// record exports the warnings count to the Prometheus metrics and the
+// distinct warnings to the status of the given Kustomization, capped
+// to maxAPIWarnings.
+func (c *apiWarningCollector) record(obj *kustomizev1.Kustomization) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	apiWarningsTotal.WithLabelValues(obj.GetName(), obj.GetNamespace()).Add(float64(c.total))
+	warnings := slices.Clone(c.warnings)
+	if len(warnings) > maxAPIWarnings {
+		warnings = warnings[:maxAPIWarnings]
+	}
+	obj.Status.APIWarnings = warnings
+}
+
+// APIWarningHandler is a rest.WarningHandlerWithContext which records the
+// warnings in the collector of the request context, if any, to attribute
+// them to the Kustomization being applied, and logs them like the default
+// handler of the controller-runtime clients.
+type APIWarningHandler struct {
+	logger rest.WarningHandlerWithContext
+}
+
+// NewAPIWarningHandler returns an APIWarningHandler,
+// to be set as the warning handler of the REST configs.
+func NewAPIWarningHandler() *APIWarningHandler {
+	return &APIWarningHandler{
+		logger: log.NewKubeAPIWarningLogger(log.KubeAPIWarningLoggerOptions{}),
+	}
+}
+
+// HandleWarningHeaderWithContext implements rest.WarningHandlerWithContext.
+func (h *APIWarningHandler) HandleWarningHeaderWithContext(ctx context.Context, code int, agent string, message string) {
+	if c, ok := ctx.Value(apiWarningsKey{}).(*apiWarningCollector); ok && code == 299 && message != "" {
+		c.add(message)
+	}
+	h.logger.HandleWarningHeaderWithContext(ctx, code, agent, message)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestAPIWarningHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	h := NewAPIWarningHandler()
+	deprecated := "policy/v1beta1 PodDisruptionBudget is deprecated in v1.21+, unavailable in v1.25+; use policy/v1 PodDisruptionBudget"
+
+	// The warnings of the requests without a collector are only logged.
+	h.HandleWarningHeaderWithContext(context.Background(), 299, "", deprecated)
+
+	ctx, collector := withAPIWarningCollector(context.Background())
+	h.HandleWarningHeaderWithContext(ctx, 299, "", deprecated)
+	h.HandleWarningHeaderWithContext(ctx, 299, "", deprecated)
+	h.HandleWarningHeaderWithContext(ctx, 199, "", "not a warning")
+	h.HandleWarningHeaderWithContext(ctx, 299, "", "")
+	for i := range maxAPIWarnings {
+		h.HandleWarningHeaderWithContext(ctx, 299, "", fmt.Sprintf("warning %d", i))
+	}
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apiwarnings"},
+	}
+	collector.record(obj)
+
+	g.Expect(obj.Status.APIWarnings).To(HaveLen(maxAPIWarnings))
+	g.Expect(obj.Status.APIWarnings[0]).To(Equal(deprecated))
+	g.Expect(testutil.ToFloat64(apiWarningsTotal.WithLabelValues("app", "apiwarnings"))).To(Equal(float64(2 + maxAPIWarnings)))
+
+	_, collector = withAPIWarningCollector(context.Background())
+	collector.record(obj)
+	g.Expect(obj.Status.APIWarnings).To(BeEmpty())
+
+	deleteAPIWarningsMetrics(obj)
+	g.Expect(apiWarningsTotal.DeleteLabelValues("app", "apiwarnings")).To(BeFalse())
+}
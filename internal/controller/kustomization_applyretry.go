@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+)
+
+// applyRetryInterval is the delay before the first retry of the objects
+// failing to apply with a transient error, doubled on every retry.
+var applyRetryInterval = time.Second
+
+// applyAllStagedWithRetry applies the objects in stages, and retries the
+// objects left unapplied after a transient error, e.g. while an admission
+// webhook is briefly unavailable, instead of failing the reconciliation.
+// The remaining objects are applied one by one, and only the ones failing
+// with a transient error are retried, with an exponential backoff, up to
+// ApplyRetries times and within the reconciliation deadline. The objects of
+// the stages completed before the error are not applied again.
+func (r *KustomizationReconciler) applyAllStagedWithRetry(ctx context.Context,
+	manager *ssa.ResourceManager,
+	objects []*unstructured.Unstructured,
+	opts ssa.ApplyOptions) (*ssa.ChangeSet, error) {
+	changeSet, err := manager.ApplyAllStaged(ctx, objects, opts)
+	if err == nil || r.ApplyRetries <= 0 || !isTransientApplyError(err) {
+		return changeSet, err
+	}
+	if changeSet == nil {
+		changeSet = ssa.NewChangeSet()
+	}
+
+	applied := changeSet.ToObjMetadataSet()
+	var pending []*unstructured.Unstructured
+	for _, u := range objects {
+		if !applied.Contains(object.UnstructuredToObjMetadata(u)) {
+			pending = append(pending, u)
+		}
+	}
+	sort.Sort(ssa.SortableUnstructureds(pending))
+
+	log := ctrl.LoggerFrom(ctx)
+	interval := applyRetryInterval
+	for attempt := 1; attempt <= r.ApplyRetries && len(pending) > 0; attempt++ {
+		// Give up if the backoff would exceed the reconciliation deadline.
+		if remainingTimeout(ctx, interval) < interval {
+			break
+		}
+		log.Info("retrying the apply after a transient error",
+			"attempt", attempt, "objects", len(pending), "after", interval.String(), "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return changeSet, err
+		case <-time.After(interval):
+		}
+		interval *= 2
+
+		var failed []*unstructured.Unstructured
+		for _, u := range pending {
+			entry, applyErr := manager.Apply(ctx, u, opts)
+			if applyErr != nil {
+				if !isTransientApplyError(applyErr) {
+					return changeSet, applyErr
+				}
+				log.V(1).Info("apply failed with a transient error",
+					"object", ssautil.FmtUnstructured(u), "error", applyErr.Error())
+				failed = append(failed, u)
+				err = applyErr
+				continue
+			}
+			changeSet.Add(*entry)
+		}
+		pending = failed
+	}
+	if len(pending) > 0 {
+		return changeSet, err
+	}
+	return changeSet, nil
+}
+
+// isTransientApplyError returns true if the apply error is likely to be
+// resolved by retrying shortly after, e.g. an admission webhook or an
+// aggregated API being unavailable, the API server throttling the requests,
+// or a custom resource applied before its definition is established.
+func isTransientApplyError(err error) bool {
+	return apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apimeta.IsNoMatchError(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err)
+}
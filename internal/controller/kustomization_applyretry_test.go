@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/ssa"
+)
+
+func TestApplyAllStagedWithRetry(t *testing.T) {
+	applyRetryInterval = time.Millisecond
+	t.Cleanup(func() { applyRetryInterval = time.Second })
+
+	newConfigMap := func(name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": name, "namespace": "default"},
+			"data":       map[string]any{"key": "value"},
+		}}
+	}
+	webhookErr := apierrors.NewInternalError(errors.New("failed calling webhook: connection refused"))
+	invalidErr := apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "invalid", nil)
+
+	tests := []struct {
+		name     string
+		retries  int
+		failures map[string][]error
+		wantErr  string
+		wantObjs int
+	}{
+		{
+			name:     "transient failure recovered",
+			retries:  3,
+			failures: map[string][]error{"flaky": {webhookErr, webhookErr}},
+			wantObjs: 2,
+		},
+		{
+			name:     "transient failure exhausting the retries",
+			retries:  2,
+			failures: map[string][]error{"flaky": {webhookErr, webhookErr, webhookErr, webhookErr}},
+			wantErr:  "failed calling webhook",
+		},
+		{
+			name:     "retries disabled",
+			retries:  0,
+			failures: map[string][]error{"flaky": {webhookErr}},
+			wantErr:  "failed calling webhook",
+		},
+		{
+			name:     "non-transient failure",
+			retries:  3,
+			failures: map[string][]error{"flaky": {webhookErr, invalidErr}},
+			wantErr:  "is invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			applies := make(map[string]int)
+			kubeClient := fake.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object,
+						patch client.Patch, opts ...client.PatchOption) error {
+						errs := tt.failures[obj.GetName()]
+						if n := applies[obj.GetName()]; n < len(errs) {
+							applies[obj.GetName()]++
+							return errs[n]
+						}
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).
+				Build()
+			manager := ssa.NewResourceManager(kubeClient, nil, ssa.Owner{Field: "kustomize-controller"})
+
+			r := &KustomizationReconciler{ApplyRetries: tt.retries}
+			changeSet, err := r.applyAllStagedWithRetry(context.Background(), manager,
+				[]*unstructured.Unstructured{newConfigMap("stable"), newConfigMap("flaky")},
+				ssa.DefaultApplyOptions())
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(changeSet.Entries).To(HaveLen(tt.wantObjs))
+			g.Expect(changeSet.ToMap()).To(HaveKeyWithValue("ConfigMap/default/flaky", ssa.CreatedAction))
+		})
+	}
+}
+
+func TestIsTransientApplyError(t *testing.T) {
+	g := NewWithT(t)
+
+	gr := schema.GroupResource{Resource: "configmaps"}
+	g.Expect(isTransientApplyError(apierrors.NewInternalError(errors.New("webhook")))).To(BeTrue())
+	g.Expect(isTransientApplyError(apierrors.NewServiceUnavailable("unavailable"))).To(BeTrue())
+	g.Expect(isTransientApplyError(apierrors.NewTooManyRequests("throttled", 1))).To(BeTrue())
+	g.Expect(isTransientApplyError(apierrors.NewTimeoutError("timeout", 1))).To(BeTrue())
+	g.Expect(isTransientApplyError(fmt.Errorf("dry-run failed: %w",
+		apierrors.NewServerTimeout(gr, "patch", 1)))).To(BeTrue())
+
+	g.Expect(isTransientApplyError(apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "app", nil))).To(BeFalse())
+	g.Expect(isTransientApplyError(apierrors.NewForbidden(gr, "app", errors.New("denied")))).To(BeFalse())
+	g.Expect(isTransientApplyError(errors.New("unknown"))).To(BeFalse())
+}
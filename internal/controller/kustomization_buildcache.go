@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+// cachedBuild holds the result of the last successful build of a
+// Kustomization, along with the revision and generation it was built for.
+type cachedBuild struct {
+	revision   string
+	generation int64
+	resources  []byte
+
+	// sopsMetadata holds the SOPS metadata of the decrypted objects.
+	sopsMetadata map[string]decryptor.SOPSMetadata
+}
+
+// getCachedBuild returns the resources built for the given revision of the
+// Kustomization and the SOPS metadata of the decrypted objects, if the build
+// can be reused for a manual reconcile request.
+func (r *KustomizationReconciler) getCachedBuild(obj *kustomizev1.Kustomization,
+	revision string,
+	req reconcileRequest) ([]byte, map[string]decryptor.SOPSMetadata, bool) {
+	if !r.CacheBuildOnManualReconcile || !req.manual || !r.isBuildCacheable(obj) {
+		return nil, nil, false
+	}
+
+	v, ok := r.buildCache.Load(client.ObjectKeyFromObject(obj))
+	if !ok {
+		return nil, nil, false
+	}
+	build := v.(*cachedBuild)
+	if build.revision != revision || build.generation != obj.Generation {
+		return nil, nil, false
+	}
+	return build.resources, build.sopsMetadata, true
+}
+
+// setCachedBuild stores the resources built for the given revision
+// of the Kustomization.
+func (r *KustomizationReconciler) setCachedBuild(obj *kustomizev1.Kustomization,
+	revision string,
+	resources []byte,
+	sopsMetadata map[string]decryptor.SOPSMetadata) {
+	if !r.CacheBuildOnManualReconcile || !r.isBuildCacheable(obj) {
+		return
+	}
+
+	r.buildCache.Store(client.ObjectKeyFromObject(obj), &cachedBuild{
+		revision:     revision,
+		generation:   obj.Generation,
+		resources:    resources,
+		sopsMetadata: sopsMetadata,
+	})
+}
+
+// deleteCachedBuild removes the build of the Kustomization from the cache.
+func (r *KustomizationReconciler) deleteCachedBuild(obj *kustomizev1.Kustomization) {
+	r.buildCache.Delete(client.ObjectKeyFromObject(obj))
+}
+
+// isBuildCacheable returns false if the build result depends on in-cluster
+// objects or external stores other than the source, e.g. the ConfigMaps and
+// Secrets used for post-build substitutions, the cluster facts, the builds of
+// the included Kustomizations, the tenancy profiles, the transformer
+// configurations and OpenAPI schema ConfigMaps, the image digests of the
+// registries or the placeholder values of the external secret stores, which
+// may change without a new generation.
+func (r *KustomizationReconciler) isBuildCacheable(obj *kustomizev1.Kustomization) bool {
+	if r.DefaultSubstituteFrom != "" || len(obj.Spec.Include) > 0 || obj.Spec.Tenancy != nil {
+		return false
+	}
+	if bo := obj.Spec.BuildOptions; bo != nil && (len(bo.TransformerConfigsFrom) > 0 || bo.OpenAPISchemaFrom != nil) {
+		return false
+	}
+	if obj.Spec.ImageDigestResolution != nil {
+		return false
+	}
+	if obj.Spec.Decryption != nil && obj.Spec.Decryption.Provider == decryptor.DecryptionProviderPlaceholder {
+		return false
+	}
+	if obj.Spec.PostBuild != nil && r.ClusterFacts != nil {
+		return false
+	}
+	return obj.Spec.PostBuild == nil || len(obj.Spec.PostBuild.SubstituteFrom) == 0
+}
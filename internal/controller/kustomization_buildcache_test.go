@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+func TestKustomizationReconciler_BuildCache(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &KustomizationReconciler{CacheBuildOnManualReconcile: true}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 1},
+	}
+	resources := []byte("apiVersion: v1\nkind: ConfigMap\n")
+	sopsMetadata := map[string]decryptor.SOPSMetadata{
+		"default_secret__Secret": {Keys: []string{"age:age1xyz"}},
+	}
+	manual := reconcileRequest{manual: true}
+
+	r.setCachedBuild(obj, "v1", resources, sopsMetadata)
+
+	got, gotMetadata, ok := r.getCachedBuild(obj, "v1", manual)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(got).To(Equal(resources))
+	g.Expect(gotMetadata).To(Equal(sopsMetadata))
+
+	_, _, ok = r.getCachedBuild(obj, "v1", reconcileRequest{})
+	g.Expect(ok).To(BeFalse(), "scheduled reconciliations must build")
+
+	_, _, ok = r.getCachedBuild(obj, "v2", manual)
+	g.Expect(ok).To(BeFalse(), "a new revision must be built")
+
+	obj.Generation = 2
+	_, _, ok = r.getCachedBuild(obj, "v1", manual)
+	g.Expect(ok).To(BeFalse(), "a new generation must be built")
+
+	obj.Generation = 1
+	obj.Spec.PostBuild = &kustomizev1.PostBuild{
+		SubstituteFrom: []kustomizev1.SubstituteReference{{Kind: "ConfigMap", Name: "vars"}},
+	}
+	_, _, ok = r.getCachedBuild(obj, "v1", manual)
+	g.Expect(ok).To(BeFalse(), "builds with substituteFrom must not be reused")
+
+	obj.Spec.PostBuild = nil
+	r.deleteCachedBuild(obj)
+	_, _, ok = r.getCachedBuild(obj, "v1", manual)
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestIsBuildCacheable(t *testing.T) {
+	tests := []struct {
+		name string
+		spec kustomizev1.KustomizationSpec
+		want bool
+	}{
+		{name: "source only", want: true},
+		{
+			name: "transformer configurations",
+			spec: kustomizev1.KustomizationSpec{BuildOptions: &kustomizev1.BuildOptions{
+				TransformerConfigsFrom: []meta.LocalObjectReference{{Name: "transformers"}},
+			}},
+		},
+		{
+			name: "OpenAPI schema",
+			spec: kustomizev1.KustomizationSpec{BuildOptions: &kustomizev1.BuildOptions{
+				OpenAPISchemaFrom: &kustomizev1.OpenAPISchemaReference{Name: "schema"},
+			}},
+		},
+		{
+			name: "image digest resolution",
+			spec: kustomizev1.KustomizationSpec{ImageDigestResolution: &kustomizev1.ImageDigestResolution{
+				Images: []string{"podinfo"},
+			}},
+		},
+		{
+			name: "placeholder decryption",
+			spec: kustomizev1.KustomizationSpec{Decryption: &kustomizev1.Decryption{
+				Provider: decryptor.DecryptionProviderPlaceholder,
+			}},
+		},
+		{
+			name: "sops decryption",
+			spec: kustomizev1.KustomizationSpec{Decryption: &kustomizev1.Decryption{
+				Provider: decryptor.DecryptionProviderSOPS,
+			}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := &KustomizationReconciler{}
+			obj := &kustomizev1.Kustomization{Spec: tt.spec}
+			g.Expect(r.isBuildCacheable(obj)).To(Equal(tt.want))
+		})
+	}
+}
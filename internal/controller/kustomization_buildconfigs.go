@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	openapi_v2 "github.com/google/gnostic-models/openapiv2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+	"sigs.k8s.io/yaml"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// transformerConfigsDir is the directory, relative to the path of the
+// Kustomization, where the transformer configurations are written.
+const transformerConfigsDir = ".flux-transformer-configs"
+
+// injectTransformerConfigs writes the data entries of the ConfigMaps
+// referenced in spec.buildOptions.transformerConfigsFrom to files in the
+// dirPath, and adds them to the configurations of its kustomization file.
+func (r *KustomizationReconciler) injectTransformerConfigs(ctx context.Context,
+	obj *kustomizev1.Kustomization, dirPath string) error {
+	if obj.Spec.BuildOptions == nil || len(obj.Spec.BuildOptions.TransformerConfigsFrom) == 0 {
+		return nil
+	}
+
+	var files []string
+	for _, ref := range obj.Spec.BuildOptions.TransformerConfigsFrom {
+		key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: ref.Name}
+		var cm corev1.ConfigMap
+		if err := r.Get(ctx, key, &cm); err != nil {
+			return fmt.Errorf("failed to get transformer configs ConfigMap '%s': %w", key, err)
+		}
+
+		names := make([]string, 0, len(cm.Data))
+		for name := range cm.Data {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			file := filepath.Join(transformerConfigsDir, cm.Name, name)
+			if err := os.MkdirAll(filepath.Join(dirPath, filepath.Dir(file)), 0o700); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dirPath, file), []byte(cm.Data[name]), 0o600); err != nil {
+				return fmt.Errorf("failed to write transformer configs '%s': %w", file, err)
+			}
+			files = append(files, filepath.ToSlash(file))
+		}
+	}
+
+	return editKustomizationFile(dirPath, func(kus map[string]any) error {
+		configurations, _ := kus["configurations"].([]any)
+		for _, file := range files {
+			configurations = append(configurations, file)
+		}
+		kus["configurations"] = configurations
+		return nil
+	})
+}
+
+// openAPISchema returns the OpenAPI schema of the ConfigMap referenced in
+// spec.buildOptions.openAPISchemaFrom, or nil if no schema is referenced.
+func (r *KustomizationReconciler) openAPISchema(ctx context.Context,
+	obj *kustomizev1.Kustomization) ([]byte, error) {
+	if obj.Spec.BuildOptions == nil || obj.Spec.BuildOptions.OpenAPISchemaFrom == nil {
+		return nil, nil
+	}
+
+	ref := obj.Spec.BuildOptions.OpenAPISchemaFrom
+	key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: ref.Name}
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, key, &cm); err != nil {
+		return nil, fmt.Errorf("failed to get OpenAPI schema ConfigMap '%s': %w", key, err)
+	}
+	schema, ok := cm.Data[ref.GetKey()]
+	if !ok {
+		return nil, fmt.Errorf("OpenAPI schema key '%s' not found in ConfigMap '%s'", ref.GetKey(), key)
+	}
+	return []byte(schema), nil
+}
+
+// addOpenAPISchema adds the definitions of the OpenAPI v2 schema, in JSON
+// or YAML format, to the global schema used by kustomize. The schema is
+// parsed with gnostic instead of the kustomize openapi field, as the JSON
+// decoding of the latter drops the x-kubernetes extensions which hold the
+// GVK and the patch strategies of the definitions.
+func addOpenAPISchema(schema []byte) error {
+	doc := make(map[string]any)
+	if err := yaml.Unmarshal(schema, &doc); err != nil {
+		return fmt.Errorf("failed to decode OpenAPI schema: %w", err)
+	}
+	// Fill in the fields required by the OpenAPI v2 specification,
+	// which are usually omitted from the schemas of custom resources.
+	if _, ok := doc["swagger"]; !ok {
+		doc["swagger"] = "2.0"
+	}
+	if _, ok := doc["info"]; !ok {
+		doc["info"] = map[string]any{"title": "Kustomization", "version": "v1"}
+	}
+	if _, ok := doc["paths"]; !ok {
+		doc["paths"] = map[string]any{}
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	gdoc, err := openapi_v2.ParseDocument(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse OpenAPI schema: %w", err)
+	}
+	var swagger spec.Swagger
+	if _, err := swagger.FromGnostic(gdoc); err != nil {
+		return fmt.Errorf("failed to parse OpenAPI schema: %w", err)
+	}
+	openapi.AddDefinitions(swagger.Definitions)
+	return nil
+}
+
+// editKustomizationFile applies the edit function to the kustomization
+// file in the dirPath, decoded as a generic map to preserve all its fields.
+func editKustomizationFile(dirPath string, edit func(map[string]any) error) error {
+	var kfile string
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if _, err := os.Stat(filepath.Join(dirPath, name)); err == nil {
+			kfile = filepath.Join(dirPath, name)
+			break
+		}
+	}
+	if kfile == "" {
+		return fmt.Errorf("kustomization file not found in '%s'", dirPath)
+	}
+
+	data, err := os.ReadFile(kfile)
+	if err != nil {
+		return err
+	}
+	kus := make(map[string]any)
+	if err := yaml.Unmarshal(data, &kus); err != nil {
+		return fmt.Errorf("failed to decode kustomization file: %w", err)
+	}
+	if err := edit(kus); err != nil {
+		return err
+	}
+	data, err = yaml.Marshal(kus)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(kfile, data, 0o600)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestInjectTransformerConfigs(t *testing.T) {
+	g := NewWithT(t)
+
+	root := t.TempDir()
+	files := map[string]string{
+		"kustomization.yaml": `
+namePrefix: prod-
+resources:
+- resources.yaml
+`,
+		"resources.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+spec:
+  settingsRef:
+    name: settings
+`,
+	}
+	for name, content := range files {
+		g.Expect(os.WriteFile(filepath.Join(root, name), []byte(content), 0o644)).To(Succeed())
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "crd-configs", Namespace: "apps"},
+		Data: map[string]string{
+			"app.yaml": `
+nameReference:
+- kind: ConfigMap
+  version: v1
+  fieldSpecs:
+  - kind: App
+    path: spec/settingsRef/name
+`,
+		},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(cm).Build(),
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			BuildOptions: &kustomizev1.BuildOptions{
+				TransformerConfigsFrom: []meta.LocalObjectReference{{Name: "crd-configs"}},
+			},
+		},
+	}
+
+	g.Expect(r.injectTransformerConfigs(context.Background(), obj, root)).To(Succeed())
+
+	opts, err := r.kustomizeBuildOptions(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	m, err := secureBuild(root, root, false, opts, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	ref, err := m.Resources()[1].GetString("spec.settingsRef.name")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ref).To(Equal("prod-settings"))
+
+	obj.Spec.BuildOptions.TransformerConfigsFrom = []meta.LocalObjectReference{{Name: "missing"}}
+	err = r.injectTransformerConfigs(context.Background(), obj, root)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to get transformer configs ConfigMap 'apps/missing'"))
+}
+
+func TestOpenAPISchema(t *testing.T) {
+	g := NewWithT(t)
+
+	root := t.TempDir()
+	files := map[string]string{
+		"kustomization.yaml": `
+resources:
+- app.yaml
+patches:
+- patch: |
+    apiVersion: example.com/v1
+    kind: App
+    metadata:
+      name: app
+    spec:
+      containers:
+      - name: sidecar
+        image: sidecar:v1
+`,
+		"app.yaml": `
+apiVersion: example.com/v1
+kind: App
+metadata:
+  name: app
+spec:
+  containers:
+  - name: main
+    image: main:v1
+`,
+	}
+	for name, content := range files {
+		g.Expect(os.WriteFile(filepath.Join(root, name), []byte(content), 0o644)).To(Succeed())
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "crd-schemas", Namespace: "apps"},
+		Data: map[string]string{
+			"schema.json": `{
+  "definitions": {
+    "com.example.v1.App": {
+      "type": "object",
+      "properties": {
+        "spec": {
+          "type": "object",
+          "properties": {
+            "containers": {
+              "type": "array",
+              "items": {"$ref": "#/definitions/com.example.v1.Container"},
+              "x-kubernetes-patch-merge-key": "name",
+              "x-kubernetes-patch-strategy": "merge"
+            }
+          }
+        }
+      },
+      "x-kubernetes-group-version-kind": [{"group": "example.com", "kind": "App", "version": "v1"}]
+    },
+    "com.example.v1.Container": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "image": {"type": "string"}
+      }
+    }
+  }
+}`,
+		},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(cm).Build(),
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			BuildOptions: &kustomizev1.BuildOptions{
+				OpenAPISchemaFrom: &kustomizev1.OpenAPISchemaReference{Name: "crd-schemas"},
+			},
+		},
+	}
+
+	schema, err := r.openAPISchema(context.Background(), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	opts, err := r.kustomizeBuildOptions(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Without the schema, the list of the custom resource is replaced.
+	m, err := secureBuild(root, root, false, opts, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	containers, err := m.Resources()[0].GetSlice("spec.containers")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(containers).To(HaveLen(1))
+
+	m, err = secureBuild(root, root, false, opts, schema)
+	g.Expect(err).ToNot(HaveOccurred())
+	containers, err = m.Resources()[0].GetSlice("spec.containers")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(containers).To(HaveLen(2))
+
+	obj.Spec.BuildOptions.OpenAPISchemaFrom.Key = "crds.yaml"
+	_, err = r.openAPISchema(context.Background(), obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("key 'crds.yaml' not found"))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resmap"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+
+	securefs "github.com/fluxcd/pkg/kustomize/filesys"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// kustomizeBuildMutex serializes the kustomize builds, as kustomize
+// is not safe for concurrent use, see
+// https://github.com/kubernetes-sigs/kustomize/issues/3659
+var kustomizeBuildMutex sync.Mutex
+
+// kustomizeBuildOptions returns the kustomize build options of the
+// Kustomization, or an error if they are not allowed in the controller.
+// Without build options, the build is configured like the SecureBuild
+// of the kustomize package, i.e. with files loaded from the whole source
+// artifact, resources kept in order and only the builtin plugins enabled.
+func (r *KustomizationReconciler) kustomizeBuildOptions(obj *kustomizev1.Kustomization) (*krusty.Options, error) {
+	opts := &krusty.Options{
+		LoadRestrictions: kustypes.LoadRestrictionsNone,
+		PluginConfig:     kustypes.DisabledPluginConfig(),
+	}
+
+	bo := obj.Spec.BuildOptions
+	if bo == nil {
+		return opts, nil
+	}
+
+	if obj.GetLoadRestrictor() == kustomizev1.LoadRestrictionsRootOnly {
+		opts.LoadRestrictions = kustypes.LoadRestrictionsRootOnly
+	}
+	if bo.Reorder == kustomizev1.ReorderLegacy {
+		opts.Reorder = krusty.ReorderOptionLegacy
+	}
+
+	var errs []error
+	if bo.EnableAlphaPlugins {
+		if r.AllowKustomizeAlphaPlugins {
+			opts.PluginConfig.PluginRestrictions = kustypes.PluginRestrictionsNone
+			opts.PluginConfig.FnpLoadingOptions.EnableExec = true
+		} else {
+			errs = append(errs, errors.New("spec.buildOptions.enableAlphaPlugins is not allowed, "+
+				"the controller must be started with --allow-kustomize-alpha-plugins"))
+		}
+	}
+	if bo.EnableHelm {
+		if r.KustomizeHelmCommand != "" {
+			opts.PluginConfig.HelmConfig.Enabled = true
+			opts.PluginConfig.HelmConfig.Command = r.KustomizeHelmCommand
+		} else {
+			errs = append(errs, errors.New("spec.buildOptions.enableHelm is not allowed, "+
+				"the controller must be started with --kustomize-helm-command"))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return opts, nil
+}
+
+// secureBuild runs the kustomize build of the dirPath on a file system
+// restricted to the root directory, with or without remote bases.
+// It mirrors the SecureBuild of the kustomize package with the given
+// build options, and adds the OpenAPI schema, if any, to the builtin one.
+func secureBuild(root, dirPath string, allowRemoteBases bool, opts *krusty.Options,
+	openAPISchema []byte) (res resmap.ResMap, err error) {
+	var fs filesys.FileSystem
+	if allowRemoteBases {
+		fs, err = securefs.MakeFsOnDiskSecureBuild(root)
+	} else {
+		fs, err = securefs.MakeFsOnDiskSecure(root)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kustomizeBuildMutex.Lock()
+	defer kustomizeBuildMutex.Unlock()
+
+	// Kustomize tends to panic in unpredicted ways due to invalid
+	// object data, recover to ensure continuity of operations.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from kustomize build panic: %v", r)
+		}
+	}()
+
+	// Reset the global OpenAPI schema to isolate the builds.
+	openapi.ResetOpenAPI()
+	_ = openapi.Schema()
+	defer openapi.ResetOpenAPI()
+	if len(openAPISchema) > 0 {
+		if err := addOpenAPISchema(openAPISchema); err != nil {
+			return nil, err
+		}
+	}
+
+	return krusty.MakeKustomizer(opts).Run(fs, dirPath)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kustomize/api/krusty"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestKustomizeBuildOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &kustomizev1.Kustomization{}
+	r := &KustomizationReconciler{}
+
+	opts, err := r.kustomizeBuildOptions(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(opts.LoadRestrictions).To(Equal(kustypes.LoadRestrictionsNone))
+	g.Expect(opts.PluginConfig.PluginRestrictions).To(Equal(kustypes.PluginRestrictionsBuiltinsOnly))
+
+	obj.Spec.BuildOptions = &kustomizev1.BuildOptions{
+		LoadRestrictor:     kustomizev1.LoadRestrictionsRootOnly,
+		Reorder:            kustomizev1.ReorderLegacy,
+		EnableHelm:         true,
+		EnableAlphaPlugins: true,
+	}
+	_, err = r.kustomizeBuildOptions(obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("--allow-kustomize-alpha-plugins"))
+	g.Expect(err.Error()).To(ContainSubstring("--kustomize-helm-command"))
+
+	r.AllowKustomizeAlphaPlugins = true
+	r.KustomizeHelmCommand = "/usr/local/bin/helm"
+	opts, err = r.kustomizeBuildOptions(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(opts.LoadRestrictions).To(Equal(kustypes.LoadRestrictionsRootOnly))
+	g.Expect(opts.Reorder).To(Equal(krusty.ReorderOptionLegacy))
+	g.Expect(opts.PluginConfig.PluginRestrictions).To(Equal(kustypes.PluginRestrictionsNone))
+	g.Expect(opts.PluginConfig.HelmConfig.Enabled).To(BeTrue())
+	g.Expect(opts.PluginConfig.HelmConfig.Command).To(Equal("/usr/local/bin/helm"))
+}
+
+func TestSecureBuild_LoadRestrictor(t *testing.T) {
+	g := NewWithT(t)
+
+	root := t.TempDir()
+	files := map[string]string{
+		"apps/kustomization.yaml": `
+configMapGenerator:
+- name: app
+  files:
+  - ../config/app.properties
+`,
+		"config/app.properties": "key=value",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+
+	r := &KustomizationReconciler{}
+	obj := &kustomizev1.Kustomization{}
+
+	opts, err := r.kustomizeBuildOptions(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	m, err := secureBuild(root, filepath.Join(root, "apps"), false, opts, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m.Resources()).To(HaveLen(1))
+
+	obj.Spec.BuildOptions = &kustomizev1.BuildOptions{LoadRestrictor: kustomizev1.LoadRestrictionsRootOnly}
+	opts, err = r.kustomizeBuildOptions(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = secureBuild(root, filepath.Join(root, "apps"), false, opts, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("security; file"))
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/pkg/ssa"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+var (
+	// buildObjectsKey is the event metadata key holding
+	// the number of objects of the build result.
+	buildObjectsKey = kustomizev1.GroupVersion.Group + "/objects"
+
+	// buildBytesKey is the event metadata key holding
+	// the size in bytes of the build result.
+	buildBytesKey = kustomizev1.GroupVersion.Group + "/bytes"
+
+	// buildKindsKey is the event metadata key holding the number
+	// of objects of the build result per kind and group.
+	buildKindsKey = kustomizev1.GroupVersion.Group + "/kinds"
+
+	// changedObjectsKey is the event metadata key holding
+	// the number of objects changed by the apply.
+	changedObjectsKey = kustomizev1.GroupVersion.Group + "/changed"
+)
+
+// buildSizeEventMetadata returns the event metadata with the size of the build
+// result and the number of objects changed by the apply, for the notification
+// pipelines to route the events of large changes. The objects per kind are
+// formatted as comma separated '<kind>[.<group>]=<count>' pairs.
+func buildSizeEventMetadata(objects []*unstructured.Unstructured,
+	size int,
+	changeSet *ssa.ChangeSet) map[string]string {
+	kinds := make(map[string]int)
+	for _, u := range objects {
+		kinds[u.GroupVersionKind().GroupKind().String()]++
+	}
+	pairs := make([]string, 0, len(kinds))
+	for _, kind := range slices.Sorted(maps.Keys(kinds)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", kind, kinds[kind]))
+	}
+
+	changed := 0
+	if changeSet != nil {
+		for _, entry := range changeSet.Entries {
+			if HasChanged(entry.Action) {
+				changed++
+			}
+		}
+	}
+
+	return map[string]string{
+		buildObjectsKey:   strconv.Itoa(len(objects)),
+		buildBytesKey:     strconv.Itoa(size),
+		buildKindsKey:     strings.Join(pairs, ","),
+		changedObjectsKey: strconv.Itoa(changed),
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/pkg/ssa"
+)
+
+func TestBuildSizeEventMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	newObject := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata":   map[string]any{"name": name, "namespace": "apps"},
+		}}
+	}
+	objects := []*unstructured.Unstructured{
+		newObject("apps/v1", "Deployment", "frontend"),
+		newObject("apps/v1", "Deployment", "backend"),
+		newObject("v1", "ConfigMap", "settings"),
+		newObject("v1", "Service", "frontend"),
+	}
+
+	changeSet := ssa.NewChangeSet()
+	changeSet.Add(ssa.ChangeSetEntry{Subject: "Deployment/apps/frontend", Action: ssa.ConfiguredAction})
+	changeSet.Add(ssa.ChangeSetEntry{Subject: "Deployment/apps/backend", Action: ssa.UnchangedAction})
+	changeSet.Add(ssa.ChangeSetEntry{Subject: "ConfigMap/apps/settings", Action: ssa.CreatedAction})
+	changeSet.Add(ssa.ChangeSetEntry{Subject: "Service/apps/frontend", Action: ssa.SkippedAction})
+
+	g.Expect(buildSizeEventMetadata(objects, 2048, changeSet)).To(Equal(map[string]string{
+		"kustomize.toolkit.fluxcd.io/objects": "4",
+		"kustomize.toolkit.fluxcd.io/bytes":   "2048",
+		"kustomize.toolkit.fluxcd.io/kinds":   "ConfigMap=1,Deployment.apps=2,Service=1",
+		"kustomize.toolkit.fluxcd.io/changed": "2",
+	}))
+
+	g.Expect(buildSizeEventMetadata(nil, 0, nil)).To(Equal(map[string]string{
+		"kustomize.toolkit.fluxcd.io/objects": "0",
+		"kustomize.toolkit.fluxcd.io/bytes":   "0",
+		"kustomize.toolkit.fluxcd.io/kinds":   "",
+		"kustomize.toolkit.fluxcd.io/changed": "0",
+	}))
+}
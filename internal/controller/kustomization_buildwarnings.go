@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kustomize/api/konfig"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// maxBuildWarnings is the maximum number of build warnings
+// recorded in the Kustomization status.
+const maxBuildWarnings = 10
+
+// buildWarnings returns the deprecation warnings of the kustomization files
+// of the overlay at dirPath and of its local resources and components.
+// Kustomize prints these warnings to the stderr of the controller, where
+// they can't be attributed to a Kustomization, hence they are collected
+// separately. The paths in the warnings are relative to the workDir.
+func buildWarnings(workDir, dirPath string) []string {
+	var warnings []string
+	visited := make(map[string]struct{})
+
+	var visit func(dir string)
+	visit = func(dir string) {
+		if _, ok := visited[dir]; ok {
+			return
+		}
+		visited[dir] = struct{}{}
+
+		for _, name := range konfig.RecognizedKustomizationFileNames() {
+			content, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			var k kustypes.Kustomization
+			if err := k.Unmarshal(content); err != nil {
+				// The build reports the invalid files.
+				return
+			}
+
+			path, err := filepath.Rel(workDir, filepath.Join(dir, name))
+			if err != nil {
+				path = name
+			}
+			if msgs := k.CheckDeprecatedFields(); msgs != nil {
+				for _, msg := range *msgs {
+					warnings = append(warnings, fmt.Sprintf("%s: %s", path, strings.TrimPrefix(msg, "# Warning: ")))
+				}
+			}
+
+			for _, ref := range slices.Concat(k.Resources, k.Bases, k.Components) {
+				if strings.Contains(ref, "://") || strings.HasPrefix(ref, "github.com/") {
+					continue
+				}
+				rel, err := filepath.Rel(workDir, filepath.Join(dir, ref))
+				if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					continue
+				}
+				next, err := securejoin.SecureJoin(workDir, rel)
+				if err != nil {
+					continue
+				}
+				if fi, err := os.Stat(next); err == nil && fi.IsDir() {
+					visit(next)
+				}
+			}
+			return
+		}
+	}
+	visit(dirPath)
+
+	return warnings
+}
+
+// recordBuildWarnings records the build warnings in the Kustomization status,
+// capped to maxBuildWarnings, and emits a BuildWarnings event when they
+// differ from the ones of the previous build.
+func (r *KustomizationReconciler) recordBuildWarnings(obj *kustomizev1.Kustomization,
+	revision, originRevision string, warnings []string) {
+	total := len(warnings)
+	if total > maxBuildWarnings {
+		warnings = warnings[:maxBuildWarnings]
+	}
+	if slices.Equal(obj.Status.BuildWarnings, warnings) {
+		return
+	}
+	obj.Status.BuildWarnings = warnings
+	if total == 0 {
+		return
+	}
+
+	msg := strings.Join(warnings, "\n")
+	if total > maxBuildWarnings {
+		msg += fmt.Sprintf("\n(%d more warnings omitted)", total-maxBuildWarnings)
+	}
+
+	metadata := map[string]string{
+		kustomizev1.GroupVersion.Group + "/" + eventv1.MetaRevisionKey: revision,
+	}
+	if originRevision != "" {
+		metadata[kustomizev1.GroupVersion.Group+"/"+eventv1.MetaOriginRevisionKey] = originRevision
+	}
+	r.EventRecorder.AnnotatedEventf(obj, metadata, corev1.EventTypeNormal, kustomizev1.BuildWarningsReason,
+		"Kustomize build warnings:\n%s", msg)
+}
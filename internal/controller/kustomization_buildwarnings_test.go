@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestBuildWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	workDir := t.TempDir()
+	files := map[string]string{
+		"apps/prod/kustomization.yaml": `
+resources:
+- ../base
+- https://github.com/stefanprodan/podinfo//kustomize
+commonLabels:
+  env: prod
+`,
+		"apps/base/kustomization.yaml": `
+resources:
+- deployment.yaml
+- ../../outside
+patchesStrategicMerge:
+- patch.yaml
+`,
+		"apps/base/deployment.yaml": "",
+		"outside/Kustomization": `
+vars:
+- name: NAME
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(workDir, name)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+
+	warnings := buildWarnings(workDir, filepath.Join(workDir, "apps/prod"))
+	g.Expect(warnings).To(HaveLen(3))
+	g.Expect(warnings[0]).To(HavePrefix("apps/prod/kustomization.yaml: 'commonLabels' is deprecated."))
+	g.Expect(warnings[1]).To(HavePrefix("apps/base/kustomization.yaml: 'patchesStrategicMerge' is deprecated."))
+	g.Expect(warnings[2]).To(HavePrefix("outside/Kustomization: 'vars' is deprecated."))
+
+	// The resources outside the workDir are skipped.
+	warnings = buildWarnings(filepath.Join(workDir, "apps"), filepath.Join(workDir, "apps/base"))
+	g.Expect(warnings).To(HaveLen(1))
+	g.Expect(warnings[0]).To(HavePrefix("base/kustomization.yaml: 'patchesStrategicMerge' is deprecated."))
+}
+
+func TestRecordBuildWarnings(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(10)
+	r := &KustomizationReconciler{EventRecorder: recorder}
+	obj := &kustomizev1.Kustomization{}
+
+	var warnings []string
+	for i := range 12 {
+		warnings = append(warnings, fmt.Sprintf("warning %d", i))
+	}
+	r.recordBuildWarnings(obj, "main@sha1:a1b2c3d4", "", warnings)
+	g.Expect(obj.Status.BuildWarnings).To(Equal(warnings[:maxBuildWarnings]))
+	g.Expect(recorder.Events).To(Receive(And(
+		ContainSubstring(kustomizev1.BuildWarningsReason),
+		ContainSubstring("warning 9"),
+		ContainSubstring("(2 more warnings omitted)"),
+	)))
+
+	// Unchanged warnings are not reported again.
+	r.recordBuildWarnings(obj, "main@sha1:a1b2c3d4", "", warnings)
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	r.recordBuildWarnings(obj, "main@sha1:e5f6a7b8", "", nil)
+	g.Expect(obj.Status.BuildWarnings).To(BeEmpty())
+	g.Expect(recorder.Events).ToNot(Receive())
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get
+
+// clusterAPIClusterGK is the Cluster API Cluster kind. Only the metadata of
+// the object is read, so the preferred version served by Cluster API is used.
+var clusterAPIClusterGK = schema.GroupKind{
+	Group: "cluster.x-k8s.io",
+	Kind:  "Cluster",
+}
+
+const (
+	// clusterAPIKubeConfigSuffix is the suffix of the name of the
+	// kubeconfig Secret generated by Cluster API for a Cluster.
+	clusterAPIKubeConfigSuffix = "-kubeconfig"
+
+	// clusterAPIKubeConfigKey is the key of the kubeconfig in the
+	// Secret generated by Cluster API.
+	clusterAPIKubeConfigKey = "value"
+)
+
+// clusterAPIKubeConfigSecretName returns the name of the kubeconfig
+// Secret generated by Cluster API for the given Cluster.
+func clusterAPIKubeConfigSecretName(cluster string) string {
+	return cluster + clusterAPIKubeConfigSuffix
+}
+
+// isRemote returns true if the Kustomization is reconciled on a remote
+// cluster, with a kubeconfig or a Cluster API Cluster reference.
+func isRemote(obj *kustomizev1.Kustomization) bool {
+	return obj.Spec.KubeConfig != nil || obj.Spec.KubeConfigClusterRef != nil
+}
+
+// getKubeConfigReference returns the reference of the kubeconfig used to
+// connect to the remote cluster. For a Cluster API Cluster reference, it
+// points at the kubeconfig Secret of the Cluster, which is read on every
+// reconciliation to pick up the rotated credentials. An error is returned
+// along with the reference if the Cluster can't be found.
+func (r *KustomizationReconciler) getKubeConfigReference(ctx context.Context,
+	obj *kustomizev1.Kustomization) (*meta.KubeConfigReference, error) {
+	clusterRef := obj.Spec.KubeConfigClusterRef
+	if clusterRef == nil {
+		return obj.Spec.KubeConfig, nil
+	}
+
+	ref := &meta.KubeConfigReference{
+		SecretRef: &meta.SecretKeyReference{
+			Name: clusterAPIKubeConfigSecretName(clusterRef.Name),
+			Key:  clusterAPIKubeConfigKey,
+		},
+	}
+
+	var reader client.Reader = r.Client
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+
+	clusterName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: clusterRef.Name}
+	mapping, err := r.Client.RESTMapper().RESTMapping(clusterAPIClusterGK)
+	if err != nil {
+		return ref, fmt.Errorf("unable to get Cluster API Cluster '%s': %w", clusterName, err)
+	}
+
+	cluster := &metav1.PartialObjectMetadata{}
+	cluster.SetGroupVersionKind(mapping.GroupVersionKind)
+	if err := reader.Get(ctx, clusterName, cluster); err != nil {
+		return ref, fmt.Errorf("unable to get Cluster API Cluster '%s': %w", clusterName, err)
+	}
+	return ref, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/testserver"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestKustomizationReconciler_KubeConfigClusterRef(t *testing.T) {
+	g := NewWithT(t)
+	id := "capi-" + randStringRunes(5)
+	revision := "v1.0.0"
+
+	err := createNamespace(id)
+	g.Expect(err).NotTo(HaveOccurred(), "failed to create test namespace")
+
+	artifact, err := testServer.ArtifactFromFiles([]testserver.File{
+		{
+			Name: "config.yaml",
+			Body: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  key: value
+`,
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	repositoryName := types.NamespacedName{
+		Name:      fmt.Sprintf("capi-%s", randStringRunes(5)),
+		Namespace: id,
+	}
+
+	err = applyGitRepository(repositoryName, artifact, revision)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newKustomization := func(kubeConfig *meta.KubeConfigReference, clusterRef *meta.LocalObjectReference) *kustomizev1.Kustomization {
+		return &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("capi-%s", randStringRunes(5)),
+				Namespace: id,
+			},
+			Spec: kustomizev1.KustomizationSpec{
+				Interval:             metav1.Duration{Duration: reconciliationInterval},
+				Path:                 "./",
+				KubeConfig:           kubeConfig,
+				KubeConfigClusterRef: clusterRef,
+				SourceRef: kustomizev1.CrossNamespaceSourceReference{
+					Name: repositoryName.Name,
+					Kind: sourcev1.GitRepositoryKind,
+				},
+			},
+		}
+	}
+
+	t.Run("rejects clusterRef with kubeConfig", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := newKustomization(&meta.KubeConfigReference{
+			SecretRef: &meta.SecretKeyReference{Name: "kubeconfig"},
+		}, &meta.LocalObjectReference{Name: "stage"})
+		err := k8sClient.Create(context.Background(), obj)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("spec.kubeConfig and spec.kubeConfigClusterRef are mutually exclusive"))
+	})
+
+	t.Run("fails when the Cluster is not found", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := newKustomization(nil, &meta.LocalObjectReference{Name: "stage"})
+		g.Expect(k8sClient.Create(context.Background(), obj)).To(Succeed())
+
+		resultK := &kustomizev1.Kustomization{}
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(obj), resultK)
+			return conditions.IsFalse(resultK, meta.ReadyCondition)
+		}, timeout, time.Second).Should(BeTrue())
+
+		g.Expect(conditions.GetReason(resultK, meta.ReadyCondition)).To(Equal(meta.ReconciliationFailedReason))
+		g.Expect(conditions.GetMessage(resultK, meta.ReadyCondition)).To(ContainSubstring("unable to get Cluster API Cluster"))
+
+		g.Expect(k8sClient.Delete(context.Background(), obj)).To(Succeed())
+		g.Eventually(func() bool {
+			err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(obj), resultK)
+			return err != nil
+		}, timeout, time.Second).Should(BeTrue())
+	})
+}
+
+func TestGetKubeConfigReference_PreferredVersion(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	mapper := apimeta.NewDefaultRESTMapper([]schema.GroupVersion{
+		{Group: "cluster.x-k8s.io", Version: "v1beta2"},
+		{Group: "cluster.x-k8s.io", Version: "v1beta1"},
+	})
+	mapper.Add(clusterAPIClusterGK.WithVersion("v1beta2"), apimeta.RESTScopeNamespace)
+	mapper.Add(clusterAPIClusterGK.WithVersion("v1beta1"), apimeta.RESTScopeNamespace)
+
+	var got schema.GroupVersionKind
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithRESTMapper(mapper).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				got = obj.GetObjectKind().GroupVersionKind()
+				return nil
+			},
+		}).Build(),
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "addons", Namespace: "capi"},
+		Spec: kustomizev1.KustomizationSpec{
+			KubeConfigClusterRef: &meta.LocalObjectReference{Name: "stage"},
+		},
+	}
+
+	ref, err := r.getKubeConfigReference(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ref.SecretRef.Name).To(Equal("stage-kubeconfig"))
+	g.Expect(got).To(Equal(clusterAPIClusterGK.WithVersion("v1beta2")))
+
+	// Cluster API is not installed.
+	r.Client = fake.NewClientBuilder().Build()
+	_, err = r.getKubeConfigReference(ctx, obj)
+	g.Expect(apimeta.IsNoMatchError(err)).To(BeTrue())
+}
+
+func TestDeleteInventoryObjects_ClusterNotFound(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	mapper.Add(clusterAPIClusterGK.WithVersion("v1beta1"), apimeta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), apimeta.RESTScopeRoot)
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "capi"}}
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{
+		Client:        fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(ns).Build(),
+		EventRecorder: recorder,
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "addons", Namespace: "capi"},
+		Spec: kustomizev1.KustomizationSpec{
+			KubeConfigClusterRef: &meta.LocalObjectReference{Name: "stage"},
+		},
+	}
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetName("test")
+	cm.SetNamespace("default")
+
+	// The garbage collection is skipped when the Cluster is gone.
+	g.Expect(r.deleteInventoryObjects(ctx, obj, []*unstructured.Unstructured{cm})).To(Succeed())
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("ConfigMap/default/test")))
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/mergepatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// isClientSideApply returns true if the object is annotated
+// to be applied with a client-side three-way merge.
+func isClientSideApply(u *unstructured.Unstructured) bool {
+	return u.GetAnnotations()[kustomizev1.ApplyStrategyAnnotation] == kustomizev1.ApplyStrategyClientSide
+}
+
+// splitClientSideApply splits the objects into the ones
+// applied server-side and the ones applied client-side.
+func splitClientSideApply(objects []*unstructured.Unstructured) (serverSide, clientSide []*unstructured.Unstructured) {
+	for _, u := range objects {
+		if isClientSideApply(u) {
+			clientSide = append(clientSide, u)
+		} else {
+			serverSide = append(serverSide, u)
+		}
+	}
+	return serverSide, clientSide
+}
+
+// applyClientSide applies the objects with a client-side three-way JSON
+// merge patch between the last applied configuration, the manifest and the
+// in-cluster object, like 'kubectl apply' does. It is meant for the objects
+// rejected by server-side apply, e.g. the ones served by aggregated APIs
+// without an OpenAPI schema. The exclusion and if-not-present selectors are
+// honoured, while the drift ignore rules and the force policy are not.
+func (r *KustomizationReconciler) applyClientSide(ctx context.Context,
+	kubeClient client.Client,
+	objects []*unstructured.Unstructured,
+	opts ssa.ApplyOptions) (*ssa.ChangeSet, error) {
+	changeSet := ssa.NewChangeSet()
+	for _, u := range objects {
+		action, err := r.applyClientSideObject(ctx, kubeClient, u, opts)
+		if err != nil {
+			return changeSet, err
+		}
+		changeSet.Add(ssa.ChangeSetEntry{
+			ObjMetadata:  object.UnstructuredToObjMetadata(u),
+			GroupVersion: u.GroupVersionKind().Version,
+			Subject:      ssautil.FmtUnstructured(u),
+			Action:       action,
+		})
+	}
+	return changeSet, nil
+}
+
+// applyClientSideObject creates or patches the object and returns the action taken.
+func (r *KustomizationReconciler) applyClientSideObject(ctx context.Context,
+	kubeClient client.Client,
+	u *unstructured.Unstructured,
+	opts ssa.ApplyOptions) (ssa.Action, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(u.GroupVersionKind())
+	err := kubeClient.Get(ctx, client.ObjectKeyFromObject(u), existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("%s query failed: %w", ssautil.FmtUnstructured(u), err)
+	}
+	found := err == nil
+
+	if ssautil.AnyInMetadata(u, opts.ExclusionSelector) ||
+		(found && ssautil.AnyInMetadata(existing, opts.ExclusionSelector)) ||
+		(found && ssautil.AnyInMetadata(u, opts.IfNotPresentSelector)) {
+		return ssa.SkippedAction, nil
+	}
+
+	desired, err := withLastAppliedConfig(u)
+	if err != nil {
+		return "", fmt.Errorf("%s failed to encode the last applied configuration: %w", ssautil.FmtUnstructured(u), err)
+	}
+
+	if !found {
+		if err := kubeClient.Create(ctx, desired, client.FieldOwner(r.ControllerName)); err != nil {
+			return "", fmt.Errorf("%s client-side apply failed: %w", ssautil.FmtUnstructured(u), err)
+		}
+		return ssa.CreatedAction, nil
+	}
+
+	original := []byte(existing.GetAnnotations()[corev1.LastAppliedConfigAnnotation])
+	modified, err := desired.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	current, err := existing.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current,
+		mergepatch.RequireKeyUnchanged("kind"),
+		mergepatch.RequireMetadataKeyUnchanged("name"),
+		mergepatch.RequireMetadataKeyUnchanged("namespace"))
+	if err != nil {
+		return "", fmt.Errorf("%s failed to compute the client-side apply patch: %w", ssautil.FmtUnstructured(u), err)
+	}
+	if string(patch) == "{}" {
+		return ssa.UnchangedAction, nil
+	}
+
+	if err := kubeClient.Patch(ctx, existing, client.RawPatch(types.MergePatchType, patch),
+		client.FieldOwner(r.ControllerName)); err != nil {
+		return "", fmt.Errorf("%s client-side apply failed: %w", ssautil.FmtUnstructured(u), err)
+	}
+	return ssa.ConfiguredAction, nil
+}
+
+// withLastAppliedConfig returns a copy of the object with the last applied
+// configuration annotation set to the object encoded without the annotation.
+func withLastAppliedConfig(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	desired := u.DeepCopy()
+	annotations := desired.GetAnnotations()
+	delete(annotations, corev1.LastAppliedConfigAnnotation)
+	desired.SetAnnotations(annotations)
+
+	lastApplied, err := desired.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[corev1.LastAppliedConfigAnnotation] = string(lastApplied)
+	desired.SetAnnotations(annotations)
+	return desired, nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=list
+
+const (
+	// clusterVersionVar is the post-build variable holding the
+	// '<major>.<minor>' version of the Kubernetes API server.
+	clusterVersionVar = "FLUX_CLUSTER_VERSION"
+
+	// clusterNodesVar is the post-build variable holding
+	// the range of the number of nodes, e.g. '4-10'.
+	clusterNodesVar = "FLUX_CLUSTER_NODES"
+
+	// clusterProviderVar is the post-build variable holding the platform
+	// provider of the nodes, e.g. 'aws', or 'unknown' if not detected.
+	clusterProviderVar = "FLUX_CLUSTER_PROVIDER"
+
+	// clusterFactsTTL is the duration for which the cluster facts
+	// are reused before being read again.
+	clusterFactsTTL = 10 * time.Minute
+
+	// clusterFactsPageSize is the number of nodes listed per request.
+	clusterFactsPageSize = 500
+)
+
+// nodeCountRanges are the ranges of the number of nodes, with their max
+// number of nodes, so that the variables don't change with every scaling of
+// the cluster.
+var nodeCountRanges = []struct {
+	max  int
+	name string
+}{
+	{0, "0"},
+	{1, "1"},
+	{3, "2-3"},
+	{10, "4-10"},
+	{50, "11-50"},
+	{200, "51-200"},
+}
+
+// ClusterFacts reads the facts of the cluster the controller runs in,
+// injected as variables into the post-build substitutions of the
+// Kustomizations which don't target a remote cluster.
+type ClusterFacts struct {
+	reader    client.Reader
+	discovery discovery.ServerVersionInterface
+
+	mu     sync.Mutex
+	vars   map[string]string
+	readAt time.Time
+}
+
+// NewClusterFacts returns a ClusterFacts listing the nodes with the given
+// reader, and reading the version of the API server with the given client.
+func NewClusterFacts(reader client.Reader, discovery discovery.ServerVersionInterface) *ClusterFacts {
+	return &ClusterFacts{
+		reader:    reader,
+		discovery: discovery,
+	}
+}
+
+// variables returns the variables of the cluster facts, read at most once
+// per clusterFactsTTL. The last variables read are returned if the facts
+// can't be read again.
+func (f *ClusterFacts) variables(ctx context.Context) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.vars != nil && time.Since(f.readAt) < clusterFactsTTL {
+		return maps.Clone(f.vars), nil
+	}
+	vars, err := f.read(ctx)
+	if err != nil {
+		if f.vars == nil {
+			return nil, err
+		}
+		ctrl.LoggerFrom(ctx).Error(err, "failed to read the cluster facts, using the last facts read",
+			"readAt", f.readAt.UTC().Format(time.RFC3339))
+		return maps.Clone(f.vars), nil
+	}
+	f.vars, f.readAt = vars, time.Now()
+	return maps.Clone(vars), nil
+}
+
+// read returns the variables of the version of the API server,
+// and of the number and provider of the nodes.
+func (f *ClusterFacts) read(ctx context.Context) (map[string]string, error) {
+	info, err := f.discovery.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the server version: %w", err)
+	}
+
+	count := 0
+	providers := make(map[string]int)
+	opts := []client.ListOption{client.Limit(clusterFactsPageSize)}
+	for {
+		var nodes corev1.NodeList
+		if err := f.reader.List(ctx, &nodes, opts...); err != nil {
+			return nil, fmt.Errorf("failed to list the nodes: %w", err)
+		}
+		for _, node := range nodes.Items {
+			count++
+			if scheme, _, ok := strings.Cut(node.Spec.ProviderID, "://"); ok && scheme != "" {
+				providers[strings.ToLower(scheme)]++
+			}
+		}
+		if nodes.Continue == "" {
+			break
+		}
+		opts = []client.ListOption{client.Limit(clusterFactsPageSize), client.Continue(nodes.Continue)}
+	}
+
+	return map[string]string{
+		// The minor version of some distributions has a '+' suffix, e.g. '31+'.
+		clusterVersionVar:  fmt.Sprintf("%s.%s", info.Major, strings.TrimSuffix(info.Minor, "+")),
+		clusterNodesVar:    nodeCountRange(count),
+		clusterProviderVar: mainProvider(providers),
+	}, nil
+}
+
+// nodeCountRange returns the range of the given number of nodes.
+func nodeCountRange(count int) string {
+	for _, r := range nodeCountRanges {
+		if count <= r.max {
+			return r.name
+		}
+	}
+	return fmt.Sprintf("%d+", nodeCountRanges[len(nodeCountRanges)-1].max+1)
+}
+
+// mainProvider returns the provider of most nodes, the first in
+// alphabetical order on a tie, or 'unknown' if there's none.
+func mainProvider(providers map[string]int) string {
+	if len(providers) == 0 {
+		return "unknown"
+	}
+	names := slices.Sorted(maps.Keys(providers))
+	return slices.MaxFunc(names, func(a, b string) int {
+		return cmp.Compare(providers[a], providers[b])
+	})
+}
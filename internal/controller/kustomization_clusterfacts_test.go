@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func newTestClusterFacts(minor string, providerIDs ...string) *ClusterFacts {
+	objects := make([]client.Object, 0, len(providerIDs))
+	for i, id := range providerIDs {
+		objects = append(objects, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)},
+			Spec:       corev1.NodeSpec{ProviderID: id},
+		})
+	}
+	disco := &fakediscovery.FakeDiscovery{
+		Fake:               &clienttesting.Fake{},
+		FakedServerVersion: &version.Info{Major: "1", Minor: minor},
+	}
+	return NewClusterFacts(fake.NewClientBuilder().WithObjects(objects...).Build(), disco)
+}
+
+func TestClusterFacts_variables(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	facts := newTestClusterFacts("31+",
+		"aws:///eu-west-1a/i-0a", "aws:///eu-west-1b/i-0b", "kind://docker/kind/kind-worker", "")
+	vars, err := facts.variables(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vars).To(Equal(map[string]string{
+		clusterVersionVar:  "1.31",
+		clusterNodesVar:    "4-10",
+		clusterProviderVar: "aws",
+	}))
+
+	// The facts are reused within the TTL.
+	facts.discovery.(*fakediscovery.FakeDiscovery).FakedServerVersion.Minor = "32"
+	vars, err = facts.variables(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vars[clusterVersionVar]).To(Equal("1.31"))
+
+	// The facts are read again after the TTL.
+	facts.readAt = time.Now().Add(-clusterFactsTTL)
+	vars, err = facts.variables(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vars[clusterVersionVar]).To(Equal("1.32"))
+
+	// The last facts are used if they can't be read again.
+	facts.readAt = time.Now().Add(-clusterFactsTTL)
+	facts.discovery.(*fakediscovery.FakeDiscovery).PrependReactor("*", "*",
+		func(clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, fmt.Errorf("unavailable")
+		})
+	facts.discovery.(*fakediscovery.FakeDiscovery).FakedServerVersion = nil
+	vars, err = facts.variables(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vars[clusterVersionVar]).To(Equal("1.32"))
+}
+
+func TestNodeCountRange(t *testing.T) {
+	tests := map[int]string{
+		0:   "0",
+		1:   "1",
+		2:   "2-3",
+		10:  "4-10",
+		11:  "11-50",
+		200: "51-200",
+		201: "201+",
+	}
+	for count, want := range tests {
+		t.Run(want, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(nodeCountRange(count)).To(Equal(want))
+		})
+	}
+}
+
+func TestMainProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(mainProvider(nil)).To(Equal("unknown"))
+	g.Expect(mainProvider(map[string]int{"gce": 1, "aws": 1})).To(Equal("aws"))
+	g.Expect(mainProvider(map[string]int{"gce": 2, "aws": 1})).To(Equal("gce"))
+}
+
+func TestWithDefaultSubstitutions_clusterFacts(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	r := &KustomizationReconciler{
+		Client:       fake.NewClientBuilder().Build(),
+		ClusterFacts: newTestClusterFacts("30", "gce://project/zone/node"),
+	}
+	obj := &kustomizev1.Kustomization{
+		Spec: kustomizev1.KustomizationSpec{
+			PostBuild: &kustomizev1.PostBuild{
+				Substitute: map[string]string{clusterProviderVar: "override"},
+			},
+		},
+	}
+	k, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	u, err := r.withDefaultSubstitutions(ctx, unstructured.Unstructured{Object: k})
+	g.Expect(err).ToNot(HaveOccurred())
+	vars, _, err := unstructured.NestedStringMap(u.Object, "spec", "postBuild", "substitute")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vars).To(Equal(map[string]string{
+		clusterVersionVar:  "1.30",
+		clusterNodesVar:    "1",
+		clusterProviderVar: "override",
+	}))
+
+	// The facts are not injected for the remote clusters.
+	obj.Spec.KubeConfigClusterRef = &meta.LocalObjectReference{Name: "remote"}
+	k, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	u, err = r.withDefaultSubstitutions(ctx, unstructured.Unstructured{Object: k})
+	g.Expect(err).ToNot(HaveOccurred())
+	vars, _, err = unstructured.NestedStringMap(u.Object, "spec", "postBuild", "substitute")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vars).To(Equal(map[string]string{clusterProviderVar: "override"}))
+}
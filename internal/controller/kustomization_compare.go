@@ -0,0 +1,295 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// compareConfigMapName returns the name of the ConfigMap holding
+// the result of the last comparison of the Kustomization.
+func compareConfigMapName(obj *kustomizev1.Kustomization) string {
+	return fmt.Sprintf("%s-compare", obj.GetName())
+}
+
+// compareResult holds the differences between the objects of two builds.
+type compareResult struct {
+	Added     []string
+	Changed   []string
+	Removed   []string
+	Unchanged int
+}
+
+// String returns the number of objects per difference.
+func (c compareResult) String() string {
+	return fmt.Sprintf("%d added, %d changed, %d removed, %d unchanged",
+		len(c.Added), len(c.Changed), len(c.Removed), c.Unchanged)
+}
+
+// Diff returns the sorted differences, one '<+|~|-> <subject>' line per object.
+func (c compareResult) Diff() string {
+	var lines []string
+	for _, s := range c.Added {
+		lines = append(lines, "+ "+s)
+	}
+	for _, s := range c.Changed {
+		lines = append(lines, "~ "+s)
+	}
+	for _, s := range c.Removed {
+		lines = append(lines, "- "+s)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// compareObjects returns the objects added, changed and removed
+// by the target objects compared with the current ones.
+func compareObjects(current, target []*unstructured.Unstructured) compareResult {
+	currentByID := make(map[string]*unstructured.Unstructured, len(current))
+	for _, u := range current {
+		currentByID[object.UnstructuredToObjMetadata(u).String()] = u
+	}
+
+	var result compareResult
+	seen := make(map[string]bool, len(target))
+	for _, u := range target {
+		id := object.UnstructuredToObjMetadata(u).String()
+		seen[id] = true
+		c, ok := currentByID[id]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, ssautil.FmtUnstructured(u))
+		case !apiequality.Semantic.DeepEqual(c.Object, u.Object):
+			result.Changed = append(result.Changed, ssautil.FmtUnstructured(u))
+		default:
+			result.Unchanged++
+		}
+	}
+	for _, u := range current {
+		if !seen[object.UnstructuredToObjMetadata(u).String()] {
+			result.Removed = append(result.Removed, ssautil.FmtUnstructured(u))
+		}
+	}
+	slices.Sort(result.Added)
+	slices.Sort(result.Changed)
+	slices.Sort(result.Removed)
+	return result
+}
+
+// parseCompareSource returns the source reference of the
+// CompareSourceAnnotation value.
+func parseCompareSource(value string) (kustomizev1.CrossNamespaceSourceReference, error) {
+	var ref kustomizev1.CrossNamespaceSourceReference
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	switch len(parts) {
+	case 2:
+		ref.Kind, ref.Name = parts[0], parts[1]
+	case 3:
+		ref.Kind, ref.Namespace, ref.Name = parts[0], parts[1], parts[2]
+	default:
+		return ref, fmt.Errorf("invalid compare source '%s', must be in the '<kind>/<name>' or '<kind>/<namespace>/<name>' format", value)
+	}
+	if slices.Contains(parts, "") {
+		return ref, fmt.Errorf("invalid compare source '%s', must be in the '<kind>/<name>' or '<kind>/<namespace>/<name>' format", value)
+	}
+	return ref, nil
+}
+
+// reconcileCompare builds the manifests of the revision of the source of the
+// CompareSourceAnnotation with the spec of the Kustomization, and stores the
+// differences with the objects of the latest revision in the compare
+// ConfigMap, e.g. to preview the changes of the promotion of a revision.
+// Nothing is applied or deleted.
+func (r *KustomizationReconciler) reconcileCompare(ctx context.Context,
+	manager *ssa.ResourceManager,
+	mapper apimeta.RESTMapper,
+	obj *kustomizev1.Kustomization,
+	revision string,
+	originRevision string,
+	objects []*unstructured.Unstructured) error {
+	ref, err := parseCompareSource(obj.GetAnnotations()[kustomizev1.CompareSourceAnnotation])
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	targetRevision, targetObjects, err := r.buildCompareSource(ctx, mapper, obj, ref)
+	if err != nil {
+		err = fmt.Errorf("failed to build the compare source %s: %w", ref.String(), err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+		return err
+	}
+	manager.SetOwnerLabels(targetObjects, obj.GetName(), obj.GetNamespace())
+	result := compareObjects(objects, targetObjects)
+
+	if err := r.storeComparison(ctx, obj, map[string]string{
+		"source":         ref.String(),
+		"revision":       revision,
+		"targetRevision": targetRevision,
+		"summary":        result.String(),
+		"diff":           result.Diff(),
+	}); err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	msg := fmt.Sprintf("Compared revision %s with revision %s of %s: %s",
+		revision, targetRevision, ref.String(), result.String())
+	ctrl.LoggerFrom(ctx).Info(msg, "configMap", compareConfigMapName(obj))
+	if diff := result.Diff(); diff != "" {
+		msg = fmt.Sprintf("%s\n%s", msg, diff)
+	}
+	r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+	conditions.MarkTrue(obj,
+		meta.ReadyCondition,
+		meta.ReconciliationSucceededReason,
+		"Compared revision: %s", revision)
+	return nil
+}
+
+// storeComparison stores the given data in the compare ConfigMap,
+// unless the ConfigMap exists and isn't owned by the Kustomization.
+func (r *KustomizationReconciler) storeComparison(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	data map[string]string) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      compareConfigMapName(obj),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		if cm.GetResourceVersion() != "" && !isOwnedBy(obj, cm) {
+			return fmt.Errorf("configmap '%s' is not owned by the Kustomization", client.ObjectKeyFromObject(cm))
+		}
+		cm.Labels = map[string]string{
+			fmt.Sprintf("%s/name", kustomizev1.GroupVersion.Group):      obj.GetName(),
+			fmt.Sprintf("%s/namespace", kustomizev1.GroupVersion.Group): obj.GetNamespace(),
+		}
+		cm.Data = data
+		return controllerutil.SetOwnerReference(obj, cm, r.Client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to store the comparison in ConfigMap '%s': %w", cm.GetName(), err)
+	}
+	return nil
+}
+
+// buildCompareSource builds the manifests of the artifact of the given
+// source with the spec of the Kustomization, and returns the revision
+// of the artifact with the built objects.
+func (r *KustomizationReconciler) buildCompareSource(ctx context.Context,
+	mapper apimeta.RESTMapper,
+	obj *kustomizev1.Kustomization,
+	ref kustomizev1.CrossNamespaceSourceReference) (string, []*unstructured.Unstructured, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	srcObj := obj.DeepCopy()
+	srcObj.Spec.SourceRef = ref
+	src, err := r.getSource(ctx, srcObj)
+	if err != nil {
+		return "", nil, err
+	}
+	if src.GetArtifact() == nil {
+		return "", nil, fmt.Errorf("source is not ready, artifact not found")
+	}
+
+	tmpDir, err := MkdirTempAbs("", "kustomization-compare-")
+	if err != nil {
+		return "", nil, fmt.Errorf("tmp dir error: %w", err)
+	}
+	defer func(path string) {
+		if err := os.RemoveAll(path); err != nil {
+			log.Error(err, "failed to remove tmp dir", "path", path)
+		}
+	}(tmpDir)
+
+	if err := r.fetchArtifact(ctx, src, tmpDir); err != nil {
+		return "", nil, err
+	}
+	dirPath, err := securejoin.SecureJoin(tmpDir, obj.Spec.Path)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(dirPath); err != nil {
+		return "", nil, fmt.Errorf("kustomization path not found: %w", err)
+	}
+
+	// Build the manifests like the latest revision.
+	images, err := r.resolveImageDigests(ctx, obj)
+	if err != nil {
+		return "", nil, err
+	}
+	buildObj := obj.DeepCopy()
+	buildObj.Spec.Images = images
+	k, err := runtime.DefaultUnstructuredConverter.ToUnstructured(buildObj)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := r.generate(unstructured.Unstructured{Object: k}, tmpDir, dirPath); err != nil {
+		return "", nil, err
+	}
+	if err := r.injectTransformerConfigs(ctx, obj, dirPath); err != nil {
+		return "", nil, err
+	}
+	if err := r.injectIncludes(ctx, obj, dirPath); err != nil {
+		return "", nil, err
+	}
+	resources, _, err := r.build(ctx, obj, unstructured.Unstructured{Object: k}, tmpDir, dirPath)
+	if err != nil {
+		return "", nil, err
+	}
+	resources, _, err = migrateDeprecatedAPIVersions(obj, mapper, resources)
+	if err != nil {
+		return "", nil, err
+	}
+	if resources, err = r.generateImagePullSecret(ctx, obj, resources); err != nil {
+		return "", nil, err
+	}
+	if resources, err = r.generateGuardrails(ctx, obj, resources); err != nil {
+		return "", nil, err
+	}
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader(resources))
+	if err != nil {
+		return "", nil, err
+	}
+	return src.GetArtifact().Revision, filterCRDs(obj, objects), nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestCompareObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	newConfigMap := func(name, value string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": name, "namespace": "apps"},
+			"data":       map[string]any{"key": value},
+		}}
+	}
+
+	current := []*unstructured.Unstructured{
+		newConfigMap("unchanged", "a"),
+		newConfigMap("changed", "a"),
+		newConfigMap("removed", "a"),
+	}
+	target := []*unstructured.Unstructured{
+		newConfigMap("unchanged", "a"),
+		newConfigMap("changed", "b"),
+		newConfigMap("added-b", "a"),
+		newConfigMap("added-a", "a"),
+	}
+
+	result := compareObjects(current, target)
+	g.Expect(result.Added).To(Equal([]string{"ConfigMap/apps/added-a", "ConfigMap/apps/added-b"}))
+	g.Expect(result.Changed).To(Equal([]string{"ConfigMap/apps/changed"}))
+	g.Expect(result.Removed).To(Equal([]string{"ConfigMap/apps/removed"}))
+	g.Expect(result.Unchanged).To(Equal(1))
+	g.Expect(result.String()).To(Equal("2 added, 1 changed, 1 removed, 1 unchanged"))
+	g.Expect(result.Diff()).To(Equal(`+ ConfigMap/apps/added-a
++ ConfigMap/apps/added-b
+~ ConfigMap/apps/changed
+- ConfigMap/apps/removed`))
+
+	result = compareObjects(current, current)
+	g.Expect(result.Diff()).To(BeEmpty())
+	g.Expect(result.Unchanged).To(Equal(3))
+}
+
+func TestParseCompareSource(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    kustomizev1.CrossNamespaceSourceReference
+		wantErr bool
+	}{
+		{
+			value: "GitRepository/release-candidate",
+			want:  kustomizev1.CrossNamespaceSourceReference{Kind: "GitRepository", Name: "release-candidate"},
+		},
+		{
+			value: "OCIRepository/flux-system/release-candidate",
+			want:  kustomizev1.CrossNamespaceSourceReference{Kind: "OCIRepository", Namespace: "flux-system", Name: "release-candidate"},
+		},
+		{value: "", wantErr: true},
+		{value: "GitRepository", wantErr: true},
+		{value: "GitRepository/", wantErr: true},
+		{value: "GitRepository/a/b/c", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseCompareSource(tt.value)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestStoreComparison(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "uid"},
+	}
+	other := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "apps", UID: "other-uid"},
+	}
+	foreign := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-compare", Namespace: "apps"},
+		Data:       map[string]string{"key": "value"},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(obj, other, foreign).Build(),
+	}
+
+	// The ConfigMap is created and updated for the Kustomization.
+	g.Expect(r.storeComparison(ctx, obj, map[string]string{"summary": "1 added"})).To(Succeed())
+	g.Expect(r.storeComparison(ctx, obj, map[string]string{"summary": "2 added"})).To(Succeed())
+	cm := &corev1.ConfigMap{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Namespace: "apps", Name: "app-compare"}, cm)).To(Succeed())
+	g.Expect(cm.Data).To(HaveKeyWithValue("summary", "2 added"))
+	g.Expect(isOwnedBy(obj, cm)).To(BeTrue())
+
+	// A ConfigMap not owned by the Kustomization is left unchanged.
+	err := r.storeComparison(ctx, other, map[string]string{"summary": "1 added"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not owned by the Kustomization"))
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(foreign), cm)).To(Succeed())
+	g.Expect(cm.Data).To(Equal(foreign.Data))
+}
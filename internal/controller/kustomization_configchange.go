@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// rendersFrom returns true if the ConfigMap or Secret of the given kind and
+// name is used by the Kustomization only to render its manifests, i.e. it is
+// referenced by the post-build substitutions, the build options, the image
+// registry credentials or the image pull secret template, but not by the
+// kubeconfig of the remote cluster.
+func rendersFrom(obj *kustomizev1.Kustomization, kind, name string) bool {
+	if kc := obj.Spec.KubeConfig; kc != nil {
+		switch {
+		case kind == "ConfigMap" && kc.ConfigMapRef != nil && kc.ConfigMapRef.Name == name,
+			kind == "Secret" && kc.SecretRef != nil && kc.SecretRef.Name == name:
+			return false
+		}
+	}
+	if ref := obj.Spec.KubeConfigClusterRef; ref != nil && kind == "Secret" && clusterAPIKubeConfigSecretName(ref.Name) == name {
+		return false
+	}
+	if kp := obj.Spec.KubeConfigProxy; kp != nil && kind == "Secret" && kp.CASecretRef != nil && kp.CASecretRef.Name == name {
+		return false
+	}
+
+	if pb := obj.Spec.PostBuild; pb != nil {
+		for _, ref := range pb.SubstituteFrom {
+			if ref.Kind == kind && ref.Name == name {
+				return true
+			}
+		}
+	}
+	if ps := obj.Spec.ImagePullSecret; ps != nil && kind == "Secret" && ps.TemplateRef.Name == name {
+		return true
+	}
+	if dr := obj.Spec.ImageDigestResolution; dr != nil && kind == "Secret" && dr.SecretRef != nil && dr.SecretRef.Name == name {
+		return true
+	}
+	if bo := obj.Spec.BuildOptions; bo != nil && kind == "ConfigMap" {
+		for _, ref := range bo.TransformerConfigsFrom {
+			if ref.Name == name {
+				return true
+			}
+		}
+		if bo.OpenAPISchemaFrom != nil && bo.OpenAPISchemaFrom.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// recordConfigChange records that the Kustomization is enqueued for the
+// change of a ConfigMap or Secret used only to render its manifests.
+func (r *KustomizationReconciler) recordConfigChange(obj *kustomizev1.Kustomization) {
+	r.configChanges.Store(client.ObjectKeyFromObject(obj).String(), struct{}{})
+}
+
+// takeConfigChange returns true if the Kustomization was enqueued for the
+// change of a ConfigMap or Secret used only to render its manifests, and
+// clears the record.
+func (r *KustomizationReconciler) takeConfigChange(obj *kustomizev1.Kustomization) bool {
+	_, ok := r.configChanges.LoadAndDelete(client.ObjectKeyFromObject(obj).String())
+	return ok
+}
+
+// isUnchangedRender returns true if the reconciliation of a render-only
+// config change produced the manifests of the last successful
+// reconciliation of the same revision and generation, in which case
+// the apply and the health checks can be skipped.
+func isUnchangedRender(obj *kustomizev1.Kustomization, revision, checksum string,
+	req reconcileRequest) bool {
+	if !req.renderOnly || req.manual || req.force || req.mode != "" {
+		return false
+	}
+	if obj.Status.ObservedGeneration != obj.Generation ||
+		obj.Status.LastAppliedRevision != revision {
+		return false
+	}
+	latest := obj.Status.History.Latest()
+	return latest != nil &&
+		latest.Digest == checksum &&
+		latest.LastReconciledStatus == meta.ReconciliationSucceededReason
+}
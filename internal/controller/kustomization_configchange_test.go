@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestRendersFrom(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &kustomizev1.Kustomization{
+		Spec: kustomizev1.KustomizationSpec{
+			PostBuild: &kustomizev1.PostBuild{
+				SubstituteFrom: []kustomizev1.SubstituteReference{
+					{Kind: "ConfigMap", Name: "vars"},
+					{Kind: "Secret", Name: "secret-vars"},
+					{Kind: "ConfigMap", Name: "cluster"},
+				},
+			},
+			BuildOptions: &kustomizev1.BuildOptions{
+				TransformerConfigsFrom: []meta.LocalObjectReference{{Name: "configs"}},
+				OpenAPISchemaFrom:      &kustomizev1.OpenAPISchemaReference{Name: "schemas"},
+			},
+			KubeConfig: &meta.KubeConfigReference{
+				ConfigMapRef: &meta.LocalObjectReference{Name: "cluster"},
+			},
+		},
+	}
+
+	g.Expect(rendersFrom(obj, "ConfigMap", "vars")).To(BeTrue())
+	g.Expect(rendersFrom(obj, "Secret", "secret-vars")).To(BeTrue())
+	g.Expect(rendersFrom(obj, "ConfigMap", "configs")).To(BeTrue())
+	g.Expect(rendersFrom(obj, "ConfigMap", "schemas")).To(BeTrue())
+	g.Expect(rendersFrom(obj, "Secret", "vars")).To(BeFalse())
+	g.Expect(rendersFrom(obj, "Secret", "configs")).To(BeFalse())
+	// The kubeconfig may target another cluster.
+	g.Expect(rendersFrom(obj, "ConfigMap", "cluster")).To(BeFalse())
+}
+
+func TestConfigChange_Record(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &KustomizationReconciler{}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+	}
+
+	g.Expect(r.takeConfigChange(obj)).To(BeFalse())
+	r.recordConfigChange(obj)
+	g.Expect(r.takeConfigChange(obj)).To(BeTrue())
+	g.Expect(r.takeConfigChange(obj)).To(BeFalse())
+}
+
+func TestIsUnchangedRender(t *testing.T) {
+	revision := "main@sha1:a1b2c3d4"
+	checksum := "sha256:0123"
+
+	newObj := func() *kustomizev1.Kustomization {
+		obj := &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Status: kustomizev1.KustomizationStatus{
+				ObservedGeneration:  2,
+				LastAppliedRevision: revision,
+			},
+		}
+		obj.Status.History.Upsert(checksum, time.Now(), time.Second,
+			meta.ReconciliationSucceededReason, nil)
+		return obj
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*kustomizev1.Kustomization, *reconcileRequest)
+		want   bool
+	}{
+		{
+			name:   "unchanged render",
+			mutate: func(*kustomizev1.Kustomization, *reconcileRequest) {},
+			want:   true,
+		},
+		{
+			name:   "not a config change",
+			mutate: func(_ *kustomizev1.Kustomization, req *reconcileRequest) { req.renderOnly = false },
+		},
+		{
+			name:   "manual request",
+			mutate: func(_ *kustomizev1.Kustomization, req *reconcileRequest) { req.manual = true },
+		},
+		{
+			name:   "new generation",
+			mutate: func(obj *kustomizev1.Kustomization, _ *reconcileRequest) { obj.Generation = 3 },
+		},
+		{
+			name: "new revision",
+			mutate: func(obj *kustomizev1.Kustomization, _ *reconcileRequest) {
+				obj.Status.LastAppliedRevision = "main@sha1:e5f6a7b8"
+			},
+		},
+		{
+			name: "changed manifests",
+			mutate: func(obj *kustomizev1.Kustomization, _ *reconcileRequest) {
+				obj.Status.History.Upsert("sha256:4567", time.Now().Add(time.Minute), time.Second,
+					meta.ReconciliationSucceededReason, nil)
+			},
+		},
+		{
+			name: "failed reconciliation",
+			mutate: func(obj *kustomizev1.Kustomization, _ *reconcileRequest) {
+				obj.Status.History.Upsert(checksum, time.Now().Add(time.Minute), time.Second,
+					meta.HealthCheckFailedReason, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			obj := newObj()
+			req := reconcileRequest{renderOnly: true}
+			tt.mutate(obj, &req)
+			g.Expect(isUnchangedRender(obj, revision, checksum, req)).To(Equal(tt.want))
+		})
+	}
+}
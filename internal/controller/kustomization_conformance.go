@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// tenantConformanceError lists the cluster-scoped objects of a build
+// rejected by the tenant conformance of the Kustomization namespace.
+type tenantConformanceError struct {
+	namespace string
+	objects   []string
+}
+
+func (e *tenantConformanceError) Error() string {
+	return fmt.Sprintf("cluster-scoped objects are not allowed in the tenant namespace '%s':\n%s",
+		e.namespace, strings.Join(e.objects, "\n"))
+}
+
+// checkTenantConformance returns a tenantConformanceError if the namespace
+// of the Kustomization is annotated with the TenantConformanceAnnotation and
+// the objects include cluster-scoped ones. The scope of the kinds unknown to
+// the API server, e.g. of the custom resources defined in the same build, is
+// inferred from the namespace of the objects.
+func (r *KustomizationReconciler) checkTenantConformance(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	mapper apimeta.RESTMapper,
+	objects []*unstructured.Unstructured) error {
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, ns); err != nil {
+		return fmt.Errorf("failed to get namespace '%s': %w", obj.GetNamespace(), err)
+	}
+	if ns.GetAnnotations()[kustomizev1.TenantConformanceAnnotation] != kustomizev1.EnabledValue {
+		return nil
+	}
+
+	var rejected []string
+	for _, u := range objects {
+		gvk := u.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		switch {
+		case err == nil:
+			if mapping.Scope.Name() != apimeta.RESTScopeNameRoot {
+				continue
+			}
+		case apimeta.IsNoMatchError(err):
+			if u.GetNamespace() != "" {
+				continue
+			}
+		default:
+			return fmt.Errorf("failed to get the scope of %s: %w", ssautil.FmtUnstructured(u), err)
+		}
+		rejected = append(rejected, ssautil.FmtUnstructured(u))
+	}
+	if len(rejected) > 0 {
+		return &tenantConformanceError{namespace: obj.GetNamespace(), objects: rejected}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestCheckTenantConformance(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: tenant
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: tenant-admin
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: namespaced
+  namespace: tenant
+---
+apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: cluster
+`)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, apimeta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, apimeta.RESTScopeRoot)
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "tenant"},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant"}}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(namespace).Build(),
+	}
+
+	// Without the annotation, the cluster-scoped objects are allowed.
+	g.Expect(r.checkTenantConformance(ctx, obj, mapper, objects)).To(Succeed())
+
+	namespace.SetAnnotations(map[string]string{
+		kustomizev1.TenantConformanceAnnotation: kustomizev1.EnabledValue,
+	})
+	g.Expect(r.Update(ctx, namespace)).To(Succeed())
+
+	err = r.checkTenantConformance(ctx, obj, mapper, objects)
+	var tce *tenantConformanceError
+	g.Expect(errors.As(err, &tce)).To(BeTrue())
+	g.Expect(tce.objects).To(ConsistOf(
+		"ClusterRole/tenant-admin",
+		"Gadget/cluster",
+	))
+	g.Expect(err.Error()).To(ContainSubstring("tenant namespace 'tenant'"))
+
+	// Without cluster-scoped objects, the build conforms.
+	g.Expect(r.checkTenantConformance(ctx, obj, mapper, objects[:1])).To(Succeed())
+}
@@ -21,8 +21,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
@@ -71,7 +73,10 @@ import (
 
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+	"github.com/fluxcd/kustomize-controller/internal/health"
+	"github.com/fluxcd/kustomize-controller/internal/healthcheck"
 	"github.com/fluxcd/kustomize-controller/internal/inventory"
+	"github.com/fluxcd/kustomize-controller/internal/policy"
 )
 
 // +kubebuilder:rbac:groups=kustomize.toolkit.fluxcd.io,resources=kustomizations,verbs=get;list;watch;create;update;patch;delete
@@ -80,6 +85,10 @@ import (
 // +kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=buckets;ocirepositories;gitrepositories,verbs=get;list;watch
 // +kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=buckets/status;ocirepositories/status;gitrepositories/status,verbs=get
 // +kubebuilder:rbac:groups="",resources=configmaps;secrets;serviceaccounts,verbs=get;list;watch
+// The namespaces are read from a cluster-wide informer to resolve the default
+// service account set with an annotation on the namespace of a Kustomization.
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
@@ -89,6 +98,10 @@ type KustomizationReconciler struct {
 	kuberecorder.EventRecorder
 	runtimeCtrl.Metrics
 
+	// Health records the outcome of the artifact downloads and
+	// decryption operations. It is optional.
+	Health *health.Tracker
+
 	// Kubernetes options
 
 	APIReader        client.Reader
@@ -100,30 +113,148 @@ type KustomizationReconciler struct {
 	StatusManager    string
 	CustomStageKinds map[schema.GroupKind]struct{}
 
+	// ClusterFacts provides the facts of the cluster injected into the
+	// post-build substitutions when ClusterFactsSubstitution is enabled.
+	ClusterFacts *ClusterFacts
+
+	// LockNamespace is the namespace of the Leases of the locks shared by
+	// Kustomizations. When empty, the Leases are created in the namespace
+	// of each Kustomization.
+	LockNamespace string
+
+	// EphemeralObjectLabels are set on the Jobs, CronJobs and Pods applied
+	// by the controller, and on their pod templates, to exclude them from
+	// the cluster backups.
+	EphemeralObjectLabels map[string]string
+
+	// NamespaceLabels are set on the Namespaces applied by the controller,
+	// overriding the labels set in the manifests and in the Kustomizations.
+	NamespaceLabels map[string]string
+
+	// ApplyReadCache serves the reads of the apply for the ApplyReadCacheKinds
+	// when CacheApplyReads is enabled. The reads of the objects written by
+	// the controller within ApplyReadCacheMaxStaleness are served from the
+	// cache only if it holds the written version.
+	ApplyReadCache             client.Reader
+	ApplyReadCacheKinds        map[schema.GroupKind]struct{}
+	ApplyReadCacheMaxStaleness time.Duration
+
+	// KubeConfigQPS and KubeConfigBurst tune the rate limiter of the clients
+	// of the remote clusters targeted with a kubeconfig. The clients are
+	// reused across reconciliations for up to KubeConfigClientTTL after
+	// their last use, zero disables the reuse.
+	KubeConfigBurst     int
+	KubeConfigClientTTL time.Duration
+	KubeConfigQPS       float32
+
 	// Multi-tenancy and security options
 
-	DefaultServiceAccount   string
-	DisallowedFieldManagers []string
-	NoCrossNamespaceRefs    bool
-	NoRemoteBases           bool
-	SOPSAgeSecret           string
-	SOPSVaultConfigMap      string
-	TokenCache              *cache.TokenCache
+	AllowKustomizeAlphaPlugins  bool
+	CRDServiceAccount           string
+	CRDServiceAccountNamespaces []string
+	DecryptionKeyExpiryWarning  time.Duration
+	DecryptionKeysCacheTTL      time.Duration
+	DecryptionMaxBinaryFileSize int64
+	DefaultServiceAccount       string
+	DefaultSubstituteFrom       string
+	DisallowedFieldManagers     []string
+	KustomizeHelmCommand        string
+	NoCrossNamespaceRefs        bool
+	NoRemoteBases               bool
+	SOPSAgeSecret               string
+	SOPSVaultConfigMap          string
+	SpecValidationRules         string
+	TenancyProfiles             string
+	TokenCache                  *cache.TokenCache
+
+	// UpgradeFreezeLease is the name of the Lease in the namespace of the
+	// controller signalling a cluster upgrade, during which the
+	// reconciliations are paused. Empty disables the upgrade freeze.
+	UpgradeFreezeLease string
 
 	// Retry and requeue options
 
-	ArtifactFetchRetries      int
-	DependencyRequeueInterval time.Duration
+	ApplyRetries                 int
+	ArtifactFetchRetries         int
+	DependencyRequeueInterval    time.Duration
+	DependencyWaitTimeout        time.Duration
+	RemoteUnreachableGracePeriod time.Duration
+	ShutdownDrainTimeout         time.Duration
 
 	// Feature gates
 
-	AdditiveCELDependencyCheck bool
-	AllowExternalArtifact      bool
-	DirectSourceFetch          bool
-	FailFast                   bool
-	GroupChangeLog             bool
-	MigrateAPIVersion          bool
-	StrictSubstitutions        bool
+	AdditiveCELDependencyCheck  bool
+	AllowExternalArtifact       bool
+	ApplyJournal                bool
+	CacheApplyReads             bool
+	CacheBuildOnManualReconcile bool
+	DirectSourceFetch           bool
+	FailFast                    bool
+	GroupChangeLog              bool
+	InventoryObjectRevisions    bool
+	MigrateAPIVersion           bool
+	RefreshRemoteTokens         bool
+	SkipHPAReplicasDrift        bool
+	SkipUnchangedConfigRenders  bool
+	StrictSubstitutions         bool
+	WatchInventoryKinds         bool
+
+	// enqueueFilters holds the custom filters of the reconciliations
+	// enqueued on the change of the objects referenced by Kustomizations.
+	enqueueFilters []EnqueueFilter
+
+	// buildCache holds the last build result of every Kustomization
+	// when CacheBuildOnManualReconcile is enabled.
+	buildCache sync.Map
+
+	// applyWrites holds the resource versions of the objects written
+	// by the apply when CacheApplyReads is enabled.
+	applyWrites sync.Map
+
+	// mutationTrackers holds the fields drifted in the last reconciliations
+	// of every Kustomization, to detect the mutation loops.
+	mutationTrackers sync.Map
+
+	// configChanges holds the Kustomizations enqueued for the change of a
+	// ConfigMap or Secret used only to render their manifests, when
+	// SkipUnchangedConfigRenders is enabled.
+	configChanges sync.Map
+
+	// dependencyWaits holds the start of the wait of the Kustomizations
+	// whose dependencies are not ready.
+	dependencyWaits sync.Map
+
+	// hpaReplicasDrifts holds the last reported objects for which the
+	// drift correction of spec.replicas was skipped.
+	hpaReplicasDrifts sync.Map
+
+	// imageDigests holds the digests of the images resolved with the
+	// registry API, reused for the interval of the Kustomizations.
+	imageDigests sync.Map
+
+	// remoteOutages holds the start of the outage of the remote
+	// clusters targeted by the Kustomizations.
+	remoteOutages sync.Map
+
+	// decryptionSecrets holds the last version read of the decryption
+	// Secrets, used when a Secret can't be read due to a transient error.
+	decryptionSecrets decryptor.SecretCache
+
+	// decryptionKeyrings holds the keys imported from the decryption
+	// Secrets, reused for DecryptionKeysCacheTTL while a Secret is unchanged.
+	decryptionKeyrings decryptor.KeyringCache
+
+	// remoteClients holds the clients of the remote clusters
+	// targeted with a kubeconfig.
+	remoteClients remoteClientPool
+
+	// remoteTokens holds the credentials of the remote clusters
+	// when RefreshRemoteTokens is enabled.
+	remoteTokens remoteTokenCache
+
+	// inventoryWatcher watches the kinds present in the inventories
+	// when WatchInventoryKinds is enabled.
+	inventoryWatcher *inventoryWatcher
 }
 
 func (r *KustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
@@ -134,30 +265,50 @@ func (r *KustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	configChanged := r.takeConfigChange(obj)
 
 	// Initialize the runtime patcher with the current version of the object.
 	patcher := patch.NewSerialPatcher(obj, r.Client)
+	var readyKept bool
+
+	// Keep the Ready message the reconciliation starts with, and the
+	// changes it makes, to render the Ready message template.
+	readyMessageBefore := conditions.GetMessage(obj, meta.ReadyCondition)
+	var changes readyMessageChanges
 
 	// Finalise the reconciliation and report the results.
 	defer func() {
-		// Patch finalizers, status and conditions.
-		if err := r.finalizeStatus(ctx, obj, patcher); err != nil {
+		// Patch finalizers, status and conditions,
+		// within the drain timeout if the controller is shutting down.
+		patchCtx, cancel := r.drainContext(ctx)
+		defer cancel()
+		renderReadyMessage(ctx, obj, readyMessageBefore, changes)
+		if err := r.finalizeStatus(patchCtx, obj, patcher); err != nil {
 			retErr = kerrors.NewAggregate([]error{retErr, err})
 		}
 
 		// Record Prometheus metrics.
 		r.Metrics.RecordDuration(ctx, obj, reconcileStart)
 
+		// Requeue the reconciliation at the expiration of the TTL.
+		if retErr == nil {
+			result = requeueAtExpiration(obj, result)
+		}
+
 		// Do not proceed if the Kustomization is suspended
 		if obj.Spec.Suspend {
 			return
 		}
 
-		// Log and emit success event.
-		if conditions.IsReady(obj) {
-			msg := fmt.Sprintf("Reconciliation finished in %s, next run in %s",
-				time.Since(reconcileStart).String(),
-				obj.Spec.Interval.Duration.String())
+		// Log and emit success event, unless the Ready condition
+		// was kept during an outage of the remote cluster.
+		if conditions.IsReady(obj) && !readyKept {
+			next := "next run in " + obj.Spec.Interval.Duration.String()
+			if obj.GetRequeueAfter() == 0 {
+				next = "periodic reconciliation disabled"
+			}
+			msg := fmt.Sprintf("Reconciliation finished in %s, %s",
+				time.Since(reconcileStart).String(), next)
 			log.Info(msg, "revision", obj.Status.LastAttemptedRevision)
 			r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityInfo, msg,
 				map[string]string{
@@ -180,11 +331,51 @@ func (r *KustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// Skip reconciliation if the object is suspended.
-	if obj.Spec.Suspend {
+	// Skip reconciliation if the object is suspended, unless only the apply is.
+	if obj.Spec.Suspend && !obj.IsApplySuspended() {
 		log.Info("Reconciliation is suspended for this object")
 		return ctrl.Result{}, nil
 	}
+	if !obj.IsApplySuspended() {
+		conditions.Delete(obj, kustomizev1.DriftDetectedCondition)
+	}
+
+	// Pause the reconciliation during the cluster upgrade freeze,
+	// unless the apply is suspended.
+	if !obj.IsApplySuspended() {
+		freeze, err := r.getUpgradeFreeze(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if freeze != "" {
+			result, readyKept = r.waitForUpgradeFreeze(ctx, obj, freeze)
+			return result, nil
+		}
+	}
+	conditions.Delete(obj, kustomizev1.UpgradeFreezeCondition)
+
+	// Delete the managed resources and stop the reconciliation if the TTL expired.
+	if expired, err := r.reconcileExpiration(ctx, obj); expired || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Evaluate the platform validation rules against the spec.
+	if r.SpecValidationRules != "" {
+		rulesName := types.NamespacedName{
+			Namespace: os.Getenv(runtimeCtrl.EnvRuntimeNamespace),
+			Name:      r.SpecValidationRules,
+		}
+		if err := policy.ValidateObject(ctx, r.Client, rulesName, obj); err != nil {
+			var violation *policy.ViolationError
+			if !errors.As(err, &violation) {
+				return ctrl.Result{}, err
+			}
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.PolicyViolationReason, "%s", err)
+			log.Error(err, "Kustomization spec violates the validation rules")
+			r.event(obj, "", "", eventv1.EventSeverityError, err.Error(), nil)
+			return retryResult(obj), nil
+		}
+	}
 
 	// Configure custom health checks.
 	statusReader, err := cel.NewStatusReader(obj.Spec.HealthCheckExprs)
@@ -196,6 +387,34 @@ func (r *KustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		r.event(obj, "", "", eventv1.EventSeverityError, errMsg, nil)
 		return ctrl.Result{}, reconcile.TerminalError(err)
 	}
+	var statusReaders []func(apimeta.RESTMapper) engine.StatusReader
+	if len(obj.Spec.HealthCheckExprs) > 0 {
+		statusReaders = append(statusReaders, statusReader)
+	}
+
+	// Configure the readiness thresholds of Deployments.
+	if len(obj.Spec.HealthCheckThresholds) > 0 {
+		deploymentReader, err := healthcheck.NewDeploymentStatusReader(obj.Spec.HealthCheckThresholds)
+		if err != nil {
+			errMsg := fmt.Sprintf("%s: %v", TerminalErrorMessage, err)
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.HealthCheckFailedReason, "%s", errMsg)
+			conditions.MarkStalled(obj, kustomizev1.HealthCheckFailedReason, "%s", errMsg)
+			obj.Status.ObservedGeneration = obj.Generation
+			r.event(obj, "", "", eventv1.EventSeverityError, errMsg, nil)
+			return ctrl.Result{}, reconcile.TerminalError(err)
+		}
+		statusReaders = append(statusReaders, deploymentReader)
+	}
+
+	// Check that the build options are allowed in the controller.
+	if _, err := r.kustomizeBuildOptions(obj); err != nil {
+		errMsg := fmt.Sprintf("%s: %v", TerminalErrorMessage, err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", errMsg)
+		conditions.MarkStalled(obj, kustomizev1.BuildFailedReason, "%s", errMsg)
+		obj.Status.ObservedGeneration = obj.Generation
+		r.event(obj, "", "", eventv1.EventSeverityError, errMsg, nil)
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
 
 	// Check object-level workload identity feature gate and decryption with service account.
 	if d := obj.Spec.Decryption; d != nil && d.ServiceAccountName != "" && !auth.IsObjectLevelWorkloadIdentityEnabled() {
@@ -212,12 +431,12 @@ func (r *KustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	// Resolve the source reference and requeue the reconciliation if the source is not found.
 	artifactSource, err := r.getSource(ctx, obj)
 	if err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ArtifactFailedReason, "%s", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ArtifactFailedReason, "%s", err)
 
 		if apierrors.IsNotFound(err) {
 			msg := fmt.Sprintf("Source '%s' not found", obj.Spec.SourceRef.String())
 			log.Info(msg)
-			return ctrl.Result{RequeueAfter: obj.GetRetryInterval()}, nil
+			return retryResult(obj), nil
 		}
 
 		if acl.IsAccessDenied(err) {
@@ -234,15 +453,30 @@ func (r *KustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	// Requeue the reconciliation if the source artifact is not found.
 	if artifactSource.GetArtifact() == nil {
 		msg := fmt.Sprintf("Source artifact not found, retrying in %s", r.DependencyRequeueInterval.String())
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ArtifactFailedReason, "%s", msg)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ArtifactFailedReason, "%s", msg)
 		log.Info(msg)
 		return ctrl.Result{RequeueAfter: r.DependencyRequeueInterval}, nil
 	}
 	revision := artifactSource.GetArtifact().Revision
 	originRevision := getOriginRevision(artifactSource)
 
+	// Generate the Kustomization applying the delegated CustomResourceDefinitions,
+	// or delete it when the CustomResourceDefinitions are no longer delegated.
+	switch {
+	case obj.GetCRDPolicy() != kustomizev1.CRDPolicyDelegate:
+		if err := r.deleteCRDsKustomization(ctx, obj); err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+			return ctrl.Result{}, err
+		}
+	case !obj.IsApplySuspended():
+		if err := r.reconcileCRDsKustomization(ctx, obj); err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Check dependencies and requeue the reconciliation if the check fails.
-	if len(obj.Spec.DependsOn) > 0 {
+	if len(dependencyRefs(obj)) > 0 {
 		if err := r.checkDependencies(ctx, obj, artifactSource); err != nil {
 			// Check if this is a terminal error that should not trigger retries
 			if errors.Is(err, reconcile.TerminalError(nil)) {
@@ -255,22 +489,39 @@ func (r *KustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			}
 
 			// Retry on transient errors.
-			conditions.MarkFalse(obj, meta.ReadyCondition, meta.DependencyNotReadyReason, "%s", err)
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.DependencyNotReadyReason, "%s", err)
 			msg := fmt.Sprintf("Dependencies do not meet ready condition, retrying in %s", r.DependencyRequeueInterval.String())
 			log.Info(msg)
-			r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+			r.waitForDependencies(obj, revision, originRevision, err)
 			return ctrl.Result{RequeueAfter: r.DependencyRequeueInterval}, nil
 		}
 		log.Info("All dependencies are ready, proceeding with reconciliation")
+		r.endDependencyWait(obj, revision, originRevision)
+	} else {
+		r.deleteDependencyWait(obj)
 	}
 
 	// Reconcile the latest revision.
-	reconcileErr := r.reconcile(ctx, obj, artifactSource, patcher, statusReader)
+	reconcileReq := getReconcileRequest(ctx, obj)
+	reconcileReq.renderOnly = configChanged && conditions.IsReady(obj)
+	if obj.IsApplySuspended() {
+		reconcileReq.mode = reconcileModeApplySuspended
+	}
+	readyBefore := conditions.Get(obj, meta.ReadyCondition)
+	reconcileErr := r.reconcile(ctx, obj, artifactSource, patcher, statusReaders, reconcileReq, &changes)
 
 	// Requeue at the specified retry interval if the artifact tarball is not found.
 	if errors.Is(reconcileErr, fetch.ErrFileNotFound) {
 		msg := fmt.Sprintf("Source is not ready, artifact not found, retrying in %s", r.DependencyRequeueInterval.String())
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ArtifactFailedReason, "%s", msg)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ArtifactFailedReason, "%s", msg)
+		log.Info(msg)
+		return ctrl.Result{RequeueAfter: r.DependencyRequeueInterval}, nil
+	}
+
+	// Requeue the reconciliation if the lock is held by another Kustomization.
+	if lwe := new(lockWaitError); errors.As(reconcileErr, &lwe) {
+		msg := fmt.Sprintf("%s, retrying in %s", reconcileErr, r.DependencyRequeueInterval.String())
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.LockWaitReason, "%s", msg)
 		log.Info(msg)
 		return ctrl.Result{RequeueAfter: r.DependencyRequeueInterval}, nil
 	}
@@ -293,20 +544,69 @@ func (r *KustomizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Stop retrying the revision if it violates the tenant conformance.
+	if tce := new(tenantConformanceError); errors.As(reconcileErr, &tce) {
+		errMsg := fmt.Sprintf("%s: %v", TerminalErrorMessage, reconcileErr)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.TenantConformanceReason, "%s", errMsg)
+		conditions.MarkStalled(obj, kustomizev1.TenantConformanceReason, "%s", errMsg)
+		obj.Status.ObservedGeneration = obj.Generation
+		log.Error(reconcileErr, "Reconciliation failed terminally", "revision", revision)
+		r.event(obj, revision, originRevision, eventv1.EventSeverityError, errMsg, nil)
+		return ctrl.Result{}, reconcile.TerminalError(reconcileErr)
+	}
+
+	// Retry the reconciliation while the remote cluster is unreachable,
+	// instead of failing it as if the apply was rejected.
+	if isRemote(obj) {
+		if isRemoteUnreachable(reconcileErr) {
+			result, readyKept = r.waitForRemote(ctx, obj, revision, originRevision, readyBefore, reconcileErr)
+			return result, nil
+		}
+		r.endRemoteOutage(obj, revision, originRevision)
+	}
+
 	// Broadcast the reconciliation failure and requeue at the specified retry interval.
 	if reconcileErr != nil {
-		log.Error(reconcileErr, fmt.Sprintf("Reconciliation failed after %s, next try in %s",
-			time.Since(reconcileStart).String(),
-			obj.GetRetryInterval().String()),
+		next := "next try in " + obj.GetRetryInterval().String()
+		if obj.GetRetryInterval() == 0 {
+			next = "retrying with backoff"
+		}
+		log.Error(reconcileErr, fmt.Sprintf("Reconciliation failed after %s, %s",
+			time.Since(reconcileStart).String(), next),
 			"revision",
 			revision)
 		r.event(obj, revision, originRevision, eventv1.EventSeverityError,
 			reconcileErr.Error(), nil)
-		return ctrl.Result{RequeueAfter: obj.GetRetryInterval()}, nil
+		return retryResult(obj), nil
+	}
+
+	// Wait for a source or dependency change if the periodic reconciliation is disabled.
+	if obj.GetRequeueAfter() == 0 {
+		return ctrl.Result{}, nil
 	}
 
 	// Requeue the reconciliation at the specified interval.
-	return ctrl.Result{RequeueAfter: jitter.JitteredIntervalDuration(obj.GetRequeueAfter())}, nil
+	return ctrl.Result{RequeueAfter: jitteredRequeueAfter(obj)}, nil
+}
+
+// jitteredRequeueAfter returns the interval of the Kustomization with the
+// jitter of its spec if specified, or else the jitter of the controller.
+func jitteredRequeueAfter(obj *kustomizev1.Kustomization) time.Duration {
+	if p := obj.Spec.IntervalJitterPercentage; p != nil {
+		return jitter.Percent(float64(*p)/100, nil)(obj.GetRequeueAfter())
+	}
+	return jitter.JitteredIntervalDuration(obj.GetRequeueAfter())
+}
+
+// retryResult returns the result requeuing a failed reconciliation at the
+// retry interval. When the periodic reconciliation is disabled and no retry
+// interval is specified, the reconciliation is retried with the exponential
+// backoff of the controller, as it would never be retried otherwise.
+func retryResult(obj *kustomizev1.Kustomization) ctrl.Result {
+	if retryInterval := obj.GetRetryInterval(); retryInterval > 0 {
+		return ctrl.Result{RequeueAfter: retryInterval}
+	}
+	return ctrl.Result{Requeue: true}
 }
 
 func (r *KustomizationReconciler) reconcile(
@@ -314,10 +614,15 @@ func (r *KustomizationReconciler) reconcile(
 	obj *kustomizev1.Kustomization,
 	src sourcev1.Source,
 	patcher *patch.SerialPatcher,
-	statusReader func(apimeta.RESTMapper) engine.StatusReader) error {
+	statusReaders []func(apimeta.RESTMapper) engine.StatusReader,
+	req reconcileRequest,
+	changes *readyMessageChanges) error {
 	reconcileStart := time.Now()
 	log := ctrl.LoggerFrom(ctx)
 
+	// Bound the decryption and the health checks with the spec.timeout.
+	ctx = withReconcileDeadline(ctx, obj.GetTimeout())
+
 	// Update status with the reconciliation progress.
 	revision := src.GetArtifact().Revision
 	originRevision := getOriginRevision(src)
@@ -329,9 +634,16 @@ func (r *KustomizationReconciler) reconcile(
 	}
 
 	// Create a snapshot of the current inventory.
-	oldInventory := inventory.New()
-	if obj.Status.Inventory != nil {
-		obj.Status.Inventory.DeepCopyInto(oldInventory)
+	oldInventory, err := r.getInventory(ctx, obj)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	// Refuse the revision if its commit is not signed by a trusted key.
+	if err := r.verifyGitSignature(ctx, obj, src); err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.VerificationFailedReason, "%s", err)
+		return err
 	}
 
 	// Create tmp dir.
@@ -348,35 +660,29 @@ func (r *KustomizationReconciler) reconcile(
 		}
 	}(tmpDir)
 
-	// Set the artifact URL hostname override for localhost access.
-	sourceLocalhost := os.Getenv("SOURCE_CONTROLLER_LOCALHOST")
-	if strings.Contains(src.GetArtifact().URL, "//source-watcher") {
-		sourceLocalhost = os.Getenv("SOURCE_WATCHER_LOCALHOST")
-	}
-
 	// Download artifact and extract files to the tmp dir.
-	fetcher := fetch.New(
-		fetch.WithLogger(ctrl.LoggerFrom(ctx)),
-		fetch.WithRetries(r.ArtifactFetchRetries),
-		fetch.WithMaxDownloadSize(tar.UnlimitedUntarSize),
-		fetch.WithUntar(tar.WithMaxUntarSize(tar.UnlimitedUntarSize)),
-		fetch.WithHostnameOverwrite(sourceLocalhost),
-	)
-	if err = fetcher.Fetch(src.GetArtifact().URL, src.GetArtifact().Digest, tmpDir); err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ArtifactFailedReason, "%s", err)
+	err = r.fetchArtifact(ctx, src, tmpDir)
+	if errors.Is(err, fetch.ErrFileNotFound) {
+		// The artifact server is reachable, the artifact is just not there yet.
+		r.Health.RecordObject(health.SubsystemSource, client.ObjectKeyFromObject(obj).String(), nil)
+	} else {
+		r.Health.RecordObject(health.SubsystemSource, client.ObjectKeyFromObject(obj).String(), err)
+	}
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ArtifactFailedReason, "%s", err)
 		return err
 	}
 
 	// check build path exists
 	dirPath, err := securejoin.SecureJoin(tmpDir, obj.Spec.Path)
 	if err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ArtifactFailedReason, "%s", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ArtifactFailedReason, "%s", err)
 		return err
 	}
 
 	if _, err := os.Stat(dirPath); err != nil {
 		err = fmt.Errorf("kustomization path not found: %w", err)
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ArtifactFailedReason, "%s", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ArtifactFailedReason, "%s", err)
 		return err
 	}
 
@@ -389,54 +695,173 @@ func (r *KustomizationReconciler) reconcile(
 	}
 
 	// Configure the Kubernetes client for impersonation.
+	defaultServiceAccount, err := r.getDefaultServiceAccount(ctx, obj)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
 	var impersonatorOpts []runtimeClient.ImpersonatorOption
 	var mustImpersonate bool
-	if r.DefaultServiceAccount != "" || obj.Spec.ServiceAccountName != "" {
+	var kubeConfig *meta.KubeConfigReference
+	if defaultServiceAccount != "" || obj.Spec.ServiceAccountName != "" {
 		mustImpersonate = true
 		impersonatorOpts = append(impersonatorOpts,
-			runtimeClient.WithServiceAccount(r.DefaultServiceAccount, obj.Spec.ServiceAccountName, obj.GetNamespace()))
+			runtimeClient.WithServiceAccount(defaultServiceAccount, obj.Spec.ServiceAccountName, obj.GetNamespace()))
 	}
-	if obj.Spec.KubeConfig != nil {
+	if isRemote(obj) {
 		mustImpersonate = true
+		kubeConfig, err = r.getKubeConfigReference(ctx, obj)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+			return err
+		}
 		provider := r.getProviderRESTConfigFetcher(obj)
 		impersonatorOpts = append(impersonatorOpts,
-			runtimeClient.WithKubeConfig(obj.Spec.KubeConfig, r.KubeConfigOpts, obj.GetNamespace(), provider))
+			runtimeClient.WithKubeConfig(kubeConfig, r.KubeConfigOpts, obj.GetNamespace(), provider))
 	}
-	if r.ClusterReader != nil || len(obj.Spec.HealthCheckExprs) > 0 {
+	if r.ClusterReader != nil || len(statusReaders) > 0 {
 		impersonatorOpts = append(impersonatorOpts,
-			runtimeClient.WithPolling(r.ClusterReader, statusReader))
+			runtimeClient.WithPolling(r.ClusterReader, statusReaders...))
 	}
 	impersonation := runtimeClient.NewImpersonator(r.Client, impersonatorOpts...)
 
 	// Create the Kubernetes client that runs under impersonation.
 	var kubeClient client.Client
-	var statusPoller *polling.StatusPoller
+	mapper := r.Mapper
 	if mustImpersonate {
-		kubeClient, statusPoller, err = impersonation.GetClient(ctx)
+		if kubeConfig != nil {
+			kubeClient, err = r.getRemoteClient(ctx, obj, kubeConfig, defaultServiceAccount)
+		} else {
+			kubeClient, _, err = impersonation.GetClient(ctx)
+		}
+		if err == nil {
+			mapper = kubeClient.RESTMapper()
+		}
 	} else {
-		kubeClient, statusPoller = r.getClientAndPoller(obj, statusReader)
+		kubeClient = r.Client
 	}
 	if err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ReconciliationFailedReason, "%s", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
 		return fmt.Errorf("failed to build kube client: %w", err)
 	}
 
-	// Generate kustomization.yaml if needed.
-	k, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
-	if err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.BuildFailedReason, "%s", err)
-		return err
+	// Tag the API requests of the reconciliation with their operation,
+	// the reconciliations of the last applied revision run in background.
+	ctx = withAPIOperation(ctx, apiOperationApply, src.GetArtifact().HasRevision(obj.Status.LastAppliedRevision))
+
+	// Count the API requests of the reconciliation, including
+	// the ones issued by the status poller of the health checks.
+	apiRequests := newAPIRequestCounter()
+	defer apiRequests.record(obj)
+	kubeClient = apiRequests.wrap(kubeClient)
+	kubeClient = &applyProgressClient{Client: kubeClient}
+	statusPoller := r.newStatusPoller(kubeClient, mapper, statusReaders)
+
+	// Serve the reads of the apply from the shared cache, unless the
+	// client is impersonated or targets a remote cluster.
+	if r.CacheApplyReads && !mustImpersonate {
+		kubeClient = r.newCachedReadClient(kubeClient)
 	}
-	err = r.generate(unstructured.Unstructured{Object: k}, tmpDir, dirPath)
-	if err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.BuildFailedReason, "%s", err)
-		return err
+
+	// Set the server-side field validation directive for apply requests.
+	if obj.Spec.Validation != "" {
+		kubeClient = client.WithFieldValidation(kubeClient, client.FieldValidation(obj.Spec.Validation))
 	}
 
-	// Build the Kustomize overlay and decrypt secrets if needed.
-	resources, err := r.build(ctx, obj, unstructured.Unstructured{Object: k}, tmpDir, dirPath)
-	if err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.BuildFailedReason, "%s", err)
+	// Run the health checks of the last applied objects if requested.
+	if req.mode == kustomizev1.ReconcileModeHealthOnly {
+		return r.reconcileHealthOnly(ctx, kubeClient, statusPoller, patcher, obj, oldInventory)
+	}
+
+	// Export or restore the live state of the objects if requested.
+	switch req.mode {
+	case kustomizev1.ReconcileModeSnapshot:
+		return r.reconcileSnapshot(ctx, kubeClient, obj, oldInventory)
+	case kustomizev1.ReconcileModeRestore:
+		return r.reconcileRestore(ctx, kubeClient, obj)
+	}
+
+	// Reuse the last build on manual requests if the revision and spec are unchanged.
+	resources, sopsMetadata, cached := r.getCachedBuild(obj, revision, req)
+	if cached {
+		log.V(1).Info("reusing the manifests built for revision", "revision", revision)
+	} else {
+		// Pin the images to the digests of their tags if requested.
+		images, err := r.resolveImageDigests(ctx, obj)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+		buildObj := obj.DeepCopy()
+		buildObj.Spec.Images = images
+
+		// Generate kustomization.yaml if needed.
+		k, err := runtime.DefaultUnstructuredConverter.ToUnstructured(buildObj)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+		err = r.generate(unstructured.Unstructured{Object: k}, tmpDir, dirPath)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+		if err := r.injectTransformerConfigs(ctx, obj, dirPath); err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+
+		// Compose the manifests of the included Kustomizations into the build.
+		if err := r.injectIncludes(ctx, obj, dirPath); err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+
+		// Build the Kustomize overlay and decrypt secrets if needed.
+		resources, sopsMetadata, err = r.build(ctx, obj, unstructured.Unstructured{Object: k}, tmpDir, dirPath)
+		checkDecryptionKeyGroups(obj, err)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, failureReason(err, kustomizev1.BuildFailedReason), "%s", err)
+			return err
+		}
+		warnings := buildWarnings(tmpDir, dirPath)
+
+		// Check the API versions of the objects against the target cluster.
+		var apiVersionWarnings []string
+		resources, apiVersionWarnings, err = migrateDeprecatedAPIVersions(obj, mapper, resources)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+
+		// Add the image pull secret of the target namespace if requested.
+		resources, err = r.generateImagePullSecret(ctx, obj, resources)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+
+		// Add the guardrails of the tenancy profile to the target namespace.
+		resources, err = r.generateGuardrails(ctx, obj, resources)
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+		// Record the fingerprint of the build inputs.
+		fingerprint, err := r.buildFingerprint(ctx, buildObj, src.GetArtifact().Digest, unstructured.Unstructured{Object: k})
+		if err != nil {
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
+			return err
+		}
+		obj.Status.BuildFingerprint = fingerprint
+
+		r.recordBuildWarnings(obj, revision, originRevision, append(warnings, apiVersionWarnings...))
+		r.setCachedBuild(obj, revision, resources, sopsMetadata)
+	}
+
+	// Export the manifests for the Kustomizations including this one.
+	if err := r.reconcileExport(ctx, obj, revision, resources); err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
 		return err
 	}
 
@@ -447,12 +872,29 @@ func (r *KustomizationReconciler) reconcile(
 		historyMeta["originRevision"] = originRevision
 	}
 
+	// Skip the apply and the health checks if a config change
+	// left the manifests of the applied revision unchanged.
+	if isUnchangedRender(obj, revision, checksum, req) {
+		log.Info("skipping the apply, the config change left the manifests unchanged", "revision", revision)
+		conditions.MarkTrue(obj,
+			meta.ReadyCondition,
+			meta.ReconciliationSucceededReason,
+			"Applied revision: %s", revision)
+		obj.Status.History.Upsert(checksum,
+			time.Now(),
+			time.Since(reconcileStart),
+			meta.ReconciliationSucceededReason,
+			historyMeta)
+		return nil
+	}
+
 	// Convert the build result into Kubernetes unstructured objects.
 	objects, err := ssautil.ReadObjects(bytes.NewReader(resources))
 	if err != nil {
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.BuildFailedReason, "%s", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.BuildFailedReason, "%s", err)
 		return err
 	}
+	objects = filterCRDs(obj, objects)
 
 	// Create the server-side apply manager.
 	resourceManager := ssa.NewResourceManager(kubeClient, statusPoller, ssa.Owner{
@@ -462,55 +904,181 @@ func (r *KustomizationReconciler) reconcile(
 	resourceManager.SetOwnerLabels(objects, obj.GetName(), obj.GetNamespace())
 	resourceManager.SetConcurrency(r.ConcurrentSSA)
 
+	// Report the drift and the health without applying if the apply is suspended.
+	if req.mode == reconcileModeApplySuspended {
+		return r.reconcileApplySuspended(ctx, resourceManager, kubeClient, statusPoller, patcher, obj, revision, oldInventory, objects)
+	}
+
+	// Garbage collect the stale objects without applying if requested.
+	if req.mode == kustomizev1.ReconcileModePruneOnly {
+		return r.reconcilePruneOnly(ctx, resourceManager, obj, revision, originRevision, oldInventory, objects)
+	}
+
+	// Report the existing objects of the target namespace without applying if requested.
+	if req.mode == kustomizev1.ReconcileModeObserve {
+		return r.reconcileObserve(ctx, kubeClient, mapper, obj, revision, originRevision, oldInventory, objects)
+	}
+
+	// Compare the manifests with the ones of the compare source if requested.
+	if req.mode == kustomizev1.ReconcileModeCompare {
+		return r.reconcileCompare(ctx, resourceManager, mapper, obj, revision, originRevision, objects)
+	}
+
+	// Reject the cluster-scoped objects if the namespace enforces the tenant conformance.
+	if err := r.checkTenantConformance(ctx, obj, mapper, objects); err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.TenantConformanceReason, "%s", err)
+		return err
+	}
+	if conditions.GetReason(obj, meta.StalledCondition) == kustomizev1.TenantConformanceReason {
+		conditions.Delete(obj, meta.StalledCondition)
+	}
+
+	// Wait for the lock shared with the Kustomizations managing overlapping objects.
+	releaseLock, err := r.acquireLock(ctx, obj)
+	if err != nil {
+		reason := kustomizev1.ReconciliationFailedReason
+		if lwe := new(lockWaitError); errors.As(err, &lwe) {
+			reason = kustomizev1.LockWaitReason
+		}
+		conditions.MarkFalse(obj, meta.ReadyCondition, reason, "%s", err)
+		return err
+	}
+	defer releaseLock()
+
+	// Complete the apply and garbage collection of the revision
+	// within the drain timeout if the controller is shutting down.
+	applyCtx, cancelApply := r.drainContext(ctx)
+	defer cancelApply()
+
 	// Update status with the reconciliation progress.
 	progressingMsg = fmt.Sprintf("Detecting drift for revision %s with a timeout of %s", revision, obj.GetTimeout().String())
 	conditions.MarkReconciling(obj, meta.ProgressingReason, "%s", progressingMsg)
-	if err := r.patch(ctx, obj, patcher); err != nil {
+	if err := r.patch(applyCtx, obj, patcher); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
-	// Validate and apply resources in stages.
-	drifted, changeSet, err := r.apply(ctx, resourceManager, obj, revision, originRevision, objects)
+	// Read the journal of a reconciliation interrupted by a controller restart.
+	journal, err := r.getApplyJournal(applyCtx, obj)
 	if err != nil {
-		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), meta.ReconciliationFailedReason, historyMeta)
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ReconciliationFailedReason, "%s", err)
-		return err
+		log.Error(err, "ignoring the apply journal")
 	}
+	defer func() {
+		// Keep the journal to resume the reconciliation after the shutdown.
+		if ctx.Err() == nil {
+			r.deleteApplyJournal(ctx, obj)
+		}
+	}()
+
+	// Resume the reconciliation if the revision was fully applied,
+	// otherwise validate and apply resources in stages.
+	var drifted bool
+	changeSet, resumed := journal.resumeChangeSet(revision, checksum, objects)
+	if resumed {
+		log.Info("resuming the interrupted reconciliation of the applied revision",
+			"revision", revision, "lastObject", journal.LastObject)
+	} else {
+		if journal != nil {
+			log.Info("re-applying the interrupted revision",
+				"revision", journal.Revision, "phase", journal.Phase)
+		}
+		if err := r.setApplyJournal(applyCtx, obj, &applyJournal{
+			Revision: revision,
+			Checksum: checksum,
+			Phase:    applyJournalApplying,
+		}); err != nil {
+			log.Error(err, "failed to record the apply in the journal")
+		}
+
+		// Collect the warnings returned by the API server to the apply requests.
+		warningsCtx, apiWarnings := withAPIWarningCollector(applyCtx)
+		drifted, changeSet, err = r.apply(warningsCtx, resourceManager, obj, revision, originRevision, objects, len(resources), sopsMetadata, req.force)
+		apiWarnings.record(obj)
+		if err != nil {
+			reason := failureReason(err, kustomizev1.ReconciliationFailedReason)
+			obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), reason, historyMeta)
+			conditions.MarkFalse(obj, meta.ReadyCondition, reason, "%s", err)
+			return err
+		}
+
+		if err := r.setApplyJournal(applyCtx, obj, newAppliedJournal(revision, checksum, changeSet)); err != nil {
+			log.Error(err, "failed to record the apply in the journal")
+		}
+	}
+
+	changes.add(changeSet)
 
 	// Create an inventory from the reconciled resources.
 	newInventory := inventory.New()
 	err = inventory.AddChangeSet(newInventory, changeSet)
+	if err == nil && r.InventoryObjectRevisions {
+		err = r.setInventoryRevisions(obj, newInventory, oldInventory, objects, changeSet, revision, resumed)
+	}
 	if err != nil {
-		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), meta.ReconciliationFailedReason, historyMeta)
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ReconciliationFailedReason, "%s", err)
+		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), kustomizev1.ReconciliationFailedReason, historyMeta)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
 		return err
 	}
 
 	// Set last applied inventory in status.
-	obj.Status.Inventory = newInventory
+	if err := r.setInventory(applyCtx, obj, newInventory); err != nil {
+		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), kustomizev1.ReconciliationFailedReason, historyMeta)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	// Watch the kinds of the applied objects to detect drift.
+	r.watchInventory(ctx, obj, newInventory)
 
 	// Detect stale resources which are subject to garbage collection.
 	staleObjects, err := inventory.Diff(oldInventory, newInventory)
 	if err != nil {
-		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), meta.ReconciliationFailedReason, historyMeta)
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.ReconciliationFailedReason, "%s", err)
+		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), kustomizev1.ReconciliationFailedReason, historyMeta)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
 		return err
 	}
+	staleObjects = retainDelegatedCRDs(obj, staleObjects)
 
 	// Run garbage collection for stale resources that do not have pruning disabled.
 	// On failure, re-track the objects whose DELETE wasn't confirmed so that the
 	// next reconcile retries — otherwise status.Inventory advances past them
-	// and they leak as untracked orphans (issue #1664).
-	if _, survivors, err := r.prune(ctx, resourceManager, obj, revision, originRevision, staleObjects); err != nil {
-		inventory.Merge(obj.Status.Inventory, survivors)
-		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), meta.PruneFailedReason, historyMeta)
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.PruneFailedReason, "%s", err)
+	// and they leak as untracked orphans (issue #1664). The objects whose
+	// API was removed from the cluster are re-tracked even on success.
+	_, survivors, err := r.prune(applyCtx, resourceManager, obj, revision, originRevision, staleObjects)
+	if len(survivors) > 0 {
+		inventory.Merge(newInventory, survivors)
+		if r.InventoryObjectRevisions {
+			_ = inventory.SetRevisions(newInventory, oldInventory, nil, nil, revision)
+		}
+		if invErr := r.setInventory(applyCtx, obj, newInventory); invErr != nil {
+			err = kerrors.NewAggregate([]error{err, invErr})
+		}
+	}
+	if err != nil {
+		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), kustomizev1.PruneFailedReason, historyMeta)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.PruneFailedReason, "%s", err)
+		return err
+	}
+
+	// Checkpoint the progress and leave the health checks
+	// to the next reconciliation if the controller is shutting down.
+	if ctx.Err() != nil {
+		return checkpointShutdown(ctx, obj, revision)
+	}
+
+	// Restrict the health checks to the objects matching the wait selector.
+	waitChangeSet, err := selectWaitObjects(obj, changeSet, objects)
+	if err != nil {
+		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), kustomizev1.HealthCheckFailedReason, historyMeta)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.HealthCheckFailedReason, "%s", err)
 		return err
 	}
 
-	// Run the health checks for the last applied resources.
+	// Run the health checks for the last applied resources,
+	// unless skipped by an emergency override.
 	isNewRevision := !src.GetArtifact().HasRevision(obj.Status.LastAppliedRevision)
-	if err := r.checkHealth(ctx,
+	if req.mode == kustomizev1.ReconcileModeSkipHealthChecks {
+		r.recordHealthCheckOverride(ctx, obj, revision, originRevision, req)
+	} else if err := r.checkHealth(ctx,
 		resourceManager,
 		patcher,
 		obj,
@@ -518,15 +1086,15 @@ func (r *KustomizationReconciler) reconcile(
 		originRevision,
 		isNewRevision,
 		drifted,
-		changeSet,
+		waitChangeSet,
 		ssautil.ExtractJobsWithTTL(objects)); err != nil {
 
 		if errors.Is(err, &runtimeCtrl.QueueEventSource{}) {
 			return err
 		}
 
-		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), meta.HealthCheckFailedReason, historyMeta)
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.HealthCheckFailedReason, "%s", err)
+		obj.Status.History.Upsert(checksum, time.Now(), time.Since(reconcileStart), kustomizev1.HealthCheckFailedReason, historyMeta)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.HealthCheckFailedReason, "%s", err)
 		return err
 	}
 
@@ -565,7 +1133,7 @@ func (r *KustomizationReconciler) checkDependencies(ctx context.Context,
 		return fmt.Errorf("failed to convert Kustomization to unstructured: %w", err)
 	}
 
-	for _, depRef := range obj.Spec.DependsOn {
+	for _, depRef := range dependencyRefs(obj) {
 		// Check if the dependency exists by querying
 		// the API server bypassing the cache.
 		if depRef.Namespace == "" {
@@ -744,6 +1312,25 @@ func (r *KustomizationReconciler) getSource(ctx context.Context,
 	return src, nil
 }
 
+// fetchArtifact downloads the artifact of the source
+// and extracts its files to the given directory.
+func (r *KustomizationReconciler) fetchArtifact(ctx context.Context, src sourcev1.Source, dir string) error {
+	// Set the artifact URL hostname override for localhost access.
+	sourceLocalhost := os.Getenv("SOURCE_CONTROLLER_LOCALHOST")
+	if strings.Contains(src.GetArtifact().URL, "//source-watcher") {
+		sourceLocalhost = os.Getenv("SOURCE_WATCHER_LOCALHOST")
+	}
+
+	fetcher := fetch.New(
+		fetch.WithLogger(ctrl.LoggerFrom(ctx)),
+		fetch.WithRetries(r.ArtifactFetchRetries),
+		fetch.WithMaxDownloadSize(tar.UnlimitedUntarSize),
+		fetch.WithUntar(tar.WithMaxUntarSize(tar.UnlimitedUntarSize)),
+		fetch.WithHostnameOverwrite(sourceLocalhost),
+	)
+	return fetcher.Fetch(src.GetArtifact().URL, src.GetArtifact().Digest, dir)
+}
+
 func (r *KustomizationReconciler) generate(obj unstructured.Unstructured,
 	workDir string, dirPath string) error {
 	_, err := generator.NewGenerator(workDir, obj).WriteFile(dirPath)
@@ -752,7 +1339,7 @@ func (r *KustomizationReconciler) generate(obj unstructured.Unstructured,
 
 func (r *KustomizationReconciler) build(ctx context.Context,
 	obj *kustomizev1.Kustomization, u unstructured.Unstructured,
-	workDir, dirPath string) ([]byte, error) {
+	workDir, dirPath string) ([]byte, map[string]decryptor.SOPSMetadata, error) {
 
 	// Build decryptor.
 	decryptorOpts := []decryptor.Option{
@@ -761,55 +1348,106 @@ func (r *KustomizationReconciler) build(ctx context.Context,
 	if r.TokenCache != nil {
 		decryptorOpts = append(decryptorOpts, decryptor.WithTokenCache(*r.TokenCache))
 	}
+	decryptorOpts = append(decryptorOpts, decryptor.WithSecretCache(&r.decryptionSecrets))
+	decryptorOpts = append(decryptorOpts, decryptor.WithMaxBinaryFileSize(r.DecryptionMaxBinaryFileSize))
+	if r.DecryptionKeysCacheTTL > 0 {
+		decryptorOpts = append(decryptorOpts, decryptor.WithKeyringCache(&r.decryptionKeyrings, r.DecryptionKeysCacheTTL))
+	}
 	if name, ns := r.SOPSAgeSecret, os.Getenv(runtimeCtrl.EnvRuntimeNamespace); name != "" && ns != "" {
 		decryptorOpts = append(decryptorOpts, decryptor.WithSOPSAgeSecret(name, ns))
 	}
 	if name, ns := r.SOPSVaultConfigMap, os.Getenv(runtimeCtrl.EnvRuntimeNamespace); name != "" && ns != "" {
 		decryptorOpts = append(decryptorOpts, decryptor.WithVaultConfigMap(name, ns))
 	}
+	if r.Health != nil {
+		decryptorOpts = append(decryptorOpts, decryptor.WithHealth(r.Health))
+	}
+
+	// Bound the requests to the key management services with the reconciliation deadline.
+	decryptCtx, cancelDecrypt := stageContext(ctx)
+	defer cancelDecrypt()
+	decryptorOpts = append(decryptorOpts, decryptor.WithContext(decryptCtx))
+
 	dec, cleanup, err := decryptor.New(r.Client, obj, decryptorOpts...)
 	if err != nil {
-		return nil, err
+		return nil, nil, decryptionFailed(ctx, err)
 	}
 	defer cleanup()
 
 	// Import keys and static credentials for decryption.
-	if err := dec.ImportKeys(ctx); err != nil {
-		return nil, err
+	if err := dec.ImportKeys(decryptCtx); err != nil {
+		return nil, nil, decryptionFailed(ctx, err)
 	}
+	recordDecryptionKeys(ctx, obj, dec.KeysSecret())
+	r.checkDecryptionKeyExpiry(obj, dec.KeyExpiries())
+	r.recordDecryptionKeyHashes(obj, dec.KeyRecipients())
 
 	// Set options for secret-less authentication with cloud providers for decryption.
-	dec.SetAuthOptions(ctx)
+	dec.SetAuthOptions(decryptCtx)
+
+	// Check the recipients of the encrypted files before they are decrypted.
+	r.checkDecryptionRecipients(obj, dec.CheckRecipients(dirPath))
 
 	// Decrypt Kustomize EnvSources files before build
 	if err = dec.DecryptSources(dirPath); err != nil {
-		return nil, fmt.Errorf("error decrypting sources: %w", err)
+		return nil, nil, decryptionFailed(ctx, fmt.Errorf("error decrypting sources: %w", err))
 	}
 
-	m, err := generator.SecureBuild(workDir, dirPath, !r.NoRemoteBases)
+	// Merge the controller-level default substitutions.
+	if obj.Spec.PostBuild != nil {
+		u, err = r.withDefaultSubstitutions(ctx, u)
+		if err != nil {
+			return nil, nil, err
+		}
+		if obj.Spec.Decryption != nil && obj.Spec.Decryption.Provider == decryptor.DecryptionProviderPlaceholder {
+			u, err = r.withResolvedSubstitutions(ctx, dec, u)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	buildOpts, err := r.kustomizeBuildOptions(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	openAPISchema, err := r.openAPISchema(ctx, obj)
 	if err != nil {
-		return nil, fmt.Errorf("kustomize build failed: %w", err)
+		return nil, nil, err
+	}
+	m, err := secureBuild(workDir, dirPath, !r.NoRemoteBases, buildOpts, openAPISchema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("kustomize build failed: %w", err)
 	}
 
 	for _, res := range m.Resources() {
 		// check if resources conform to the Kubernetes API conventions
 		if res.GetName() == "" || res.GetKind() == "" || res.GetApiVersion() == "" {
-			return nil, fmt.Errorf("failed to decode Kubernetes apiVersion, kind and name from: %v", res.String())
+			return nil, nil, fmt.Errorf("failed to decode Kubernetes apiVersion, kind and name from: %v", res.String())
 		}
 
 		// check if resources are encrypted and decrypt them before generating the final YAML
 		if obj.Spec.Decryption != nil {
 			outRes, err := dec.DecryptResource(res)
 			if err != nil {
-				return nil, fmt.Errorf("decryption failed for '%s/%s': %w", res.GetGvk(), res.GetName(), err)
+				return nil, nil, decryptionFailed(ctx,
+					fmt.Errorf("decryption failed for '%s/%s': %w", res.GetGvk(), res.GetName(), err))
 			}
 
 			if outRes != nil {
 				_, err = m.Replace(res)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 			}
+
+			// fail if encrypted data remains, unless decryption is disabled for the resource
+			if obj.Spec.Decryption.FailOnUndecrypted &&
+				!decryptor.IsDecryptionDisabled(res.GetAnnotations()) &&
+				decryptor.IsUndecryptedResource(res) {
+				return nil, nil, decryptionFailed(ctx,
+					fmt.Errorf("'%s/%s' still holds SOPS encrypted data after decryption", res.GetGvk(), res.GetName()))
+			}
 		}
 
 		// run variable substitutions
@@ -819,13 +1457,13 @@ func (r *KustomizationReconciler) build(ctx context.Context,
 				generator.SubstituteWithStrict(r.StrictSubstitutions),
 				generator.SubstituteWithAlways(always))
 			if err != nil {
-				return nil, fmt.Errorf("post build failed for '%s/%s': %w", res.GetGvk(), res.GetName(), err)
+				return nil, nil, fmt.Errorf("post build failed for '%s/%s': %w", res.GetGvk(), res.GetName(), err)
 			}
 
 			if outRes != nil {
 				_, err = m.Replace(res)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 			}
 		}
@@ -833,10 +1471,10 @@ func (r *KustomizationReconciler) build(ctx context.Context,
 
 	resources, err := m.AsYaml()
 	if err != nil {
-		return nil, fmt.Errorf("kustomize build failed: %w", err)
+		return nil, nil, fmt.Errorf("kustomize build failed: %w", err)
 	}
 
-	return resources, nil
+	return resources, dec.DecryptedMetadata(), nil
 }
 
 func (r *KustomizationReconciler) apply(ctx context.Context,
@@ -844,19 +1482,18 @@ func (r *KustomizationReconciler) apply(ctx context.Context,
 	obj *kustomizev1.Kustomization,
 	revision string,
 	originRevision string,
-	objects []*unstructured.Unstructured) (bool, *ssa.ChangeSet, error) {
+	objects []*unstructured.Unstructured,
+	buildSize int,
+	sopsMetadata map[string]decryptor.SOPSMetadata,
+	force bool) (bool, *ssa.ChangeSet, error) {
 	log := ctrl.LoggerFrom(ctx)
 
-	if err := normalize.UnstructuredList(objects); err != nil {
+	if err := r.prepareObjects(obj, objects); err != nil {
 		return false, nil, err
 	}
 
-	if cmeta := obj.Spec.CommonMetadata; cmeta != nil {
-		ssautil.SetCommonMetadata(objects, cmeta.Labels, cmeta.Annotations)
-	}
-
 	applyOpts := ssa.DefaultApplyOptions()
-	applyOpts.Force = obj.Spec.Force
+	applyOpts.Force = obj.Spec.Force || force
 	applyOpts.ExclusionSelector = map[string]string{
 		fmt.Sprintf("%s/reconcile", kustomizev1.GroupVersion.Group): kustomizev1.DisabledValue,
 		fmt.Sprintf("%s/ssa", kustomizev1.GroupVersion.Group):       kustomizev1.IgnoreValue,
@@ -870,27 +1507,23 @@ func (r *KustomizationReconciler) apply(ctx context.Context,
 	applyOpts.CustomStageKinds = r.CustomStageKinds
 	applyOpts.MigrateAPIVersion = r.MigrateAPIVersion
 
-	if len(obj.Spec.Ignore) > 0 {
-		ignoreRules := make([]jsondiff.IgnoreRule, len(obj.Spec.Ignore))
-		for i, rule := range obj.Spec.Ignore {
-			ignoreRules[i] = jsondiff.IgnoreRule{
-				Paths: rule.Paths,
-			}
-			if rule.Target != nil {
-				ignoreRules[i].Selector = &jsondiff.Selector{
-					Group:              rule.Target.Group,
-					Version:            rule.Target.Version,
-					Kind:               rule.Target.Kind,
-					Name:               rule.Target.Name,
-					Namespace:          rule.Target.Namespace,
-					AnnotationSelector: rule.Target.AnnotationSelector,
-					LabelSelector:      rule.Target.LabelSelector,
-				}
-			}
+	applyOpts.DriftIgnoreRules = driftIgnoreRules(obj)
+
+	if r.SkipHPAReplicasDrift {
+		// Read the autoscalers of the local cluster from the shared cache.
+		var hpaReader client.Reader = r.Client
+		if isRemote(obj) {
+			hpaReader = manager.Client()
 		}
-		applyOpts.DriftIgnoreRules = ignoreRules
+		hpaRules, skipped := r.hpaDriftIgnoreRules(ctx, hpaReader, objects)
+		applyOpts.DriftIgnoreRules = append(applyOpts.DriftIgnoreRules, hpaRules...)
+		r.recordHPAReplicasDrift(ctx, obj, revision, originRevision, skipped)
 	}
 
+	// Suspend the drift correction of the fields reverted on every apply.
+	loopRules := r.mutationLoopIgnoreRules(ctx, manager.Client(), obj, revision, originRevision, objects)
+	applyOpts.DriftIgnoreRules = append(applyOpts.DriftIgnoreRules, loopRules...)
+
 	fieldManagers := []ssa.FieldManager{
 		{
 			// to undo changes made with 'kubectl apply --server-side --force-conflicts'
@@ -946,9 +1579,10 @@ func (r *KustomizationReconciler) apply(ctx context.Context,
 
 	for _, u := range objects {
 		if decryptor.IsEncryptedSecret(u) && !decryptor.IsDecryptionDisabled(u.GetAnnotations()) {
-			return false, nil,
-				fmt.Errorf("%s is SOPS encrypted, configuring decryption is required for this secret to be reconciled",
-					ssautil.FmtUnstructured(u))
+			return false, nil, &decryptionError{
+				err: fmt.Errorf("%s is SOPS encrypted, configuring decryption is required for this secret to be reconciled",
+					ssautil.FmtUnstructured(u)),
+			}
 		}
 	}
 
@@ -957,7 +1591,44 @@ func (r *KustomizationReconciler) apply(ctx context.Context,
 	var changeSetLog strings.Builder
 
 	if len(objects) > 0 {
-		changeSet, err := manager.ApplyAllStaged(ctx, objects, applyOpts)
+		// Recreate the StatefulSets with immutable field changes without
+		// deleting their pods, which are adopted by the new StatefulSets
+		// and optionally replaced gradually after the apply.
+		var recreations []statefulSetRecreation
+		if obj.GetForceStatefulSetPolicy() == kustomizev1.ForceStatefulSetPolicyOrphan {
+			var err error
+			recreations, err = r.orphanImmutableStatefulSets(ctx, manager.Client(), objects, applyOpts, obj.GetTimeout())
+			if err != nil {
+				return false, nil, err
+			}
+		}
+
+		// Report the progress of the long applies in the status.
+		progressCtx, stopProgress := r.reportApplyProgress(ctx, obj, len(objects), applyOpts.CustomStageKinds)
+
+		// Apply the objects rejected by server-side apply with a client-side
+		// three-way merge, and the objects rejected by server-side dry-run
+		// without it, after the staged apply of the other objects.
+		serverSideObjects, clientSideObjects := splitClientSideApply(objects)
+		serverSideObjects, skipDryRunObjects := splitSkipDryRun(serverSideObjects)
+		changeSet, err := r.applyAllStagedWithRetry(progressCtx, manager, serverSideObjects, applyOpts)
+		if err == nil && len(skipDryRunObjects) > 0 {
+			var sdrChangeSet *ssa.ChangeSet
+			sdrChangeSet, err = r.applySkipDryRun(progressCtx, manager.Client(), skipDryRunObjects, applyOpts)
+			if changeSet == nil {
+				changeSet = ssa.NewChangeSet()
+			}
+			changeSet.Append(sdrChangeSet.Entries)
+		}
+		if err == nil && len(clientSideObjects) > 0 {
+			var csaChangeSet *ssa.ChangeSet
+			csaChangeSet, err = r.applyClientSide(progressCtx, manager.Client(), clientSideObjects, applyOpts)
+			if changeSet == nil {
+				changeSet = ssa.NewChangeSet()
+			}
+			changeSet.Append(csaChangeSet.Entries)
+		}
+		stopProgress()
 
 		if changeSet != nil && len(changeSet.Entries) > 0 {
 			resultSet.Append(changeSet.Entries)
@@ -975,6 +1646,13 @@ func (r *KustomizationReconciler) apply(ctx context.Context,
 			return false, nil, fmt.Errorf("%w\n%s", err, changeSetLog.String())
 		}
 
+		if len(recreations) > 0 && obj.Spec.ForceMaxUnavailable != nil {
+			if err := replaceOrphanedPods(ctx, manager.Client(), recreations,
+				int(*obj.Spec.ForceMaxUnavailable), obj.GetTimeout()); err != nil {
+				return false, nil, fmt.Errorf("%w\n%s", err, changeSetLog.String())
+			}
+		}
+
 		// log all applied objects
 		if changeSet != nil && len(changeSet.Entries) > 0 {
 			if r.GroupChangeLog {
@@ -985,15 +1663,65 @@ func (r *KustomizationReconciler) apply(ctx context.Context,
 		}
 	}
 
+	r.recordMutationSuspects(obj, resultSet)
+
 	// emit event only if the server-side apply resulted in changes
 	applyLog := strings.TrimSuffix(changeSetLog.String(), "\n")
 	if applyLog != "" {
-		r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, applyLog, nil)
+		metadata := buildSizeEventMetadata(objects, buildSize, resultSet)
+		maps.Copy(metadata, sopsEventMetadata(resultSet, sopsMetadata))
+		r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, applyLog, metadata)
 	}
 
 	return applyLog != "", resultSet, nil
 }
 
+// prepareObjects normalizes the objects and sets the common metadata, the
+// ephemeral object labels and the namespace labels, before they are applied.
+func (r *KustomizationReconciler) prepareObjects(obj *kustomizev1.Kustomization,
+	objects []*unstructured.Unstructured) error {
+	if err := normalize.UnstructuredList(objects); err != nil {
+		return err
+	}
+
+	if cmeta := obj.Spec.CommonMetadata; cmeta != nil {
+		ssautil.SetCommonMetadata(objects, cmeta.Labels, cmeta.Annotations)
+	}
+
+	if err := setEphemeralObjectLabels(objects, r.EphemeralObjectLabels); err != nil {
+		return err
+	}
+
+	setNamespaceLabels(objects, obj.Spec.NamespaceLabels, r.NamespaceLabels)
+	return nil
+}
+
+// driftIgnoreRules returns the drift ignore rules of the spec.ignore
+// rules of the Kustomization.
+func driftIgnoreRules(obj *kustomizev1.Kustomization) []jsondiff.IgnoreRule {
+	if len(obj.Spec.Ignore) == 0 {
+		return nil
+	}
+	ignoreRules := make([]jsondiff.IgnoreRule, len(obj.Spec.Ignore))
+	for i, rule := range obj.Spec.Ignore {
+		ignoreRules[i] = jsondiff.IgnoreRule{
+			Paths: rule.Paths,
+		}
+		if rule.Target != nil {
+			ignoreRules[i].Selector = &jsondiff.Selector{
+				Group:              rule.Target.Group,
+				Version:            rule.Target.Version,
+				Kind:               rule.Target.Kind,
+				Name:               rule.Target.Name,
+				Namespace:          rule.Target.Namespace,
+				AnnotationSelector: rule.Target.AnnotationSelector,
+				LabelSelector:      rule.Target.LabelSelector,
+			}
+		}
+	}
+	return ignoreRules
+}
+
 func (r *KustomizationReconciler) checkHealth(ctx context.Context,
 	manager *ssa.ResourceManager,
 	patcher *patch.SerialPatcher,
@@ -1004,6 +1732,7 @@ func (r *KustomizationReconciler) checkHealth(ctx context.Context,
 	drifted bool,
 	changeSet *ssa.ChangeSet,
 	jobsWithTTL object.ObjMetadataSet) error {
+	ctx = withAPIOperation(ctx, apiOperationHealth, false)
 
 	// We should not check the health of skipped objects, as they are chosen
 	// to be ignored by the user and may not be in a healthy state.
@@ -1013,20 +1742,16 @@ func (r *KustomizationReconciler) checkHealth(ctx context.Context,
 			changeSetWithoutSkipped.Add(entry)
 		}
 	}
-	objects := changeSetWithoutSkipped.ToObjMetadataSet()
 
-	if len(obj.Spec.HealthChecks) == 0 && !obj.Spec.Wait {
+	if len(obj.Spec.HealthChecks) == 0 && len(obj.Spec.ExternalHealthChecks) == 0 && !obj.Spec.Wait {
 		conditions.Delete(obj, meta.HealthyCondition)
 		return nil
 	}
 
 	checkStart := time.Now()
-	var err error
-	if !obj.Spec.Wait {
-		objects, err = inventory.ReferenceToObjMetadataSet(obj.Spec.HealthChecks)
-		if err != nil {
-			return err
-		}
+	objects, err := healthCheckObjects(obj, changeSetWithoutSkipped.ToObjMetadataSet())
+	if err != nil {
+		return err
 	}
 
 	if len(objects) == 0 {
@@ -1048,8 +1773,18 @@ func (r *KustomizationReconciler) checkHealth(ctx context.Context,
 	// Find the previous health check result.
 	wasHealthy := apimeta.IsStatusConditionTrue(obj.Status.Conditions, meta.HealthyCondition)
 
+	// Run the health checks within the time left until the reconciliation deadline.
+	timeout := remainingTimeout(ctx, obj.GetTimeout())
+	if timeout <= 0 {
+		err := stageError(ctx, "health checks", errors.New("no time left to run the health checks"))
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.HealthCheckFailedReason, "%s", err)
+		conditions.MarkFalse(obj, meta.HealthyCondition, kustomizev1.HealthCheckFailedReason, "%s", err)
+		return err
+	}
+
 	// Update status with the reconciliation progress.
-	message := fmt.Sprintf("Running health checks for revision %s with a timeout of %s", revision, obj.GetTimeout().String())
+	message := fmt.Sprintf("Running health checks for revision %s with a timeout of %s",
+		revision, timeout.Round(time.Second).String())
 	conditions.MarkReconciling(obj, meta.ProgressingReason, "%s", message)
 	conditions.MarkUnknown(obj, meta.HealthyCondition, meta.ProgressingReason, "%s", message)
 	if err := r.patch(ctx, obj, patcher); err != nil {
@@ -1060,15 +1795,16 @@ func (r *KustomizationReconciler) checkHealth(ctx context.Context,
 	healthCtx := runtimeCtrl.GetInterruptContext(ctx)
 	if err := manager.WaitForSetWithContext(healthCtx, toCheck, ssa.WaitOptions{
 		Interval:    5 * time.Second,
-		Timeout:     obj.GetTimeout(),
+		Timeout:     timeout,
 		FailFast:    r.FailFast,
 		JobsWithTTL: jobsWithTTL,
 	}); err != nil {
 		if is, err := runtimeCtrl.IsObjectEnqueued(ctx); is {
 			return err
 		}
-		conditions.MarkFalse(obj, meta.ReadyCondition, meta.HealthCheckFailedReason, "%s", err)
-		conditions.MarkFalse(obj, meta.HealthyCondition, meta.HealthCheckFailedReason, "%s", err)
+		err = stageError(ctx, "health checks", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.HealthCheckFailedReason, "%s", err)
+		conditions.MarkFalse(obj, meta.HealthyCondition, kustomizev1.HealthCheckFailedReason, "%s", err)
 		return fmt.Errorf("health check failed after %s: %w", time.Since(checkStart).String(), err)
 	}
 
@@ -1092,18 +1828,52 @@ func (r *KustomizationReconciler) checkHealth(ctx context.Context,
 // must merge those survivors back into status.Inventory; otherwise the next
 // reconcile's old-vs-new diff will not surface them again and they become
 // untracked orphans labeled with this Kustomization. See issue #1664.
+// The objects whose kind is no longer served by the apiserver are returned
+// as survivors even on success, unless purged with the PurgeOrphanedAnnotation.
+// In dry-run mode, all the objects are returned as survivors.
 func (r *KustomizationReconciler) prune(ctx context.Context,
 	manager *ssa.ResourceManager,
 	obj *kustomizev1.Kustomization,
 	revision string,
 	originRevision string,
 	objects []*unstructured.Unstructured) (bool, []*unstructured.Unstructured, error) {
+	obj.Status.OrphanedByAPIRemoval = nil
+	obj.Status.PruneDryRun = nil
 	if !obj.Spec.Prune {
+		conditions.Delete(obj, kustomizev1.CRDDeletionBlockedCondition)
 		return false, nil, nil
 	}
 
 	log := ctrl.LoggerFrom(ctx)
 
+	// Skip the objects whose API was removed from the cluster, as the delete
+	// requests would fail, and keep tracking them until purged.
+	objects, orphaned := splitOrphaned(manager.Client().RESTMapper(), objects)
+	if len(orphaned) > 0 {
+		msg := fmt.Sprintf("objects of kinds no longer served by the API server:\n%s",
+			ssautil.FmtUnstructuredList(orphaned))
+		if obj.GetAnnotations()[kustomizev1.PurgeOrphanedAnnotation] == kustomizev1.EnabledValue {
+			log.Info("purged from inventory " + msg)
+			r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, "purged from inventory "+msg, nil)
+			orphaned = nil
+		} else {
+			obj.Status.OrphanedByAPIRemoval = resourceRefs(orphaned)
+			log.Info("skipping pruning of " + msg)
+			r.event(obj, revision, originRevision, eventv1.EventSeverityError, "skipping pruning of "+msg, nil)
+		}
+	}
+
+	// Keep tracking the CustomResourceDefinitions whose deletion, which would
+	// cascade to all their custom resources, was not confirmed.
+	objects, blocked := r.blockCRDDeletion(obj, revision, originRevision, objects)
+	orphaned = append(orphaned, blocked...)
+
+	// Keep tracking all the stale objects when running in dry-run mode.
+	if isPruneDryRun(obj) {
+		err := r.pruneDryRun(ctx, manager, obj, revision, originRevision, objects)
+		return false, append(objects, orphaned...), err
+	}
+
 	changeSet, err := deleteObjects(ctx, obj, manager, objects)
 	if err != nil {
 		// Identify objects whose DELETE wasn't confirmed (apiserver rejected,
@@ -1111,17 +1881,17 @@ func (r *KustomizationReconciler) prune(ctx context.Context,
 		// entries are intentional opt-outs (kustomize.toolkit.fluxcd.io/prune:
 		// disabled) and are treated as settled, otherwise we'd cause an endless
 		// retry of a delete the operator explicitly disabled.
-		return false, pruneSurvivors(objects, changeSet), err
+		return false, append(pruneSurvivors(objects, changeSet), orphaned...), err
 	}
 
 	// emit event only if the prune operation resulted in changes
 	if changeSet != nil && len(changeSet.Entries) > 0 {
 		log.Info(fmt.Sprintf("garbage collection completed: %s", changeSet.String()))
 		r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, changeSet.String(), nil)
-		return true, nil, nil
+		return true, orphaned, nil
 	}
 
-	return false, nil, nil
+	return false, orphaned, nil
 }
 
 // pruneSurvivors returns the subset of objects whose DELETE was not confirmed
@@ -1154,12 +1924,12 @@ func pruneSurvivors(objects []*unstructured.Unstructured, changeSet *ssa.ChangeS
 // finalizerShouldDeleteResources determines if resources should be deleted
 // based on the object's inventory and deletion policy.
 // A suspended Kustomization or one without an inventory will not delete resources.
-func finalizerShouldDeleteResources(obj *kustomizev1.Kustomization) bool {
+func finalizerShouldDeleteResources(obj *kustomizev1.Kustomization, inv *kustomizev1.ResourceInventory) bool {
 	if obj.Spec.Suspend {
 		return false
 	}
 
-	if obj.Status.Inventory == nil || len(obj.Status.Inventory.Entries) == 0 {
+	if inv == nil || len(inv.Entries) == 0 {
 		return false
 	}
 
@@ -1181,75 +1951,50 @@ func finalizerShouldDeleteResources(obj *kustomizev1.Kustomization) bool {
 // to be terminated by the Kubernetes Garbage Collector for the specified timeout duration.
 // If the service account used for impersonation is no longer available or if a timeout occurs
 // while waiting for resources to be terminated, an error is logged and the finalizer is removed.
+// The garbage collection is skipped when disabled with the DeletionGCAnnotation, or when it
+// keeps failing after the deletion timeout, in which case the skipped objects are reported
+// in an event and the finalizer is removed.
 func (r *KustomizationReconciler) finalize(ctx context.Context,
 	obj *kustomizev1.Kustomization) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx)
-	if finalizerShouldDeleteResources(obj) {
-		objects, _ := inventory.List(obj.Status.Inventory)
-
-		var impersonatorOpts []runtimeClient.ImpersonatorOption
-		var mustImpersonate bool
-		if r.DefaultServiceAccount != "" || obj.Spec.ServiceAccountName != "" {
-			mustImpersonate = true
-			impersonatorOpts = append(impersonatorOpts,
-				runtimeClient.WithServiceAccount(r.DefaultServiceAccount, obj.Spec.ServiceAccountName, obj.GetNamespace()))
-		}
-		if obj.Spec.KubeConfig != nil {
-			mustImpersonate = true
-			provider := r.getProviderRESTConfigFetcher(obj)
-			impersonatorOpts = append(impersonatorOpts,
-				runtimeClient.WithKubeConfig(obj.Spec.KubeConfig, r.KubeConfigOpts, obj.GetNamespace(), provider))
-		}
-		if r.ClusterReader != nil {
-			impersonatorOpts = append(impersonatorOpts, runtimeClient.WithPolling(r.ClusterReader))
-		}
-		impersonation := runtimeClient.NewImpersonator(r.Client, impersonatorOpts...)
-		if impersonation.CanImpersonate(ctx) {
-			var kubeClient client.Client
-			var err error
-			if mustImpersonate {
-				kubeClient, _, err = impersonation.GetClient(ctx)
-			} else {
-				kubeClient = r.Client
-			}
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-
-			resourceManager := ssa.NewResourceManager(kubeClient, nil, ssa.Owner{
-				Field: r.ControllerName,
-				Group: kustomizev1.GroupVersion.Group,
-			})
-
-			changeSet, err := deleteObjects(ctx, obj, resourceManager, objects)
-			if err != nil {
-				r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, "pruning for deleted resource failed", nil)
-				// Return the error so we retry the failed garbage collection
+	r.deleteCachedBuild(obj)
+	r.deleteMutationTracker(obj)
+	r.deleteDependencyWait(obj)
+	r.deleteRemoteOutage(obj)
+	r.deleteHPAReplicasDrift(obj)
+	r.unwatchInventory(ctx, obj)
+	r.Health.Forget(client.ObjectKeyFromObject(obj).String())
+	gcDisabled := obj.GetAnnotations()[kustomizev1.DeletionGCAnnotation] == kustomizev1.DisabledValue
+	timeout := obj.GetDeletionTimeout()
+	timedOut := timeout > 0 && time.Since(obj.GetDeletionTimestamp().Time) >= timeout
+	inv, err := r.getInventory(ctx, obj)
+	switch {
+	case err != nil && gcDisabled:
+		// The garbage collection is skipped anyway, report the objects
+		// of the status inventory instead of blocking the deletion.
+		objects, _ := inventory.List(statusInventory(obj))
+		r.skipDeletionGC(ctx, obj, objects,
+			fmt.Sprintf("garbage collection disabled with the %s annotation, failed to read the inventory: %s",
+				kustomizev1.DeletionGCAnnotation, err))
+	case err != nil && timedOut:
+		objects, _ := inventory.List(statusInventory(obj))
+		r.skipDeletionGC(ctx, obj, objects,
+			fmt.Sprintf("deletion timeout of %s exceeded: %s", timeout, err))
+	case err != nil:
+		return ctrl.Result{}, err
+	case finalizerShouldDeleteResources(obj, inv):
+		objects, _ := inventory.List(inv)
+
+		if gcDisabled {
+			r.skipDeletionGC(ctx, obj, objects,
+				fmt.Sprintf("garbage collection disabled with the %s annotation", kustomizev1.DeletionGCAnnotation))
+		} else if err := r.deleteInventoryObjects(ctx, obj, objects); err != nil {
+			// Give up the garbage collection after the deletion timeout,
+			// otherwise retry it until it succeeds.
+			if !timedOut {
 				return ctrl.Result{}, err
 			}
-
-			if changeSet != nil && len(changeSet.Entries) > 0 {
-				// Emit event with the resources marked for deletion.
-				r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityInfo, changeSet.String(), nil)
-
-				// Wait for the resources marked for deletion to be terminated.
-				if obj.GetDeletionPolicy() == kustomizev1.DeletionPolicyWaitForTermination {
-					if err := resourceManager.WaitForSetTermination(changeSet, ssa.WaitOptions{
-						Interval: 2 * time.Second,
-						Timeout:  obj.GetTimeout(),
-					}); err != nil {
-						// Emit an event and log the error if a timeout occurs.
-						msg := "failed to wait for resources termination"
-						log.Error(err, msg)
-						r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, msg, nil)
-					}
-				}
-			}
-		} else {
-			// when the account to impersonate is gone, log the stale objects and continue with the finalization
-			msg := fmt.Sprintf("unable to prune objects: \n%s", ssautil.FmtUnstructuredList(objects))
-			log.Error(fmt.Errorf("skiping pruning, failed to find account to impersonate"), msg)
-			r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, msg, nil)
+			r.skipDeletionGC(ctx, obj, objects,
+				fmt.Sprintf("deletion timeout of %s exceeded: %s", timeout, err))
 		}
 	}
 
@@ -1260,11 +2005,151 @@ func (r *KustomizationReconciler) finalize(ctx context.Context,
 	for _, op := range kustomizev1.AllMetrics {
 		r.TokenCache.DeleteEventsForObject(kustomizev1.KustomizationKind, obj.GetName(), obj.GetNamespace(), op)
 	}
+	deleteAPIRequestsMetrics(obj)
+	deleteAPIWarningsMetrics(obj)
+	deleteRemoteReachableMetrics(obj)
 
 	// Stop reconciliation as the object is being deleted
 	return ctrl.Result{}, nil
 }
 
+// deleteInventoryObjects deletes the objects of the inventory of the
+// Kustomization being finalized, impersonating the service account or
+// using the kubeconfig if specified.
+func (r *KustomizationReconciler) deleteInventoryObjects(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	objects []*unstructured.Unstructured) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	defaultServiceAccount, err := r.getDefaultServiceAccount(ctx, obj)
+	switch {
+	case errors.Is(err, errNamespaceNotFound) && obj.Spec.ServiceAccountName == "" && !isRemote(obj):
+		// The namespace is gone with the annotation that may have set the
+		// service account, skip the pruning instead of deleting the objects
+		// with the controller identity.
+		msg := fmt.Sprintf("unable to prune objects: \n%s", ssautil.FmtUnstructuredList(objects))
+		log.Error(fmt.Errorf("skipping pruning, %w", err), msg)
+		r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, msg, nil)
+		return nil
+	case err != nil && !errors.Is(err, errNamespaceNotFound):
+		return err
+	}
+	var impersonatorOpts []runtimeClient.ImpersonatorOption
+	var mustImpersonate bool
+	var kubeConfig *meta.KubeConfigReference
+	if defaultServiceAccount != "" || obj.Spec.ServiceAccountName != "" {
+		mustImpersonate = true
+		impersonatorOpts = append(impersonatorOpts,
+			runtimeClient.WithServiceAccount(defaultServiceAccount, obj.Spec.ServiceAccountName, obj.GetNamespace()))
+	}
+	if isRemote(obj) {
+		mustImpersonate = true
+		kubeConfig, err = r.getKubeConfigReference(ctx, obj)
+		if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			// The Cluster API Cluster is gone along with the remote
+			// cluster, skip the pruning instead of retrying forever.
+			msg := fmt.Sprintf("unable to prune objects: \n%s", ssautil.FmtUnstructuredList(objects))
+			log.Error(fmt.Errorf("skipping pruning, %w", err), msg)
+			r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, msg, nil)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		provider := r.getProviderRESTConfigFetcher(obj)
+		impersonatorOpts = append(impersonatorOpts,
+			runtimeClient.WithKubeConfig(kubeConfig, r.KubeConfigOpts, obj.GetNamespace(), provider))
+	}
+	if r.ClusterReader != nil {
+		impersonatorOpts = append(impersonatorOpts, runtimeClient.WithPolling(r.ClusterReader))
+	}
+	impersonation := runtimeClient.NewImpersonator(r.Client, impersonatorOpts...)
+	if impersonation.CanImpersonate(ctx) {
+		var kubeClient client.Client
+		var err error
+		switch {
+		case kubeConfig != nil:
+			kubeClient, err = r.getRemoteClient(ctx, obj, kubeConfig, defaultServiceAccount)
+		case mustImpersonate:
+			kubeClient, _, err = impersonation.GetClient(ctx)
+		default:
+			kubeClient = r.Client
+		}
+		if err != nil {
+			return err
+		}
+
+		resourceManager := ssa.NewResourceManager(kubeClient, nil, ssa.Owner{
+			Field: r.ControllerName,
+			Group: kustomizev1.GroupVersion.Group,
+		})
+
+		// Skip the objects whose API was removed from the cluster,
+		// otherwise the finalization would never complete.
+		objects, orphaned := splitOrphaned(kubeClient.RESTMapper(), objects)
+		if len(orphaned) > 0 {
+			log.Info(fmt.Sprintf("skipping pruning of objects of kinds no longer served by the API server:\n%s",
+				ssautil.FmtUnstructuredList(orphaned)))
+		}
+
+		// Keep the Namespaces still used by other Kustomizations,
+		// otherwise their objects would be garbage collected.
+		objects, shared, err := r.splitSharedNamespaces(ctx, obj, objects)
+		if err != nil {
+			return err
+		}
+		if len(shared) > 0 {
+			msg := fmt.Sprintf("skipped the deletion of Namespaces used by other Kustomizations:\n%s",
+				formatSharedNamespaces(shared))
+			log.Info(msg)
+			r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityInfo, msg, nil)
+		}
+
+		changeSet, err := deleteObjects(ctx, obj, resourceManager, objects)
+		if err != nil {
+			r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, "pruning for deleted resource failed", nil)
+			// Return the error so we retry the failed garbage collection
+			return err
+		}
+
+		if changeSet != nil && len(changeSet.Entries) > 0 {
+			// Emit event with the resources marked for deletion.
+			r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityInfo, changeSet.String(), nil)
+
+			// Wait for the resources marked for deletion to be terminated.
+			if obj.GetDeletionPolicy() == kustomizev1.DeletionPolicyWaitForTermination {
+				if err := resourceManager.WaitForSetTermination(changeSet, ssa.WaitOptions{
+					Interval: 2 * time.Second,
+					Timeout:  obj.GetTimeout(),
+				}); err != nil {
+					// Emit an event and log the error if a timeout occurs.
+					msg := "failed to wait for resources termination"
+					log.Error(err, msg)
+					r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, msg, nil)
+				}
+			}
+		}
+	} else {
+		// when the account to impersonate is gone, log the stale objects and continue with the finalization
+		msg := fmt.Sprintf("unable to prune objects: \n%s", ssautil.FmtUnstructuredList(objects))
+		log.Error(fmt.Errorf("skiping pruning, failed to find account to impersonate"), msg)
+		r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, msg, nil)
+	}
+	return nil
+}
+
+// skipDeletionGC logs and reports in an event the objects left
+// in the cluster by the finalization for the given reason.
+func (r *KustomizationReconciler) skipDeletionGC(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	objects []*unstructured.Unstructured,
+	reason string) {
+	msg := fmt.Sprintf("skipped the garbage collection, %s, objects left in the cluster:\n%s",
+		reason, ssautil.FmtUnstructuredList(objects))
+	ctrl.LoggerFrom(ctx).Info(msg)
+	r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityError, msg, nil)
+}
+
 func (r *KustomizationReconciler) event(obj *kustomizev1.Kustomization,
 	revision, originRevision, severity, msg string,
 	metadata map[string]string) {
@@ -1326,10 +2211,17 @@ func (r *KustomizationReconciler) patch(ctx context.Context,
 	// Configure the runtime patcher.
 	patchOpts := []patch.Option{}
 	ownedConditions := []string{
+		kustomizev1.CRDDeletionBlockedCondition,
+		kustomizev1.DecryptionKeyExpiringCondition,
+		kustomizev1.DecryptionKeyGroupsUnsatisfiedCondition,
+		kustomizev1.DecryptionRecipientsMismatchCondition,
+		kustomizev1.DriftDetectedCondition,
 		meta.HealthyCondition,
+		kustomizev1.MutationLoopDetectedCondition,
 		meta.ReadyCondition,
 		meta.ReconcilingCondition,
 		meta.StalledCondition,
+		kustomizev1.UpgradeFreezeCondition,
 	}
 	patchOpts = append(patchOpts,
 		patch.WithOwnedConditions{Conditions: ownedConditions},
@@ -1351,28 +2243,24 @@ func (r *KustomizationReconciler) patch(ctx context.Context,
 	return nil
 }
 
-// getClientAndPoller creates a status poller with the custom status readers
-// from CEL expressions and the custom job status reader, and returns the
-// Kubernetes client of the controller and the status poller.
-// Should be used for reconciliations that are not configured to use
-// ServiceAccount impersonation or kubeconfig.
-func (r *KustomizationReconciler) getClientAndPoller(
-	obj *kustomizev1.Kustomization,
-	readerCtor func(apimeta.RESTMapper) engine.StatusReader,
-) (client.Client, *polling.StatusPoller) {
+// newStatusPoller creates a status poller for the given Kubernetes client
+// with the custom status readers from CEL expressions, the Deployment
+// readiness thresholds and the custom job status reader.
+func (r *KustomizationReconciler) newStatusPoller(kubeClient client.Client,
+	mapper apimeta.RESTMapper,
+	readerCtors []func(apimeta.RESTMapper) engine.StatusReader,
+) *polling.StatusPoller {
 
-	readers := make([]engine.StatusReader, 0, 1+len(obj.Spec.HealthCheckExprs))
-	readers = append(readers, statusreaders.NewCustomJobStatusReader(r.Mapper))
-	if len(obj.Spec.HealthCheckExprs) > 0 {
-		readers = append(readers, readerCtor(r.Mapper))
+	readers := make([]engine.StatusReader, 0, 1+len(readerCtors))
+	readers = append(readers, statusreaders.NewCustomJobStatusReader(mapper))
+	for _, ctor := range readerCtors {
+		readers = append(readers, ctor(mapper))
 	}
 
-	poller := polling.NewStatusPoller(r.Client, r.Mapper, polling.Options{
+	return polling.NewStatusPoller(kubeClient, mapper, polling.Options{
 		CustomStatusReaders:  readers,
 		ClusterReaderFactory: r.ClusterReader,
 	})
-
-	return r.Client, poller
 }
 
 // getProviderRESTConfigFetcher returns a ProviderRESTConfigFetcher for the
@@ -1381,6 +2269,9 @@ func (r *KustomizationReconciler) getClientAndPoller(
 func (r *KustomizationReconciler) getProviderRESTConfigFetcher(obj *kustomizev1.Kustomization) runtimeClient.ProviderRESTConfigFetcher {
 	var provider runtimeClient.ProviderRESTConfigFetcher
 	if kc := obj.Spec.KubeConfig; kc != nil && kc.SecretRef == nil && kc.ConfigMapRef != nil {
+		if r.RefreshRemoteTokens {
+			return r.getRefreshingRESTConfigFetcher()
+		}
 		var opts []auth.Option
 		if r.TokenCache != nil {
 			involvedObject := cache.InvolvedObject{
@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -136,3 +137,27 @@ func TestKustomizationReconciler_deleteBeforeFinalizer(t *testing.T) {
 	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(kustomization)})
 	g.Expect(err).NotTo(HaveOccurred())
 }
+
+func TestJitteredRequeueAfter(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &kustomizev1.Kustomization{
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+
+	obj.Spec.IntervalJitterPercentage = ptr.To[int32](0)
+	g.Expect(jitteredRequeueAfter(obj)).To(Equal(10 * time.Minute))
+
+	obj.Spec.IntervalJitterPercentage = ptr.To[int32](20)
+	for range 100 {
+		g.Expect(jitteredRequeueAfter(obj)).To(And(
+			BeNumerically(">=", 8*time.Minute),
+			BeNumerically("<=", 12*time.Minute),
+		))
+	}
+
+	obj.Spec.Interval = metav1.Duration{}
+	g.Expect(jitteredRequeueAfter(obj)).To(BeZero())
+}
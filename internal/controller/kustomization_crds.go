@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// crdsKustomizationName returns the name of the Kustomization generated
+// to apply the CustomResourceDefinitions delegated by the Kustomization.
+func crdsKustomizationName(obj *kustomizev1.Kustomization) string {
+	return fmt.Sprintf("%s-crds", obj.GetName())
+}
+
+// isCRD returns true if the object is a CustomResourceDefinition.
+func isCRD(u *unstructured.Unstructured) bool {
+	gvk := u.GroupVersionKind()
+	return gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition"
+}
+
+// filterCRDs returns the objects applied by the Kustomization according to
+// its CRD policy, i.e. all the objects but the CustomResourceDefinitions for
+// Delegate, and only the CustomResourceDefinitions for Only.
+func filterCRDs(obj *kustomizev1.Kustomization, objects []*unstructured.Unstructured) []*unstructured.Unstructured {
+	switch obj.GetCRDPolicy() {
+	case kustomizev1.CRDPolicyDelegate:
+		return slices.DeleteFunc(objects, isCRD)
+	case kustomizev1.CRDPolicyOnly:
+		return slices.DeleteFunc(objects, func(u *unstructured.Unstructured) bool {
+			return !isCRD(u)
+		})
+	default:
+		return objects
+	}
+}
+
+// dependencyRefs returns the dependencies of the Kustomization, including
+// the Kustomization generated to apply its delegated CustomResourceDefinitions,
+// so that the custom resources are applied once their definitions are.
+func dependencyRefs(obj *kustomizev1.Kustomization) []kustomizev1.DependencyReference {
+	if obj.GetCRDPolicy() != kustomizev1.CRDPolicyDelegate {
+		return obj.Spec.DependsOn
+	}
+	return append(slices.Clone(obj.Spec.DependsOn),
+		kustomizev1.DependencyReference{Name: crdsKustomizationName(obj)})
+}
+
+// reconcileCRDsKustomization creates or updates the Kustomization which
+// applies the CustomResourceDefinitions delegated by the Kustomization. It
+// builds the same source and path, and impersonates the CRDServiceAccount
+// if set and allowed in the namespace of the Kustomization. The generated
+// Kustomization is owned by the delegating one, and orphans its objects on
+// deletion, so that the definitions, and with them all the custom resources
+// of the cluster, are not garbage collected with it.
+func (r *KustomizationReconciler) reconcileCRDsKustomization(ctx context.Context,
+	obj *kustomizev1.Kustomization) error {
+	serviceAccountName := ""
+	if slices.Contains(r.CRDServiceAccountNamespaces, obj.GetNamespace()) {
+		serviceAccountName = r.CRDServiceAccount
+	}
+
+	crds := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      crdsKustomizationName(obj),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, crds, func() error {
+		if crds.GetResourceVersion() != "" &&
+			crds.GetLabels()[kustomizev1.CRDsDelegatedByLabel] != obj.GetName() {
+			return fmt.Errorf("'%s' exists and is not generated for the CustomResourceDefinitions of '%s'",
+				crds.GetName(), obj.GetName())
+		}
+		if crds.Labels == nil {
+			crds.Labels = make(map[string]string)
+		}
+		crds.Labels[kustomizev1.CRDsDelegatedByLabel] = obj.GetName()
+		crds.Spec = crdsKustomizationSpec(obj, serviceAccountName)
+		return controllerutil.SetOwnerReference(obj, crds, r.Client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to generate the Kustomization of the CustomResourceDefinitions: %w", err)
+	}
+	return nil
+}
+
+// deleteCRDsKustomization deletes the Kustomization generated for the
+// CustomResourceDefinitions delegated by the Kustomization, if any. The
+// CustomResourceDefinitions are orphaned by the generated Kustomization.
+func (r *KustomizationReconciler) deleteCRDsKustomization(ctx context.Context,
+	obj *kustomizev1.Kustomization) error {
+	crds := &kustomizev1.Kustomization{}
+	key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: crdsKustomizationName(obj)}
+	if err := r.Get(ctx, key, crds); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if crds.GetLabels()[kustomizev1.CRDsDelegatedByLabel] != obj.GetName() ||
+		!slices.ContainsFunc(crds.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+			return ref.UID == obj.GetUID()
+		}) {
+		return nil
+	}
+	if err := r.Delete(ctx, crds); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete the Kustomization of the CustomResourceDefinitions: %w", err)
+	}
+	return nil
+}
+
+// crdsKustomizationSpec returns the spec of the Kustomization generated to
+// apply the CustomResourceDefinitions delegated by the given one, without
+// the settings specific to the custom resources and the tenant namespaces.
+func crdsKustomizationSpec(obj *kustomizev1.Kustomization, serviceAccountName string) kustomizev1.KustomizationSpec {
+	spec := *obj.Spec.DeepCopy()
+	spec.CRDPolicy = kustomizev1.CRDPolicyOnly
+	spec.DeletionPolicy = kustomizev1.DeletionPolicyOrphan
+	spec.DependsOn = nil
+	spec.Suspend = false
+	spec.TTL = nil
+	spec.ExpirationPolicy = ""
+	spec.Tenancy = nil
+	spec.ImagePullSecret = nil
+	spec.NamespaceLabels = nil
+	spec.HealthChecks = nil
+	spec.ExternalHealthChecks = nil
+	spec.HealthCheckExprs = nil
+	spec.HealthCheckThresholds = nil
+	spec.WaitSelector = nil
+	spec.Wait = true
+	if serviceAccountName != "" {
+		spec.ServiceAccountName = serviceAccountName
+	}
+	return spec
+}
+
+// retainDelegatedCRDs removes the CustomResourceDefinitions from the stale
+// objects of a Kustomization delegating them, as they are now applied by
+// the generated Kustomization.
+func retainDelegatedCRDs(obj *kustomizev1.Kustomization,
+	staleObjects []*unstructured.Unstructured) []*unstructured.Unstructured {
+	if obj.GetCRDPolicy() != kustomizev1.CRDPolicyDelegate {
+		return staleObjects
+	}
+	return slices.DeleteFunc(staleObjects, isCRD)
+}
+
+// isCRDDeletionConfirmed returns true if the Kustomization allows the garbage
+// collection of CustomResourceDefinitions, or if their deletion was confirmed
+// for the revision being reconciled.
+func isCRDDeletionConfirmed(obj *kustomizev1.Kustomization, revision string) bool {
+	if obj.Spec.AllowCRDDeletion {
+		return true
+	}
+	confirmed := obj.GetAnnotations()[kustomizev1.ConfirmCRDDeletionAnnotation]
+	return confirmed != "" && confirmed == revision
+}
+
+// blockCRDDeletion splits the stale objects into the ones to prune and the
+// CustomResourceDefinitions whose deletion was not confirmed, which are kept
+// in the inventory. It records the blocked CustomResourceDefinitions in the
+// CRDDeletionBlocked condition, and emits an event when they change.
+func (r *KustomizationReconciler) blockCRDDeletion(obj *kustomizev1.Kustomization,
+	revision string,
+	originRevision string,
+	objects []*unstructured.Unstructured) ([]*unstructured.Unstructured, []*unstructured.Unstructured) {
+	var blocked []*unstructured.Unstructured
+	if !isCRDDeletionConfirmed(obj, revision) {
+		for _, o := range objects {
+			if isCRD(o) {
+				blocked = append(blocked, o)
+			}
+		}
+	}
+	if len(blocked) == 0 {
+		conditions.Delete(obj, kustomizev1.CRDDeletionBlockedCondition)
+		return objects, nil
+	}
+
+	objects = slices.DeleteFunc(slices.Clone(objects), isCRD)
+	msg := fmt.Sprintf("skipping pruning of CustomResourceDefinitions pending confirmation of their deletion "+
+		"with the '%s' annotation set to '%s':\n%s",
+		kustomizev1.ConfirmCRDDeletionAnnotation, revision, ssautil.FmtUnstructuredList(blocked))
+	if conditions.IsTrue(obj, kustomizev1.CRDDeletionBlockedCondition) &&
+		conditions.GetMessage(obj, kustomizev1.CRDDeletionBlockedCondition) == msg {
+		return objects, blocked
+	}
+	conditions.MarkTrue(obj, kustomizev1.CRDDeletionBlockedCondition, kustomizev1.CRDDeletionNotConfirmedReason, "%s", msg)
+	r.event(obj, revision, originRevision, eventv1.EventSeverityError, msg, nil)
+	return objects, blocked
+}
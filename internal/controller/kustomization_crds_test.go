@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+const crdsTestManifests = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: apps
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: apps
+`
+
+func crdsTestObjectNames(objects []*unstructured.Unstructured) []string {
+	names := make([]string, 0, len(objects))
+	for _, u := range objects {
+		names = append(names, u.GetName())
+	}
+	return names
+}
+
+func TestFilterCRDs(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{policy: kustomizev1.CRDPolicyApply, want: []string{"widgets.example.com", "widget", "settings"}},
+		{policy: kustomizev1.CRDPolicyDelegate, want: []string{"widget", "settings"}},
+		{policy: kustomizev1.CRDPolicyOnly, want: []string{"widgets.example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			g := NewWithT(t)
+			objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(crdsTestManifests)))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			obj := &kustomizev1.Kustomization{}
+			obj.Spec.CRDPolicy = tt.policy
+			g.Expect(crdsTestObjectNames(filterCRDs(obj, objects))).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRetainDelegatedCRDs(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &kustomizev1.Kustomization{}
+	objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(crdsTestManifests)))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(retainDelegatedCRDs(obj, objects)).To(HaveLen(3))
+
+	obj.Spec.CRDPolicy = kustomizev1.CRDPolicyDelegate
+	g.Expect(crdsTestObjectNames(retainDelegatedCRDs(obj, objects))).To(Equal([]string{"widget", "settings"}))
+}
+
+func TestReconcileCRDsKustomization(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "app-uid"},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval:           metav1.Duration{Duration: 10 * time.Minute},
+			Path:               "./deploy",
+			Prune:              true,
+			ServiceAccountName: "tenant",
+			CRDPolicy:          kustomizev1.CRDPolicyDelegate,
+			DependsOn:          []kustomizev1.DependencyReference{{Name: "infra"}},
+			Wait:               false,
+			WaitSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "critical"}},
+			NamespaceLabels:    map[string]string{"team": "apps"},
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{
+				Kind: "GitRepository",
+				Name: "app",
+			},
+		},
+	}
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	r := &KustomizationReconciler{
+		Client:                      fake.NewClientBuilder().WithScheme(scheme).WithObjects(obj).Build(),
+		CRDServiceAccount:           "crd-manager",
+		CRDServiceAccountNamespaces: []string{"apps"},
+	}
+
+	// The delegating Kustomization depends on the generated one.
+	g.Expect(dependencyRefs(obj)).To(Equal([]kustomizev1.DependencyReference{
+		{Name: "infra"},
+		{Name: "app-crds"},
+	}))
+	g.Expect(obj.Spec.DependsOn).To(HaveLen(1))
+
+	g.Expect(r.reconcileCRDsKustomization(ctx, obj)).To(Succeed())
+	crds := &kustomizev1.Kustomization{}
+	g.Expect(r.Get(ctx, client.ObjectKey{Namespace: "apps", Name: "app-crds"}, crds)).To(Succeed())
+	g.Expect(crds.GetLabels()).To(HaveKeyWithValue(kustomizev1.CRDsDelegatedByLabel, "app"))
+	g.Expect(crds.GetOwnerReferences()).To(HaveLen(1))
+	g.Expect(crds.GetOwnerReferences()[0].UID).To(Equal(obj.GetUID()))
+	g.Expect(crds.Spec.CRDPolicy).To(Equal(kustomizev1.CRDPolicyOnly))
+	g.Expect(crds.Spec.DeletionPolicy).To(Equal(kustomizev1.DeletionPolicyOrphan))
+	g.Expect(crds.Spec.ServiceAccountName).To(Equal("crd-manager"))
+	g.Expect(crds.Spec.SourceRef).To(Equal(obj.Spec.SourceRef))
+	g.Expect(crds.Spec.Path).To(Equal("./deploy"))
+	g.Expect(crds.Spec.Prune).To(BeTrue())
+	g.Expect(crds.Spec.Wait).To(BeTrue())
+	g.Expect(crds.Spec.WaitSelector).To(BeNil())
+	g.Expect(crds.Spec.DependsOn).To(BeEmpty())
+	g.Expect(crds.Spec.NamespaceLabels).To(BeEmpty())
+	g.Expect(dependencyRefs(crds)).To(BeEmpty())
+
+	// The generated Kustomization follows the spec of the delegating one.
+	obj.Spec.Path = "./overlays/production"
+	g.Expect(r.reconcileCRDsKustomization(ctx, obj)).To(Succeed())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(crds), crds)).To(Succeed())
+	g.Expect(crds.Spec.Path).To(Equal("./overlays/production"))
+
+	// The CRD service account is impersonated only in the allowed namespaces.
+	r.CRDServiceAccountNamespaces = []string{"infra"}
+	g.Expect(r.reconcileCRDsKustomization(ctx, obj)).To(Succeed())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(crds), crds)).To(Succeed())
+	g.Expect(crds.Spec.ServiceAccountName).To(Equal("tenant"))
+
+	// The Kustomizations not generated for the delegating one are not overwritten.
+	other := obj.DeepCopy()
+	other.Name = "other"
+	other.ResourceVersion = ""
+	g.Expect(r.Create(ctx, other)).To(Succeed())
+	taken := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-crds", Namespace: "apps"},
+		Spec:       kustomizev1.KustomizationSpec{Path: "./crds"},
+	}
+	g.Expect(r.Create(ctx, taken)).To(Succeed())
+	err := r.reconcileCRDsKustomization(ctx, other)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("'other-crds' exists and is not generated"))
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(taken), taken)).To(Succeed())
+	g.Expect(taken.Spec.Path).To(Equal("./crds"))
+
+	// The Kustomizations not generated for the delegating one are not deleted.
+	g.Expect(r.deleteCRDsKustomization(ctx, other)).To(Succeed())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(taken), taken)).To(Succeed())
+
+	// The generated Kustomization is deleted when the CRDs are no longer delegated.
+	obj.Spec.CRDPolicy = kustomizev1.CRDPolicyApply
+	g.Expect(r.deleteCRDsKustomization(ctx, obj)).To(Succeed())
+	err = r.Get(ctx, client.ObjectKeyFromObject(crds), crds)
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(r.deleteCRDsKustomization(ctx, obj)).To(Succeed())
+}
+
+func TestBlockCRDDeletion(t *testing.T) {
+	g := NewWithT(t)
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{EventRecorder: recorder}
+	obj := &kustomizev1.Kustomization{}
+	revision := "main@sha1:abc"
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(crdsTestManifests)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The deletion of the stale CustomResourceDefinitions is blocked.
+	stale, blocked := r.blockCRDDeletion(obj, revision, "", objects)
+	g.Expect(crdsTestObjectNames(stale)).To(Equal([]string{"widget", "settings"}))
+	g.Expect(crdsTestObjectNames(blocked)).To(Equal([]string{"widgets.example.com"}))
+	g.Expect(objects).To(HaveLen(3))
+	g.Expect(conditions.IsTrue(obj, kustomizev1.CRDDeletionBlockedCondition)).To(BeTrue())
+	msg := conditions.GetMessage(obj, kustomizev1.CRDDeletionBlockedCondition)
+	g.Expect(msg).To(ContainSubstring("CustomResourceDefinition/widgets.example.com"))
+	g.Expect(msg).To(ContainSubstring("set to '" + revision + "'"))
+	g.Expect(recorder.Events).To(Receive(HavePrefix("Warning")))
+
+	// The event is emitted once for the same CustomResourceDefinitions.
+	_, blocked = r.blockCRDDeletion(obj, revision, "", objects)
+	g.Expect(blocked).To(HaveLen(1))
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The confirmation of a previous revision is ignored.
+	obj.SetAnnotations(map[string]string{kustomizev1.ConfirmCRDDeletionAnnotation: "main@sha1:old"})
+	_, blocked = r.blockCRDDeletion(obj, revision, "", objects)
+	g.Expect(blocked).To(HaveLen(1))
+
+	// The deletion is confirmed for the revision.
+	obj.SetAnnotations(map[string]string{kustomizev1.ConfirmCRDDeletionAnnotation: revision})
+	stale, blocked = r.blockCRDDeletion(obj, revision, "", objects)
+	g.Expect(stale).To(HaveLen(3))
+	g.Expect(blocked).To(BeEmpty())
+	g.Expect(conditions.Has(obj, kustomizev1.CRDDeletionBlockedCondition)).To(BeFalse())
+
+	// The deletion is allowed by the spec.
+	obj.SetAnnotations(nil)
+	obj.Spec.AllowCRDDeletion = true
+	stale, blocked = r.blockCRDDeletion(obj, revision, "", objects)
+	g.Expect(stale).To(HaveLen(3))
+	g.Expect(blocked).To(BeEmpty())
+}
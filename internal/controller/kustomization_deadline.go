@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// reconcileDeadlineKey is the context key of the reconcileDeadline.
+type reconcileDeadlineKey struct{}
+
+// reconcileDeadline is the time by which the reconciliation of a
+// Kustomization must complete, set from its spec.timeout.
+type reconcileDeadline struct {
+	timeout time.Duration
+	at      time.Time
+}
+
+// withReconcileDeadline returns a context carrying the deadline of the
+// reconciliation starting now with the given timeout. The deadline bounds
+// only the stages that opt in with stageContext or remainingTimeout, so
+// that the status can still be patched once it is exceeded.
+func withReconcileDeadline(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, reconcileDeadlineKey{}, reconcileDeadline{
+		timeout: timeout,
+		at:      time.Now().Add(timeout),
+	})
+}
+
+// stageContext returns a context expiring at the reconciliation deadline.
+func stageContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d, ok := ctx.Value(reconcileDeadlineKey{}).(reconcileDeadline); ok {
+		return context.WithDeadline(ctx, d.at)
+	}
+	return context.WithCancel(ctx)
+}
+
+// remainingTimeout returns the time left until the reconciliation deadline,
+// or the given timeout if the context carries no deadline.
+func remainingTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	if d, ok := ctx.Value(reconcileDeadlineKey{}).(reconcileDeadline); ok {
+		return time.Until(d.at)
+	}
+	return timeout
+}
+
+// deadlineExceededError reports the stage of the reconciliation
+// which was interrupted by the reconciliation deadline.
+type deadlineExceededError struct {
+	stage   string
+	timeout time.Duration
+	err     error
+}
+
+func (e *deadlineExceededError) Error() string {
+	return fmt.Sprintf("timeout of %s exceeded during %s: %s", e.timeout.String(), e.stage, e.err)
+}
+
+func (e *deadlineExceededError) Unwrap() error {
+	return e.err
+}
+
+// stageError wraps the error of the given stage in a deadlineExceededError
+// if the reconciliation deadline was exceeded.
+func stageError(ctx context.Context, stage string, err error) error {
+	d, ok := ctx.Value(reconcileDeadlineKey{}).(reconcileDeadline)
+	if !ok || err == nil {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) || !time.Now().Before(d.at) {
+		return &deadlineExceededError{stage: stage, timeout: d.timeout, err: err}
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestReconcileDeadline(t *testing.T) {
+	g := NewWithT(t)
+	errKMS := errors.New("kms unavailable")
+
+	// Without a deadline, the stages run with the full timeout.
+	ctx := context.Background()
+	g.Expect(remainingTimeout(ctx, time.Minute)).To(Equal(time.Minute))
+	g.Expect(stageError(ctx, "decryption", errKMS)).To(Equal(errKMS))
+
+	// Within the deadline, the errors are returned as is.
+	ctx = withReconcileDeadline(context.Background(), time.Hour)
+	g.Expect(remainingTimeout(ctx, time.Minute)).To(BeNumerically(">", time.Minute))
+	g.Expect(stageError(ctx, "decryption", errKMS)).To(Equal(errKMS))
+
+	// The errors caused by the deadline report the interrupted stage.
+	stageCtx, cancel := stageContext(withReconcileDeadline(context.Background(), 0))
+	defer cancel()
+	<-stageCtx.Done()
+	err := stageError(stageCtx, "decryption", stageCtx.Err())
+	g.Expect(err).To(MatchError(context.DeadlineExceeded))
+	g.Expect(err.Error()).To(Equal("timeout of 0s exceeded during decryption: context deadline exceeded"))
+
+	// The errors after the deadline are reported as interrupted.
+	ctx = withReconcileDeadline(context.Background(), time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	g.Expect(remainingTimeout(ctx, time.Minute)).To(BeNumerically("<=", 0))
+	err = stageError(ctx, "health checks", errors.New("timeout waiting for: [Deployment/apps/backend status: 'InProgress']"))
+	g.Expect(err.Error()).To(Equal("timeout of 1ms exceeded during health checks: " +
+		"timeout waiting for: [Deployment/apps/backend status: 'InProgress']"))
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+// recordDecryptionKeys records in status the Secret the decryption keys of
+// the build were imported from, with the time at which its resourceVersion
+// was first imported, so that the rotation of the keys is observable.
+func recordDecryptionKeys(ctx context.Context, obj *kustomizev1.Kustomization, keys *decryptor.KeysSecret) {
+	if keys == nil {
+		obj.Status.DecryptionKeys = nil
+		return
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	if keys.Cached {
+		log.Info("failed to read the decryption Secret, using the keys of its last version read",
+			"secret", keys.NamespacedName.String(), "resourceVersion", keys.ResourceVersion)
+	}
+
+	ref := meta.NamespacedObjectReference{Name: keys.Name, Namespace: keys.Namespace}
+	lastChange := metav1.Now()
+	if prev := obj.Status.DecryptionKeys; prev != nil && prev.SecretRef == ref {
+		if prev.ResourceVersion == keys.ResourceVersion {
+			lastChange = prev.LastChangeTime
+		} else {
+			log.Info("imported the decryption keys of a new version of the Secret",
+				"secret", keys.NamespacedName.String(), "resourceVersion", keys.ResourceVersion)
+		}
+	}
+	obj.Status.DecryptionKeys = &kustomizev1.DecryptionKeys{
+		SecretRef:       ref,
+		ResourceVersion: keys.ResourceVersion,
+		LastChangeTime:  lastChange,
+		Cached:          keys.Cached,
+	}
+}
+
+// checkDecryptionKeyExpiry sets the DecryptionKeyExpiring condition if one or
+// more of the imported decryption keys expire within the
+// DecryptionKeyExpiryWarning, and emits a warning event when the expiring
+// keys change, so that the keys are rotated before the decryption fails.
+func (r *KustomizationReconciler) checkDecryptionKeyExpiry(obj *kustomizev1.Kustomization,
+	expiries []decryptor.KeyExpiry) {
+	var expiring []string
+	if r.DecryptionKeyExpiryWarning > 0 {
+		now := time.Now()
+		for _, key := range expiries {
+			if key.Expires.After(now.Add(r.DecryptionKeyExpiryWarning)) {
+				continue
+			}
+			verb := "expires"
+			if !key.Expires.After(now) {
+				verb = "expired"
+			}
+			expiring = append(expiring, fmt.Sprintf("%s of '%s' %s at %s",
+				key.Key, key.Entry, verb, key.Expires.UTC().Format(time.RFC3339)))
+		}
+	}
+	if len(expiring) == 0 {
+		conditions.Delete(obj, kustomizev1.DecryptionKeyExpiringCondition)
+		return
+	}
+
+	slices.Sort(expiring)
+	msg := fmt.Sprintf("Decryption keys to rotate:\n%s", strings.Join(expiring, "\n"))
+	if conditions.IsTrue(obj, kustomizev1.DecryptionKeyExpiringCondition) &&
+		conditions.GetMessage(obj, kustomizev1.DecryptionKeyExpiringCondition) == msg {
+		return
+	}
+	conditions.MarkTrue(obj, kustomizev1.DecryptionKeyExpiringCondition, kustomizev1.DecryptionKeyExpiringReason, "%s", msg)
+	r.EventRecorder.Eventf(obj, corev1.EventTypeWarning, kustomizev1.DecryptionKeyExpiringReason, "%s", msg)
+}
+
+// checkDecryptionRecipients sets the DecryptionRecipientsMismatch condition
+// if one or more SOPS files are encrypted to keys not in the creation rules of
+// their .sops.yaml file, and emits a warning event when the files change, so
+// that the secrets encrypted only to personal keys are caught before the keys
+// of the cluster are rotated.
+func (r *KustomizationReconciler) checkDecryptionRecipients(obj *kustomizev1.Kustomization, mismatches []string) {
+	if len(mismatches) == 0 {
+		conditions.Delete(obj, kustomizev1.DecryptionRecipientsMismatchCondition)
+		return
+	}
+
+	msg := fmt.Sprintf("Files encrypted to recipients not in the .sops.yaml creation rules:\n%s",
+		strings.Join(mismatches, "\n"))
+	if conditions.IsTrue(obj, kustomizev1.DecryptionRecipientsMismatchCondition) &&
+		conditions.GetMessage(obj, kustomizev1.DecryptionRecipientsMismatchCondition) == msg {
+		return
+	}
+	conditions.MarkTrue(obj, kustomizev1.DecryptionRecipientsMismatchCondition, kustomizev1.RecipientsMismatchReason, "%s", msg)
+	r.EventRecorder.Eventf(obj, corev1.EventTypeWarning, kustomizev1.RecipientsMismatchReason, "%s", msg)
+}
+
+// checkDecryptionKeyGroups sets the DecryptionKeyGroupsUnsatisfied condition
+// with the SOPS key groups which can't be decrypted if the build failed to
+// recover a data key, and removes it once the build succeeds.
+func checkDecryptionKeyGroups(obj *kustomizev1.Kustomization, err error) {
+	if err == nil {
+		conditions.Delete(obj, kustomizev1.DecryptionKeyGroupsUnsatisfiedCondition)
+		return
+	}
+	var groupsErr *decryptor.KeyGroupsError
+	if !errors.As(err, &groupsErr) {
+		return
+	}
+	conditions.MarkTrue(obj, kustomizev1.DecryptionKeyGroupsUnsatisfiedCondition, kustomizev1.KeyGroupsUnsatisfiedReason,
+		"%d of %d required key groups satisfied, unsatisfied key groups:\n%s",
+		groupsErr.Satisfied, groupsErr.Required, strings.Join(groupsErr.Unsatisfied, "\n"))
+}
+
+// recordDecryptionKeyHashes records in status the hashes of the recipients
+// of the imported decryption keys, and emits an event when they change, so
+// that the Kustomizations still using the keys scheduled for rotation can be
+// audited without exposing the recipients.
+func (r *KustomizationReconciler) recordDecryptionKeyHashes(obj *kustomizev1.Kustomization, recipients []string) {
+	hashes := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		keyType, id, _ := strings.Cut(recipient, ":")
+		hashes = append(hashes, fmt.Sprintf("%s:sha256:%x", keyType, sha256.Sum256([]byte(id))))
+	}
+	slices.Sort(hashes)
+
+	prev := obj.Status.Decryption
+	var prevHashes []string
+	if prev != nil {
+		prevHashes = prev.KeyHashes
+	}
+	if slices.Equal(prevHashes, hashes) {
+		return
+	}
+	obj.Status.Decryption = nil
+	if len(hashes) > 0 {
+		obj.Status.Decryption = &kustomizev1.DecryptionStatus{
+			KeyHashes:      hashes,
+			LastChangeTime: metav1.Now(),
+		}
+	}
+	if prev == nil {
+		return
+	}
+
+	var changes []string
+	for _, h := range hashes {
+		if !slices.Contains(prevHashes, h) {
+			changes = append(changes, "+ "+h)
+		}
+	}
+	for _, h := range prevHashes {
+		if !slices.Contains(hashes, h) {
+			changes = append(changes, "- "+h)
+		}
+	}
+	r.EventRecorder.Eventf(obj, corev1.EventTypeNormal, kustomizev1.DecryptionKeysChangedReason,
+		"Decryption keys changed:\n%s", strings.Join(changes, "\n"))
+}
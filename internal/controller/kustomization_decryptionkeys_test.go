@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+func TestRecordDecryptionKeys(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	obj := &kustomizev1.Kustomization{}
+	secret := types.NamespacedName{Namespace: "apps", Name: "sops-keys"}
+
+	recordDecryptionKeys(ctx, obj, &decryptor.KeysSecret{NamespacedName: secret, ResourceVersion: "1"})
+	g.Expect(obj.Status.DecryptionKeys).ToNot(BeNil())
+	g.Expect(obj.Status.DecryptionKeys.SecretRef.Name).To(Equal("sops-keys"))
+	g.Expect(obj.Status.DecryptionKeys.SecretRef.Namespace).To(Equal("apps"))
+	g.Expect(obj.Status.DecryptionKeys.ResourceVersion).To(Equal("1"))
+	g.Expect(obj.Status.DecryptionKeys.Cached).To(BeFalse())
+
+	// The change time is kept while the resourceVersion is the same.
+	firstChange := metav1.NewTime(time.Now().Add(-time.Hour))
+	obj.Status.DecryptionKeys.LastChangeTime = firstChange
+	recordDecryptionKeys(ctx, obj, &decryptor.KeysSecret{NamespacedName: secret, ResourceVersion: "1", Cached: true})
+	g.Expect(obj.Status.DecryptionKeys.LastChangeTime).To(Equal(firstChange))
+	g.Expect(obj.Status.DecryptionKeys.Cached).To(BeTrue())
+
+	// The change time is updated when the keys are rotated.
+	recordDecryptionKeys(ctx, obj, &decryptor.KeysSecret{NamespacedName: secret, ResourceVersion: "2"})
+	g.Expect(obj.Status.DecryptionKeys.ResourceVersion).To(Equal("2"))
+	g.Expect(obj.Status.DecryptionKeys.LastChangeTime.After(firstChange.Time)).To(BeTrue())
+	g.Expect(obj.Status.DecryptionKeys.Cached).To(BeFalse())
+
+	// The status is cleared when the keys are not imported from a Secret.
+	recordDecryptionKeys(ctx, obj, nil)
+	g.Expect(obj.Status.DecryptionKeys).To(BeNil())
+}
+
+func TestCheckDecryptionKeyExpiry(t *testing.T) {
+	g := NewWithT(t)
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{
+		EventRecorder:              recorder,
+		DecryptionKeyExpiryWarning: 14 * 24 * time.Hour,
+	}
+	obj := &kustomizev1.Kustomization{}
+	expiries := []decryptor.KeyExpiry{
+		{Key: "pgp:AAAA", Entry: "sops.asc", Expires: time.Now().Add(90 * 24 * time.Hour)},
+	}
+
+	// The keys expiring after the warning period are ignored.
+	r.checkDecryptionKeyExpiry(obj, expiries)
+	g.Expect(conditions.Has(obj, kustomizev1.DecryptionKeyExpiringCondition)).To(BeFalse())
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	expiries = append(expiries,
+		decryptor.KeyExpiry{Key: "aws_kms:AKIA", Entry: "sops.aws-kms", Expires: time.Now().Add(-time.Hour)},
+		decryptor.KeyExpiry{Key: "pgp:BBBB", Entry: "sops.asc", Expires: time.Now().Add(7 * 24 * time.Hour)},
+	)
+	r.checkDecryptionKeyExpiry(obj, expiries)
+	g.Expect(conditions.IsTrue(obj, kustomizev1.DecryptionKeyExpiringCondition)).To(BeTrue())
+	msg := conditions.GetMessage(obj, kustomizev1.DecryptionKeyExpiringCondition)
+	g.Expect(msg).To(ContainSubstring("aws_kms:AKIA of 'sops.aws-kms' expired at"))
+	g.Expect(msg).To(ContainSubstring("pgp:BBBB of 'sops.asc' expires at"))
+	g.Expect(msg).ToNot(ContainSubstring("pgp:AAAA"))
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix("Warning "+kustomizev1.DecryptionKeyExpiringReason),
+		ContainSubstring("pgp:BBBB"),
+	)))
+
+	// The warning is emitted once for the same keys.
+	r.checkDecryptionKeyExpiry(obj, expiries)
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The condition is removed once the keys are rotated.
+	r.checkDecryptionKeyExpiry(obj, expiries[:1])
+	g.Expect(conditions.Has(obj, kustomizev1.DecryptionKeyExpiringCondition)).To(BeFalse())
+
+	// The check is disabled with a zero warning period.
+	r.DecryptionKeyExpiryWarning = 0
+	r.checkDecryptionKeyExpiry(obj, expiries)
+	g.Expect(conditions.Has(obj, kustomizev1.DecryptionKeyExpiringCondition)).To(BeFalse())
+}
+
+func TestCheckDecryptionRecipients(t *testing.T) {
+	g := NewWithT(t)
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{EventRecorder: recorder}
+	obj := &kustomizev1.Kustomization{}
+
+	r.checkDecryptionRecipients(obj, nil)
+	g.Expect(conditions.Has(obj, kustomizev1.DecryptionRecipientsMismatchCondition)).To(BeFalse())
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	mismatches := []string{"secret.yaml: encrypted to keys not in the creation rule of .sops.yaml: age:age1dev"}
+	r.checkDecryptionRecipients(obj, mismatches)
+	g.Expect(conditions.IsTrue(obj, kustomizev1.DecryptionRecipientsMismatchCondition)).To(BeTrue())
+	g.Expect(conditions.GetMessage(obj, kustomizev1.DecryptionRecipientsMismatchCondition)).To(ContainSubstring(mismatches[0]))
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix("Warning "+kustomizev1.RecipientsMismatchReason),
+		ContainSubstring("age:age1dev"),
+	)))
+
+	// The warning is emitted once for the same files.
+	r.checkDecryptionRecipients(obj, mismatches)
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The condition is removed once the files are re-encrypted.
+	r.checkDecryptionRecipients(obj, nil)
+	g.Expect(conditions.Has(obj, kustomizev1.DecryptionRecipientsMismatchCondition)).To(BeFalse())
+}
+
+func TestCheckDecryptionKeyGroups(t *testing.T) {
+	g := NewWithT(t)
+	obj := &kustomizev1.Kustomization{}
+
+	err := decryptionFailed(context.Background(), fmt.Errorf("cannot get sops data key: %w", &decryptor.KeyGroupsError{
+		Required:    2,
+		Satisfied:   1,
+		Unsatisfied: []string{"1 (age:age1aaaa)", "2 (pgp:BBBB)"},
+	}))
+	checkDecryptionKeyGroups(obj, err)
+	g.Expect(conditions.IsTrue(obj, kustomizev1.DecryptionKeyGroupsUnsatisfiedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, kustomizev1.DecryptionKeyGroupsUnsatisfiedCondition)).To(Equal(kustomizev1.KeyGroupsUnsatisfiedReason))
+	g.Expect(conditions.GetMessage(obj, kustomizev1.DecryptionKeyGroupsUnsatisfiedCondition)).To(Equal(
+		"1 of 2 required key groups satisfied, unsatisfied key groups:\n1 (age:age1aaaa)\n2 (pgp:BBBB)"))
+
+	// The condition is kept on the failures of the build unrelated to the key groups.
+	checkDecryptionKeyGroups(obj, errors.New("kustomize build failed"))
+	g.Expect(conditions.IsTrue(obj, kustomizev1.DecryptionKeyGroupsUnsatisfiedCondition)).To(BeTrue())
+
+	// The condition is removed once the build succeeds.
+	checkDecryptionKeyGroups(obj, nil)
+	g.Expect(conditions.Has(obj, kustomizev1.DecryptionKeyGroupsUnsatisfiedCondition)).To(BeFalse())
+}
+
+func TestRecordDecryptionKeyHashes(t *testing.T) {
+	g := NewWithT(t)
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{EventRecorder: recorder}
+	obj := &kustomizev1.Kustomization{}
+	ageHash := "age:sha256:" + fmt.Sprintf("%x", sha256.Sum256([]byte("age1abc")))
+	pgpHash := "pgp:sha256:" + fmt.Sprintf("%x", sha256.Sum256([]byte("0A1B")))
+
+	// The first recording emits no event.
+	r.recordDecryptionKeyHashes(obj, []string{"pgp:0A1B", "age:age1abc"})
+	g.Expect(obj.Status.Decryption).ToNot(BeNil())
+	g.Expect(obj.Status.Decryption.KeyHashes).To(Equal([]string{ageHash, pgpHash}))
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The change time is kept while the keys are the same.
+	firstChange := metav1.NewTime(time.Now().Add(-time.Hour))
+	obj.Status.Decryption.LastChangeTime = firstChange
+	r.recordDecryptionKeyHashes(obj, []string{"age:age1abc", "pgp:0A1B"})
+	g.Expect(obj.Status.Decryption.LastChangeTime).To(Equal(firstChange))
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The rotation of the keys is reported.
+	r.recordDecryptionKeyHashes(obj, []string{"age:age1def", "pgp:0A1B"})
+	g.Expect(obj.Status.Decryption.LastChangeTime.After(firstChange.Time)).To(BeTrue())
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix("Normal "+kustomizev1.DecryptionKeysChangedReason),
+		ContainSubstring("+ age:sha256:"+fmt.Sprintf("%x", sha256.Sum256([]byte("age1def")))),
+		ContainSubstring("- "+ageHash),
+	)))
+
+	// The status is cleared when no key is imported.
+	r.recordDecryptionKeyHashes(obj, nil)
+	g.Expect(obj.Status.Decryption).To(BeNil())
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("- " + pgpHash)))
+}
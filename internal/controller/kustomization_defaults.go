@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// errNamespaceNotFound is returned by getDefaultServiceAccount when the
+// namespace of the Kustomization is gone and no controller-level default
+// service account is configured, in which case the service account set
+// with an annotation on the namespace can't be known.
+var errNamespaceNotFound = errors.New("namespace not found and no default service account configured")
+
+// getDefaultServiceAccount returns the service account to impersonate when
+// the Kustomization does not specify one. The default set with an annotation
+// on the Kustomization namespace takes precedence over the controller-level
+// default service account. The controller-level default is used when the
+// namespace is gone, so that the finalization is not blocked by the namespace
+// deletion. If there is no controller-level default, errNamespaceNotFound is
+// returned instead of falling back to the controller identity.
+func (r *KustomizationReconciler) getDefaultServiceAccount(ctx context.Context,
+	obj *kustomizev1.Kustomization) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, ns); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get namespace '%s': %w", obj.GetNamespace(), err)
+		}
+		if r.DefaultServiceAccount == "" {
+			return "", fmt.Errorf("%w: '%s'", errNamespaceNotFound, obj.GetNamespace())
+		}
+		return r.DefaultServiceAccount, nil
+	}
+
+	if sa := ns.GetAnnotations()[kustomizev1.DefaultServiceAccountAnnotation]; sa != "" {
+		return sa, nil
+	}
+	return r.DefaultServiceAccount, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestGetDefaultServiceAccount(t *testing.T) {
+	scheme := runtime.NewScheme()
+	NewWithT(t).Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	annotated := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "apps",
+			Annotations: map[string]string{kustomizev1.DefaultServiceAccountAnnotation: "tenant"},
+		},
+	}
+	failingGet := interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return errors.New("connection refused")
+		},
+	}
+
+	tests := []struct {
+		name      string
+		objects   []client.Object
+		funcs     interceptor.Funcs
+		deleting  bool
+		noDefault bool
+		want      string
+		wantErr   bool
+	}{
+		{name: "namespace annotation", objects: []client.Object{annotated}, want: "tenant"},
+		{name: "namespace not found", want: "default"},
+		{name: "namespace not found without default", noDefault: true, wantErr: true},
+		{name: "read failure", funcs: failingGet, wantErr: true},
+		{name: "read failure during deletion", funcs: failingGet, deleting: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := &KustomizationReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).
+					WithObjects(tt.objects...).WithInterceptorFuncs(tt.funcs).Build(),
+				DefaultServiceAccount: "default",
+			}
+			if tt.noDefault {
+				r.DefaultServiceAccount = ""
+			}
+			obj := &kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"}}
+			if tt.deleting {
+				now := metav1.Now()
+				obj.DeletionTimestamp = &now
+			}
+
+			sa, err := r.getDefaultServiceAccount(context.Background(), obj)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(sa).To(Equal(tt.want))
+		})
+	}
+}
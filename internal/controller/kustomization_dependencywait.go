@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// dependencyWait tracks a Kustomization waiting for its dependencies.
+type dependencyWait struct {
+	start    time.Time
+	timedOut bool
+}
+
+// waitForDependencies records that the dependencies of the Kustomization
+// are not ready. Instead of an event on every retry, an event is emitted
+// when the wait starts, and a warning when it exceeds DependencyWaitTimeout.
+func (r *KustomizationReconciler) waitForDependencies(obj *kustomizev1.Kustomization,
+	revision, originRevision string, reason error) {
+	v, loaded := r.dependencyWaits.LoadOrStore(client.ObjectKeyFromObject(obj).String(),
+		&dependencyWait{start: time.Now()})
+	if !loaded {
+		r.event(obj, revision, originRevision, eventv1.EventSeverityInfo,
+			fmt.Sprintf("Waiting for dependencies: %s, retrying every %s",
+				reason, r.DependencyRequeueInterval.String()), nil)
+		return
+	}
+
+	// The reconciliations of a Kustomization never run concurrently.
+	wait := v.(*dependencyWait)
+	waited := time.Since(wait.start)
+	if r.DependencyWaitTimeout > 0 && !wait.timedOut && waited >= r.DependencyWaitTimeout {
+		wait.timedOut = true
+		r.event(obj, revision, originRevision, eventv1.EventSeverityError,
+			fmt.Sprintf("Dependencies are not ready after waiting %s: %s",
+				waited.Round(time.Second).String(), reason), nil)
+	}
+}
+
+// endDependencyWait emits an event with the duration of the wait if the
+// Kustomization was waiting for its dependencies, and clears the record.
+func (r *KustomizationReconciler) endDependencyWait(obj *kustomizev1.Kustomization,
+	revision, originRevision string) {
+	v, ok := r.dependencyWaits.LoadAndDelete(client.ObjectKeyFromObject(obj).String())
+	if !ok {
+		return
+	}
+	r.event(obj, revision, originRevision, eventv1.EventSeverityInfo,
+		fmt.Sprintf("Dependencies are ready after waiting %s",
+			time.Since(v.(*dependencyWait).start).Round(time.Second).String()), nil)
+}
+
+// deleteDependencyWait removes the dependency wait record of the Kustomization.
+func (r *KustomizationReconciler) deleteDependencyWait(obj *kustomizev1.Kustomization) {
+	r.dependencyWaits.Delete(client.ObjectKeyFromObject(obj).String())
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestDependencyWaitEvents(t *testing.T) {
+	g := NewWithT(t)
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{
+		EventRecorder:             recorder,
+		DependencyRequeueInterval: 30 * time.Second,
+		DependencyWaitTimeout:     time.Hour,
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+	}
+	notReady := errors.New("dependency 'apps/infra' is not ready")
+
+	// An event is emitted when the wait starts.
+	r.waitForDependencies(obj, "main@sha1:abc", "", notReady)
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix("Normal"),
+		ContainSubstring("Waiting for dependencies: dependency 'apps/infra' is not ready, retrying every 30s"),
+	)))
+
+	// The retries don't emit events.
+	r.waitForDependencies(obj, "main@sha1:abc", "", notReady)
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// A warning is emitted once when the wait times out.
+	v, ok := r.dependencyWaits.Load(client.ObjectKeyFromObject(obj).String())
+	g.Expect(ok).To(BeTrue())
+	v.(*dependencyWait).start = time.Now().Add(-2 * time.Hour)
+	r.waitForDependencies(obj, "main@sha1:abc", "", notReady)
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix("Warning"),
+		ContainSubstring("Dependencies are not ready after waiting 2h0m0s"),
+	)))
+	r.waitForDependencies(obj, "main@sha1:abc", "", notReady)
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// An event with the wait duration is emitted when the dependencies are ready.
+	r.endDependencyWait(obj, "main@sha1:abc", "")
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix("Normal"),
+		ContainSubstring("Dependencies are ready after waiting 2h0m0s"),
+	)))
+	r.endDependencyWait(obj, "main@sha1:abc", "")
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// A new wait emits the start event again.
+	r.waitForDependencies(obj, "main@sha1:def", "", notReady)
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("Waiting for dependencies")))
+}
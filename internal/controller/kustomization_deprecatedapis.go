@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// deprecatedAPIVersion describes an API version removed from Kubernetes.
+type deprecatedAPIVersion struct {
+	// removedIn is the Kubernetes version which stopped serving the API version.
+	removedIn string
+
+	// replacement is the API version replacing the removed one, if any.
+	replacement string
+
+	// lossless is true if both versions share the same schema, in which case
+	// the objects are converted by changing their apiVersion only.
+	lossless bool
+}
+
+// deprecatedAPIVersions lists the API versions of the builtin kinds
+// removed from Kubernetes, by kind.
+var deprecatedAPIVersions = map[schema.GroupVersionKind]deprecatedAPIVersion{
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:        {"v1.16", "apps/v1", false},
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:         {"v1.16", "apps/v1", false},
+	{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:        {"v1.16", "apps/v1", false},
+	{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:     {"v1.16", "networking.k8s.io/v1", false},
+	{Group: "extensions", Version: "v1beta1", Kind: "PodSecurityPolicy"}: {"v1.16", "", false},
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:           {"v1.22", "networking.k8s.io/v1", false},
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:              {"v1.16", "apps/v1", false},
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:             {"v1.16", "apps/v1", false},
+	{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:              {"v1.16", "apps/v1", false},
+	{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:             {"v1.16", "apps/v1", false},
+	{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:               {"v1.16", "apps/v1", false},
+	{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:              {"v1.16", "apps/v1", false},
+
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}:                                   {"v1.22", "networking.k8s.io/v1", false},
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass"}:                              {"v1.22", "networking.k8s.io/v1", true},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "Role"}:                              {"v1.22", "rbac.authorization.k8s.io/v1", true},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRole"}:                       {"v1.22", "rbac.authorization.k8s.io/v1", true},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "RoleBinding"}:                       {"v1.22", "rbac.authorization.k8s.io/v1", true},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRoleBinding"}:                {"v1.22", "rbac.authorization.k8s.io/v1", true},
+	{Group: "scheduling.k8s.io", Version: "v1beta1", Kind: "PriorityClass"}:                             {"v1.22", "scheduling.k8s.io/v1", true},
+	{Group: "coordination.k8s.io", Version: "v1beta1", Kind: "Lease"}:                                   {"v1.22", "coordination.k8s.io/v1", true},
+	{Group: "apiregistration.k8s.io", Version: "v1beta1", Kind: "APIService"}:                           {"v1.22", "apiregistration.k8s.io/v1", true},
+	{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}:               {"v1.22", "apiextensions.k8s.io/v1", false},
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration"}:   {"v1.22", "admissionregistration.k8s.io/v1", false},
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration"}: {"v1.22", "admissionregistration.k8s.io/v1", false},
+	{Group: "certificates.k8s.io", Version: "v1beta1", Kind: "CertificateSigningRequest"}:               {"v1.22", "certificates.k8s.io/v1", false},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIDriver"}:                                    {"v1.22", "storage.k8s.io/v1", true},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSINode"}:                                      {"v1.22", "storage.k8s.io/v1", true},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass"}:                                 {"v1.22", "storage.k8s.io/v1", true},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "VolumeAttachment"}:                             {"v1.22", "storage.k8s.io/v1", true},
+
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"}:                       {"v1.25", "batch/v1", true},
+	{Group: "discovery.k8s.io", Version: "v1beta1", Kind: "EndpointSlice"}:      {"v1.25", "discovery.k8s.io/v1", false},
+	{Group: "events.k8s.io", Version: "v1beta1", Kind: "Event"}:                 {"v1.25", "events.k8s.io/v1", false},
+	{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"}: {"v1.25", "autoscaling/v2", false},
+	{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}:          {"v1.25", "policy/v1", false},
+	{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"}:            {"v1.25", "", false},
+	{Group: "node.k8s.io", Version: "v1beta1", Kind: "RuntimeClass"}:            {"v1.25", "node.k8s.io/v1", true},
+
+	{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler"}:                     {"v1.26", "autoscaling/v2", true},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema"}:                 {"v1.26", "flowcontrol.apiserver.k8s.io/v1", false},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "PriorityLevelConfiguration"}: {"v1.26", "flowcontrol.apiserver.k8s.io/v1", false},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}:                       {"v1.27", "storage.k8s.io/v1", true},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema"}:                 {"v1.29", "flowcontrol.apiserver.k8s.io/v1", false},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration"}: {"v1.29", "flowcontrol.apiserver.k8s.io/v1", false},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}:                 {"v1.32", "flowcontrol.apiserver.k8s.io/v1", false},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}: {"v1.32", "flowcontrol.apiserver.k8s.io/v1", false},
+}
+
+// migrateDeprecatedAPIVersions looks up the objects of the build using the
+// API versions removed from Kubernetes, and checks with the REST mapper of
+// the target cluster whether it still serves them. It returns a warning for
+// each object using such a version, unless the object is rewritten to the
+// replacement version, which happens if requested with the Rewrite policy,
+// the cluster no longer serves the version and the conversion is lossless.
+// The rewrites are recorded in the Kustomization status, and the manifests
+// are returned re-encoded if any object was rewritten.
+func migrateDeprecatedAPIVersions(obj *kustomizev1.Kustomization,
+	mapper apimeta.RESTMapper,
+	resources []byte) ([]byte, []string, error) {
+	obj.Status.APIVersionRewrites = nil
+	if mapper == nil {
+		return resources, nil, nil
+	}
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader(resources))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rewrite := obj.Spec.BuildOptions != nil &&
+		obj.Spec.BuildOptions.DeprecatedAPIVersions == kustomizev1.DeprecatedAPIVersionsRewrite
+
+	// served returns false only if the cluster is known to not serve the version.
+	served := func(gvk schema.GroupVersionKind) bool {
+		_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		return !apimeta.IsNoMatchError(err)
+	}
+
+	var warnings []string
+	var rewrites []kustomizev1.APIVersionRewrite
+	for _, u := range objects {
+		gvk := u.GroupVersionKind()
+		dep, ok := deprecatedAPIVersions[gvk]
+		if !ok {
+			continue
+		}
+
+		subject := ssautil.FmtUnstructured(u)
+		apiVersion := gvk.GroupVersion().String()
+		if served(gvk) {
+			warnings = append(warnings, fmt.Sprintf("%s: %s is deprecated and removed in Kubernetes %s%s",
+				subject, apiVersion, dep.removedIn, replacementHint(dep)))
+			continue
+		}
+
+		if rewrite && dep.lossless {
+			replacement, err := schema.ParseGroupVersion(dep.replacement)
+			if err == nil && served(replacement.WithKind(gvk.Kind)) {
+				u.SetAPIVersion(dep.replacement)
+				rewrites = append(rewrites, kustomizev1.APIVersionRewrite{
+					Object: subject,
+					From:   apiVersion,
+					To:     dep.replacement,
+				})
+				continue
+			}
+		}
+		warnings = append(warnings, fmt.Sprintf("%s: %s is no longer served by the cluster, it was removed in Kubernetes %s%s",
+			subject, apiVersion, dep.removedIn, replacementHint(dep)))
+	}
+
+	if len(rewrites) == 0 {
+		return resources, warnings, nil
+	}
+	obj.Status.APIVersionRewrites = rewrites
+
+	manifests, err := ssautil.ObjectsToYAML(objects)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(manifests), warnings, nil
+}
+
+// replacementHint returns the hint to use the replacement version, if any.
+func replacementHint(dep deprecatedAPIVersion) string {
+	if dep.replacement == "" {
+		return ", without replacement"
+	}
+	return fmt.Sprintf(", use %s", dep.replacement)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestMigrateDeprecatedAPIVersions(t *testing.T) {
+	g := NewWithT(t)
+
+	resources := []byte(`
+apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  namespace: apps
+---
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+  namespace: apps
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+  namespace: apps
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: apps
+`)
+
+	// The cluster serves batch/v1beta1 but not autoscaling/v2beta2
+	// nor networking.k8s.io/v1beta1.
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"},
+		{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+		{Group: "batch", Version: "v1beta1", Kind: "CronJob"},
+		{Group: "batch", Version: "v1", Kind: "CronJob"},
+		{Group: "", Version: "v1", Kind: "ConfigMap"},
+	} {
+		mapper.Add(gvk, apimeta.RESTScopeNamespace)
+	}
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+	}
+
+	out, warnings, err := migrateDeprecatedAPIVersions(obj, mapper, resources)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(out).To(Equal(resources))
+	g.Expect(obj.Status.APIVersionRewrites).To(BeEmpty())
+	g.Expect(warnings).To(ConsistOf(
+		"HorizontalPodAutoscaler/apps/web: autoscaling/v2beta2 is no longer served by the cluster, it was removed in Kubernetes v1.26, use autoscaling/v2",
+		"Ingress/apps/web: networking.k8s.io/v1beta1 is no longer served by the cluster, it was removed in Kubernetes v1.22, use networking.k8s.io/v1",
+		"CronJob/apps/backup: batch/v1beta1 is deprecated and removed in Kubernetes v1.25, use batch/v1",
+	))
+
+	obj.Spec.BuildOptions = &kustomizev1.BuildOptions{
+		DeprecatedAPIVersions: kustomizev1.DeprecatedAPIVersionsRewrite,
+	}
+	out, warnings, err = migrateDeprecatedAPIVersions(obj, mapper, resources)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(warnings).To(HaveLen(2))
+	g.Expect(obj.Status.APIVersionRewrites).To(Equal([]kustomizev1.APIVersionRewrite{{
+		Object: "HorizontalPodAutoscaler/apps/web",
+		From:   "autoscaling/v2beta2",
+		To:     "autoscaling/v2",
+	}}))
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader(out))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(4))
+	g.Expect(objects[0].GetAPIVersion()).To(Equal("autoscaling/v2"))
+	g.Expect(objects[1].GetAPIVersion()).To(Equal("networking.k8s.io/v1beta1"))
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// drainContext returns a context that is canceled once the drain timeout has
+// elapsed after the cancellation of the given context, allowing the in-flight
+// applies to complete and their progress to be persisted when the controller
+// shuts down. If the drain timeout is not set, the given context is returned.
+func (r *KustomizationReconciler) drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.ShutdownDrainTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	drainCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(r.ShutdownDrainTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-drainCtx.Done():
+		}
+	})
+	return drainCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// checkpointShutdown records in status that the revision was applied but its
+// health checks were interrupted by the controller shutdown. The health
+// checks run again when the Kustomization is reconciled after the restart.
+func checkpointShutdown(ctx context.Context, obj *kustomizev1.Kustomization, revision string) error {
+	msg := fmt.Sprintf("Reconciliation interrupted by controller shutdown after applying revision %s", revision)
+	conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationInterruptedReason, "%s", msg)
+	conditions.MarkReconciling(obj, kustomizev1.ReconciliationInterruptedReason, "%s", msg)
+	return fmt.Errorf("%s: %w", msg, ctx.Err())
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// EnqueueFilter decides whether the change of an object referenced by a
+// Kustomization, i.e. its source, a ConfigMap or Secret it depends on, or
+// a Kustomization it includes, enqueues the reconciliation of the
+// Kustomization. Custom filters are registered with the
+// KustomizationReconcilerOptions, e.g. to hold back some Kustomizations
+// during freeze windows, and run after the built-in filters.
+type EnqueueFilter interface {
+	// Enqueue returns false if the change of the trigger object
+	// must not enqueue the reconciliation of the Kustomization.
+	Enqueue(ctx context.Context, obj *kustomizev1.Kustomization, trigger client.Object) bool
+}
+
+// EnqueueFilterFunc is an EnqueueFilter implemented by a function.
+type EnqueueFilterFunc func(ctx context.Context, obj *kustomizev1.Kustomization, trigger client.Object) bool
+
+// Enqueue calls f(ctx, obj, trigger).
+func (f EnqueueFilterFunc) Enqueue(ctx context.Context, obj *kustomizev1.Kustomization, trigger client.Object) bool {
+	return f(ctx, obj, trigger)
+}
+
+// filterEnqueue returns the Kustomizations whose reconciliation is accepted
+// by the given built-in filters and by the filters of the reconciler.
+func (r *KustomizationReconciler) filterEnqueue(ctx context.Context,
+	trigger client.Object,
+	items []kustomizev1.Kustomization,
+	filters ...EnqueueFilter) []*kustomizev1.Kustomization {
+	filters = append(filters, r.enqueueFilters...)
+	accepted := make([]*kustomizev1.Kustomization, 0, len(items))
+	for i := range items {
+		obj := &items[i]
+		enqueue := true
+		for _, filter := range filters {
+			if !filter.Enqueue(ctx, obj, trigger) {
+				enqueue = false
+				break
+			}
+		}
+		if enqueue {
+			accepted = append(accepted, obj)
+		}
+	}
+	return accepted
+}
+
+// revisionChangeFilter skips the Kustomizations which are ready or
+// reconciling and already attempted the revision of the artifact.
+func revisionChangeFilter(artifact *meta.Artifact) EnqueueFilter {
+	return EnqueueFilterFunc(func(_ context.Context, obj *kustomizev1.Kustomization, _ client.Object) bool {
+		return !(conditions.IsReady(obj) || conditions.IsReconciling(obj)) ||
+			!artifact.HasRevision(obj.Status.LastAttemptedRevision)
+	})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestRequestsForRevisionChangeOf_EnqueueFilters(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	const indexGitRepository = ".metadata.gitRepository"
+
+	repo := &sourcev1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "apps"},
+		Status: sourcev1.GitRepositoryStatus{
+			Artifact: &meta.Artifact{Revision: "main@sha1:new"},
+		},
+	}
+	newKustomization := func(name, revision string, labels map[string]string) *kustomizev1.Kustomization {
+		obj := &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps", Labels: labels},
+			Spec: kustomizev1.KustomizationSpec{
+				SourceRef: kustomizev1.CrossNamespaceSourceReference{
+					Kind: sourcev1.GitRepositoryKind,
+					Name: "repo",
+				},
+			},
+			Status: kustomizev1.KustomizationStatus{LastAttemptedRevision: revision},
+		}
+		conditions.MarkTrue(obj, meta.ReadyCondition, meta.ReconciliationSucceededReason, "ok")
+		return obj
+	}
+	stale := newKustomization("stale", "main@sha1:old", nil)
+	current := newKustomization("current", "main@sha1:new", nil)
+	canary := newKustomization("canary", "main@sha1:old", map[string]string{"canary": "true"})
+
+	scheme := runtime.NewScheme()
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(sourcev1.AddToScheme(scheme)).To(Succeed())
+	r := &KustomizationReconciler{}
+	r.Client = fake.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&kustomizev1.Kustomization{}, indexGitRepository, r.indexBy(sourcev1.GitRepositoryKind)).
+		WithObjects(repo, stale, current, canary).Build()
+
+	// The built-in filter skips the Kustomizations which attempted the revision.
+	reqs := r.requestsForRevisionChangeOf(indexGitRepository)(ctx, repo)
+	g.Expect(reqs).To(ConsistOf(
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "apps", Name: "stale"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "apps", Name: "canary"}},
+	))
+
+	// The custom filters run after the built-in ones.
+	var filtered []string
+	r.enqueueFilters = []EnqueueFilter{
+		EnqueueFilterFunc(func(_ context.Context, obj *kustomizev1.Kustomization, trigger client.Object) bool {
+			g.Expect(trigger.GetName()).To(Equal("repo"))
+			filtered = append(filtered, obj.GetName())
+			return obj.GetLabels()["canary"] != "true"
+		}),
+	}
+	reqs = r.requestsForRevisionChangeOf(indexGitRepository)(ctx, repo)
+	g.Expect(reqs).To(ConsistOf(
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "apps", Name: "stale"}},
+	))
+	g.Expect(filtered).To(ConsistOf("stale", "canary"))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"maps"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ephemeralPodTemplatePaths holds, for the kinds of the ephemeral objects,
+// the paths to the metadata labels of the pod templates they create.
+var ephemeralPodTemplatePaths = map[string][][]string{
+	"batch/Job": {
+		{"spec", "template", "metadata", "labels"},
+	},
+	"batch/CronJob": {
+		{"spec", "jobTemplate", "metadata", "labels"},
+		{"spec", "jobTemplate", "spec", "template", "metadata", "labels"},
+	},
+	"/Pod": nil,
+}
+
+// setEphemeralObjectLabels sets the given labels on the ephemeral objects,
+// i.e. the Jobs, CronJobs and Pods, and on the templates of the Jobs and
+// Pods they create, so that they are excluded from the cluster backups.
+// The labels already set in the manifests take precedence.
+func setEphemeralObjectLabels(objects []*unstructured.Unstructured, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	for _, u := range objects {
+		gk := u.GroupVersionKind().GroupKind()
+		paths, ok := ephemeralPodTemplatePaths[gk.Group+"/"+gk.Kind]
+		if !ok {
+			continue
+		}
+
+		u.SetLabels(mergeLabels(labels, u.GetLabels()))
+		for _, path := range paths {
+			existing, _, err := unstructured.NestedStringMap(u.Object, path...)
+			if err != nil {
+				return err
+			}
+			if err := unstructured.SetNestedStringMap(u.Object, mergeLabels(labels, existing), path...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergeLabels returns the given defaults overridden by the existing labels.
+func mergeLabels(defaults, existing map[string]string) map[string]string {
+	merged := maps.Clone(defaults)
+	maps.Copy(merged, existing)
+	return merged
+}
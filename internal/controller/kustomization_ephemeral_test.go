@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+)
+
+func TestSetEphemeralObjectLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  namespace: apps
+spec:
+  template:
+    spec:
+      containers:
+      - name: migrate
+        image: migrate
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+  namespace: apps
+  labels:
+    velero.io/exclude-from-backup: "false"
+spec:
+  schedule: "@daily"
+  jobTemplate:
+    spec:
+      template:
+        metadata:
+          labels:
+            app: cleanup
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: hook
+  namespace: apps
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+`)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	labels := map[string]string{"velero.io/exclude-from-backup": "true"}
+	g.Expect(setEphemeralObjectLabels(objects, labels)).To(Succeed())
+
+	byKind := make(map[string]*unstructured.Unstructured)
+	for _, u := range objects {
+		byKind[u.GetKind()] = u
+	}
+
+	job := byKind["Job"]
+	g.Expect(job.GetLabels()).To(Equal(labels))
+	template, _, _ := unstructured.NestedStringMap(job.Object, "spec", "template", "metadata", "labels")
+	g.Expect(template).To(Equal(labels))
+
+	// The labels set in the manifests take precedence.
+	cronJob := byKind["CronJob"]
+	g.Expect(cronJob.GetLabels()).To(HaveKeyWithValue("velero.io/exclude-from-backup", "false"))
+	jobTemplate, _, _ := unstructured.NestedStringMap(cronJob.Object, "spec", "jobTemplate", "metadata", "labels")
+	g.Expect(jobTemplate).To(Equal(labels))
+	template, _, _ = unstructured.NestedStringMap(cronJob.Object, "spec", "jobTemplate", "spec", "template", "metadata", "labels")
+	g.Expect(template).To(Equal(map[string]string{
+		"app":                           "cleanup",
+		"velero.io/exclude-from-backup": "true",
+	}))
+
+	g.Expect(byKind["Pod"].GetLabels()).To(Equal(labels))
+	g.Expect(byKind["Deployment"].GetLabels()).To(BeEmpty())
+
+	// Without labels, the objects are left untouched.
+	g.Expect(setEphemeralObjectLabels(objects, nil)).To(Succeed())
+}
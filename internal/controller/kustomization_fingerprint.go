@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	generator "github.com/fluxcd/pkg/kustomize"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// buildFingerprintInputs are the inputs of a build accounted for in its
+// fingerprint.
+type buildFingerprintInputs struct {
+	Source    string                        `json:"source"`
+	Spec      kustomizev1.KustomizationSpec `json:"spec"`
+	Variables map[string]string             `json:"variables,omitempty"`
+	Includes  map[string]string             `json:"includes,omitempty"`
+}
+
+// buildFingerprint returns the digest of the inputs of the build of the
+// Kustomization: the digest of the source artifact, the spec fields applied
+// to the manifests, the post build variables and the digests of the builds
+// exported by the included Kustomizations. The variables are only hashed,
+// as they may be read from Secrets.
+func (r *KustomizationReconciler) buildFingerprint(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	sourceDigest string,
+	u unstructured.Unstructured) (string, error) {
+	spec := obj.Spec
+	inputs := buildFingerprintInputs{
+		Source: sourceDigest,
+		Spec: kustomizev1.KustomizationSpec{
+			CommonMetadata:          spec.CommonMetadata,
+			NamespaceLabels:         spec.NamespaceLabels,
+			Decryption:              spec.Decryption,
+			Path:                    spec.Path,
+			NamePrefix:              spec.NamePrefix,
+			NameSuffix:              spec.NameSuffix,
+			Patches:                 spec.Patches,
+			Images:                  spec.Images,
+			ImagePullSecret:         spec.ImagePullSecret,
+			Tenancy:                 spec.Tenancy,
+			TargetNamespace:         spec.TargetNamespace,
+			BuildMetadata:           spec.BuildMetadata,
+			BuildOptions:            spec.BuildOptions,
+			Components:              spec.Components,
+			IgnoreMissingComponents: spec.IgnoreMissingComponents,
+			Include:                 spec.Include,
+			CRDPolicy:               spec.CRDPolicy,
+		},
+	}
+
+	if spec.PostBuild != nil {
+		inputs.Spec.PostBuild = &kustomizev1.PostBuild{
+			SubstituteStrategy: spec.PostBuild.SubstituteStrategy,
+		}
+		u, err := r.withDefaultSubstitutions(ctx, u)
+		if err != nil {
+			return "", err
+		}
+		vars, err := generator.LoadVariables(ctx, r.Client, u)
+		if err != nil {
+			return "", fmt.Errorf("post build failed: %w", err)
+		}
+		inline, _, err := unstructured.NestedStringMap(u.Object, "spec", "postBuild", "substitute")
+		if err != nil {
+			return "", fmt.Errorf("post build failed: %w", err)
+		}
+		for k, v := range inline {
+			vars[k] = strings.ReplaceAll(v, "\n", "")
+		}
+		inputs.Variables = vars
+	}
+
+	for _, ref := range spec.Include {
+		key := includeKey(obj, ref)
+		var included kustomizev1.Kustomization
+		if err := r.Get(ctx, key, &included); err != nil {
+			return "", fmt.Errorf("failed to get included Kustomization '%s': %w", key, err)
+		}
+		if export := included.Status.ExportedBuild; export != nil {
+			if inputs.Includes == nil {
+				inputs.Includes = make(map[string]string, len(spec.Include))
+			}
+			inputs.Includes[key.String()] = export.Digest
+		}
+	}
+
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(data).String(), nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/kustomize"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestBuildFingerprint(t *testing.T) {
+	g := NewWithT(t)
+
+	vars := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "vars", Namespace: "apps"},
+		Data:       map[string]string{"cluster": "prod"},
+	}
+	kubeClient := fake.NewClientBuilder().WithObjects(vars).Build()
+	r := &KustomizationReconciler{Client: kubeClient}
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: time.Minute},
+			Path:     "./deploy",
+			PostBuild: &kustomizev1.PostBuild{
+				Substitute:     map[string]string{"region": "eu"},
+				SubstituteFrom: []kustomizev1.SubstituteReference{{Kind: "ConfigMap", Name: "vars"}},
+			},
+		},
+	}
+	fingerprint := func(obj *kustomizev1.Kustomization, sourceDigest string) string {
+		k, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		fp, err := r.buildFingerprint(context.TODO(), obj, sourceDigest, unstructured.Unstructured{Object: k})
+		g.Expect(err).ToNot(HaveOccurred())
+		return fp
+	}
+
+	initial := fingerprint(obj, "sha256:aaa")
+	g.Expect(initial).To(HavePrefix("sha256:"))
+	g.Expect(fingerprint(obj, "sha256:aaa")).To(Equal(initial))
+	g.Expect(fingerprint(obj, "sha256:bbb")).ToNot(Equal(initial))
+
+	// The fields not applied to the manifests are ignored.
+	other := obj.DeepCopy()
+	other.Spec.Interval = metav1.Duration{Duration: time.Hour}
+	other.Spec.Prune = true
+	g.Expect(fingerprint(other, "sha256:aaa")).To(Equal(initial))
+
+	other = obj.DeepCopy()
+	other.Spec.Patches = []kustomize.Patch{{Patch: "- op: remove\n  path: /spec/replicas"}}
+	g.Expect(fingerprint(other, "sha256:aaa")).ToNot(Equal(initial))
+
+	other = obj.DeepCopy()
+	other.Spec.PostBuild.Substitute["region"] = "us"
+	g.Expect(fingerprint(other, "sha256:aaa")).ToNot(Equal(initial))
+
+	// The values of the variables read from the cluster are accounted for.
+	vars.Data["cluster"] = "staging"
+	g.Expect(kubeClient.Update(context.TODO(), vars)).To(Succeed())
+	g.Expect(fingerprint(obj, "sha256:aaa")).ToNot(Equal(initial))
+}
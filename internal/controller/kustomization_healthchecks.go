@@ -0,0 +1,48 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/fluxcd/cli-utils/pkg/object"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+// healthCheckObjects returns the objects to health check: the applied objects
+// when spec.wait is enabled, or the objects referenced in spec.healthChecks
+// otherwise. The objects referenced in spec.externalHealthChecks are checked
+// in both cases, as they are not managed by the Kustomization.
+func healthCheckObjects(obj *kustomizev1.Kustomization,
+	applied object.ObjMetadataSet) (object.ObjMetadataSet, error) {
+	objects := applied
+	if !obj.Spec.Wait {
+		var err error
+		objects, err = inventory.ReferenceToObjMetadataSet(obj.Spec.HealthChecks)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(obj.Spec.ExternalHealthChecks) == 0 {
+		return objects, nil
+	}
+	external, err := inventory.ReferenceToObjMetadataSet(obj.Spec.ExternalHealthChecks)
+	if err != nil {
+		return nil, err
+	}
+	return objects.Union(external), nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestHealthCheckObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	applied := object.ObjMetadataSet{{
+		GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		Namespace: "apps",
+		Name:      "podinfo",
+	}}
+	obj := &kustomizev1.Kustomization{
+		Spec: kustomizev1.KustomizationSpec{
+			HealthChecks: []meta.NamespacedObjectKindReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "podinfo", Namespace: "apps"},
+			},
+			ExternalHealthChecks: []meta.NamespacedObjectKindReference{
+				{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "node-agent", Namespace: "kube-system"},
+			},
+		},
+	}
+
+	objects, err := healthCheckObjects(obj, applied)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+	g.Expect(objects[0].Name).To(Equal("podinfo"))
+	g.Expect(objects[1].Name).To(Equal("node-agent"))
+
+	// With wait enabled, the applied objects are checked along with the external ones.
+	obj.Spec.Wait = true
+	applied = append(applied, object.ObjMetadata{
+		GroupKind: schema.GroupKind{Kind: "Service"},
+		Namespace: "apps",
+		Name:      "podinfo",
+	})
+	objects, err = healthCheckObjects(obj, applied)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(3))
+	g.Expect(objects[2].GroupKind.Kind).To(Equal("DaemonSet"))
+
+	obj.Spec.ExternalHealthChecks = nil
+	objects, err = healthCheckObjects(obj, applied)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(Equal(applied))
+
+	obj.Spec.ExternalHealthChecks = []meta.NamespacedObjectKindReference{
+		{APIVersion: "apps/v1/beta", Kind: "DaemonSet", Name: "node-agent"},
+	}
+	_, err = healthCheckObjects(obj, applied)
+	g.Expect(err).To(HaveOccurred())
+}
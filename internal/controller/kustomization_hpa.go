@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// hpaReplicasPath is the JSON pointer of the field managed by
+// HorizontalPodAutoscalers on their scale targets.
+const hpaReplicasPath = "/spec/replicas"
+
+// hpaDriftIgnoreRules returns drift ignore rules for the spec.replicas field
+// of the objects targeted by a HorizontalPodAutoscaler, so that the replicas
+// set by the autoscaler are not reverted to the value from the manifests.
+// It also returns the objects for which the replicas reported by the
+// autoscaler differ from the desired replicas, i.e. the objects for which
+// drift correction was skipped. The autoscalers are read with the given
+// reader, which is backed by the shared cache for the local cluster.
+// Failures to look up the autoscalers are logged and result in no rules, as
+// the lookup must not prevent the objects from being applied.
+func (r *KustomizationReconciler) hpaDriftIgnoreRules(ctx context.Context,
+	reader client.Reader,
+	objects []*unstructured.Unstructured) ([]jsondiff.IgnoreRule, []string) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Index the namespaced objects that declare the replicas field.
+	candidates := make(map[string]*unstructured.Unstructured)
+	for _, u := range objects {
+		if u.GetNamespace() == "" {
+			continue
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "replicas"); !found {
+			continue
+		}
+		gvk := u.GroupVersionKind()
+		candidates[hpaTargetKey(u.GetNamespace(), gvk.Group, gvk.Kind, u.GetName())] = u
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	namespaces := make(map[string]struct{})
+	for _, u := range candidates {
+		namespaces[u.GetNamespace()] = struct{}{}
+	}
+
+	var rules []jsondiff.IgnoreRule
+	var skipped []string
+	for ns := range namespaces {
+		hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+		if err := reader.List(ctx, hpaList, client.InNamespace(ns)); err != nil {
+			log.Info("skipping HPA replicas drift detection, failed to list HorizontalPodAutoscalers",
+				"namespace", ns, "error", err.Error())
+			continue
+		}
+
+		for _, hpa := range hpaList.Items {
+			ref := hpa.Spec.ScaleTargetRef
+			gv, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil {
+				continue
+			}
+			u, ok := candidates[hpaTargetKey(ns, gv.Group, ref.Kind, ref.Name)]
+			if !ok {
+				continue
+			}
+
+			rules = append(rules, jsondiff.IgnoreRule{
+				Paths: []string{hpaReplicasPath},
+				Selector: &jsondiff.Selector{
+					Group:     regexp.QuoteMeta(gv.Group),
+					Kind:      regexp.QuoteMeta(u.GetKind()),
+					Name:      regexp.QuoteMeta(u.GetName()),
+					Namespace: regexp.QuoteMeta(ns),
+				},
+			})
+
+			desired, _, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "replicas")
+			if hpa.Status.CurrentReplicas > 0 && fmt.Sprint(desired) != fmt.Sprint(hpa.Status.CurrentReplicas) {
+				skipped = append(skipped, ssautil.FmtUnstructured(u))
+			}
+		}
+	}
+
+	sort.Strings(skipped)
+	return rules, skipped
+}
+
+// recordHPAReplicasDrift emits an event when the objects for which the
+// drift correction of spec.replicas was skipped change, instead of on every
+// reconciliation while the replicas set by the autoscalers differ.
+func (r *KustomizationReconciler) recordHPAReplicasDrift(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	revision, originRevision string,
+	skipped []string) {
+	key := client.ObjectKeyFromObject(obj).String()
+	if len(skipped) == 0 {
+		r.hpaReplicasDrifts.Delete(key)
+		return
+	}
+
+	msg := fmt.Sprintf("Skipped drift correction of spec.replicas for objects scaled by HorizontalPodAutoscalers:\n%s",
+		strings.Join(skipped, "\n"))
+	if prev, loaded := r.hpaReplicasDrifts.Swap(key, msg); loaded && prev.(string) == msg {
+		return
+	}
+	ctrl.LoggerFrom(ctx).Info(msg, "revision", revision)
+	r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+}
+
+// deleteHPAReplicasDrift forgets the objects for which the drift correction
+// of spec.replicas was skipped for the given Kustomization.
+func (r *KustomizationReconciler) deleteHPAReplicasDrift(obj *kustomizev1.Kustomization) {
+	r.hpaReplicasDrifts.Delete(client.ObjectKeyFromObject(obj).String())
+}
+
+func hpaTargetKey(namespace, group, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", namespace, group, kind, name)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+const hpaTestManifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+  namespace: apps
+spec:
+  replicas: 2
+`
+
+func TestHPADriftIgnoreRules(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "web",
+			},
+			MaxReplicas: 5,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 3},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(hpa).Build()
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{EventRecorder: recorder}
+	obj := &kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"}}
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(hpaTestManifests)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Only the replicas of the autoscaled Deployment are ignored.
+	rules, skipped := r.hpaDriftIgnoreRules(ctx, reader, objects)
+	g.Expect(rules).To(HaveLen(1))
+	g.Expect(rules[0].Paths).To(Equal([]string{hpaReplicasPath}))
+	g.Expect(rules[0].Selector.Name).To(Equal("web"))
+	g.Expect(skipped).To(Equal([]string{"Deployment/apps/web"}))
+
+	// The event is emitted when the skipped objects change.
+	r.recordHPAReplicasDrift(ctx, obj, "rev", "", skipped)
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("Deployment/apps/web")))
+	r.recordHPAReplicasDrift(ctx, obj, "rev", "", skipped)
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The event is emitted again after the replicas converged.
+	r.recordHPAReplicasDrift(ctx, obj, "rev", "", nil)
+	r.recordHPAReplicasDrift(ctx, obj, "rev", "", skipped)
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("Deployment/apps/web")))
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/fluxcd/pkg/apis/kustomize"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// imageDigestKey identifies a digest resolved with the
+// registry credentials of a namespace.
+type imageDigestKey struct {
+	namespace string
+	secret    string
+	image     string
+}
+
+// imageDigest is a resolved digest and its expiry time.
+type imageDigest struct {
+	digest  string
+	expires time.Time
+}
+
+// resolveImageDigests returns the images of the Kustomization with the
+// digests of the images listed in spec.imageDigestResolution, looked up with
+// the registry API, and records the resolutions in the status. The digests are
+// reused for the interval of the Kustomization, so that the registry is not
+// queried on every reconciliation. The returned images are meant for the
+// generated kustomization.yaml only, the spec of the Kustomization is left
+// unchanged.
+func (r *KustomizationReconciler) resolveImageDigests(ctx context.Context,
+	obj *kustomizev1.Kustomization) ([]kustomize.Image, error) {
+	images := slices.Clone(obj.Spec.Images)
+	dr := obj.Spec.ImageDigestResolution
+	if dr == nil {
+		obj.Status.ResolvedImages = nil
+		return images, nil
+	}
+	for _, imgName := range dr.Images {
+		if !slices.ContainsFunc(images, func(img kustomize.Image) bool { return img.Name == imgName }) {
+			return nil, fmt.Errorf("image '%s' of the digest resolution is not listed in spec.images", imgName)
+		}
+	}
+
+	var resolved []kustomizev1.ResolvedImage
+	for i, img := range images {
+		if !slices.Contains(dr.Images, img.Name) || img.Digest != "" {
+			continue
+		}
+
+		repository := img.NewName
+		if repository == "" {
+			repository = img.Name
+		}
+		if img.NewTag == "" {
+			return nil, fmt.Errorf("newTag of image '%s' must be set to resolve the digest", img.Name)
+		}
+		ref, err := name.NewTag(repository + ":" + img.NewTag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image reference '%s:%s': %w", repository, img.NewTag, err)
+		}
+
+		key := imageDigestKey{namespace: obj.GetNamespace(), image: ref.String()}
+		if dr.SecretRef != nil {
+			key.secret = dr.SecretRef.Name
+		}
+		if v, ok := r.imageDigests.Load(key); ok && time.Now().Before(v.(imageDigest).expires) {
+			images[i].Digest = v.(imageDigest).digest
+		} else {
+			auth := authn.Anonymous
+			if dr.SecretRef != nil {
+				auth, err = r.imageRegistryAuth(ctx, obj.GetNamespace(), dr.SecretRef.Name, ref.RegistryStr())
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			desc, err := remote.Head(ref, remote.WithContext(ctx), remote.WithAuth(auth))
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve the digest of image '%s': %w", ref.String(), err)
+			}
+
+			images[i].Digest = desc.Digest.String()
+			r.imageDigests.Store(key, imageDigest{
+				digest:  images[i].Digest,
+				expires: time.Now().Add(obj.Spec.Interval.Duration),
+			})
+		}
+		resolved = append(resolved, kustomizev1.ResolvedImage{
+			Image:  ref.String(),
+			Digest: images[i].Digest,
+		})
+	}
+
+	obj.Status.ResolvedImages = resolved
+	return images, nil
+}
+
+// imageRegistryAuth returns the credentials of the registry
+// from the given Secret of type 'kubernetes.io/dockerconfigjson'.
+func (r *KustomizationReconciler) imageRegistryAuth(ctx context.Context,
+	namespace, secretName, registry string) (authn.Authenticator, error) {
+	secretKey := types.NamespacedName{Namespace: namespace, Name: secretName}
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretKey, &secret); err != nil {
+		return nil, fmt.Errorf("unable to read image pull secret '%s' error: %w", secretKey.String(), err)
+	}
+
+	data, ok := secret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return nil, fmt.Errorf("image pull secret '%s' does not contain a '%s' key", secretKey, corev1.DockerConfigJsonKey)
+	}
+	var config struct {
+		Auths map[string]authn.AuthConfig `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse image pull secret '%s' error: %w", secretKey, err)
+	}
+
+	for host, auth := range config.Auths {
+		if registryHost(host) == registryHost(registry) {
+			return authn.FromConfig(auth), nil
+		}
+	}
+	return authn.Anonymous, nil
+}
+
+// registryHost returns the host of a registry address of a docker config,
+// e.g. 'index.docker.io' for 'https://index.docker.io/v1/', with Docker Hub
+// aliases normalized.
+func registryHost(address string) string {
+	host := address
+	if u, err := url.Parse(address); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host, _, _ = strings.Cut(host, "/")
+	if host == "docker.io" || host == "registry-1.docker.io" {
+		return name.DefaultRegistry
+	}
+	return host
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/kustomize"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestResolveImageDigests(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	img, err := random.Image(1024, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	ref, err := name.NewTag(host + "/podinfo:6.7.0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remote.Write(ref, img)).To(Succeed())
+	digest, err := img.Digest()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "regcred", Namespace: "apps"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"http://` + host + `/v2/":{"username":"flux","password":"secret"}}}`),
+		},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(secret).Build(),
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: time.Minute},
+			Images: []kustomize.Image{
+				{
+					Name:    "podinfo",
+					NewName: host + "/podinfo",
+					NewTag:  "6.7.0",
+				},
+				{
+					Name:   "flagger",
+					NewTag: "1.40.0",
+				},
+			},
+			ImageDigestResolution: &kustomizev1.ImageDigestResolution{
+				Images:    []string{"podinfo"},
+				SecretRef: &meta.LocalObjectReference{Name: "regcred"},
+			},
+		},
+	}
+
+	images, err := r.resolveImageDigests(context.Background(), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(images[0].Digest).To(Equal(digest.String()))
+	g.Expect(images[1].Digest).To(BeEmpty())
+	g.Expect(obj.Spec.Images[0].Digest).To(BeEmpty())
+	g.Expect(obj.Status.ResolvedImages).To(Equal([]kustomizev1.ResolvedImage{{
+		Image:  ref.String(),
+		Digest: digest.String(),
+	}}))
+
+	// The digest is reused within the interval even if the tag moved.
+	moved, err := random.Image(1024, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remote.Write(ref, moved)).To(Succeed())
+	images, err = r.resolveImageDigests(context.Background(), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(images[0].Digest).To(Equal(digest.String()))
+
+	r.imageDigests.Clear()
+	movedDigest, err := moved.Digest()
+	g.Expect(err).ToNot(HaveOccurred())
+	images, err = r.resolveImageDigests(context.Background(), obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(images[0].Digest).To(Equal(movedDigest.String()))
+
+	obj.Spec.Images[0].NewTag = ""
+	_, err = r.resolveImageDigests(context.Background(), obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("newTag of image 'podinfo' must be set"))
+
+	obj.Spec.Images[0].NewTag = "missing"
+	_, err = r.resolveImageDigests(context.Background(), obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to resolve the digest"))
+
+	obj.Spec.ImageDigestResolution.SecretRef.Name = "unknown"
+	_, err = r.resolveImageDigests(context.Background(), obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unable to read image pull secret"))
+
+	obj.Spec.ImageDigestResolution.Images = []string{"missing"}
+	_, err = r.resolveImageDigests(context.Background(), obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("image 'missing' of the digest resolution is not listed"))
+}
+
+func TestImageRegistryAuth(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "regcred", Namespace: "apps"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"https://index.docker.io/v1/":{"username":"flux","password":"secret"}}}`),
+		},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(secret).Build(),
+	}
+
+	auth, err := r.imageRegistryAuth(context.Background(), "apps", "regcred", "docker.io")
+	g.Expect(err).ToNot(HaveOccurred())
+	cfg, err := auth.Authorization()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cfg.Username).To(Equal("flux"))
+	g.Expect(cfg.Password).To(Equal("secret"))
+
+	auth, err = r.imageRegistryAuth(context.Background(), "apps", "regcred", "ghcr.io")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(auth).To(Equal(authn.Anonymous))
+}
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -137,6 +138,24 @@ data:
 		g.Expect(readyCondition.Message).To(ContainSubstring("system:serviceaccount:%s:default", id))
 	})
 
+	t.Run("fails to reconcile impersonating the namespace default service account", func(t *testing.T) {
+		ns := &corev1.Namespace{}
+		g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: id}, ns)).To(Succeed())
+		patch := client.MergeFrom(ns.DeepCopy())
+		ns.SetAnnotations(map[string]string{kustomizev1.DefaultServiceAccountAnnotation: "tenant"})
+		g.Expect(k8sClient.Patch(context.Background(), ns, patch)).To(Succeed())
+
+		revision = "v2.1.0"
+		err = applyGitRepository(repositoryName, artifact, revision)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			readyCondition = apimeta.FindStatusCondition(resultK.Status.Conditions, meta.ReadyCondition)
+			return readyCondition.Reason == meta.ReconciliationFailedReason &&
+				strings.Contains(readyCondition.Message, "tenant")
+		}, timeout, time.Second).Should(BeTrue())
+	})
 	t.Run("reconciles impersonating service account", func(t *testing.T) {
 		sa := corev1.ServiceAccount{
 			TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,338 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/opencontainers/go-digest"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/acl"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// indexInclude is the index of the Kustomizations
+// by the Kustomizations they include.
+const indexInclude = ".metadata.include"
+
+// exportSecretKey is the data key of the export Secret
+// holding the gzipped multi-doc YAML of the built manifests.
+const exportSecretKey = "manifests.yaml.gz"
+
+// includeDir is the directory, relative to the path of the Kustomization,
+// where the manifests of the included Kustomizations are written.
+const includeDir = ".flux-include"
+
+// exportSecretName returns the name of the Secret holding
+// the exported build of the Kustomization.
+func exportSecretName(obj *kustomizev1.Kustomization) string {
+	return fmt.Sprintf("%s-export", obj.GetName())
+}
+
+// includeKey returns the key of the Kustomization included with the given
+// reference, defaulting to the namespace of the including Kustomization.
+func includeKey(obj *kustomizev1.Kustomization, ref meta.NamespacedObjectReference) types.NamespacedName {
+	namespace := obj.GetNamespace()
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+// indexByInclude returns the keys of the Kustomizations included by the given one.
+func indexByInclude(o client.Object) []string {
+	obj, ok := o.(*kustomizev1.Kustomization)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Kustomization, got %T", o))
+	}
+	keys := make([]string, 0, len(obj.Spec.Include))
+	for _, ref := range obj.Spec.Include {
+		keys = append(keys, includeKey(obj, ref).String())
+	}
+	return keys
+}
+
+// injectIncludes writes the manifests exported by the Kustomizations
+// referenced in spec.include to files in the build directory, and adds
+// them to the resources of the kustomization.yaml.
+func (r *KustomizationReconciler) injectIncludes(ctx context.Context,
+	obj *kustomizev1.Kustomization, dirPath string) error {
+	if len(obj.Spec.Include) == 0 {
+		return nil
+	}
+
+	if err := r.checkIncludeCycle(ctx, obj); err != nil {
+		return err
+	}
+
+	var files []string
+	for _, ref := range obj.Spec.Include {
+		key := includeKey(obj, ref)
+		if r.NoCrossNamespaceRefs && key.Namespace != obj.GetNamespace() {
+			return acl.AccessDeniedError(
+				fmt.Sprintf("can't access '%s/%s', cross-namespace references have been blocked",
+					kustomizev1.KustomizationKind, key))
+		}
+
+		manifests, err := r.readExportedBuild(ctx, obj, key)
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(includeDir, fmt.Sprintf("%s-%s.yaml", key.Namespace, key.Name))
+		if err := os.MkdirAll(filepath.Join(dirPath, includeDir), 0o700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dirPath, file), manifests, 0o600); err != nil {
+			return fmt.Errorf("failed to write the manifests of the included Kustomization '%s': %w", key, err)
+		}
+		files = append(files, filepath.ToSlash(file))
+	}
+
+	return editKustomizationFile(dirPath, func(kus map[string]any) error {
+		resources, _ := kus["resources"].([]any)
+		for _, file := range files {
+			resources = append(resources, file)
+		}
+		kus["resources"] = resources
+		return nil
+	})
+}
+
+// checkIncludeCycle returns an error if the Kustomization
+// includes itself, directly or through other Kustomizations.
+func (r *KustomizationReconciler) checkIncludeCycle(ctx context.Context,
+	obj *kustomizev1.Kustomization) error {
+	self := client.ObjectKeyFromObject(obj)
+	visited := make(map[types.NamespacedName]struct{})
+	var visit func(k *kustomizev1.Kustomization) error
+	visit = func(k *kustomizev1.Kustomization) error {
+		for _, ref := range k.Spec.Include {
+			key := includeKey(k, ref)
+			if key == self {
+				return fmt.Errorf("circular include detected: Kustomization '%s' includes itself through '%s'",
+					self, client.ObjectKeyFromObject(k))
+			}
+			if _, ok := visited[key]; ok {
+				continue
+			}
+			visited[key] = struct{}{}
+
+			var included kustomizev1.Kustomization
+			if err := r.Get(ctx, key, &included); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("failed to get included Kustomization '%s': %w", key, err)
+			}
+			if err := visit(&included); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(obj)
+}
+
+// exportAllowed returns true if the export policy of the Kustomization
+// allows the Kustomizations of the given namespace to include its build.
+func exportAllowed(obj *kustomizev1.Kustomization, namespace string) bool {
+	if namespace == obj.GetNamespace() {
+		return true
+	}
+	if obj.Spec.Export == nil {
+		return false
+	}
+	return slices.ContainsFunc(obj.Spec.Export.AllowedNamespaces, func(ns string) bool {
+		return ns == "*" || ns == namespace
+	})
+}
+
+// readExportedBuild returns the manifests exported by the Kustomization
+// with the given key, if its export policy allows the given Kustomization
+// to include them.
+func (r *KustomizationReconciler) readExportedBuild(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	key types.NamespacedName) ([]byte, error) {
+	var included kustomizev1.Kustomization
+	if err := r.Get(ctx, key, &included); err != nil {
+		return nil, fmt.Errorf("failed to get included Kustomization '%s': %w", key, err)
+	}
+	if !exportAllowed(&included, obj.GetNamespace()) {
+		return nil, acl.AccessDeniedError(
+			fmt.Sprintf("can't include '%s/%s', the namespace '%s' is not allowed by its export policy",
+				kustomizev1.KustomizationKind, key, obj.GetNamespace()))
+	}
+	export := included.Status.ExportedBuild
+	if export == nil {
+		return nil, fmt.Errorf("included Kustomization '%s' has not exported its build yet", key)
+	}
+
+	secretName := types.NamespacedName{Namespace: key.Namespace, Name: export.SecretName}
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretName, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get export secret '%s': %w", secretName, err)
+	}
+	if !isOwnedBy(&included, &secret) {
+		return nil, fmt.Errorf("export secret '%s' is not owned by the included Kustomization", secretName)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(secret.Data[exportSecretKey]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode export secret '%s': %w", secretName, err)
+	}
+	defer gz.Close()
+	manifests, err := readAllLimited(gz, maxManifestsSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode export secret '%s': %w", secretName, err)
+	}
+	return manifests, nil
+}
+
+// reconcileExport stores the built manifests in the export Secret if the
+// Kustomization is included by other Kustomizations allowed by its export
+// policy, and records the export in status. The Secret is deleted when the
+// Kustomization is no longer included. The Secrets of the build, which hold
+// decrypted data, are removed unless the export policy allows them. The
+// manifests are stored in a Secret as they may still hold the decrypted
+// data of Secrets.
+func (r *KustomizationReconciler) reconcileExport(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	revision string,
+	resources []byte) error {
+	var list kustomizev1.KustomizationList
+	if err := r.List(ctx, &list, client.MatchingFields{
+		indexInclude: client.ObjectKeyFromObject(obj).String(),
+	}); err != nil {
+		return fmt.Errorf("failed to list the including Kustomizations: %w", err)
+	}
+	list.Items = slices.DeleteFunc(list.Items, func(k kustomizev1.Kustomization) bool {
+		return !exportAllowed(obj, k.GetNamespace())
+	})
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      exportSecretName(obj),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	if len(list.Items) == 0 {
+		if obj.Status.ExportedBuild == nil {
+			return nil
+		}
+		if err := r.deleteExport(ctx, obj, secret); err != nil {
+			return err
+		}
+		obj.Status.ExportedBuild = nil
+		return nil
+	}
+
+	if obj.Spec.Export == nil || !obj.Spec.Export.AllowSecrets {
+		var err error
+		if resources, err = removeSecrets(resources); err != nil {
+			return fmt.Errorf("failed to remove the Secrets from the export: %w", err)
+		}
+	}
+
+	checksum := digest.FromBytes(resources).String()
+	if export := obj.Status.ExportedBuild; export != nil &&
+		export.Revision == revision && export.Digest == checksum {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(resources); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.GetResourceVersion() != "" && !isOwnedBy(obj, secret) {
+			return fmt.Errorf("secret '%s' is not owned by the Kustomization", client.ObjectKeyFromObject(secret))
+		}
+		secret.Labels = map[string]string{
+			fmt.Sprintf("%s/name", kustomizev1.GroupVersion.Group):      obj.GetName(),
+			fmt.Sprintf("%s/namespace", kustomizev1.GroupVersion.Group): obj.GetNamespace(),
+		}
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{exportSecretKey: buf.Bytes()}
+		return controllerutil.SetOwnerReference(obj, secret, r.Client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to store export secret: %w", err)
+	}
+
+	obj.Status.ExportedBuild = &kustomizev1.ExportedBuild{
+		SecretName: secret.GetName(),
+		Revision:   revision,
+		Digest:     checksum,
+	}
+	return nil
+}
+
+// deleteExport deletes the export Secret if it is owned by the Kustomization.
+func (r *KustomizationReconciler) deleteExport(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	secret *corev1.Secret) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get export secret: %w", err)
+	}
+	if !isOwnedBy(obj, secret) {
+		return nil
+	}
+	if err := r.Delete(ctx, secret, client.Preconditions{UID: &secret.UID}); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete export secret: %w", err)
+	}
+	return nil
+}
+
+// removeSecrets returns the multi-doc YAML manifests without the Secrets.
+func removeSecrets(resources []byte) ([]byte, error) {
+	objects, err := ssautil.ReadObjects(bytes.NewReader(resources))
+	if err != nil {
+		return nil, err
+	}
+	filtered := slices.DeleteFunc(slices.Clone(objects), func(u *unstructured.Unstructured) bool {
+		return u.GetAPIVersion() == "v1" && u.GetKind() == "Secret"
+	})
+	if len(filtered) == len(objects) {
+		return resources, nil
+	}
+	manifests, err := ssautil.ObjectsToYAML(filtered)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(manifests), nil
+}
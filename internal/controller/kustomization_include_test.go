@@ -0,0 +1,232 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestIncludeExportedBuild(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	base := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "hub", UID: "base-uid"},
+	}
+	app := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "spoke"},
+		Spec: kustomizev1.KustomizationSpec{
+			Include: []meta.NamespacedObjectReference{{Name: "base", Namespace: "hub"}},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).
+			WithIndex(&kustomizev1.Kustomization{}, indexInclude, indexByInclude).
+			WithStatusSubresource(&kustomizev1.Kustomization{}).
+			WithObjects(base, app).Build(),
+	}
+
+	// The build can't be included from another namespace by default.
+	dirPath := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dirPath, "kustomization.yaml"),
+		[]byte("resources:\n- deployment.yaml\n"), 0o600)).To(Succeed())
+	err := r.injectIncludes(ctx, app, dirPath)
+	g.Expect(err).To(MatchError(ContainSubstring("is not allowed by its export policy")))
+
+	// The build is not exported to the namespaces not allowed.
+	manifests := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: shared\n")
+	secretManifest := []byte("---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: token\nstringData:\n  token: s3cr3t\n")
+	g.Expect(r.reconcileExport(ctx, base, "main@sha1:abc", manifests)).To(Succeed())
+	g.Expect(base.Status.ExportedBuild).To(BeNil())
+
+	// The build can't be included before it's exported.
+	base.Spec.Export = &kustomizev1.ExportPolicy{AllowedNamespaces: []string{"spoke"}}
+	g.Expect(r.Update(ctx, base)).To(Succeed())
+	err = r.injectIncludes(ctx, app, dirPath)
+	g.Expect(err).To(MatchError(ContainSubstring("has not exported its build yet")))
+
+	// The build of an included Kustomization is exported without the Secrets.
+	g.Expect(r.reconcileExport(ctx, base, "main@sha1:abc", append(manifests, secretManifest...))).To(Succeed())
+	g.Expect(base.Status.ExportedBuild).ToNot(BeNil())
+	g.Expect(base.Status.ExportedBuild.SecretName).To(Equal("base-export"))
+	g.Expect(base.Status.ExportedBuild.Revision).To(Equal("main@sha1:abc"))
+	g.Expect(r.Status().Update(ctx, base)).To(Succeed())
+	exported, err := r.readExportedBuild(ctx, app, client.ObjectKeyFromObject(base))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(exported)).ToNot(ContainSubstring("s3cr3t"))
+	g.Expect(string(exported)).To(ContainSubstring("name: shared"))
+
+	// The Secrets are exported only if allowed.
+	base.Spec.Export.AllowSecrets = true
+	g.Expect(r.reconcileExport(ctx, base, "main@sha1:abc", append(manifests, secretManifest...))).To(Succeed())
+	g.Expect(r.Status().Update(ctx, base)).To(Succeed())
+	exported, err = r.readExportedBuild(ctx, app, client.ObjectKeyFromObject(base))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(exported)).To(ContainSubstring("s3cr3t"))
+	g.Expect(r.reconcileExport(ctx, base, "main@sha1:abc", manifests)).To(Succeed())
+	g.Expect(r.Status().Update(ctx, base)).To(Succeed())
+
+	// The exported build is added to the resources of the including Kustomization.
+	g.Expect(r.injectIncludes(ctx, app, dirPath)).To(Succeed())
+	included, err := os.ReadFile(filepath.Join(dirPath, includeDir, "hub-base.yaml"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(included).To(Equal(manifests))
+	data, err := os.ReadFile(filepath.Join(dirPath, "kustomization.yaml"))
+	g.Expect(err).ToNot(HaveOccurred())
+	var kus map[string]any
+	g.Expect(yaml.Unmarshal(data, &kus)).To(Succeed())
+	g.Expect(kus["resources"]).To(Equal([]any{"deployment.yaml", ".flux-include/hub-base.yaml"}))
+
+	// Cross-namespace includes can be blocked.
+	r.NoCrossNamespaceRefs = true
+	err = r.injectIncludes(ctx, app, dirPath)
+	g.Expect(err).To(MatchError(ContainSubstring("cross-namespace references have been blocked")))
+	r.NoCrossNamespaceRefs = false
+
+	// Circular includes are rejected.
+	latest := &kustomizev1.Kustomization{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(base), latest)).To(Succeed())
+	latest.Spec.Include = []meta.NamespacedObjectReference{{Name: "app", Namespace: "spoke"}}
+	g.Expect(r.Update(ctx, latest)).To(Succeed())
+	err = r.injectIncludes(ctx, app, dirPath)
+	g.Expect(err).To(MatchError(ContainSubstring("circular include detected")))
+
+	// The export is deleted when the Kustomization is no longer included.
+	g.Expect(r.Delete(ctx, app)).To(Succeed())
+	g.Expect(r.reconcileExport(ctx, base, "main@sha1:abc", manifests)).To(Succeed())
+	g.Expect(base.Status.ExportedBuild).To(BeNil())
+	err = r.Get(ctx, client.ObjectKey{Namespace: "hub", Name: "base-export"}, &corev1.Secret{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
+
+func TestExportSecretLimits(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	base := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "hub", UID: "base-uid"},
+		Status: kustomizev1.KustomizationStatus{
+			ExportedBuild: &kustomizev1.ExportedBuild{SecretName: "base-export"},
+		},
+	}
+	app := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "hub"},
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write(make([]byte, maxManifestsSize+1))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gz.Close()).To(Succeed())
+	bomb := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "base-export",
+			Namespace:       "hub",
+			Labels:          map[string]string{kustomizev1.GroupVersion.Group + "/name": "base"},
+			OwnerReferences: []metav1.OwnerReference{{Name: "base", UID: "base-uid"}},
+		},
+		Data: map[string][]byte{exportSecretKey: buf.Bytes()},
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).
+			WithIndex(&kustomizev1.Kustomization{}, indexInclude, indexByInclude).
+			WithObjects(base, app, bomb).Build(),
+	}
+
+	// An export decompressing above the limit is not read.
+	_, err = r.readExportedBuild(ctx, app, client.ObjectKeyFromObject(base))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("exceeds the 67108864 bytes limit"))
+
+	// A Secret not owned by the Kustomization is not deleted.
+	foreign := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "base-export", Namespace: "hub"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	g.Expect(r.Delete(ctx, bomb)).To(Succeed())
+	g.Expect(r.Create(ctx, foreign)).To(Succeed())
+	g.Expect(r.reconcileExport(ctx, base, "main@sha1:abc", nil)).To(Succeed())
+	g.Expect(base.Status.ExportedBuild).To(BeNil())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(foreign), foreign)).To(Succeed())
+	g.Expect(foreign.Data).To(HaveKey("token"))
+}
+
+func TestExportAllowed(t *testing.T) {
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "hub"},
+	}
+	tests := []struct {
+		name      string
+		export    *kustomizev1.ExportPolicy
+		namespace string
+		want      bool
+	}{
+		{name: "same namespace", namespace: "hub", want: true},
+		{name: "other namespace by default", namespace: "spoke", want: false},
+		{
+			name:      "allowed namespace",
+			export:    &kustomizev1.ExportPolicy{AllowedNamespaces: []string{"spoke"}},
+			namespace: "spoke",
+			want:      true,
+		},
+		{
+			name:      "namespace not allowed",
+			export:    &kustomizev1.ExportPolicy{AllowedNamespaces: []string{"spoke"}},
+			namespace: "other",
+			want:      false,
+		},
+		{
+			name:      "wildcard",
+			export:    &kustomizev1.ExportPolicy{AllowedNamespaces: []string{"*"}},
+			namespace: "other",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			obj.Spec.Export = tt.export
+			g.Expect(exportAllowed(obj, tt.namespace)).To(Equal(tt.want))
+		})
+	}
+}
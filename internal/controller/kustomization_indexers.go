@@ -26,7 +26,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/fluxcd/pkg/apis/meta"
-	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/dependency"
 
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
@@ -56,14 +55,8 @@ func (r *KustomizationReconciler) requestsForRevisionChangeOf(indexKey string) h
 			return nil
 		}
 		var dd []dependency.Dependent
-		for i, d := range list.Items {
-			// If the Kustomization is ready or reconciling and the revision of the artifact equals
-			// to the last attempted revision, we should not make a request for this Kustomization
-			if (conditions.IsReady(&list.Items[i]) || conditions.IsReconciling(&list.Items[i])) &&
-				repo.GetArtifact().HasRevision(d.Status.LastAttemptedRevision) {
-				continue
-			}
-			dd = append(dd, d.DeepCopy())
+		for _, d := range r.filterEnqueue(ctx, obj, list.Items, revisionChangeFilter(repo.GetArtifact())) {
+			dd = append(dd, d)
 		}
 		reqs, err := sortAndEnqueue(dd)
 		if err != nil {
@@ -74,6 +67,31 @@ func (r *KustomizationReconciler) requestsForRevisionChangeOf(indexKey string) h
 	}
 }
 
+// requestsForExportChangeOf enqueues requests for the Kustomizations
+// including the Kustomization whose exported build changed.
+func (r *KustomizationReconciler) requestsForExportChangeOf(indexKey string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		log := ctrl.LoggerFrom(ctx)
+		var list kustomizev1.KustomizationList
+		if err := r.List(ctx, &list, client.MatchingFields{
+			indexKey: client.ObjectKeyFromObject(obj).String(),
+		}); err != nil {
+			log.Error(err, "failed to list objects for export change")
+			return nil
+		}
+		var dd []dependency.Dependent
+		for _, d := range r.filterEnqueue(ctx, obj, list.Items) {
+			dd = append(dd, d)
+		}
+		reqs, err := sortAndEnqueue(dd)
+		if err != nil {
+			log.Error(err, "failed to sort dependencies for export change")
+			return nil
+		}
+		return reqs
+	}
+}
+
 func (r *KustomizationReconciler) indexBy(kind string) func(o client.Object) []string {
 	return func(o client.Object) []string {
 		k, ok := o.(*kustomizev1.Kustomization)
@@ -94,9 +112,11 @@ func (r *KustomizationReconciler) indexBy(kind string) func(o client.Object) []s
 }
 
 // requestsForConfigDependency enqueues requests for watched ConfigMaps or Secrets
-// according to the specified index.
+// according to the specified index. With SkipUnchangedConfigRenders, the
+// Kustomizations using the object only to render their manifests are recorded,
+// so that their reconciliation can skip the apply if the manifests are unchanged.
 func (r *KustomizationReconciler) requestsForConfigDependency(
-	index string) func(ctx context.Context, o client.Object) []reconcile.Request {
+	index, kind string) func(ctx context.Context, o client.Object) []reconcile.Request {
 
 	return func(ctx context.Context, o client.Object) []reconcile.Request {
 		log := ctrl.LoggerFrom(ctx).WithValues("index", index, "objectRef", map[string]string{
@@ -115,9 +135,12 @@ func (r *KustomizationReconciler) requestsForConfigDependency(
 
 		// Sort the Kustomizations by their dependencies to ensure
 		// that dependent Kustomizations are reconciled after their dependencies.
-		dd := make([]dependency.Dependent, 0, len(list.Items))
-		for i := range list.Items {
-			dd = append(dd, &list.Items[i])
+		var dd []dependency.Dependent
+		for _, d := range r.filterEnqueue(ctx, o, list.Items) {
+			if r.SkipUnchangedConfigRenders && rendersFrom(d, kind, o.GetName()) {
+				r.recordConfigChange(d)
+			}
+			dd = append(dd, d)
 		}
 
 		// Enqueue requests for each Kustomization in the list.
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/fluxcd/pkg/ssa"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+const (
+	// inventorySecretKey is the data key of the inventory Secret
+	// holding the JSON encoded ResourceInventory.
+	inventorySecretKey = "inventory.json"
+
+	// inventorySecretHashKey is the data key of the inventory Secret
+	// holding the random key of the HMAC digests of the hashed entries.
+	inventorySecretHashKey = "hash.key"
+
+	// inventorySecretLabel is the label of the inventory Secret holding
+	// the name of the Kustomization. The Secret doesn't carry the labels
+	// of the objects managed by the Kustomization, as it's not applied.
+	inventorySecretLabel = "kustomize.toolkit.fluxcd.io/inventory-of"
+)
+
+// inventorySecretName returns the name of the Secret that stores the
+// full inventory when Secret entries are redacted from the status.
+func inventorySecretName(obj *kustomizev1.Kustomization) string {
+	return fmt.Sprintf("%s-inventory", obj.GetName())
+}
+
+// isInventorySecretOf returns true if the Secret is labelled as the
+// inventory of the Kustomization and is owned by it.
+func isInventorySecretOf(obj *kustomizev1.Kustomization, secret *corev1.Secret) bool {
+	if secret.GetLabels()[inventorySecretLabel] != obj.GetName() {
+		return false
+	}
+	for _, ref := range secret.GetOwnerReferences() {
+		if ref.UID == obj.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// statusInventory returns a copy of the status inventory of the
+// Kustomization without the hashed entries.
+func statusInventory(obj *kustomizev1.Kustomization) *kustomizev1.ResourceInventory {
+	inv := inventory.New()
+	if obj.Status.Inventory != nil {
+		obj.Status.Inventory.DeepCopyInto(inv)
+	}
+	return inventory.WithoutHashedEntries(inv)
+}
+
+// getInventory returns a copy of the full inventory of the Kustomization.
+// When the status records an inventory Secret, its entries are merged with
+// the status inventory whatever the current policy, so that the Secret
+// entries redacted under a previous policy are still pruned. If the Secret
+// is missing, the hashed entries are dropped as they can't be mapped back
+// to the Secret objects.
+func (r *KustomizationReconciler) getInventory(ctx context.Context,
+	obj *kustomizev1.Kustomization) (*kustomizev1.ResourceInventory, error) {
+	inv := statusInventory(obj)
+
+	if obj.Status.InventorySecretName == "" {
+		return inv, nil
+	}
+
+	secret := &corev1.Secret{}
+	secretName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.Status.InventorySecretName}
+	if err := r.Get(ctx, secretName, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return inv, nil
+		}
+		return nil, fmt.Errorf("failed to get inventory secret '%s': %w", secretName, err)
+	}
+	if !isInventorySecretOf(obj, secret) {
+		return nil, fmt.Errorf("inventory secret '%s' is not owned by the Kustomization", secretName)
+	}
+
+	full := inventory.New()
+	if data, ok := secret.Data[inventorySecretKey]; ok {
+		if err := json.Unmarshal(data, full); err != nil {
+			return nil, fmt.Errorf("failed to decode inventory secret '%s': %w", secretName, err)
+		}
+	}
+	known := make(map[string]bool, len(full.Entries))
+	for _, entry := range full.Entries {
+		known[entry.ID] = true
+	}
+	for _, entry := range inv.Entries {
+		if !known[entry.ID] {
+			full.Entries = append(full.Entries, entry)
+		}
+	}
+	return full, nil
+}
+
+// setInventory records the given inventory in the Kustomization status.
+// Depending on the secret inventory policy, the Secret entries are hashed or
+// omitted from the status, and the full inventory is stored in the inventory
+// Secret owned by the Kustomization. With the Plain policy, the inventory
+// Secret left by a previous policy is deleted, as its entries were merged
+// back by getInventory. Secrets not owned by the Kustomization are never
+// overwritten nor deleted.
+func (r *KustomizationReconciler) setInventory(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	inv *kustomizev1.ResourceInventory) error {
+	policy := obj.GetSecretInventoryPolicy()
+	if policy == kustomizev1.SecretInventoryPolicyPlain {
+		obj.Status.Inventory = inv
+		if obj.Status.InventorySecretName == "" {
+			return nil
+		}
+
+		secret := &corev1.Secret{}
+		secretName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.Status.InventorySecretName}
+		if err := r.Get(ctx, secretName, secret); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get inventory secret '%s': %w", secretName, err)
+		} else if err == nil && isInventorySecretOf(obj, secret) {
+			if err := r.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete inventory secret '%s': %w", secretName, err)
+			}
+		}
+		obj.Status.InventorySecretName = ""
+		return nil
+	}
+
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      inventorySecretName(obj),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.GetResourceVersion() != "" && !isInventorySecretOf(obj, secret) {
+			return fmt.Errorf("secret '%s' is not owned by the Kustomization", client.ObjectKeyFromObject(secret))
+		}
+		hashKey := secret.Data[inventorySecretHashKey]
+		if len(hashKey) == 0 {
+			hashKey = make([]byte, 32)
+			if _, err := rand.Read(hashKey); err != nil {
+				return fmt.Errorf("failed to generate hash key: %w", err)
+			}
+		}
+		secret.Labels = map[string]string{
+			inventorySecretLabel: obj.GetName(),
+		}
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			inventorySecretKey:     data,
+			inventorySecretHashKey: hashKey,
+		}
+		return controllerutil.SetOwnerReference(obj, secret, r.Client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to store inventory secret: %w", err)
+	}
+
+	obj.Status.InventorySecretName = secret.GetName()
+	obj.Status.Inventory = inventory.Redact(inv, policy, secret.Data[inventorySecretHashKey])
+	return nil
+}
+
+// setInventoryRevisions records in the entries of the given inventory the
+// revision at which the objects last changed. The objects of a resumed
+// apply are prepared as by the apply, so that the digests of their
+// manifests match the ones recorded by the interrupted reconciliation.
+func (r *KustomizationReconciler) setInventoryRevisions(obj *kustomizev1.Kustomization,
+	inv, oldInv *kustomizev1.ResourceInventory,
+	objects []*unstructured.Unstructured,
+	changeSet *ssa.ChangeSet,
+	revision string,
+	resumed bool) error {
+	if resumed {
+		if err := r.prepareObjects(obj, objects); err != nil {
+			return err
+		}
+	}
+	return inventory.SetRevisions(inv, oldInv, objects, changeSet, revision)
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+const (
+	// maxDriftCorrections is the number of drift corrections allowed
+	// within the driftCorrectionWindow, above which the changes are
+	// considered to be caused by another controller fighting over the
+	// objects and are left to the periodic reconciliation.
+	maxDriftCorrections   = 5
+	driftCorrectionWindow = 10 * time.Minute
+)
+
+// inventoryWatcher watches the metadata of the kinds present in the
+// inventories of the Kustomizations, and triggers the reconciliation of
+// the Kustomization managing an object when the object is modified by
+// another field manager or deleted. The watch of a kind is stopped when
+// no Kustomization manages objects of that kind anymore.
+type inventoryWatcher struct {
+	informers      cache.Informers
+	controllerName string
+	events         chan event.GenericEvent
+
+	mu sync.Mutex
+	// inventories holds the tracked inventory of each Kustomization.
+	inventories map[types.NamespacedName]*trackedInventory
+	// kinds holds the watched kinds and the Kustomizations managing them.
+	kinds map[schema.GroupKind]*inventoryKindWatch
+}
+
+// trackedInventory holds the inventory object IDs of a Kustomization
+// and the state of its drift corrections.
+type trackedInventory struct {
+	ids         map[string]struct{}
+	debounce    time.Duration
+	pending     *time.Timer
+	corrections []time.Time
+	throttled   bool
+}
+
+// inventoryKindWatch records the version used to watch a kind
+// and the Kustomizations with objects of that kind.
+type inventoryKindWatch struct {
+	gvk    schema.GroupVersionKind
+	owners map[types.NamespacedName]struct{}
+}
+
+// newInventoryCache returns a metadata cache restricted
+// to the objects labeled by the controller.
+func newInventoryCache(mgr ctrl.Manager) (cache.Cache, error) {
+	req, err := labels.NewRequirement(fmt.Sprintf("%s/name", kustomizev1.GroupVersion.Group), selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient:           mgr.GetHTTPClient(),
+		Scheme:               mgr.GetScheme(),
+		Mapper:               mgr.GetRESTMapper(),
+		DefaultLabelSelector: labels.NewSelector().Add(*req),
+	})
+}
+
+func newInventoryWatcher(informers cache.Informers, controllerName string) *inventoryWatcher {
+	return &inventoryWatcher{
+		informers:      informers,
+		controllerName: controllerName,
+		events:         make(chan event.GenericEvent, 1024),
+		inventories:    make(map[types.NamespacedName]*trackedInventory),
+		kinds:          make(map[schema.GroupKind]*inventoryKindWatch),
+	}
+}
+
+// track records the inventory of the given Kustomization, starts the
+// watches of the kinds not watched yet and stops the watches of the kinds
+// no longer present in any inventory. The changes to the objects are
+// coalesced into a single notification within the debounce delay.
+func (w *inventoryWatcher) track(ctx context.Context, key types.NamespacedName,
+	inv *kustomizev1.ResourceInventory, debounce time.Duration) error {
+	objects, err := inventory.List(inv)
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]struct{}, len(objects))
+	gvks := make(map[schema.GroupKind]schema.GroupVersionKind)
+	for _, o := range objects {
+		ids[object.UnstructuredToObjMetadata(o).String()] = struct{}{}
+		gvk := o.GroupVersionKind()
+		if _, ok := gvks[gvk.GroupKind()]; !ok {
+			gvks[gvk.GroupKind()] = gvk
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t, ok := w.inventories[key]; ok {
+		t.ids = ids
+		t.debounce = debounce
+	} else {
+		w.inventories[key] = &trackedInventory{ids: ids, debounce: debounce}
+	}
+
+	var errs []error
+	for gk, gvk := range gvks {
+		kw, ok := w.kinds[gk]
+		if !ok {
+			if err := w.startWatch(ctx, gvk); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			kw = &inventoryKindWatch{gvk: gvk, owners: make(map[types.NamespacedName]struct{})}
+			w.kinds[gk] = kw
+		}
+		kw.owners[key] = struct{}{}
+	}
+	for gk, kw := range w.kinds {
+		if _, ok := gvks[gk]; !ok {
+			errs = append(errs, w.release(ctx, key, gk, kw))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// untrack removes the inventory of the given Kustomization and stops
+// the watches of the kinds no longer present in any inventory.
+func (w *inventoryWatcher) untrack(ctx context.Context, key types.NamespacedName) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t, ok := w.inventories[key]; ok && t.pending != nil {
+		t.pending.Stop()
+	}
+	delete(w.inventories, key)
+
+	var errs []error
+	for gk, kw := range w.kinds {
+		errs = append(errs, w.release(ctx, key, gk, kw))
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// release removes the given Kustomization from the owners of the kind,
+// and stops the watch if the kind has no owners left.
+func (w *inventoryWatcher) release(ctx context.Context, key types.NamespacedName,
+	gk schema.GroupKind, kw *inventoryKindWatch) error {
+	delete(kw.owners, key)
+	if len(kw.owners) > 0 {
+		return nil
+	}
+	delete(w.kinds, gk)
+	if err := w.informers.RemoveInformer(ctx, metadataFor(kw.gvk)); err != nil {
+		return fmt.Errorf("failed to stop watching %s: %w", gk.String(), err)
+	}
+	return nil
+}
+
+func (w *inventoryWatcher) startWatch(ctx context.Context, gvk schema.GroupVersionKind) error {
+	informer, err := w.informers.GetInformer(ctx, metadataFor(gvk), cache.BlockUntilSynced(false))
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", gvk.GroupKind().String(), err)
+	}
+	// The additions are ignored, as the objects are created
+	// by the controller or listed when the watch starts.
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj any) {
+			o, ok := oldObj.(metav1.Object)
+			n, nok := newObj.(metav1.Object)
+			if ok && nok && isForeignChange(o, n, w.controllerName) {
+				w.notify(gvk.GroupKind(), n)
+			}
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if o, ok := obj.(metav1.Object); ok {
+				w.notify(gvk.GroupKind(), o)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", gvk.GroupKind().String(), err)
+	}
+	return nil
+}
+
+// notify schedules the reconciliation of the Kustomization whose
+// inventory contains the given object after the debounce delay,
+// unless a reconciliation is already scheduled or the drift
+// corrections of the Kustomization are throttled.
+func (w *inventoryWatcher) notify(gk schema.GroupKind, obj metav1.Object) {
+	key := types.NamespacedName{
+		Namespace: obj.GetLabels()[fmt.Sprintf("%s/namespace", kustomizev1.GroupVersion.Group)],
+		Name:      obj.GetLabels()[fmt.Sprintf("%s/name", kustomizev1.GroupVersion.Group)],
+	}
+	id := object.ObjMetadata{Namespace: obj.GetNamespace(), Name: obj.GetName(), GroupKind: gk}.String()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t, ok := w.inventories[key]
+	if !ok || t.pending != nil {
+		return
+	}
+	if _, ok := t.ids[id]; !ok {
+		return
+	}
+	if !t.allowCorrection(time.Now()) {
+		if !t.throttled {
+			t.throttled = true
+			ctrl.Log.WithName("inventory-watcher").Info("drift correction loop detected, deferring to the periodic reconciliation",
+				"kustomization", key.String(), "object", id)
+		}
+		return
+	}
+
+	t.pending = time.AfterFunc(t.debounce, func() {
+		w.mu.Lock()
+		t.pending = nil
+		w.mu.Unlock()
+		w.events <- event.GenericEvent{Object: &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		}}
+	})
+}
+
+// allowCorrection records a drift correction at the given time, unless
+// the maximum number of corrections within the window is reached.
+func (t *trackedInventory) allowCorrection(now time.Time) bool {
+	recent := t.corrections[:0]
+	for _, c := range t.corrections {
+		if now.Sub(c) < driftCorrectionWindow {
+			recent = append(recent, c)
+		}
+	}
+	t.corrections = recent
+	if len(t.corrections) >= maxDriftCorrections {
+		return false
+	}
+	t.corrections = append(t.corrections, now)
+	t.throttled = false
+	return true
+}
+
+// isForeignChange returns true if a field manager other than the
+// controller has modified the object outside the status subresource.
+func isForeignChange(oldObj, newObj metav1.Object, controllerName string) bool {
+	if oldObj.GetResourceVersion() == newObj.GetResourceVersion() {
+		return false
+	}
+	previous := make(map[string]metav1.Time)
+	for _, entry := range oldObj.GetManagedFields() {
+		if entry.Time != nil {
+			previous[entry.Manager+"/"+string(entry.Operation)+"/"+entry.Subresource] = *entry.Time
+		}
+	}
+	for _, entry := range newObj.GetManagedFields() {
+		if entry.Manager == controllerName || entry.Subresource == "status" || entry.Time == nil {
+			continue
+		}
+		t, ok := previous[entry.Manager+"/"+string(entry.Operation)+"/"+entry.Subresource]
+		if !ok || !t.Equal(entry.Time) {
+			return true
+		}
+	}
+	return false
+}
+
+func metadataFor(gvk schema.GroupVersionKind) *metav1.PartialObjectMetadata {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// watchInventory starts watching the kinds present in the inventory of
+// the Kustomization when WatchInventoryKinds is enabled and the drift
+// correction mode is Immediate. The objects applied on remote clusters
+// are not watched.
+func (r *KustomizationReconciler) watchInventory(ctx context.Context,
+	obj *kustomizev1.Kustomization, inv *kustomizev1.ResourceInventory) {
+	if r.inventoryWatcher == nil {
+		return
+	}
+	var err error
+	if isRemote(obj) || obj.GetDriftCorrectionMode() != kustomizev1.DriftCorrectionModeImmediate {
+		err = r.inventoryWatcher.untrack(ctx, client.ObjectKeyFromObject(obj))
+	} else {
+		err = r.inventoryWatcher.track(ctx, client.ObjectKeyFromObject(obj), inv, obj.GetDriftCorrectionDebounce())
+	}
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to watch the inventory kinds")
+	}
+}
+
+// unwatchInventory stops watching the kinds present
+// only in the inventory of the Kustomization.
+func (r *KustomizationReconciler) unwatchInventory(ctx context.Context, obj *kustomizev1.Kustomization) {
+	if r.inventoryWatcher == nil {
+		return
+	}
+	if err := r.inventoryWatcher.untrack(ctx, client.ObjectKeyFromObject(obj)); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to stop watching the inventory kinds")
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// metadataInformers is a fake cache keyed by the GVK of the metadata objects.
+type metadataInformers struct {
+	informertest.FakeInformers
+	informers map[schema.GroupVersionKind]*controllertest.FakeInformer
+}
+
+func (c *metadataInformers) GetInformer(_ context.Context, obj client.Object, _ ...cache.InformerGetOption) (cache.Informer, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if _, ok := c.informers[gvk]; !ok {
+		c.informers[gvk] = &controllertest.FakeInformer{}
+	}
+	return c.informers[gvk], nil
+}
+
+func (c *metadataInformers) RemoveInformer(_ context.Context, obj client.Object) error {
+	delete(c.informers, obj.GetObjectKind().GroupVersionKind())
+	return nil
+}
+
+func TestInventoryWatcher(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	serviceGVK := schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+	informers := &metadataInformers{informers: make(map[schema.GroupVersionKind]*controllertest.FakeInformer)}
+	w := newInventoryWatcher(informers, "kustomize-controller")
+
+	app := types.NamespacedName{Namespace: "flux-system", Name: "app"}
+	infra := types.NamespacedName{Namespace: "flux-system", Name: "infra"}
+	g.Expect(w.track(ctx, app, &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+		{ID: "default_web_apps_Deployment", Version: "v1"},
+		{ID: "default_web__Service", Version: "v1"},
+	}}, 0)).To(Succeed())
+	g.Expect(w.track(ctx, infra, &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+		{ID: "default_ingress_apps_Deployment", Version: "v1"},
+	}}, 0)).To(Succeed())
+	g.Expect(informers.informers).To(HaveLen(2))
+
+	newObject := func(name, manager, resourceVersion string) *metav1.PartialObjectMetadata {
+		now := metav1.Now()
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            name,
+			ResourceVersion: resourceVersion,
+			Labels: map[string]string{
+				"kustomize.toolkit.fluxcd.io/name":      "app",
+				"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: manager, Operation: metav1.ManagedFieldsOperationUpdate, Time: &now}},
+		}}
+	}
+
+	t.Run("ignores the changes made by the controller", func(t *testing.T) {
+		informers.informers[deploymentGVK].Update(
+			newObject("web", "kustomize-controller", "1"),
+			newObject("web", "kustomize-controller", "22"))
+		g.Expect(w.events).To(BeEmpty())
+	})
+
+	t.Run("notifies the changes made by other managers", func(t *testing.T) {
+		informers.informers[deploymentGVK].Update(
+			newObject("web", "kustomize-controller", "1"),
+			newObject("web", "kubectl-edit", "2"))
+		g.Eventually(w.events).Should(HaveLen(1))
+		e := <-w.events
+		g.Expect(client.ObjectKeyFromObject(e.Object)).To(Equal(app))
+	})
+
+	t.Run("notifies the deletions", func(t *testing.T) {
+		informers.informers[serviceGVK].Delete(newObject("web", "kustomize-controller", "1"))
+		g.Eventually(w.events).Should(HaveLen(1))
+		<-w.events
+	})
+
+	t.Run("coalesces the changes within the debounce delay", func(t *testing.T) {
+		g.Expect(w.track(ctx, infra, &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+			{ID: "default_ingress_apps_Deployment", Version: "v1"},
+		}}, 100*time.Millisecond)).To(Succeed())
+		newIngress := func(manager, resourceVersion string) *metav1.PartialObjectMetadata {
+			obj := newObject("ingress", manager, resourceVersion)
+			obj.Labels["kustomize.toolkit.fluxcd.io/name"] = "infra"
+			return obj
+		}
+		for i := range 3 {
+			informers.informers[deploymentGVK].Update(
+				newIngress("kustomize-controller", "1"),
+				newIngress("kubectl-edit", fmt.Sprintf("%d", i+2)))
+		}
+		g.Consistently(w.events, 50*time.Millisecond).Should(BeEmpty())
+		g.Eventually(w.events).Should(HaveLen(1))
+		<-w.events
+		g.Consistently(w.events, 200*time.Millisecond).Should(BeEmpty())
+	})
+
+	t.Run("ignores the objects not in the inventory", func(t *testing.T) {
+		informers.informers[deploymentGVK].Delete(newObject("other", "kustomize-controller", "1"))
+		g.Expect(w.events).To(BeEmpty())
+	})
+
+	t.Run("stops watching the kinds no longer managed", func(t *testing.T) {
+		g.Expect(w.track(ctx, app, &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+			{ID: "default_web_apps_Deployment", Version: "v1"},
+		}}, 0)).To(Succeed())
+		g.Expect(informers.informers).To(HaveKey(deploymentGVK))
+		g.Expect(informers.informers).ToNot(HaveKey(serviceGVK))
+
+		g.Expect(w.untrack(ctx, app)).To(Succeed())
+		g.Expect(informers.informers).To(HaveKey(deploymentGVK))
+		g.Expect(w.untrack(ctx, infra)).To(Succeed())
+		g.Expect(informers.informers).To(BeEmpty())
+	})
+}
+
+func TestAllowCorrection(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	inv := &trackedInventory{}
+	for i := range maxDriftCorrections {
+		g.Expect(inv.allowCorrection(now.Add(time.Duration(i) * time.Second))).To(BeTrue())
+	}
+	g.Expect(inv.allowCorrection(now.Add(time.Minute))).To(BeFalse())
+	g.Expect(inv.allowCorrection(now.Add(driftCorrectionWindow))).To(BeTrue())
+}
+
+func TestIsForeignChange(t *testing.T) {
+	g := NewWithT(t)
+
+	t1 := metav1.NewTime(time.Unix(1, 0))
+	t2 := metav1.NewTime(time.Unix(2, 0))
+	newObject := func(resourceVersion string, entries ...metav1.ManagedFieldsEntry) *metav1.ObjectMeta {
+		return &metav1.ObjectMeta{ResourceVersion: resourceVersion, ManagedFields: entries}
+	}
+	ssa := metav1.ManagedFieldsEntry{Manager: "kustomize-controller", Operation: metav1.ManagedFieldsOperationApply, Time: &t1}
+	ssaLater := metav1.ManagedFieldsEntry{Manager: "kustomize-controller", Operation: metav1.ManagedFieldsOperationApply, Time: &t2}
+	status := metav1.ManagedFieldsEntry{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: &t2}
+	edit := metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &t1}
+	editLater := metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &t2}
+
+	g.Expect(isForeignChange(newObject("1", ssa), newObject("1", ssa), "kustomize-controller")).To(BeFalse())
+	g.Expect(isForeignChange(newObject("1", ssa), newObject("2", ssaLater), "kustomize-controller")).To(BeFalse())
+	g.Expect(isForeignChange(newObject("1", ssa), newObject("2", ssa, status), "kustomize-controller")).To(BeFalse())
+	g.Expect(isForeignChange(newObject("1", ssa, edit), newObject("2", ssaLater, edit), "kustomize-controller")).To(BeFalse())
+	g.Expect(isForeignChange(newObject("1", ssa), newObject("2", ssa, edit), "kustomize-controller")).To(BeTrue())
+	g.Expect(isForeignChange(newObject("1", ssa, edit), newObject("2", ssa, editLater), "kustomize-controller")).To(BeTrue())
+}
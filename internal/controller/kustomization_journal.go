@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=create;update;patch;delete
+
+// applyJournalKey is the data key of the apply journal ConfigMap
+// holding the JSON encoded applyJournal.
+const applyJournalKey = "journal.json"
+
+// applyJournalPhase is the phase of the reconciliation recorded in the journal.
+type applyJournalPhase string
+
+const (
+	// applyJournalApplying signals that the apply of the revision
+	// started and may have been partially performed.
+	applyJournalApplying applyJournalPhase = "Applying"
+
+	// applyJournalApplied signals that the revision was fully applied,
+	// and that the garbage collection and health checks are pending.
+	applyJournalApplied applyJournalPhase = "Applied"
+)
+
+// applyJournal records the progress of the reconciliation of a revision,
+// so that the reconciliation interrupted by a controller crash or restart
+// can be resumed.
+type applyJournal struct {
+	// Revision is the source revision being applied.
+	Revision string `json:"revision"`
+
+	// Checksum is the digest of the built manifests being applied.
+	Checksum string `json:"checksum"`
+
+	// Phase is the reconciliation phase reached.
+	Phase applyJournalPhase `json:"phase"`
+
+	// LastObject is the last object applied, in the 'kind/namespace/name' format.
+	LastObject string `json:"lastObject,omitempty"`
+
+	// Skipped holds the IDs of the objects excluded from the apply.
+	Skipped []string `json:"skipped,omitempty"`
+
+	// UpdatedAt is the time of the last journal update.
+	UpdatedAt metav1.Time `json:"updatedAt"`
+}
+
+// applyJournalName returns the name of the ConfigMap
+// holding the apply journal of the Kustomization.
+func applyJournalName(obj *kustomizev1.Kustomization) string {
+	return fmt.Sprintf("%s-apply-journal", obj.GetName())
+}
+
+// getApplyJournal returns the apply journal of the Kustomization,
+// or nil if the journal is disabled or doesn't exist.
+func (r *KustomizationReconciler) getApplyJournal(ctx context.Context,
+	obj *kustomizev1.Kustomization) (*applyJournal, error) {
+	if !r.ApplyJournal {
+		return nil, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	cmName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: applyJournalName(obj)}
+	if err := r.Get(ctx, cmName, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get apply journal '%s': %w", cmName, err)
+	}
+	if !isOwnedBy(obj, cm) {
+		return nil, fmt.Errorf("apply journal '%s' is not owned by the Kustomization", cmName)
+	}
+
+	journal := &applyJournal{}
+	if err := json.Unmarshal([]byte(cm.Data[applyJournalKey]), journal); err != nil {
+		return nil, fmt.Errorf("failed to decode apply journal '%s': %w", cmName, err)
+	}
+	return journal, nil
+}
+
+// setApplyJournal stores the apply journal in the ConfigMap
+// owned by the Kustomization.
+func (r *KustomizationReconciler) setApplyJournal(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	journal *applyJournal) error {
+	if !r.ApplyJournal {
+		return nil
+	}
+
+	journal.UpdatedAt = metav1.NewTime(time.Now())
+	data, err := json.Marshal(journal)
+	if err != nil {
+		return fmt.Errorf("failed to encode apply journal: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      applyJournalName(obj),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		if cm.GetResourceVersion() != "" && !isOwnedBy(obj, cm) {
+			return fmt.Errorf("configmap '%s' is not owned by the Kustomization", client.ObjectKeyFromObject(cm))
+		}
+		cm.Labels = map[string]string{
+			fmt.Sprintf("%s/name", kustomizev1.GroupVersion.Group):      obj.GetName(),
+			fmt.Sprintf("%s/namespace", kustomizev1.GroupVersion.Group): obj.GetNamespace(),
+		}
+		cm.Data = map[string]string{applyJournalKey: string(data)}
+		return controllerutil.SetOwnerReference(obj, cm, r.Client.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to store apply journal: %w", err)
+	}
+	return nil
+}
+
+// deleteApplyJournal deletes the apply journal of the Kustomization
+// once the reconciliation of the revision is over. A ConfigMap with
+// the same name not owned by the Kustomization is left in place.
+func (r *KustomizationReconciler) deleteApplyJournal(ctx context.Context,
+	obj *kustomizev1.Kustomization) {
+	if !r.ApplyJournal {
+		return
+	}
+
+	cm := &corev1.ConfigMap{}
+	cmName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: applyJournalName(obj)}
+	if err := r.Get(ctx, cmName, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to delete apply journal")
+		}
+		return
+	}
+	if !isOwnedBy(obj, cm) {
+		return
+	}
+	if err := r.Delete(ctx, cm, client.Preconditions{UID: &cm.UID}); client.IgnoreNotFound(err) != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to delete apply journal")
+	}
+}
+
+// newAppliedJournal returns the journal of the fully applied revision.
+func newAppliedJournal(revision, checksum string, changeSet *ssa.ChangeSet) *applyJournal {
+	journal := &applyJournal{
+		Revision: revision,
+		Checksum: checksum,
+		Phase:    applyJournalApplied,
+	}
+	for _, entry := range changeSet.Entries {
+		journal.LastObject = entry.Subject
+		if entry.Action == ssa.SkippedAction {
+			journal.Skipped = append(journal.Skipped, entry.ObjMetadata.String())
+		}
+	}
+	return journal
+}
+
+// resumeChangeSet returns the change set of the objects applied before the
+// reconciliation was interrupted, if the journal records that the given
+// revision and manifests were fully applied.
+func (j *applyJournal) resumeChangeSet(revision, checksum string,
+	objects []*unstructured.Unstructured) (*ssa.ChangeSet, bool) {
+	if j == nil || j.Phase != applyJournalApplied ||
+		j.Revision != revision || j.Checksum != checksum {
+		return nil, false
+	}
+
+	skipped := make(map[string]bool, len(j.Skipped))
+	for _, id := range j.Skipped {
+		skipped[id] = true
+	}
+
+	changeSet := ssa.NewChangeSet()
+	for _, u := range objects {
+		id := object.UnstructuredToObjMetadata(u)
+		action := ssa.UnchangedAction
+		if skipped[id.String()] {
+			action = ssa.SkippedAction
+		}
+		changeSet.Add(ssa.ChangeSetEntry{
+			ObjMetadata:  id,
+			GroupVersion: u.GroupVersionKind().GroupVersion().String(),
+			Subject:      ssautil.FmtUnstructured(u),
+			Action:       action,
+		})
+	}
+	return changeSet, true
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestApplyJournal_ResumeChangeSet(t *testing.T) {
+	g := NewWithT(t)
+
+	newConfigMap := func(name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetNamespace("default")
+		u.SetName(name)
+		return u
+	}
+	objects := []*unstructured.Unstructured{newConfigMap("first"), newConfigMap("second")}
+
+	applied := ssa.NewChangeSet()
+	for _, u := range objects {
+		action := ssa.CreatedAction
+		if u.GetName() == "second" {
+			action = ssa.SkippedAction
+		}
+		applied.Add(ssa.ChangeSetEntry{
+			ObjMetadata:  object.UnstructuredToObjMetadata(u),
+			GroupVersion: "v1",
+			Subject:      "ConfigMap/default/" + u.GetName(),
+			Action:       action,
+		})
+	}
+
+	journal := newAppliedJournal("v1", "sha256:abc", applied)
+	g.Expect(journal.Phase).To(Equal(applyJournalApplied))
+	g.Expect(journal.LastObject).To(Equal("ConfigMap/default/second"))
+	g.Expect(journal.Skipped).To(ConsistOf("default_second__ConfigMap"))
+
+	changeSet, ok := journal.resumeChangeSet("v1", "sha256:abc", objects)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(changeSet.Entries).To(HaveLen(2))
+	g.Expect(changeSet.Entries[0].Action).To(Equal(ssa.UnchangedAction))
+	g.Expect(changeSet.Entries[0].GroupVersion).To(Equal("v1"))
+	g.Expect(changeSet.Entries[1].Action).To(Equal(ssa.SkippedAction))
+
+	_, ok = journal.resumeChangeSet("v2", "sha256:abc", objects)
+	g.Expect(ok).To(BeFalse(), "a different revision must be applied")
+
+	_, ok = journal.resumeChangeSet("v1", "sha256:def", objects)
+	g.Expect(ok).To(BeFalse(), "different manifests must be applied")
+
+	journal.Phase = applyJournalApplying
+	_, ok = journal.resumeChangeSet("v1", "sha256:abc", objects)
+	g.Expect(ok).To(BeFalse(), "a partially applied revision must be applied again")
+
+	var missing *applyJournal
+	_, ok = missing.resumeChangeSet("v1", "sha256:abc", objects)
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestApplyJournal_Ownership(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "flux-system", UID: "uid"},
+	}
+	other := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "flux-system", UID: "other-uid"},
+	}
+	foreign := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-apply-journal", Namespace: "flux-system"},
+		Data:       map[string]string{"key": "value"},
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(obj, other, foreign).Build()
+	r := &KustomizationReconciler{
+		Client:       kubeClient,
+		ApplyJournal: true,
+	}
+
+	// The journal is stored in a ConfigMap owned by the Kustomization.
+	g.Expect(r.setApplyJournal(ctx, obj, &applyJournal{Revision: "v1", Phase: applyJournalApplying})).To(Succeed())
+	journal, err := r.getApplyJournal(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(journal.Revision).To(Equal("v1"))
+	r.deleteApplyJournal(ctx, obj)
+	journal, err = r.getApplyJournal(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(journal).To(BeNil())
+
+	// A ConfigMap not owned by the Kustomization is neither used, updated nor deleted.
+	_, err = r.getApplyJournal(ctx, other)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not owned by the Kustomization"))
+	err = r.setApplyJournal(ctx, other, &applyJournal{Revision: "v1", Phase: applyJournalApplying})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not owned by the Kustomization"))
+	r.deleteApplyJournal(ctx, other)
+	g.Expect(kubeClient.Get(ctx, client.ObjectKeyFromObject(foreign), foreign)).To(Succeed())
+	g.Expect(foreign.Data).To(Equal(map[string]string{"key": "value"}))
+}
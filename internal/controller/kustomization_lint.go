@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// Lint builds the Kustomization from the sources found at root with the
+// code paths of the reconciliation: the path checks, the generation of the
+// kustomization.yaml, the transformer configs, the build options, the
+// decryption and the post build substitutions. The objects referenced by
+// the Kustomization, e.g. the decryption Secret, are read with the client
+// of the reconciler. The sources are copied to a temporary directory, as
+// the build writes to them. It returns the objects applied by the
+// Kustomization and the warnings of the build.
+//
+// The image digests and the included Kustomizations, which depend on the
+// state of the registries and the cluster, are not resolved.
+func (r *KustomizationReconciler) Lint(ctx context.Context, obj *kustomizev1.Kustomization,
+	root string) ([]*unstructured.Unstructured, []string, error) {
+	tmpDir, err := os.MkdirTemp("", obj.GetName())
+	if err != nil {
+		return nil, nil, fmt.Errorf("tmp dir error: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := copySources(root, tmpDir); err != nil {
+		return nil, nil, fmt.Errorf("failed to copy the sources: %w", err)
+	}
+
+	dirPath, err := securejoin.SecureJoin(tmpDir, obj.Spec.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := os.Stat(dirPath); err != nil {
+		return nil, nil, fmt.Errorf("kustomization path not found: %w", err)
+	}
+
+	k, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.generate(unstructured.Unstructured{Object: k}, tmpDir, dirPath); err != nil {
+		return nil, nil, err
+	}
+	if err := r.injectTransformerConfigs(ctx, obj, dirPath); err != nil {
+		return nil, nil, err
+	}
+	resources, _, err := r.build(ctx, obj, unstructured.Unstructured{Object: k}, tmpDir, dirPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings := buildWarnings(tmpDir, dirPath)
+	if conditions.IsTrue(obj, kustomizev1.DecryptionKeyExpiringCondition) {
+		warnings = append(warnings, conditions.GetMessage(obj, kustomizev1.DecryptionKeyExpiringCondition))
+	}
+	if conditions.IsTrue(obj, kustomizev1.DecryptionRecipientsMismatchCondition) {
+		warnings = append(warnings, conditions.GetMessage(obj, kustomizev1.DecryptionRecipientsMismatchCondition))
+	}
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader(resources))
+	if err != nil {
+		return nil, nil, err
+	}
+	return filterCRDs(obj, objects), warnings, nil
+}
+
+// copySources copies the regular files, directories and symlinks of the
+// root directory to the destination directory, skipping the .git directory.
+func copySources(root, dst string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0o755)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return copyFile(path, target)
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
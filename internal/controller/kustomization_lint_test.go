@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestLint(t *testing.T) {
+	g := NewWithT(t)
+
+	root := t.TempDir()
+	g.Expect(os.MkdirAll(filepath.Join(root, "apps"), 0o755)).To(Succeed())
+	files := map[string]string{
+		"apps/app.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  env: ${env}
+`,
+		"apps/crd.yaml": `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: apps.example.com
+`,
+	}
+	for name, content := range files {
+		g.Expect(os.WriteFile(filepath.Join(root, name), []byte(content), 0o644)).To(Succeed())
+	}
+
+	vars := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "vars", Namespace: "apps"},
+		Data:       map[string]string{"env": "prod"},
+	}
+	r := &KustomizationReconciler{
+		Client:        fake.NewClientBuilder().WithObjects(vars).Build(),
+		EventRecorder: &record.FakeRecorder{},
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Path:      "./apps",
+			CRDPolicy: kustomizev1.CRDPolicyDelegate,
+			PostBuild: &kustomizev1.PostBuild{
+				SubstituteFrom: []kustomizev1.SubstituteReference{{Kind: "ConfigMap", Name: "vars"}},
+			},
+		},
+	}
+
+	objects, _, err := r.Lint(context.Background(), obj, root)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(1))
+	env, _, _ := unstructured.NestedString(objects[0].Object, "data", "env")
+	g.Expect(env).To(Equal("prod"))
+
+	// The kustomization.yaml is generated in the copy of the sources.
+	_, err = os.Stat(filepath.Join(root, "apps", "kustomization.yaml"))
+	g.Expect(os.IsNotExist(err)).To(BeTrue())
+
+	obj.Spec.PostBuild.SubstituteFrom[0].Name = "missing"
+	_, _, err = r.Lint(context.Background(), obj, root)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("missing"))
+
+	obj.Spec.Path = "./missing"
+	_, _, err = r.Lint(context.Background(), obj, root)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("kustomization path not found"))
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update;delete
+
+const (
+	// lockLeasePrefix is the name prefix of the Leases of the locks.
+	lockLeasePrefix = "kustomize-lock-"
+
+	// lockLeaseMargin is added to the timeout of the Kustomization holding
+	// a lock to obtain the duration of its Lease, after which the lock is
+	// released if the holder did not release it, e.g. on a controller crash.
+	lockLeaseMargin = 30 * time.Second
+)
+
+// lockWaitError is returned by acquireLock when the lock is
+// held by another Kustomization.
+type lockWaitError struct {
+	name   string
+	holder string
+}
+
+func (e *lockWaitError) Error() string {
+	holder := "another Kustomization"
+	if e.holder != "" {
+		holder = "Kustomization " + e.holder
+	}
+	return fmt.Sprintf("lock '%s' is held by %s", e.name, holder)
+}
+
+// lockKey returns the key of the Lease of the lock with the given name.
+// The Leases are created in the LockNamespace, or in the namespace of the
+// Kustomization if not set.
+func (r *KustomizationReconciler) lockKey(obj *kustomizev1.Kustomization, name string) types.NamespacedName {
+	namespace := r.LockNamespace
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	return types.NamespacedName{Namespace: namespace, Name: lockLeasePrefix + name}
+}
+
+// acquireLock acquires the lock of the Kustomization by taking over its
+// Lease, if the Lease is free, expired or already held by the Kustomization.
+// It returns a lockWaitError if the lock is held by another Kustomization,
+// and records the wait in status. The returned function releases the lock.
+func (r *KustomizationReconciler) acquireLock(ctx context.Context,
+	obj *kustomizev1.Kustomization) (func(), error) {
+	name := obj.GetLockName()
+	if name == "" {
+		obj.Status.LockWait = nil
+		return func() {}, nil
+	}
+
+	key := r.lockKey(obj, name)
+	identity := client.ObjectKeyFromObject(obj).String()
+	now := metav1.NewMicroTime(time.Now())
+	duration := int32((obj.GetTimeout() + lockLeaseMargin).Seconds())
+
+	lease := &coordinationv1.Lease{}
+	err := r.APIReader.Get(ctx, key, lease)
+	switch {
+	case apierrors.IsNotFound(err):
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &identity,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		err = r.Create(ctx, lease)
+	case err != nil:
+		return nil, fmt.Errorf("failed to get lock '%s': %w", name, err)
+	default:
+		holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+		if holder != "" && holder != identity && !isLeaseExpired(lease, now.Time) {
+			return nil, r.waitForLock(obj, name, holder)
+		}
+		if holder != identity {
+			lease.Spec.AcquireTime = &now
+		}
+		lease.Spec.HolderIdentity = &identity
+		lease.Spec.LeaseDurationSeconds = &duration
+		lease.Spec.RenewTime = &now
+		err = r.Update(ctx, lease)
+	}
+	if err != nil {
+		// Another Kustomization acquired the lock concurrently.
+		if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
+			return nil, r.waitForLock(obj, name, "")
+		}
+		return nil, fmt.Errorf("failed to acquire lock '%s': %w", name, err)
+	}
+
+	obj.Status.LockWait = nil
+	return func() { r.releaseLock(ctx, key, identity) }, nil
+}
+
+// waitForLock records in status the wait for the lock held by the given
+// holder, keeping the start of a wait already recorded for the same lock.
+func (r *KustomizationReconciler) waitForLock(obj *kustomizev1.Kustomization, name, holder string) error {
+	if w := obj.Status.LockWait; w == nil || w.Name != name {
+		obj.Status.LockWait = &kustomizev1.LockWait{Name: name, Since: metav1.Now()}
+	}
+	obj.Status.LockWait.Holder = holder
+	return &lockWaitError{name: name, holder: holder}
+}
+
+// releaseLock deletes the Lease of the lock if it is still held by the
+// given identity.
+func (r *KustomizationReconciler) releaseLock(ctx context.Context, key types.NamespacedName, identity string) {
+	lease := &coordinationv1.Lease{}
+	if err := r.APIReader.Get(ctx, key, lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to release lock", "lease", key)
+		}
+		return
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != identity {
+		return
+	}
+	if err := r.Delete(ctx, lease, client.Preconditions{
+		UID:             &lease.UID,
+		ResourceVersion: &lease.ResourceVersion,
+	}); client.IgnoreNotFound(err) != nil && !apierrors.IsConflict(err) {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to release lock", "lease", key)
+	}
+}
+
+// isLeaseExpired returns true if the Lease was not renewed within its duration.
+func isLeaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return !now.Before(expiry)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestAcquireLock(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &KustomizationReconciler{
+		Client:        kubeClient,
+		APIReader:     kubeClient,
+		LockNamespace: "flux-system",
+	}
+
+	newKustomization := func(name string) *kustomizev1.Kustomization {
+		return &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenants"},
+			Spec: kustomizev1.KustomizationSpec{
+				TargetNamespace: "apps",
+				Lock:            &kustomizev1.Lock{},
+			},
+		}
+	}
+	leaseKey := types.NamespacedName{Namespace: "flux-system", Name: "kustomize-lock-apps"}
+
+	// Without a lock, nothing is acquired.
+	unlocked := newKustomization("unlocked")
+	unlocked.Spec.Lock = nil
+	release, err := r.acquireLock(ctx, unlocked)
+	g.Expect(err).ToNot(HaveOccurred())
+	release()
+
+	// The first Kustomization acquires the lock of the target namespace.
+	frontend := newKustomization("frontend")
+	releaseFrontend, err := r.acquireLock(ctx, frontend)
+	g.Expect(err).ToNot(HaveOccurred())
+	lease := &coordinationv1.Lease{}
+	g.Expect(kubeClient.Get(ctx, leaseKey, lease)).To(Succeed())
+	g.Expect(lease.Spec.HolderIdentity).To(Equal(ptr.To("tenants/frontend")))
+
+	// The lock can be acquired again by its holder.
+	_, err = r.acquireLock(ctx, frontend)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The second Kustomization waits for the lock.
+	backend := newKustomization("backend")
+	_, err = r.acquireLock(ctx, backend)
+	g.Expect(err).To(MatchError("lock 'apps' is held by Kustomization tenants/frontend"))
+	g.Expect(backend.Status.LockWait).ToNot(BeNil())
+	g.Expect(backend.Status.LockWait.Name).To(Equal("apps"))
+	g.Expect(backend.Status.LockWait.Holder).To(Equal("tenants/frontend"))
+	since := backend.Status.LockWait.Since
+
+	_, err = r.acquireLock(ctx, backend)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(backend.Status.LockWait.Since).To(Equal(since))
+
+	// The lock is acquired once released by its holder.
+	releaseFrontend()
+	g.Expect(apierrors.IsNotFound(kubeClient.Get(ctx, leaseKey, lease))).To(BeTrue())
+	releaseBackend, err := r.acquireLock(ctx, backend)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(backend.Status.LockWait).To(BeNil())
+
+	// The lock is taken over once the Lease of its holder expired.
+	g.Expect(kubeClient.Get(ctx, leaseKey, lease)).To(Succeed())
+	lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(time.Now().Add(-time.Hour)))
+	g.Expect(kubeClient.Update(ctx, lease)).To(Succeed())
+	_, err = r.acquireLock(ctx, frontend)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// A released lock taken over by another Kustomization is not deleted.
+	releaseBackend()
+	g.Expect(kubeClient.Get(ctx, leaseKey, lease)).To(Succeed())
+	g.Expect(lease.Spec.HolderIdentity).To(Equal(ptr.To("tenants/frontend")))
+
+	// A named lock is shared regardless of the target namespace.
+	named := newKustomization("named")
+	named.Spec.Lock.Name = "apps"
+	named.Spec.TargetNamespace = "other"
+	_, err = r.acquireLock(ctx, named)
+	g.Expect(err).To(MatchError(ContainSubstring("held by Kustomization tenants/frontend")))
+}
@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	runtimeCtrl "github.com/fluxcd/pkg/runtime/controller"
 	"github.com/fluxcd/pkg/runtime/predicates"
@@ -44,6 +45,11 @@ type KustomizationReconcilerOptions struct {
 	WatchConfigsPredicate      predicate.Predicate
 	WatchExternalArtifacts     bool
 	CancelHealthCheckOnRequeue bool
+
+	// EnqueueFilters are run on the Kustomizations referencing a changed
+	// source, ConfigMap, Secret or included Kustomization, after the
+	// built-in filters, to decide whether their reconciliation is enqueued.
+	EnqueueFilters []EnqueueFilter
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -59,6 +65,8 @@ func (r *KustomizationReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		indexSecret           = ".metadata.secret"
 	)
 
+	r.enqueueFilters = opts.EnqueueFilters
+
 	// Index the Kustomizations by the OCIRepository references they (may) point at.
 	if err := mgr.GetCache().IndexField(ctx, &kustomizev1.Kustomization{}, indexOCIRepository,
 		r.indexBy(sourcev1.OCIRepositoryKind)); err != nil {
@@ -101,6 +109,14 @@ func (r *KustomizationReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 					}
 				}
 			}
+			if bo := obj.Spec.BuildOptions; bo != nil {
+				for _, ref := range bo.TransformerConfigsFrom {
+					keys = append(keys, fmt.Sprintf("%s/%s", namespace, ref.Name))
+				}
+				if ref := bo.OpenAPISchemaFrom; ref != nil {
+					keys = append(keys, fmt.Sprintf("%s/%s", namespace, ref.Name))
+				}
+			}
 			return keys
 		},
 	); err != nil {
@@ -119,6 +135,18 @@ func (r *KustomizationReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 			if kc := obj.Spec.KubeConfig; kc != nil && kc.SecretRef != nil {
 				keys = append(keys, fmt.Sprintf("%s/%s", namespace, kc.SecretRef.Name))
 			}
+			if ref := obj.Spec.KubeConfigClusterRef; ref != nil {
+				keys = append(keys, fmt.Sprintf("%s/%s", namespace, clusterAPIKubeConfigSecretName(ref.Name)))
+			}
+			if kp := obj.Spec.KubeConfigProxy; kp != nil && kp.CASecretRef != nil {
+				keys = append(keys, fmt.Sprintf("%s/%s", namespace, kp.CASecretRef.Name))
+			}
+			if ps := obj.Spec.ImagePullSecret; ps != nil {
+				keys = append(keys, fmt.Sprintf("%s/%s", namespace, ps.TemplateRef.Name))
+			}
+			if dr := obj.Spec.ImageDigestResolution; dr != nil && dr.SecretRef != nil {
+				keys = append(keys, fmt.Sprintf("%s/%s", namespace, dr.SecretRef.Name))
+			}
 			if pb := obj.Spec.PostBuild; pb != nil {
 				for _, ref := range pb.SubstituteFrom {
 					if ref.Kind == "Secret" {
@@ -132,6 +160,12 @@ func (r *KustomizationReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		return fmt.Errorf("failed creating index %s: %w", indexSecret, err)
 	}
 
+	// Index the Kustomizations by the Kustomizations they include.
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &kustomizev1.Kustomization{}, indexInclude,
+		indexByInclude); err != nil {
+		return fmt.Errorf("failed creating index %s: %w", indexInclude, err)
+	}
+
 	var blder *builder.Builder
 	var toComplete reconcile.TypedReconciler[reconcile.Request]
 	var enqueueRequestsFromMapFunc func(objKind string, fn handler.MapFunc) handler.EventHandler
@@ -171,18 +205,23 @@ func (r *KustomizationReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 			&sourcev1.Bucket{},
 			enqueueRequestsFromMapFunc(sourcev1.BucketKind, r.requestsForRevisionChangeOf(indexBucket)),
 			builder.WithPredicates(SourceRevisionChangePredicate{}),
+		).
+		Watches(
+			&kustomizev1.Kustomization{},
+			enqueueRequestsFromMapFunc(kustomizev1.KustomizationKind, r.requestsForExportChangeOf(indexInclude)),
+			builder.WithPredicates(ExportedBuildChangePredicate{}),
 		)
 
 	if opts.WatchConfigs {
 		blder = blder.
 			WatchesMetadata(
 				&corev1.ConfigMap{},
-				enqueueRequestsFromMapFunc("ConfigMap", r.requestsForConfigDependency(indexConfigMap)),
+				enqueueRequestsFromMapFunc("ConfigMap", r.requestsForConfigDependency(indexConfigMap, "ConfigMap")),
 				builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}, opts.WatchConfigsPredicate),
 			).
 			WatchesMetadata(
 				&corev1.Secret{},
-				enqueueRequestsFromMapFunc("Secret", r.requestsForConfigDependency(indexSecret)),
+				enqueueRequestsFromMapFunc("Secret", r.requestsForConfigDependency(indexSecret, "Secret")),
 				builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}, opts.WatchConfigsPredicate),
 			)
 	}
@@ -195,5 +234,22 @@ func (r *KustomizationReconciler) SetupWithManager(ctx context.Context, mgr ctrl
 		)
 	}
 
+	if r.WatchInventoryKinds {
+		inventoryCache, err := newInventoryCache(mgr)
+		if err != nil {
+			return fmt.Errorf("failed creating inventory cache: %w", err)
+		}
+		if err := mgr.Add(inventoryCache); err != nil {
+			return fmt.Errorf("failed adding inventory cache: %w", err)
+		}
+		r.inventoryWatcher = newInventoryWatcher(inventoryCache, r.ControllerName)
+		blder = blder.WatchesRawSource(source.Channel(
+			r.inventoryWatcher.events,
+			enqueueRequestsFromMapFunc(kustomizev1.KustomizationKind, func(_ context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(obj)}}
+			}),
+		))
+	}
+
 	return blder.WithOptions(controller.Options{RateLimiter: opts.RateLimiter}).Complete(toComplete)
 }
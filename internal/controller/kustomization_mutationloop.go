@@ -0,0 +1,250 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/ssa"
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// mutationLoopThreshold is the number of consecutive reconciliations of the
+// same revision in which a field is found drifted after being corrected,
+// from which the field is considered to be fought over by a mutating
+// admission webhook or another controller.
+const mutationLoopThreshold = 3
+
+// mutationTracker records the fields drifted in the reconciliations
+// of a Kustomization for the same revision.
+type mutationTracker struct {
+	revision string
+	// suspects holds the objects configured by the last apply.
+	suspects map[string]struct{}
+	// flips counts the consecutive drift corrections of the object fields.
+	flips map[string]int
+	// loops holds the object fields whose drift correction is suspended.
+	loops map[string]mutationLoop
+}
+
+// mutationLoop describes an object field whose drift correction is suspended.
+type mutationLoop struct {
+	id      string
+	subject string
+	path    string
+	manager string
+	rule    jsondiff.IgnoreRule
+}
+
+func (l mutationLoop) String() string {
+	return fmt.Sprintf("%s %s reverted by %s", l.subject, l.path, l.manager)
+}
+
+// mutationLoopIgnoreRules returns drift ignore rules for the fields which
+// keep drifting after being corrected for the same revision, e.g. because a
+// mutating admission webhook rewrites them on every apply. The fields are
+// found by diffing the objects configured by the previous apply, and their
+// drift correction is suspended until the revision changes. The loops are
+// reported with the MutationLoopDetected condition.
+func (r *KustomizationReconciler) mutationLoopIgnoreRules(ctx context.Context,
+	kubeClient client.Client,
+	obj *kustomizev1.Kustomization,
+	revision string,
+	originRevision string,
+	objects []*unstructured.Unstructured) []jsondiff.IgnoreRule {
+	log := ctrl.LoggerFrom(ctx)
+
+	key := client.ObjectKeyFromObject(obj).String()
+	t := &mutationTracker{revision: revision}
+	if v, ok := r.mutationTrackers.Load(key); ok && v.(*mutationTracker).revision == revision {
+		t = v.(*mutationTracker)
+	} else {
+		r.mutationTrackers.Store(key, t)
+	}
+
+	var detected []string
+	for _, u := range objects {
+		id := object.UnstructuredToObjMetadata(u).String()
+		if _, ok := t.suspects[id]; !ok {
+			continue
+		}
+
+		diff, err := jsondiff.Unstructured(ctx, kubeClient, u, jsondiff.FieldOwner(r.ControllerName))
+		if err != nil {
+			log.Info("skipping mutation loop detection, failed to diff object",
+				"object", ssautil.FmtUnstructured(u), "error", err.Error())
+			continue
+		}
+
+		drifted := make(map[string]struct{})
+		if diff.Type == jsondiff.DiffTypeUpdate {
+			for _, op := range diff.Patch {
+				drifted[id+op.Path] = struct{}{}
+				if _, ok := t.loops[id+op.Path]; ok {
+					continue
+				}
+				if t.flips == nil {
+					t.flips = make(map[string]int)
+				}
+				t.flips[id+op.Path]++
+				if t.flips[id+op.Path] < mutationLoopThreshold {
+					continue
+				}
+
+				gvk := u.GroupVersionKind()
+				loop := mutationLoop{
+					id:      id,
+					subject: ssautil.FmtUnstructured(u),
+					path:    op.Path,
+					manager: mutatingManager(diff.ClusterObject, op.Path, r.ControllerName),
+					rule: jsondiff.IgnoreRule{
+						Paths: []string{op.Path},
+						Selector: &jsondiff.Selector{
+							Group:     regexp.QuoteMeta(gvk.Group),
+							Kind:      regexp.QuoteMeta(gvk.Kind),
+							Name:      regexp.QuoteMeta(u.GetName()),
+							Namespace: regexp.QuoteMeta(u.GetNamespace()),
+						},
+					},
+				}
+				if t.loops == nil {
+					t.loops = make(map[string]mutationLoop)
+				}
+				t.loops[id+op.Path] = loop
+				detected = append(detected, loop.String())
+			}
+		}
+
+		// Reset the count of the fields no longer drifted.
+		for k := range t.flips {
+			if _, ok := drifted[k]; !ok && strings.HasPrefix(k, id+"/") {
+				delete(t.flips, k)
+			}
+		}
+	}
+
+	if len(detected) > 0 {
+		sort.Strings(detected)
+		msg := fmt.Sprintf("Mutation loop detected, suspended the drift correction of:\n%s",
+			strings.Join(detected, "\n"))
+		log.Info(msg, "revision", revision)
+		r.event(obj, revision, originRevision, eventv1.EventSeverityError, msg, nil)
+	}
+
+	if len(t.loops) == 0 {
+		conditions.Delete(obj, kustomizev1.MutationLoopDetectedCondition)
+		return nil
+	}
+
+	var rules []jsondiff.IgnoreRule
+	var loops []string
+	for _, loop := range t.loops {
+		rules = append(rules, loop.rule)
+		loops = append(loops, loop.String())
+	}
+	sort.Strings(loops)
+	conditions.MarkTrue(obj, kustomizev1.MutationLoopDetectedCondition, kustomizev1.MutationLoopReason,
+		"Drift correction suspended for revision %s of fields reverted on every apply:\n%s",
+		revision, strings.Join(loops, "\n"))
+	return rules
+}
+
+// recordMutationSuspects records the objects configured by the apply,
+// whose fields are diffed by the next reconciliation of the same revision.
+func (r *KustomizationReconciler) recordMutationSuspects(obj *kustomizev1.Kustomization, changeSet *ssa.ChangeSet) {
+	v, ok := r.mutationTrackers.Load(client.ObjectKeyFromObject(obj).String())
+	if !ok {
+		return
+	}
+	t := v.(*mutationTracker)
+	t.suspects = make(map[string]struct{})
+	if changeSet == nil {
+		return
+	}
+	for _, entry := range changeSet.Entries {
+		if entry.Action == ssa.ConfiguredAction {
+			t.suspects[entry.ObjMetadata.String()] = struct{}{}
+		}
+	}
+}
+
+// deleteMutationTracker removes the mutation tracker of the Kustomization.
+func (r *KustomizationReconciler) deleteMutationTracker(obj *kustomizev1.Kustomization) {
+	r.mutationTrackers.Delete(client.ObjectKeyFromObject(obj).String())
+}
+
+// mutatingManager returns the field managers other than the controller
+// owning the field at the given JSON pointer in the in-cluster object.
+// The changes made by mutating admission webhooks are attributed to the
+// manager of the request, in which case no other manager owns the field.
+func mutatingManager(cluster client.Object, pointer, controllerName string) string {
+	var managers []string
+	if cluster != nil {
+		for _, entry := range cluster.GetManagedFields() {
+			if entry.Manager != controllerName && ownsPath(entry.FieldsV1, pointer) {
+				managers = append(managers, entry.Manager)
+			}
+		}
+	}
+	if len(managers) == 0 {
+		return "a mutating admission webhook"
+	}
+	sort.Strings(managers)
+	return strings.Join(managers, ", ")
+}
+
+// ownsPath returns true if the managed fields set owns the field at the
+// given JSON pointer. As the keys of the list items can't be derived from
+// the pointer, the fields nested in a list are matched up to the list.
+func ownsPath(fields *metav1.FieldsV1, pointer string) bool {
+	if fields == nil {
+		return false
+	}
+	var set map[string]any
+	if err := json.Unmarshal(fields.Raw, &set); err != nil {
+		return false
+	}
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if _, err := strconv.Atoi(segment); err == nil {
+			return len(set) > 0
+		}
+		next, ok := set["f:"+unescape.Replace(segment)].(map[string]any)
+		if !ok {
+			return false
+		}
+		set = next
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/ssa"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestMutationLoopIgnoreRules(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	newConfigMap := func(value string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetNamespace("default")
+		u.SetName("app")
+		_ = unstructured.SetNestedField(u.Object, value, "data", "key")
+		return u
+	}
+
+	// Simulate a webhook rewriting the field on every apply,
+	// but not on the dry-run requests.
+	kubeClient := fake.NewClientBuilder().
+		WithObjects(newConfigMap("mutated")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+				obj.SetResourceVersion("1")
+				return nil
+			},
+		}).
+		Build()
+
+	r := &KustomizationReconciler{
+		ControllerName: "kustomize-controller",
+		EventRecorder:  record.NewFakeRecorder(32),
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "flux-system"},
+	}
+	desired := newConfigMap("desired")
+	configured := &ssa.ChangeSet{Entries: []ssa.ChangeSetEntry{{
+		ObjMetadata: object.UnstructuredToObjMetadata(desired),
+		Action:      ssa.ConfiguredAction,
+	}}}
+
+	// The first reconciliation corrects the drift without diffing.
+	g.Expect(r.mutationLoopIgnoreRules(ctx, kubeClient, obj, "rev1", "", []*unstructured.Unstructured{desired})).To(BeEmpty())
+	r.recordMutationSuspects(obj, configured)
+
+	for range mutationLoopThreshold - 1 {
+		g.Expect(r.mutationLoopIgnoreRules(ctx, kubeClient, obj, "rev1", "", []*unstructured.Unstructured{desired})).To(BeEmpty())
+		r.recordMutationSuspects(obj, configured)
+		g.Expect(conditions.Has(obj, kustomizev1.MutationLoopDetectedCondition)).To(BeFalse())
+	}
+
+	rules := r.mutationLoopIgnoreRules(ctx, kubeClient, obj, "rev1", "", []*unstructured.Unstructured{desired})
+	g.Expect(rules).To(HaveLen(1))
+	g.Expect(rules[0].Paths).To(Equal([]string{"/data/key"}))
+	g.Expect(rules[0].Selector.Kind).To(Equal("ConfigMap"))
+	g.Expect(rules[0].Selector.Name).To(Equal("app"))
+	g.Expect(conditions.IsTrue(obj, kustomizev1.MutationLoopDetectedCondition)).To(BeTrue())
+	g.Expect(conditions.GetMessage(obj, kustomizev1.MutationLoopDetectedCondition)).To(
+		ContainSubstring("ConfigMap/default/app /data/key reverted by a mutating admission webhook"))
+
+	// The drift correction is resumed for a new revision.
+	g.Expect(r.mutationLoopIgnoreRules(ctx, kubeClient, obj, "rev2", "", []*unstructured.Unstructured{desired})).To(BeEmpty())
+	g.Expect(conditions.Has(obj, kustomizev1.MutationLoopDetectedCondition)).To(BeFalse())
+}
+
+func TestMutatingManager(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &unstructured.Unstructured{}
+	cluster.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:  "kustomize-controller",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"app\"}":{"f:image":{}}}}}}}`)},
+		},
+		{
+			Manager:  "sidecar-injector",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"proxy\"}":{}}}}}}`)},
+		},
+		{
+			Manager:  "kubectl-edit",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:example.com/owner":{}}}}`)},
+		},
+	})
+
+	g.Expect(mutatingManager(cluster, "/spec/replicas", "kustomize-controller")).To(Equal("a mutating admission webhook"))
+	g.Expect(mutatingManager(cluster, "/spec/template/spec/containers/1", "kustomize-controller")).To(Equal("sidecar-injector"))
+	g.Expect(mutatingManager(cluster, "/metadata/annotations/example.com~1owner", "kustomize-controller")).To(Equal("kubectl-edit"))
+	g.Expect(mutatingManager(nil, "/spec/replicas", "kustomize-controller")).To(Equal("a mutating admission webhook"))
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"maps"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// setNamespaceLabels sets the labels on the Namespaces applied by the
+// Kustomization. The default labels are overridden by the labels set in the
+// manifests, while the mandatory labels override them. As the labels are
+// part of the applied objects, their removal or modification in-cluster is
+// reverted by the drift correction.
+func setNamespaceLabels(objects []*unstructured.Unstructured, defaults, mandatory map[string]string) {
+	if len(defaults) == 0 && len(mandatory) == 0 {
+		return
+	}
+	for _, u := range objects {
+		gvk := u.GroupVersionKind()
+		if gvk.Group != "" || gvk.Kind != "Namespace" {
+			continue
+		}
+
+		labels := make(map[string]string, len(defaults)+len(mandatory))
+		maps.Copy(labels, defaults)
+		maps.Copy(labels, u.GetLabels())
+		maps.Copy(labels, mandatory)
+		u.SetLabels(labels)
+	}
+}
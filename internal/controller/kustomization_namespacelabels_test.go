@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+)
+
+func TestSetNamespaceLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: legacy
+  labels:
+    team: legacy
+    pod-security.kubernetes.io/enforce: privileged
+    pod-security.kubernetes.io/warn: baseline
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: apps
+`)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	defaults := map[string]string{
+		"pod-security.kubernetes.io/enforce": "baseline",
+		"pod-security.kubernetes.io/warn":    "restricted",
+	}
+	mandatory := map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+	}
+	setNamespaceLabels(objects, defaults, mandatory)
+
+	byName := make(map[string]*unstructured.Unstructured)
+	for _, u := range objects {
+		byName[u.GetName()] = u
+	}
+
+	g.Expect(byName["apps"].GetLabels()).To(Equal(map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+		"pod-security.kubernetes.io/warn":    "restricted",
+	}))
+	g.Expect(byName["legacy"].GetLabels()).To(Equal(map[string]string{
+		"team":                               "legacy",
+		"pod-security.kubernetes.io/enforce": "restricted",
+		"pod-security.kubernetes.io/warn":    "baseline",
+	}))
+	g.Expect(byName["settings"].GetLabels()).To(BeEmpty())
+}
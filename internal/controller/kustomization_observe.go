@@ -0,0 +1,153 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// reconcileObserve lists the objects matching the ObserveSelectorAnnotation
+// in the namespaces of the manifests, and reports in status the ones absent
+// from the manifests as cleanup candidates, and the ones present in the
+// manifests but not in the inventory as adoption candidates. Only the
+// namespaced kinds of the manifests are listed, and the objects controlled
+// by another object, e.g. the ReplicaSets of a Deployment, are skipped.
+// Nothing is applied or deleted.
+func (r *KustomizationReconciler) reconcileObserve(ctx context.Context,
+	kubeClient client.Client,
+	mapper apimeta.RESTMapper,
+	obj *kustomizev1.Kustomization,
+	revision string,
+	originRevision string,
+	oldInventory *kustomizev1.ResourceInventory,
+	objects []*unstructured.Unstructured) error {
+	selectorValue := obj.GetAnnotations()[kustomizev1.ObserveSelectorAnnotation]
+	selector, err := labels.Parse(selectorValue)
+	if err != nil {
+		err = fmt.Errorf("invalid observe selector: %w", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	managed := make(map[string]bool)
+	if oldInventory != nil {
+		for _, entry := range oldInventory.Entries {
+			managed[entry.ID] = true
+		}
+	}
+	desired := make(map[string]bool, len(objects))
+	namespaces := make(map[string]bool)
+	if obj.Spec.TargetNamespace != "" {
+		namespaces[obj.Spec.TargetNamespace] = true
+	}
+	var kinds []schema.GroupVersionKind
+	for _, u := range objects {
+		desired[object.UnstructuredToObjMetadata(u).String()] = true
+		gvk := u.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			// The kinds unknown to the API server have no objects to list.
+			if apimeta.IsNoMatchError(err) {
+				continue
+			}
+			err = fmt.Errorf("failed to get the scope of %s: %w", gvk.Kind, err)
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+			return err
+		}
+		if mapping.Scope.Name() != apimeta.RESTScopeNameNamespace {
+			continue
+		}
+		if u.GetNamespace() != "" {
+			namespaces[u.GetNamespace()] = true
+		}
+		if !slices.Contains(kinds, gvk) {
+			kinds = append(kinds, gvk)
+		}
+	}
+
+	var adoption, cleanup []kustomizev1.ResourceRef
+	for _, gvk := range kinds {
+		for ns := range namespaces {
+			list := &metav1.PartialObjectMetadataList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := kubeClient.List(ctx, list, client.InNamespace(ns),
+				client.MatchingLabelsSelector{Selector: selector}); err != nil {
+				err = fmt.Errorf("failed to list %s in namespace '%s': %w", gvk.Kind, ns, err)
+				conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+				return err
+			}
+			for _, item := range list.Items {
+				if metav1.GetControllerOf(&item) != nil {
+					continue
+				}
+				id := object.ObjMetadata{
+					Namespace: item.GetNamespace(),
+					Name:      item.GetName(),
+					GroupKind: gvk.GroupKind(),
+				}.String()
+				if managed[id] {
+					continue
+				}
+				ref := kustomizev1.ResourceRef{ID: id, Version: gvk.Version}
+				if desired[id] {
+					adoption = append(adoption, ref)
+				} else {
+					cleanup = append(cleanup, ref)
+				}
+			}
+		}
+	}
+	sortRefs := func(a, b kustomizev1.ResourceRef) int { return strings.Compare(a.ID, b.ID) }
+	slices.SortFunc(adoption, sortRefs)
+	slices.SortFunc(cleanup, sortRefs)
+
+	obj.Status.LastObservation = &kustomizev1.Observation{
+		Revision:           revision,
+		Selector:           selectorValue,
+		AdoptionCandidates: adoption,
+		CleanupCandidates:  cleanup,
+		HandledAt:          metav1.Now(),
+	}
+
+	msg := fmt.Sprintf("Observed the objects of the target namespace: %d adoption candidates, %d cleanup candidates",
+		len(adoption), len(cleanup))
+	ctrl.LoggerFrom(ctx).Info(msg, "revision", revision, "selector", selectorValue)
+	r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+	conditions.MarkTrue(obj,
+		meta.ReadyCondition,
+		meta.ReconciliationSucceededReason,
+		"Observed the objects of revision: %s", revision)
+	return nil
+}
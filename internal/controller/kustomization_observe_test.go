@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestReconcileObserve(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	partOf := map[string]string{"app.kubernetes.io/part-of": "podinfo"}
+	existing := []*appsv1.Deployment{
+		// Part of the manifests and not managed yet.
+		{ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "apps", Labels: partOf}},
+		// Part of the manifests and already managed.
+		{ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "apps", Labels: partOf}},
+		// Not part of the manifests.
+		{ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "apps", Labels: partOf}},
+		// Not matching the selector.
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "apps"}},
+		// Controlled by another object.
+		{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: "apps", Labels: partOf,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "example.com/v1", Kind: "App", Name: "parent", UID: "uid", Controller: ptr.To(true),
+			}}}},
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), apimeta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), apimeta.RESTScopeRoot)
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper)
+	for _, d := range existing {
+		builder = builder.WithObjects(d)
+	}
+	kubeClient := builder.Build()
+
+	newObject := func(kind, apiVersion, name, namespace string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName(name)
+		u.SetNamespace(namespace)
+		return u
+	}
+	objects := []*unstructured.Unstructured{
+		newObject("Namespace", "v1", "apps", ""),
+		newObject("Deployment", "apps/v1", "frontend", "apps"),
+		newObject("Deployment", "apps/v1", "backend", "apps"),
+	}
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "podinfo",
+			Namespace: "flux-system",
+			Annotations: map[string]string{
+				kustomizev1.ObserveSelectorAnnotation: "app.kubernetes.io/part-of=podinfo",
+			},
+		},
+	}
+	oldInventory := &kustomizev1.ResourceInventory{
+		Entries: []kustomizev1.ResourceRef{{ID: "apps_backend_apps_Deployment", Version: "v1"}},
+	}
+
+	r := &KustomizationReconciler{
+		Client:        kubeClient,
+		EventRecorder: record.NewFakeRecorder(32),
+	}
+	err := r.reconcileObserve(ctx, kubeClient, mapper, obj, "main@sha1:abc", "", oldInventory, objects)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsReady(obj)).To(BeTrue())
+
+	observation := obj.Status.LastObservation
+	g.Expect(observation).ToNot(BeNil())
+	g.Expect(observation.Revision).To(Equal("main@sha1:abc"))
+	g.Expect(observation.Selector).To(Equal("app.kubernetes.io/part-of=podinfo"))
+	g.Expect(observation.AdoptionCandidates).To(Equal([]kustomizev1.ResourceRef{
+		{ID: "apps_frontend_apps_Deployment", Version: "v1"},
+	}))
+	g.Expect(observation.CleanupCandidates).To(Equal([]kustomizev1.ResourceRef{
+		{ID: "apps_legacy_apps_Deployment", Version: "v1"},
+	}))
+
+	// An invalid selector fails the reconciliation.
+	obj.Annotations[kustomizev1.ObserveSelectorAnnotation] = "app in ("
+	err = r.reconcileObserve(ctx, kubeClient, mapper, obj, "main@sha1:abc", "", oldInventory, objects)
+	g.Expect(err).To(MatchError(ContainSubstring("invalid observe selector")))
+	g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(meta.ReconciliationFailedReason))
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// splitOrphaned separates the stale objects whose kind is still served by
+// the API server from the ones orphaned by the removal of their API.
+// The objects recorded with a removed API version of a kind that is still
+// served are switched to the preferred version, so that they can be pruned.
+func splitOrphaned(mapper apimeta.RESTMapper,
+	objects []*unstructured.Unstructured) ([]*unstructured.Unstructured, []*unstructured.Unstructured) {
+	var served, orphaned []*unstructured.Unstructured
+	for _, u := range objects {
+		gvk := u.GroupVersionKind()
+		_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err == nil || !apimeta.IsNoMatchError(err) {
+			// Let the delete request surface any other error.
+			served = append(served, u)
+			continue
+		}
+
+		mapping, err := mapper.RESTMapping(gvk.GroupKind())
+		switch {
+		case err == nil:
+			u.SetGroupVersionKind(mapping.GroupVersionKind)
+			served = append(served, u)
+		case apimeta.IsNoMatchError(err):
+			orphaned = append(orphaned, u)
+		default:
+			served = append(served, u)
+		}
+	}
+	return served, orphaned
+}
+
+// resourceRefs returns the inventory entries of the given objects.
+func resourceRefs(objects []*unstructured.Unstructured) []kustomizev1.ResourceRef {
+	if len(objects) == 0 {
+		return nil
+	}
+	refs := make([]kustomizev1.ResourceRef, 0, len(objects))
+	for _, u := range objects {
+		refs = append(refs, kustomizev1.ResourceRef{
+			ID:      object.UnstructuredToObjMetadata(u).String(),
+			Version: u.GroupVersionKind().Version,
+		})
+	}
+	return refs
+}
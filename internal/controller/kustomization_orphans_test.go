@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestSplitOrphaned(t *testing.T) {
+	g := NewWithT(t)
+
+	autoscalingV2 := schema.GroupVersion{Group: "autoscaling", Version: "v2"}
+	mapper := apimeta.NewDefaultRESTMapper([]schema.GroupVersion{autoscalingV2})
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, apimeta.RESTScopeNamespace)
+	mapper.Add(autoscalingV2.WithKind("HorizontalPodAutoscaler"), apimeta.RESTScopeNamespace)
+
+	newObject := func(apiVersion, kind string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace("default")
+		u.SetName("test")
+		return u
+	}
+	cm := newObject("v1", "ConfigMap")
+	hpa := newObject("autoscaling/v2beta2", "HorizontalPodAutoscaler")
+	psp := newObject("policy/v1beta1", "PodSecurityPolicy")
+
+	served, orphaned := splitOrphaned(mapper, []*unstructured.Unstructured{cm, hpa, psp})
+	g.Expect(served).To(ConsistOf(cm, hpa))
+	g.Expect(hpa.GetAPIVersion()).To(Equal("autoscaling/v2"),
+		"removed versions of served kinds must be switched to the served version")
+	g.Expect(orphaned).To(ConsistOf(psp))
+
+	g.Expect(resourceRefs(orphaned)).To(Equal([]kustomizev1.ResourceRef{{
+		ID:      "default_test_policy_PodSecurityPolicy",
+		Version: "v1beta1",
+	}}))
+}
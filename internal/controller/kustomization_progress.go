@@ -0,0 +1,227 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// applyProgressInterval is the interval at which the progress of
+// an in-flight apply is patched in the Kustomization status.
+var applyProgressInterval = 10 * time.Second
+
+// applyProgressRateWindow is the period over which the apply
+// rate is measured to estimate the completion of the apply.
+const applyProgressRateWindow = time.Minute
+
+// The stages of the apply reported in the progress.
+const (
+	applyStageClusterDefinitions = "ClusterDefinitions"
+	applyStageClassDefinitions   = "ClassDefinitions"
+	applyStageCustom             = "CustomStage"
+	applyStageResources          = "Resources"
+	applyStageClientSide         = "ClientSide"
+)
+
+// applyProgressKey is the context key of the applyProgress.
+type applyProgressKey struct{}
+
+// applyProgress tracks the distinct objects processed by the apply
+// requests issued with its context.
+type applyProgress struct {
+	mu               sync.Mutex
+	total            int
+	customStageKinds map[schema.GroupKind]struct{}
+	stage            string
+	applied          map[string]struct{}
+	samples          []applyProgressSample
+}
+
+// applyProgressSample is the number of objects applied at a given time.
+type applyProgressSample struct {
+	at      time.Time
+	applied int
+}
+
+// newApplyProgress returns an applyProgress for the given number
+// of objects, staged with the given custom stage kinds.
+func newApplyProgress(total int, customStageKinds map[schema.GroupKind]struct{}) *applyProgress {
+	return &applyProgress{
+		total:            total,
+		customStageKinds: customStageKinds,
+		applied:          make(map[string]struct{}),
+	}
+}
+
+// add records the object as applied, and its stage as the stage in progress.
+func (p *applyProgress) add(obj client.Object) {
+	var stage string
+	var id string
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		id = u.GroupVersionKind().GroupKind().String()
+		switch {
+		case u.GetAnnotations()[kustomizev1.ApplyStrategyAnnotation] == kustomizev1.ApplyStrategyClientSide:
+			stage = applyStageClientSide
+		case ssautil.IsClusterDefinition(u):
+			stage = applyStageClusterDefinitions
+		case ssautil.IsClassDefinition(u):
+			stage = applyStageClassDefinitions
+		case ssautil.IsCustomStage(u, p.customStageKinds):
+			stage = applyStageCustom
+		default:
+			stage = applyStageResources
+		}
+	}
+	id += "/" + obj.GetNamespace() + "/" + obj.GetName()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.applied[id] = struct{}{}
+	if stage != "" {
+		p.stage = stage
+	}
+}
+
+// sample returns the progress at the given time, estimating the completion
+// of the apply from the apply rate measured over applyProgressRateWindow.
+func (p *applyProgress) sample(now time.Time) *kustomizev1.ApplyProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	applied := min(len(p.applied), p.total)
+	p.samples = append(p.samples, applyProgressSample{at: now, applied: applied})
+	for len(p.samples) > 1 && now.Sub(p.samples[1].at) >= applyProgressRateWindow {
+		p.samples = p.samples[1:]
+	}
+
+	progress := &kustomizev1.ApplyProgress{
+		Stage:          p.stage,
+		Applied:        applied,
+		Total:          p.total,
+		LastUpdateTime: metav1.NewTime(now),
+	}
+	if first := p.samples[0]; applied > first.applied && applied < p.total {
+		rate := float64(applied-first.applied) / now.Sub(first.at).Seconds()
+		eta := metav1.NewTime(now.Add(time.Duration(float64(p.total-applied) / rate * float64(time.Second))))
+		progress.EstimatedCompletion = &eta
+	}
+	return progress
+}
+
+// reportApplyProgress returns a context tracking the objects applied with
+// it, whose progress is patched in the status of the Kustomization every
+// applyProgressInterval, and a function stopping the reports, which removes
+// the progress from the status once reported. Applies completing within the
+// interval are not reported.
+func (r *KustomizationReconciler) reportApplyProgress(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	total int,
+	customStageKinds map[schema.GroupKind]struct{}) (context.Context, func()) {
+	log := ctrl.LoggerFrom(ctx)
+	key := client.ObjectKeyFromObject(obj)
+	progress := newApplyProgress(total, customStageKinds)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	var reported bool
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(applyProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := r.patchApplyProgress(ctx, key, progress.sample(now)); err != nil {
+					log.Error(err, "failed to report the apply progress")
+					continue
+				}
+				reported = true
+			}
+		}
+	}()
+
+	return context.WithValue(ctx, applyProgressKey{}, progress), func() {
+		close(done)
+		<-stopped
+		if reported {
+			if err := r.patchApplyProgress(ctx, key, nil); err != nil {
+				log.Error(err, "failed to remove the apply progress")
+			}
+		}
+	}
+}
+
+// patchApplyProgress sets the progress in the status of the Kustomization
+// with a merge patch, leaving the other status fields, owned by the serial
+// patcher of the reconciliation, untouched. A nil progress removes it.
+func (r *KustomizationReconciler) patchApplyProgress(ctx context.Context,
+	key types.NamespacedName, progress *kustomizev1.ApplyProgress) error {
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{"progress": progress},
+	})
+	if err != nil {
+		return err
+	}
+	obj := &kustomizev1.Kustomization{}
+	obj.SetName(key.Name)
+	obj.SetNamespace(key.Namespace)
+	return r.Client.Status().Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+}
+
+// applyProgressClient is a client.Client which records the objects
+// patched or created with a context tracking the apply progress.
+type applyProgressClient struct {
+	client.Client
+}
+
+func (c *applyProgressClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	if p, ok := ctx.Value(applyProgressKey{}).(*applyProgress); ok {
+		p.add(obj)
+	}
+	return nil
+}
+
+func (c *applyProgressClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	if p, ok := ctx.Value(applyProgressKey{}).(*applyProgress); ok {
+		p.add(obj)
+	}
+	return nil
+}
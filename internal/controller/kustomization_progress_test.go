@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func newProgressObject(kind, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetName(name)
+	if kind != "Namespace" {
+		u.SetNamespace("apps")
+	}
+	return u
+}
+
+func TestApplyProgress_sample(t *testing.T) {
+	g := NewWithT(t)
+
+	p := newApplyProgress(100, nil)
+	start := time.Now()
+
+	// Without a measured rate, no completion is estimated.
+	progress := p.sample(start)
+	g.Expect(progress.Applied).To(Equal(0))
+	g.Expect(progress.Total).To(Equal(100))
+	g.Expect(progress.EstimatedCompletion).To(BeNil())
+
+	// Objects applied several times are counted once.
+	p.add(newProgressObject("Namespace", "apps"))
+	p.add(newProgressObject("Namespace", "apps"))
+	progress = p.sample(start.Add(10 * time.Second))
+	g.Expect(progress.Applied).To(Equal(1))
+	g.Expect(progress.Stage).To(Equal(applyStageClusterDefinitions))
+
+	// 20 objects in 20 seconds leave 80 seconds for the last 80 objects.
+	for i := range 19 {
+		p.add(newProgressObject("ConfigMap", fmt.Sprintf("cm-%d", i)))
+	}
+	progress = p.sample(start.Add(20 * time.Second))
+	g.Expect(progress.Applied).To(Equal(20))
+	g.Expect(progress.Stage).To(Equal(applyStageResources))
+	g.Expect(progress.EstimatedCompletion).ToNot(BeNil())
+	g.Expect(progress.EstimatedCompletion.Time).To(BeTemporally("~", start.Add(100*time.Second), time.Second))
+
+	// The rate is measured from the last sample taken at least a minute
+	// ago, 60 objects in 70 seconds leave 23 seconds for the last 20.
+	for i := range 60 {
+		p.add(newProgressObject("ConfigMap", fmt.Sprintf("fast-%d", i)))
+	}
+	p.sample(start.Add(80 * time.Second))
+	progress = p.sample(start.Add(90 * time.Second))
+	g.Expect(progress.Applied).To(Equal(80))
+	g.Expect(progress.EstimatedCompletion.Time).To(BeTemporally("~", start.Add(90*time.Second+70*time.Second/3), time.Second))
+
+	// Completed applies have no estimate.
+	for i := range 30 {
+		p.add(newProgressObject("ConfigMap", fmt.Sprintf("last-%d", i)))
+	}
+	progress = p.sample(start.Add(100 * time.Second))
+	g.Expect(progress.Applied).To(Equal(100))
+	g.Expect(progress.EstimatedCompletion).To(BeNil())
+}
+
+func TestReportApplyProgress(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	applyProgressInterval = 10 * time.Millisecond
+	t.Cleanup(func() { applyProgressInterval = 10 * time.Second })
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "apps"},
+	}
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).
+			WithStatusSubresource(&kustomizev1.Kustomization{}).
+			WithObjects(obj).Build(),
+	}
+	kubeClient := &applyProgressClient{Client: fake.NewClientBuilder().Build()}
+
+	progressCtx, stop := r.reportApplyProgress(ctx, obj, 2, nil)
+	u := newProgressObject("ConfigMap", "settings")
+	g.Expect(kubeClient.Create(progressCtx, u)).To(Succeed())
+	// The objects applied with other contexts are not tracked.
+	g.Expect(kubeClient.Create(ctx, newProgressObject("ConfigMap", "other"))).To(Succeed())
+
+	g.Eventually(func() *kustomizev1.ApplyProgress {
+		current := &kustomizev1.Kustomization{}
+		g.Expect(r.Get(ctx, client.ObjectKeyFromObject(obj), current)).To(Succeed())
+		return current.Status.Progress
+	}).Should(And(
+		Not(BeNil()),
+		HaveField("Applied", 1),
+		HaveField("Total", 2),
+		HaveField("Stage", applyStageResources),
+	))
+
+	// The progress is removed once the apply completes.
+	stop()
+	current := &kustomizev1.Kustomization{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(obj), current)).To(Succeed())
+	g.Expect(current.Status.Progress).To(BeNil())
+}
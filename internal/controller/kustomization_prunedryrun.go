@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// isPruneDryRun returns true if the garbage collection of the
+// Kustomization was requested in dry-run mode.
+func isPruneDryRun(obj *kustomizev1.Kustomization) bool {
+	return obj.GetAnnotations()[kustomizev1.PruneDryRunAnnotation] == "true"
+}
+
+// pruneDryRun issues the delete requests of the given stale objects in
+// dry-run mode, and reports in status and events the objects that
+// would have been deleted.
+func (r *KustomizationReconciler) pruneDryRun(ctx context.Context,
+	manager *ssa.ResourceManager,
+	obj *kustomizev1.Kustomization,
+	revision string,
+	originRevision string,
+	objects []*unstructured.Unstructured) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	dryRunManager := ssa.NewResourceManager(client.NewDryRunClient(manager.Client()), nil, ssa.Owner{
+		Field: r.ControllerName,
+		Group: kustomizev1.GroupVersion.Group,
+	})
+
+	changeSet, err := deleteObjects(ctx, obj, dryRunManager, objects)
+	if err != nil {
+		return fmt.Errorf("garbage collection dry-run failed: %w", err)
+	}
+
+	deleted := make(map[string]bool)
+	for _, entry := range changeSet.Entries {
+		if entry.Action == ssa.DeletedAction {
+			deleted[entry.ObjMetadata.String()] = true
+		}
+	}
+	var toDelete []*unstructured.Unstructured
+	for _, u := range objects {
+		if deleted[object.UnstructuredToObjMetadata(u).String()] {
+			toDelete = append(toDelete, u)
+		}
+	}
+
+	obj.Status.PruneDryRun = &kustomizev1.PruneDryRunResult{
+		Revision:  revision,
+		Entries:   resourceRefs(toDelete),
+		HandledAt: metav1.Now(),
+	}
+
+	if len(toDelete) > 0 {
+		msg := fmt.Sprintf("garbage collection dry-run, objects to be deleted:\n%s",
+			ssautil.FmtUnstructuredList(toDelete))
+		log.Info(msg)
+		r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// generateImagePullSecret adds to the built resources a copy of the image
+// pull secret template in the target namespace, and attaches it to the
+// selected ServiceAccounts of the target namespace. The manifests are
+// returned re-encoded, or unchanged if no pull secret is requested.
+func (r *KustomizationReconciler) generateImagePullSecret(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	resources []byte) ([]byte, error) {
+	ps := obj.Spec.ImagePullSecret
+	if ps == nil {
+		return resources, nil
+	}
+	if obj.Spec.TargetNamespace == "" {
+		return nil, fmt.Errorf("the image pull secret requires the target namespace to be set")
+	}
+
+	templateKey := types.NamespacedName{Namespace: obj.GetNamespace(), Name: ps.TemplateRef.Name}
+	var template corev1.Secret
+	if err := r.Get(ctx, templateKey, &template); err != nil {
+		return nil, fmt.Errorf("unable to read image pull secret template '%s' error: %w", templateKey, err)
+	}
+	if _, ok := template.Data[corev1.DockerConfigJsonKey]; !ok {
+		return nil, fmt.Errorf("image pull secret template '%s' does not contain a '%s' key",
+			templateKey, corev1.DockerConfigJsonKey)
+	}
+
+	name := ps.Name
+	if name == "" {
+		name = template.GetName()
+	}
+	namespace := obj.Spec.TargetNamespace
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader(resources))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range objects {
+		if u.GetAPIVersion() != "v1" || u.GetNamespace() != namespace {
+			continue
+		}
+		switch u.GetKind() {
+		case "Secret":
+			if u.GetName() == name {
+				return nil, fmt.Errorf("image pull secret '%s/%s' conflicts with a Secret of the build output", namespace, name)
+			}
+		case "ServiceAccount":
+			if len(ps.ServiceAccounts) > 0 && !slices.Contains(ps.ServiceAccounts, u.GetName()) {
+				continue
+			}
+			if err := attachImagePullSecret(u, name); err != nil {
+				return nil, fmt.Errorf("unable to attach the image pull secret to %s: %w",
+					ssautil.FmtUnstructured(u), err)
+			}
+		}
+	}
+
+	data := make(map[string]any, len(template.Data))
+	for k, v := range template.Data {
+		data[k] = base64.StdEncoding.EncodeToString(v)
+	}
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"type": string(corev1.SecretTypeDockerConfigJson),
+		"data": data,
+	}}
+	objects = append(objects, secret)
+
+	manifests, err := ssautil.ObjectsToYAML(objects)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(manifests), nil
+}
+
+// attachImagePullSecret adds the named Secret to the imagePullSecrets
+// of the given ServiceAccount, if not already present.
+func attachImagePullSecret(sa *unstructured.Unstructured, name string) error {
+	refs, _, err := unstructured.NestedSlice(sa.Object, "imagePullSecrets")
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if m, ok := ref.(map[string]any); ok && m["name"] == name {
+			return nil
+		}
+	}
+	refs = append(refs, map[string]any{"name": name})
+	return unstructured.SetNestedSlice(sa.Object, refs, "imagePullSecrets")
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestGenerateImagePullSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	dockerConfig := []byte(`{"auths":{"ghcr.io":{"auth":"Zmx1eDpzZWNyZXQ="}}}`)
+	template := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "regcred", Namespace: "flux-system"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(template).Build(),
+	}
+
+	resources := []byte(`
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: default
+  namespace: tenant
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: builder
+  namespace: tenant
+imagePullSecrets:
+- name: other
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: default
+  namespace: other
+`)
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "flux-system"},
+	}
+
+	// Without pull secret settings the resources are left untouched.
+	out, err := r.generateImagePullSecret(context.Background(), obj, resources)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(out).To(Equal(resources))
+
+	obj.Spec.ImagePullSecret = &kustomizev1.ImagePullSecret{
+		TemplateRef: meta.LocalObjectReference{Name: "regcred"},
+	}
+	_, err = r.generateImagePullSecret(context.Background(), obj, resources)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("requires the target namespace"))
+
+	obj.Spec.TargetNamespace = "tenant"
+	out, err = r.generateImagePullSecret(context.Background(), obj, resources)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader(out))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(4))
+	g.Expect(pullSecretNames(objects[0])).To(Equal([]string{"regcred"}))
+	g.Expect(pullSecretNames(objects[1])).To(Equal([]string{"other", "regcred"}))
+	g.Expect(pullSecretNames(objects[2])).To(BeEmpty())
+
+	secret := objects[3]
+	g.Expect(secret.GetKind()).To(Equal("Secret"))
+	g.Expect(secret.GetName()).To(Equal("regcred"))
+	g.Expect(secret.GetNamespace()).To(Equal("tenant"))
+	data, _, _ := unstructured.NestedString(secret.Object, "data", corev1.DockerConfigJsonKey)
+	g.Expect(data).To(Equal(base64.StdEncoding.EncodeToString(dockerConfig)))
+
+	obj.Spec.ImagePullSecret.Name = "tenant-regcred"
+	obj.Spec.ImagePullSecret.ServiceAccounts = []string{"builder"}
+	out, err = r.generateImagePullSecret(context.Background(), obj, resources)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err = ssautil.ReadObjects(bytes.NewReader(out))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pullSecretNames(objects[0])).To(BeEmpty())
+	g.Expect(pullSecretNames(objects[1])).To(Equal([]string{"other", "tenant-regcred"}))
+	g.Expect(objects[3].GetName()).To(Equal("tenant-regcred"))
+
+	// The generated Secret must not override a Secret of the build output.
+	_, err = r.generateImagePullSecret(context.Background(), obj, out)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("conflicts"))
+}
+
+func pullSecretNames(sa *unstructured.Unstructured) []string {
+	refs, _, _ := unstructured.NestedSlice(sa.Object, "imagePullSecrets")
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.(map[string]any)["name"].(string))
+	}
+	return names
+}
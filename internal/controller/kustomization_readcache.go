@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// appliedWrite records the resource version of an object written by the
+// apply, an empty version meaning the object was deleted.
+type appliedWrite struct {
+	resourceVersion string
+	writtenAt       time.Time
+}
+
+// cachedReadClient is a client.Client serving the reads of the cacheable
+// kinds from the shared cache of the reconciler, unless the cache doesn't
+// hold the version last written by the controller within the staleness bound.
+type cachedReadClient struct {
+	client.Client
+	r *KustomizationReconciler
+}
+
+func (r *KustomizationReconciler) newCachedReadClient(kubeClient client.Client) client.Client {
+	return &cachedReadClient{Client: kubeClient, r: r}
+}
+
+// writeKey returns the key under which the writes of the given object are tracked.
+func writeKey(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	return fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Kind, obj.GetNamespace(), obj.GetName())
+}
+
+// cacheable returns the unstructured object if its kind is served from the cache.
+func (c *cachedReadClient) cacheable(obj client.Object) (*unstructured.Unstructured, bool) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || c.r.ApplyReadCache == nil {
+		return nil, false
+	}
+	_, ok = c.r.ApplyReadCacheKinds[u.GroupVersionKind().GroupKind()]
+	return u, ok
+}
+
+// isFresh returns true if the cached version of the object can be used,
+// i.e. the object wasn't written within the staleness bound, or the cache
+// holds the last written version.
+func (c *cachedReadClient) isFresh(key, resourceVersion string) bool {
+	v, ok := c.r.applyWrites.Load(key)
+	if !ok {
+		return true
+	}
+	write := v.(appliedWrite)
+	if time.Since(write.writtenAt) > c.r.ApplyReadCacheMaxStaleness {
+		c.r.applyWrites.CompareAndDelete(key, v)
+		return true
+	}
+	return write.resourceVersion == resourceVersion
+}
+
+// recordWrite tracks the resource version of the written object.
+func (c *cachedReadClient) recordWrite(obj client.Object, resourceVersion string) {
+	if u, ok := c.cacheable(obj); ok {
+		c.r.applyWrites.Store(writeKey(u), appliedWrite{
+			resourceVersion: resourceVersion,
+			writtenAt:       time.Now(),
+		})
+	}
+}
+
+func (c *cachedReadClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	u, ok := c.cacheable(obj)
+	if !ok || len(opts) > 0 {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+
+	cached := &unstructured.Unstructured{}
+	cached.SetGroupVersionKind(u.GroupVersionKind())
+	err := c.r.ApplyReadCache.Get(ctx, key, cached)
+	switch {
+	case err == nil && c.isFresh(writeKey(cached), cached.GetResourceVersion()):
+		cached.DeepCopyInto(u)
+		return nil
+	case apierrors.IsNotFound(err):
+		u.SetNamespace(key.Namespace)
+		u.SetName(key.Name)
+		if c.isFresh(writeKey(u), "") {
+			return err
+		}
+	}
+
+	// Fall back to the API server if the cache is stale or failed.
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *cachedReadClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	if err == nil && len((&client.CreateOptions{}).ApplyOptions(opts).DryRun) == 0 {
+		c.recordWrite(obj, obj.GetResourceVersion())
+	}
+	return err
+}
+
+func (c *cachedReadClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	if err == nil && len((&client.UpdateOptions{}).ApplyOptions(opts).DryRun) == 0 {
+		c.recordWrite(obj, obj.GetResourceVersion())
+	}
+	return err
+}
+
+func (c *cachedReadClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	if err == nil && len((&client.PatchOptions{}).ApplyOptions(opts).DryRun) == 0 {
+		c.recordWrite(obj, obj.GetResourceVersion())
+	}
+	return err
+}
+
+func (c *cachedReadClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	if err == nil && len((&client.DeleteOptions{}).ApplyOptions(opts).DryRun) == 0 {
+		c.recordWrite(obj, "")
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCachedReadClient(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	newConfigMap := func(data string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetNamespace("default")
+		u.SetName("test")
+		_ = unstructured.SetNestedField(u.Object, data, "data", "key")
+		return u
+	}
+	key := client.ObjectKey{Namespace: "default", Name: "test"}
+	get := func(c client.Client) (string, error) {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		if err := c.Get(ctx, key, u); err != nil {
+			return "", err
+		}
+		data, _, _ := unstructured.NestedString(u.Object, "data", "key")
+		return data, nil
+	}
+
+	live := fake.NewClientBuilder().WithObjects(newConfigMap("live")).Build()
+	cache := fake.NewClientBuilder().WithObjects(newConfigMap("cached")).Build()
+	r := &KustomizationReconciler{
+		ApplyReadCache:             cache,
+		ApplyReadCacheKinds:        map[schema.GroupKind]struct{}{{Kind: "ConfigMap"}: {}},
+		ApplyReadCacheMaxStaleness: time.Minute,
+	}
+	c := r.newCachedReadClient(live)
+
+	t.Run("serves reads from the cache", func(t *testing.T) {
+		g.Expect(get(c)).To(Equal("cached"))
+	})
+
+	t.Run("reads from the API server after a write", func(t *testing.T) {
+		g.Expect(c.Patch(ctx, newConfigMap("applied"), client.Merge)).To(Succeed())
+		g.Expect(get(c)).To(Equal("applied"))
+	})
+
+	t.Run("ignores dry-run writes", func(t *testing.T) {
+		r.applyWrites.Clear()
+		g.Expect(c.Patch(ctx, newConfigMap("dry-run"), client.Merge, client.DryRunAll)).To(Succeed())
+		g.Expect(get(c)).To(Equal("cached"))
+	})
+
+	t.Run("serves reads from the cache after the staleness bound", func(t *testing.T) {
+		g.Expect(c.Patch(ctx, newConfigMap("applied"), client.Merge)).To(Succeed())
+		r.ApplyReadCacheMaxStaleness = 0
+		g.Expect(get(c)).To(Equal("cached"))
+	})
+
+	t.Run("reads from the API server after a delete", func(t *testing.T) {
+		r.ApplyReadCacheMaxStaleness = time.Minute
+		g.Expect(c.Delete(ctx, newConfigMap(""))).To(Succeed())
+		_, err := get(c)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/ssa"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// maxReadyMessageLength is the max length of the rendered Ready message,
+// as allowed by the API server for the condition messages.
+const maxReadyMessageLength = 32768
+
+// readyMessageChanges holds the number of objects per apply action
+// of the reconciliation.
+type readyMessageChanges struct {
+	Created    int
+	Configured int
+	Unchanged  int
+	Skipped    int
+	// Changed is the number of objects created or configured.
+	Changed int
+}
+
+// add counts the entries of the given change set.
+func (c *readyMessageChanges) add(changeSet *ssa.ChangeSet) {
+	if changeSet == nil {
+		return
+	}
+	for _, entry := range changeSet.Entries {
+		switch entry.Action {
+		case ssa.CreatedAction:
+			c.Created++
+		case ssa.ConfiguredAction:
+			c.Configured++
+		case ssa.UnchangedAction:
+			c.Unchanged++
+		case ssa.SkippedAction:
+			c.Skipped++
+		}
+	}
+	c.Changed = c.Created + c.Configured
+}
+
+// readyMessageData is the data of the spec.readyMessageTemplate.
+type readyMessageData struct {
+	// Message is the default message of the Ready condition.
+	Message string
+	// Reason is the reason of the Ready condition.
+	Reason string
+	// Ready is true if the Ready condition is true.
+	Ready bool
+
+	Name      string
+	Namespace string
+	Labels    map[string]string
+
+	// Revision is the last attempted revision, and ShortSHA
+	// the first 7 characters of its digest.
+	Revision string
+	ShortSHA string
+	// OriginRevision is the origin revision of the applied revision,
+	// set only if the Ready condition is true.
+	OriginRevision string
+
+	// Changes are the objects changed by the reconciliation.
+	Changes readyMessageChanges
+}
+
+// renderReadyMessage replaces the message of the Ready condition set by the
+// reconciliation with the spec.readyMessageTemplate rendered with the default
+// message. The default message is kept if the template fails to render, or
+// if the message was not set by the reconciliation, i.e. is the same as the
+// message the reconciliation started with.
+func renderReadyMessage(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	messageBefore string,
+	changes readyMessageChanges) {
+	ready := conditions.Get(obj, meta.ReadyCondition)
+	if obj.Spec.ReadyMessageTemplate == "" || ready == nil || ready.Message == messageBefore {
+		return
+	}
+
+	data := readyMessageData{
+		Message:   ready.Message,
+		Reason:    ready.Reason,
+		Ready:     conditions.IsTrue(obj, meta.ReadyCondition),
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Labels:    obj.GetLabels(),
+		Revision:  obj.Status.LastAttemptedRevision,
+		ShortSHA:  shortSHA(obj.Status.LastAttemptedRevision),
+		Changes:   changes,
+	}
+	if data.Ready {
+		data.OriginRevision = obj.Status.LastAppliedOriginRevision
+	}
+
+	msg, err := executeReadyMessageTemplate(obj.Spec.ReadyMessageTemplate, data)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to render the Ready message template, keeping the default message")
+		return
+	}
+	ready.Message = msg
+	conditions.Set(obj, ready)
+}
+
+// executeReadyMessageTemplate renders the template with the given data,
+// truncated to the max length of the condition messages.
+func executeReadyMessageTemplate(text string, data readyMessageData) (string, error) {
+	tmpl, err := template.New("readyMessageTemplate").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("template execution failed: %w", err)
+	}
+	msg := strings.TrimSpace(sb.String())
+	if msg == "" {
+		return "", fmt.Errorf("template rendered an empty message")
+	}
+	if len(msg) > maxReadyMessageLength {
+		msg = strings.ToValidUTF8(msg[:maxReadyMessageLength], "")
+	}
+	return msg, nil
+}
+
+// shortSHA returns the first 7 characters of the digest of the revision,
+// e.g. 'abcdef0' for 'main@sha1:abcdef0123...'.
+func shortSHA(revision string) string {
+	if i := strings.LastIndex(revision, ":"); i >= 0 {
+		revision = revision[i+1:]
+	}
+	if len(revision) > 7 {
+		return revision[:7]
+	}
+	return revision
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/ssa"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestRenderReadyMessage(t *testing.T) {
+	const revision = "main@sha1:abcdef0123456789"
+
+	newObject := func(template string) *kustomizev1.Kustomization {
+		obj := &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "apps",
+				Namespace: "flux-system",
+				Labels:    map[string]string{"env": "production"},
+			},
+			Spec: kustomizev1.KustomizationSpec{ReadyMessageTemplate: template},
+		}
+		obj.Status.LastAttemptedRevision = revision
+		return obj
+	}
+
+	changeSet := ssa.NewChangeSet()
+	changeSet.Add(ssa.ChangeSetEntry{Action: ssa.CreatedAction})
+	changeSet.Add(ssa.ChangeSetEntry{Action: ssa.ConfiguredAction})
+	changeSet.Add(ssa.ChangeSetEntry{Action: ssa.ConfiguredAction})
+	changeSet.Add(ssa.ChangeSetEntry{Action: ssa.UnchangedAction})
+	var changes readyMessageChanges
+	changes.add(changeSet)
+
+	t.Run("renders the ready message", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := newObject("[{{ .Labels.env }}] {{ .ShortSHA }}: {{ .Changes.Changed }} changed{{ if not .Ready }} - {{ .Message }}{{ end }}")
+		conditions.MarkTrue(obj, meta.ReadyCondition, meta.ReconciliationSucceededReason, "Applied revision: %s", revision)
+		renderReadyMessage(context.Background(), obj, "", changes)
+		g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(Equal("[production] abcdef0: 3 changed"))
+	})
+
+	t.Run("renders the failure message", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := newObject("[{{ .Labels.env }}] {{ .Reason }}: {{ .Message }}")
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.HealthCheckFailedReason, "timeout waiting for Deployment/apps/web")
+		renderReadyMessage(context.Background(), obj, "", readyMessageChanges{})
+		g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(Equal(
+			"[production] HealthCheckFailed: timeout waiting for Deployment/apps/web"))
+	})
+
+	t.Run("keeps the message not set by the reconciliation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := newObject("{{ .ShortSHA }}")
+		conditions.MarkTrue(obj, meta.ReadyCondition, meta.ReconciliationSucceededReason, "already rendered")
+		renderReadyMessage(context.Background(), obj, "already rendered", changes)
+		g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(Equal("already rendered"))
+	})
+
+	t.Run("keeps the default message on template errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, template := range []string{"{{ .Missing }}", "{{ .ShortSHA", "{{ if false }}x{{ end }}"} {
+			obj := newObject(template)
+			conditions.MarkTrue(obj, meta.ReadyCondition, meta.ReconciliationSucceededReason, "Applied revision: %s", revision)
+			renderReadyMessage(context.Background(), obj, "", changes)
+			g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(Equal("Applied revision: "+revision), template)
+		}
+	})
+}
+
+func TestShortSHA(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(shortSHA("main@sha1:abcdef0123456789")).To(Equal("abcdef0"))
+	g.Expect(shortSHA("sha256:0123456789abcdef")).To(Equal("0123456"))
+	g.Expect(shortSHA("v1.0.0@sha256:fedcba9876543210")).To(Equal("fedcba9"))
+	g.Expect(shortSHA("abc")).To(Equal("abc"))
+	g.Expect(shortSHA("")).To(BeEmpty())
+}
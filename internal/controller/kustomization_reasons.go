@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	ssaerrors "github.com/fluxcd/pkg/ssa/errors"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// decryptionError marks the errors of the build caused by the
+// decryption of the manifests.
+type decryptionError struct {
+	err error
+}
+
+func (e *decryptionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *decryptionError) Unwrap() error {
+	return e.err
+}
+
+// decryptionFailed returns the given error of the decryption stage
+// wrapped in a decryptionError.
+func decryptionFailed(ctx context.Context, err error) error {
+	return &decryptionError{err: stageError(ctx, "decryption", err)}
+}
+
+// failureReason returns the reason of the given error of a stage, or the
+// given reason of the stage if the error has no more specific reason.
+func failureReason(err error, reason string) string {
+	var decErr *decryptionError
+	if errors.As(err, &decErr) {
+		return kustomizev1.DecryptionFailedReason
+	}
+	var dryRunErr *ssaerrors.DryRunErr
+	if errors.As(err, &dryRunErr) &&
+		(apierrors.IsInvalid(dryRunErr.Unwrap()) || apierrors.IsBadRequest(dryRunErr.Unwrap())) {
+		return kustomizev1.ValidationFailedReason
+	}
+	return reason
+}
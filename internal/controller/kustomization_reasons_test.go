@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	ssaerrors "github.com/fluxcd/pkg/ssa/errors"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestFailureReason(t *testing.T) {
+	errKMS := errors.New("kms unavailable")
+	invalidErr := apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "backend", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0"),
+	})
+	dryRunErr := ssaerrors.NewDryRunErr(invalidErr, &unstructured.Unstructured{})
+	forbiddenErr := ssaerrors.NewDryRunErr(apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"},
+		"backend", errors.New("access denied")), &unstructured.Unstructured{})
+
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+		want   string
+	}{
+		{
+			name:   "stage reason",
+			err:    errKMS,
+			reason: kustomizev1.BuildFailedReason,
+			want:   kustomizev1.BuildFailedReason,
+		},
+		{
+			name:   "decryption error",
+			err:    decryptionFailed(context.Background(), errKMS),
+			reason: kustomizev1.BuildFailedReason,
+			want:   kustomizev1.DecryptionFailedReason,
+		},
+		{
+			name:   "dry-run error",
+			err:    fmt.Errorf("%w\n%s", dryRunErr, "Deployment/apps/backend configured"),
+			reason: kustomizev1.ReconciliationFailedReason,
+			want:   kustomizev1.ValidationFailedReason,
+		},
+		{
+			name:   "dry-run access error",
+			err:    forbiddenErr,
+			reason: kustomizev1.ReconciliationFailedReason,
+			want:   kustomizev1.ReconciliationFailedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(failureReason(tt.err, tt.reason)).To(Equal(tt.want))
+			g.Expect(kustomizev1.FailureReasons).To(ContainElement(tt.want))
+		})
+	}
+
+	// The decryption errors keep the message of the wrapped error.
+	g := NewWithT(t)
+	err := decryptionFailed(context.Background(), errKMS)
+	g.Expect(err).To(MatchError(errKMS))
+	g.Expect(err.Error()).To(Equal("kms unavailable"))
+}
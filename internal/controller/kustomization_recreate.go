@@ -0,0 +1,245 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/ssa"
+	ssaerrors "github.com/fluxcd/pkg/ssa/errors"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+)
+
+// recreateInterval is the interval at which the progress of
+// the gradual recreation of StatefulSets is polled.
+const recreateInterval = 2 * time.Second
+
+// statefulSetRecreation holds the pods of a StatefulSet deleted with the
+// orphan propagation policy, which are to be replaced in batches.
+type statefulSetRecreation struct {
+	name string
+	pods []corev1.Pod
+}
+
+// orphanImmutableStatefulSets deletes with the orphan propagation policy the
+// StatefulSets that are force applied with immutable field changes, so that
+// they are recreated by the apply without disrupting their pods and volume
+// claims. It returns the pods left running by the deleted StatefulSets,
+// excluding the pods below the rolling update partition of the new object.
+func (r *KustomizationReconciler) orphanImmutableStatefulSets(ctx context.Context,
+	kubeClient client.Client,
+	objects []*unstructured.Unstructured,
+	opts ssa.ApplyOptions,
+	timeout time.Duration) ([]statefulSetRecreation, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	var recreations []statefulSetRecreation
+	for _, u := range objects {
+		gvk := u.GroupVersionKind()
+		if gvk.Group != appsv1.GroupName || gvk.Kind != "StatefulSet" {
+			continue
+		}
+
+		existing := &appsv1.StatefulSet{}
+		if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(u), existing); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("%s query failed: %w", ssautil.FmtUnstructured(u), err)
+		}
+
+		forced, err := isForceApplied(u, existing, opts)
+		if err != nil {
+			return nil, err
+		}
+		if !forced {
+			continue
+		}
+
+		dryRunObject := u.DeepCopy()
+		err = kubeClient.Patch(ctx, dryRunObject, client.Apply,
+			client.DryRunAll, client.ForceOwnership, client.FieldOwner(r.ControllerName))
+		if !ssaerrors.IsImmutableError(err) {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(existing.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("%s label selector failed: %w", ssautil.FmtUnstructured(u), err)
+		}
+		podList := &corev1.PodList{}
+		if err := kubeClient.List(ctx, podList,
+			client.InNamespace(existing.Namespace),
+			client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("%s pods query failed: %w", ssautil.FmtUnstructured(u), err)
+		}
+		// The pods below the rolling update partition are left
+		// to the recreated StatefulSet.
+		partition, _, _ := unstructured.NestedInt64(u.Object,
+			"spec", "updateStrategy", "rollingUpdate", "partition")
+		recreation := statefulSetRecreation{name: ssautil.FmtUnstructured(u)}
+		for _, pod := range podList.Items {
+			if metav1.IsControlledBy(&pod, existing) && podOrdinal(&pod) >= partition {
+				recreation.pods = append(recreation.pods, pod)
+			}
+		}
+
+		if err := kubeClient.Delete(ctx, existing,
+			client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%s immutable field detected, failed to delete object: %w",
+				ssautil.FmtUnstructured(u), err)
+		}
+		if err := wait.PollUntilContextTimeout(ctx, recreateInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			err := kubeClient.Get(ctx, client.ObjectKeyFromObject(existing), &appsv1.StatefulSet{})
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}); err != nil {
+			return nil, fmt.Errorf("%s immutable field detected, failed to wait for object to be deleted: %w",
+				ssautil.FmtUnstructured(u), err)
+		}
+
+		log.Info(fmt.Sprintf("%s immutable field detected, recreating with %d pods left running",
+			recreation.name, len(recreation.pods)))
+		recreations = append(recreations, recreation)
+	}
+
+	return recreations, nil
+}
+
+// replaceOrphanedPods evicts the pods of the recreated StatefulSets in batches
+// of at most maxUnavailable pods, and waits for the replacement pods to
+// become ready before evicting the next batch. The evictions are retried
+// while they are denied by a PodDisruptionBudget.
+func replaceOrphanedPods(ctx context.Context,
+	kubeClient client.Client,
+	recreations []statefulSetRecreation,
+	maxUnavailable int,
+	timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, recreation := range recreations {
+		for start := 0; start < len(recreation.pods); start += maxUnavailable {
+			batch := recreation.pods[start:min(start+maxUnavailable, len(recreation.pods))]
+			for i := range batch {
+				if err := evictPod(ctx, kubeClient, &batch[i]); err != nil {
+					return fmt.Errorf("%s failed to evict pod %s: %w", recreation.name, batch[i].Name, err)
+				}
+			}
+			for i := range batch {
+				if err := waitForPodReplacement(ctx, kubeClient, &batch[i]); err != nil {
+					return fmt.Errorf("%s failed to wait for pod %s to be replaced: %w",
+						recreation.name, batch[i].Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// evictPod evicts the given pod using the Eviction API, retrying while the
+// eviction is denied by a PodDisruptionBudget. The pod is considered evicted
+// if it no longer exists or if it was already replaced.
+func evictPod(ctx context.Context, kubeClient client.Client, pod *corev1.Pod) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		DeleteOptions: &metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &pod.UID},
+		},
+	}
+	return wait.PollUntilContextCancel(ctx, recreateInterval, true, func(ctx context.Context) (bool, error) {
+		err := kubeClient.SubResource("eviction").Create(ctx, pod.DeepCopy(), eviction)
+		switch {
+		case err == nil, apierrors.IsNotFound(err), apierrors.IsConflict(err):
+			return true, nil
+		case apierrors.IsTooManyRequests(err):
+			return false, nil
+		default:
+			return false, err
+		}
+	})
+}
+
+// waitForPodReplacement waits for the given pod to be replaced
+// by a ready pod with the same name.
+func waitForPodReplacement(ctx context.Context, kubeClient client.Client, pod *corev1.Pod) error {
+	return wait.PollUntilContextCancel(ctx, recreateInterval, true, func(ctx context.Context) (bool, error) {
+		current := &corev1.Pod{}
+		if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(pod), current); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if current.UID == pod.UID {
+			return false, nil
+		}
+		for _, c := range current.Status.Conditions {
+			if c.Type == corev1.PodReady {
+				return c.Status == corev1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// podOrdinal returns the ordinal of the given StatefulSet pod,
+// or -1 if the pod name doesn't end with an ordinal.
+func podOrdinal(pod *corev1.Pod) int64 {
+	i := strings.LastIndex(pod.Name, "-")
+	if i < 0 {
+		return -1
+	}
+	ordinal, err := strconv.ParseInt(pod.Name[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
+
+// isForceApplied returns true if the given object is
+// to be recreated on immutable field changes.
+func isForceApplied(desired *unstructured.Unstructured, existing client.Object, opts ssa.ApplyOptions) (bool, error) {
+	if opts.Force || ssautil.AnyInMetadata(desired, opts.ForceSelector) {
+		return true, nil
+	}
+	existingMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+	if err != nil {
+		return false, err
+	}
+	return ssautil.AnyInMetadata(&unstructured.Unstructured{Object: existingMap}, opts.ForceSelector), nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestReplaceOrphanedPods(t *testing.T) {
+	g := NewWithT(t)
+
+	var pods []corev1.Pod
+	var objects []client.Object
+	for i := range 3 {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("db-%d", i),
+				Namespace: "default",
+				UID:       types.UID(fmt.Sprintf("old-%d", i)),
+			},
+		}
+		pods = append(pods, pod)
+		objects = append(objects, pod.DeepCopy())
+	}
+
+	// Deny the first eviction as a PodDisruptionBudget would, and replace
+	// the evicted pods with ready ones as the StatefulSet controller would.
+	var evictions []string
+	denied := false
+	kubeClient := fake.NewClientBuilder().
+		WithObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResource string,
+				obj client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+				if !denied {
+					denied = true
+					return apierrors.NewTooManyRequests("disruption budget", 0)
+				}
+				// Only one batch may be disrupted at a time.
+				for _, name := range evictions {
+					pod := &corev1.Pod{}
+					g.Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, pod)).To(Succeed())
+					g.Expect(pod.Status.Conditions).NotTo(BeEmpty())
+				}
+				evictions = append(evictions, obj.GetName())
+				if err := c.Delete(ctx, obj); err != nil {
+					return err
+				}
+				return c.Create(ctx, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: obj.GetName(), Namespace: obj.GetNamespace()},
+					Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					}}},
+				})
+			},
+		}).
+		Build()
+
+	err := replaceOrphanedPods(context.Background(), kubeClient,
+		[]statefulSetRecreation{{name: "StatefulSet/default/db", pods: pods}}, 1, 30*time.Second)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(evictions).To(Equal([]string{"db-0", "db-1", "db-2"}))
+}
+
+func TestPodOrdinal(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(podOrdinal(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-db-12"}})).To(Equal(int64(12)))
+	g.Expect(podOrdinal(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-db"}})).To(Equal(int64(-1)))
+	g.Expect(podOrdinal(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db"}})).To(Equal(int64(-1)))
+}
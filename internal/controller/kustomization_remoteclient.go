@@ -0,0 +1,269 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	runtimeClient "github.com/fluxcd/pkg/runtime/client"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// remoteClientPool holds the clients of the remote clusters targeted with a
+// kubeconfig, to reuse their connections, rate limiter and discovery cache
+// across reconciliations instead of dialing the clusters on every apply.
+type remoteClientPool struct {
+	mu      sync.Mutex
+	clients map[string]*pooledRemoteClient
+}
+
+// pooledRemoteClient is a client of a remote cluster held by the pool.
+type pooledRemoteClient struct {
+	client     client.Client
+	httpClient *http.Client
+	lastUsed   time.Time
+}
+
+// get returns the client stored under the given key, or stores the client
+// returned by newClient. The clients left unused for longer than the TTL
+// are evicted and their idle connections closed.
+func (p *remoteClientPool) get(key string, now time.Time, ttl time.Duration,
+	newClient func() (client.Client, *http.Client, error)) (client.Client, error) {
+	p.mu.Lock()
+	p.evict(now, ttl)
+	if c, ok := p.clients[key]; ok {
+		c.lastUsed = now
+		p.mu.Unlock()
+		return c.client, nil
+	}
+	p.mu.Unlock()
+
+	// Build the client without holding the lock, as it
+	// may involve requests to the remote API server.
+	kubeClient, httpClient, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[key]; ok {
+		httpClient.CloseIdleConnections()
+		c.lastUsed = now
+		return c.client, nil
+	}
+	if p.clients == nil {
+		p.clients = make(map[string]*pooledRemoteClient)
+	}
+	p.clients[key] = &pooledRemoteClient{
+		client:     kubeClient,
+		httpClient: httpClient,
+		lastUsed:   now,
+	}
+	return kubeClient, nil
+}
+
+// evict removes the clients unused for longer than the TTL.
+// It must be called with the lock held.
+func (p *remoteClientPool) evict(now time.Time, ttl time.Duration) {
+	for key, c := range p.clients {
+		if now.Sub(c.lastUsed) > ttl {
+			c.httpClient.CloseIdleConnections()
+			delete(p.clients, key)
+		}
+	}
+}
+
+// getRemoteClient returns a client of the remote cluster targeted by the
+// kubeconfig of the Kustomization, impersonating the service account if set.
+// The client is tuned with the KubeConfigQPS and KubeConfigBurst settings,
+// it connects through the proxy of the kubeconfig if set, and it is reused by the reconciliations using the same kubeconfig for up to
+// KubeConfigClientTTL after its last use.
+func (r *KustomizationReconciler) getRemoteClient(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	kubeConfig *meta.KubeConfigReference,
+	defaultServiceAccount string) (client.Client, error) {
+	if kubeConfig == nil {
+		return nil, errors.New("invalid .spec.kubeConfig, neither .spec.kubeConfig.provider nor .spec.kubeConfig.secretRef is set")
+	}
+
+	restConfig, settings, err := r.getRemoteRESTConfig(ctx, obj, kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	proxySettings, err := r.setRemoteProxy(ctx, obj, restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var impersonate string
+	sa := defaultServiceAccount
+	if obj.Spec.ServiceAccountName != "" {
+		sa = obj.Spec.ServiceAccountName
+	}
+	if sa != "" {
+		impersonate = fmt.Sprintf("system:serviceaccount:%s:%s", obj.GetNamespace(), sa)
+	}
+
+	newClient := func() (client.Client, *http.Client, error) {
+		return r.newRemoteClient(ctx, restConfig, impersonate)
+	}
+	if r.KubeConfigClientTTL <= 0 {
+		kubeClient, _, err := newClient()
+		return kubeClient, err
+	}
+
+	key := remoteClientKey(obj.GetNamespace(), kubeConfig, restConfig, impersonate, settings+proxySettings)
+	return r.remoteClients.get(key, time.Now(), r.KubeConfigClientTTL, newClient)
+}
+
+// newRemoteClient sanitizes the remote REST config with the KubeConfigOpts,
+// applies the connection tuning and returns a client sharing its HTTP client
+// with the discovery of the REST mapper.
+func (r *KustomizationReconciler) newRemoteClient(ctx context.Context,
+	restConfig *rest.Config, impersonate string) (client.Client, *http.Client, error) {
+	restConfig = runtimeClient.KubeConfig(ctx, restConfig, r.KubeConfigOpts)
+	if r.KubeConfigQPS > 0 {
+		restConfig.QPS = r.KubeConfigQPS
+	}
+	if r.KubeConfigBurst > 0 {
+		restConfig.Burst = r.KubeConfigBurst
+	}
+	if impersonate != "" {
+		restConfig.Impersonate = rest.ImpersonationConfig{UserName: impersonate}
+	}
+	restConfig.WarningHandlerWithContext = NewAPIWarningHandler()
+	restConfig.Wrap(NewAPIOperationWrapper(r.ControllerName, ""))
+
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	restMapper, err := apiutil.NewDynamicRESTMapper(restConfig, httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	kubeClient, err := client.New(restConfig, client.Options{
+		HTTPClient: httpClient,
+		Scheme:     r.Client.Scheme(),
+		Mapper:     restMapper,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return kubeClient, httpClient, nil
+}
+
+// getRemoteRESTConfig returns the REST config of the remote cluster from
+// the kubeconfig provider or from the kubeconfig Secret. For a provider,
+// it also returns the digest of the ConfigMap holding its settings, as the
+// REST config authenticates the requests with the settings read at creation.
+func (r *KustomizationReconciler) getRemoteRESTConfig(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	kubeConfig *meta.KubeConfigReference) (*rest.Config, string, error) {
+	if provider := r.getProviderRESTConfigFetcher(obj); provider != nil {
+		cmName := types.NamespacedName{
+			Namespace: obj.GetNamespace(),
+			Name:      kubeConfig.ConfigMapRef.Name,
+		}
+		var cm corev1.ConfigMap
+		if err := r.Get(ctx, cmName, &cm); err != nil {
+			return nil, "", fmt.Errorf("failed to get configmap %s: %w", cmName.String(), err)
+		}
+		restConfig, err := provider(ctx, *kubeConfig, obj.GetNamespace(), r.Client)
+		return restConfig, configMapDigest(&cm), err
+	}
+	if kubeConfig.SecretRef == nil {
+		return nil, "", errors.New("invalid .spec.kubeConfig, neither .spec.kubeConfig.provider nor .spec.kubeConfig.secretRef is set")
+	}
+
+	secretName := types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      kubeConfig.SecretRef.Name,
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretName, &secret); err != nil {
+		return nil, "", fmt.Errorf("unable to read KubeConfig secret '%s' error: %w", secretName.String(), err)
+	}
+
+	var data []byte
+	switch {
+	case kubeConfig.SecretRef.Key != "":
+		key := kubeConfig.SecretRef.Key
+		data = secret.Data[key]
+		if data == nil {
+			return nil, "", fmt.Errorf("KubeConfig secret '%s' does not contain a '%s' key with a kubeconfig", secretName, key)
+		}
+	case secret.Data["value"] != nil:
+		data = secret.Data["value"]
+	case secret.Data["value.yaml"] != nil:
+		data = secret.Data["value.yaml"]
+	default:
+		return nil, "", fmt.Errorf("KubeConfig secret '%s' does not contain a 'value' key with a kubeconfig", secretName)
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(data)
+	return restConfig, "", err
+}
+
+// configMapDigest returns the digest of the data of the ConfigMap.
+func configMapDigest(cm *corev1.ConfigMap) string {
+	keys := slices.Sorted(maps.Keys(cm.Data))
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%q\n", k, cm.Data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// remoteClientKey returns the key of a remote client in the pool, derived
+// from the namespace and reference of the kubeconfig, the server address,
+// the credentials, the impersonated user and the provider settings. The key
+// changes when the kubeconfig is rotated, in which case the stale client is
+// evicted after the TTL.
+func remoteClientKey(namespace string, ref *meta.KubeConfigReference, restConfig *rest.Config, impersonate, settings string) string {
+	refJSON, _ := json.Marshal(ref)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n%s\n", namespace, refJSON, settings, restConfig.Host, restConfig.APIPath)
+	fmt.Fprintf(h, "%s\n%t\n", restConfig.TLSClientConfig.ServerName, restConfig.TLSClientConfig.Insecure)
+	h.Write(restConfig.TLSClientConfig.CAData)
+	h.Write(restConfig.TLSClientConfig.CertData)
+	h.Write(restConfig.TLSClientConfig.KeyData)
+	fmt.Fprintf(h, "\n%s\n%s\n%s\n%s\n", restConfig.BearerToken, restConfig.Username, restConfig.Password, impersonate)
+	if exec := restConfig.ExecProvider; exec != nil {
+		fmt.Fprintf(h, "%s\n%s\n%q\n%v\n", exec.APIVersion, exec.Command, exec.Args, exec.Env)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
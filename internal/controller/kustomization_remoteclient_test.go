@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+)
+
+func TestRemoteClientPool(t *testing.T) {
+	g := NewWithT(t)
+
+	var built int
+	newClient := func() (client.Client, *http.Client, error) {
+		built++
+		return fake.NewClientBuilder().Build(), &http.Client{}, nil
+	}
+
+	now := time.Now()
+	ttl := time.Minute
+	p := &remoteClientPool{}
+
+	c1, err := p.get("a", now, ttl, newClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	c2, err := p.get("a", now.Add(30*time.Second), ttl, newClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c2).To(BeIdenticalTo(c1))
+	g.Expect(built).To(Equal(1))
+
+	_, err = p.get("b", now.Add(time.Minute), ttl, newClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(built).To(Equal(2))
+	g.Expect(p.clients).To(HaveLen(2))
+
+	// The client of key a was last used 90s earlier.
+	_, err = p.get("b", now.Add(2*time.Minute), ttl, newClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p.clients).To(HaveLen(1))
+	g.Expect(p.clients).To(HaveKey("b"))
+
+	_, err = p.get("c", now, ttl, func() (client.Client, *http.Client, error) {
+		return nil, nil, errors.New("unreachable")
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(p.clients).ToNot(HaveKey("c"))
+}
+
+func TestRemoteClientKey(t *testing.T) {
+	g := NewWithT(t)
+
+	ref := &meta.KubeConfigReference{SecretRef: &meta.SecretKeyReference{Name: "kubeconfig"}}
+	cfg := &rest.Config{Host: "https://remote:6443", BearerToken: "token"}
+	key := remoteClientKey("apps", ref, cfg, "", "")
+
+	g.Expect(remoteClientKey("apps", ref, &rest.Config{Host: "https://remote:6443", BearerToken: "token"}, "", "")).To(Equal(key))
+	g.Expect(remoteClientKey("apps", ref, &rest.Config{Host: "https://remote:6443", BearerToken: "rotated"}, "", "")).ToNot(Equal(key))
+	g.Expect(remoteClientKey("apps", ref, cfg, "system:serviceaccount:apps:deployer", "")).ToNot(Equal(key))
+	g.Expect(remoteClientKey("infra", ref, cfg, "", "")).ToNot(Equal(key))
+	g.Expect(remoteClientKey("apps", ref, cfg, "", "settings")).ToNot(Equal(key))
+	g.Expect(remoteClientKey("apps", &meta.KubeConfigReference{SecretRef: &meta.SecretKeyReference{Name: "other"}}, cfg, "", "")).ToNot(Equal(key))
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// remoteProxyCAKey is the key of the CA certificate
+// in the Secret referenced by the proxy settings.
+const remoteProxyCAKey = "ca.crt"
+
+// setRemoteProxy configures the REST config of the remote cluster to connect
+// through the proxy of the kubeconfig, if set. The CA certificate of the proxy
+// is appended to the CAs trusted by the REST config, as the TLS settings of
+// the transport apply to both the proxy and the remote API server. Since the
+// health checks and the garbage collection use the same client, they go
+// through the proxy too. It returns the digest of the proxy settings, which
+// is part of the key of the client in the pool.
+func (r *KustomizationReconciler) setRemoteProxy(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	restConfig *rest.Config) (string, error) {
+	kp := obj.Spec.KubeConfigProxy
+	if !isRemote(obj) || kp == nil {
+		return "", nil
+	}
+
+	proxyURL, err := url.Parse(kp.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid .spec.kubeConfigProxy.url: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return "", fmt.Errorf("invalid .spec.kubeConfigProxy.url, unsupported scheme '%s'", proxyURL.Scheme)
+	}
+
+	var caData []byte
+	if ref := kp.CASecretRef; ref != nil {
+		secretName := types.NamespacedName{
+			Namespace: obj.GetNamespace(),
+			Name:      ref.Name,
+		}
+		var secret corev1.Secret
+		if err := r.Get(ctx, secretName, &secret); err != nil {
+			return "", fmt.Errorf("unable to read proxy CA secret '%s' error: %w", secretName.String(), err)
+		}
+		caData = secret.Data[remoteProxyCAKey]
+		if len(caData) == 0 {
+			return "", fmt.Errorf("proxy CA secret '%s' does not contain a '%s' key", secretName, remoteProxyCAKey)
+		}
+
+		// The CAs of the kubeconfig must be kept when adding the
+		// proxy CA, as setting CAData disables the system roots.
+		serverCA := restConfig.TLSClientConfig.CAData
+		if len(serverCA) == 0 && restConfig.TLSClientConfig.CAFile != "" {
+			serverCA, err = os.ReadFile(restConfig.TLSClientConfig.CAFile)
+			if err != nil {
+				return "", fmt.Errorf("unable to read the kubeconfig CA file: %w", err)
+			}
+			restConfig.TLSClientConfig.CAFile = ""
+		}
+		restConfig.TLSClientConfig.CAData = append(slices.Clone(serverCA), append([]byte("\n"), caData...)...)
+	}
+
+	restConfig.Proxy = http.ProxyURL(proxyURL)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", proxyURL.String())
+	h.Write(caData)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestSetRemoteProxy(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy-ca", Namespace: "apps"},
+		Data:       map[string][]byte{"ca.crt": []byte("proxy-ca")},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(secret).Build(),
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			KubeConfig: &meta.KubeConfigReference{
+				SecretRef: &meta.SecretKeyReference{Name: "kubeconfig"},
+			},
+		},
+	}
+
+	// Without proxy settings the REST config is left untouched.
+	cfg := &rest.Config{Host: "https://remote:6443"}
+	settings, err := r.setRemoteProxy(context.Background(), obj, cfg)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(settings).To(BeEmpty())
+	g.Expect(cfg.Proxy).To(BeNil())
+
+	obj.Spec.KubeConfigProxy = &kustomizev1.KubeConfigProxy{URL: "socks5://konnectivity:8132"}
+	cfg = &rest.Config{Host: "https://remote:6443"}
+	socksSettings, err := r.setRemoteProxy(context.Background(), obj, cfg)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(socksSettings).ToNot(BeEmpty())
+	g.Expect(cfg.Proxy).ToNot(BeNil())
+	req, _ := http.NewRequest(http.MethodGet, cfg.Host, nil)
+	proxyURL, err := cfg.Proxy(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(proxyURL.String()).To(Equal("socks5://konnectivity:8132"))
+
+	obj.Spec.KubeConfigProxy = &kustomizev1.KubeConfigProxy{
+		URL:         "https://konnectivity:8131",
+		CASecretRef: &meta.LocalObjectReference{Name: "proxy-ca"},
+	}
+	cfg = &rest.Config{Host: "https://remote:6443"}
+	cfg.TLSClientConfig.CAData = []byte("server-ca")
+	httpsSettings, err := r.setRemoteProxy(context.Background(), obj, cfg)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(httpsSettings).ToNot(Equal(socksSettings))
+	g.Expect(string(cfg.TLSClientConfig.CAData)).To(Equal("server-ca\nproxy-ca"))
+
+	obj.Spec.KubeConfigProxy.CASecretRef.Name = "missing"
+	_, err = r.setRemoteProxy(context.Background(), obj, &rest.Config{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unable to read proxy CA secret 'apps/missing'"))
+
+	obj.Spec.KubeConfigProxy = &kustomizev1.KubeConfigProxy{URL: "ftp://konnectivity:21"}
+	_, err = r.setRemoteProxy(context.Background(), obj, &rest.Config{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("unsupported scheme 'ftp'"))
+}
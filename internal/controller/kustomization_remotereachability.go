@@ -0,0 +1,155 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// remoteReachable reports whether the remote cluster targeted by a
+// Kustomization was reachable at its last reconciliation.
+var remoteReachable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gotk_kustomization_remote_reachable",
+	Help: "Whether the API server of the remote cluster targeted by a Kustomization was reachable (1) or not (0) at its last reconciliation.",
+}, []string{"name", "namespace"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(remoteReachable)
+}
+
+// deleteRemoteReachableMetrics removes the remote reachability
+// metrics of the given Kustomization.
+func deleteRemoteReachableMetrics(obj *kustomizev1.Kustomization) {
+	remoteReachable.DeletePartialMatch(prometheus.Labels{
+		"name":      obj.GetName(),
+		"namespace": obj.GetNamespace(),
+	})
+}
+
+// remoteOutage tracks a Kustomization whose remote cluster is unreachable.
+type remoteOutage struct {
+	start    time.Time
+	reported bool
+}
+
+// isRemoteUnreachable returns true if the error was caused by a failure to
+// reach the API server, e.g. a refused or reset connection, a DNS failure or
+// a dial timeout, as opposed to a response of the API server.
+func isRemoteUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &opErr), errors.As(err, &dnsErr):
+		return true
+	case errors.As(err, &urlErr) && urlErr.Timeout():
+		return true
+	}
+	return utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err)
+}
+
+// waitForRemote handles a reconciliation which failed as the remote cluster
+// is unreachable, by retrying it every DependencyRequeueInterval. During the
+// RemoteUnreachableGracePeriod, the Ready condition is restored to its status
+// before the reconciliation if the spec is unchanged, to not flap on brief
+// outages. An event is emitted when the outage starts, and an error event
+// when it exceeds the grace period. It returns true if the Ready condition
+// was restored.
+func (r *KustomizationReconciler) waitForRemote(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	revision, originRevision string,
+	readyBefore *metav1.Condition,
+	reason error) (ctrl.Result, bool) {
+	remoteReachable.WithLabelValues(obj.GetName(), obj.GetNamespace()).Set(0)
+	v, loaded := r.remoteOutages.LoadOrStore(client.ObjectKeyFromObject(obj).String(),
+		&remoteOutage{start: time.Now()})
+
+	// The outage is only updated by the reconciliations of the
+	// Kustomization, which are serialized by the work queue.
+	outage := v.(*remoteOutage)
+	unreachable := time.Since(outage.start)
+	msg := fmt.Sprintf("Remote cluster unreachable for %s, retrying in %s: %s",
+		unreachable.Round(time.Second).String(), r.DependencyRequeueInterval.String(), reason)
+	ctrl.LoggerFrom(ctx).Info(msg, "revision", revision)
+	result := ctrl.Result{RequeueAfter: r.DependencyRequeueInterval}
+
+	if unreachable < r.RemoteUnreachableGracePeriod && readyBefore != nil &&
+		readyBefore.Status == metav1.ConditionTrue && obj.Status.ObservedGeneration == obj.Generation {
+		// Restore the condition as is, to keep its last transition time.
+		for i := range obj.Status.Conditions {
+			if obj.Status.Conditions[i].Type == meta.ReadyCondition {
+				obj.Status.Conditions[i] = *readyBefore
+			}
+		}
+		if !loaded {
+			r.event(obj, revision, originRevision, eventv1.EventSeverityInfo,
+				fmt.Sprintf("Remote cluster unreachable: %s, retrying every %s",
+					reason, r.DependencyRequeueInterval.String()), nil)
+		}
+		return result, true
+	}
+
+	conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.RemoteUnreachableReason, "%s", msg)
+	if !outage.reported {
+		outage.reported = true
+		r.event(obj, revision, originRevision, eventv1.EventSeverityError, msg, nil)
+	}
+	return result, false
+}
+
+// endRemoteOutage records that the remote cluster of the Kustomization was
+// reached, and emits an event with the duration of the outage if it was
+// unreachable.
+func (r *KustomizationReconciler) endRemoteOutage(obj *kustomizev1.Kustomization,
+	revision, originRevision string) {
+	remoteReachable.WithLabelValues(obj.GetName(), obj.GetNamespace()).Set(1)
+	v, ok := r.remoteOutages.LoadAndDelete(client.ObjectKeyFromObject(obj).String())
+	if !ok {
+		return
+	}
+	r.event(obj, revision, originRevision, eventv1.EventSeverityInfo,
+		fmt.Sprintf("Remote cluster reachable after %s",
+			time.Since(v.(*remoteOutage).start).Round(time.Second).String()), nil)
+}
+
+// deleteRemoteOutage removes the remote outage record of the Kustomization.
+func (r *KustomizationReconciler) deleteRemoteOutage(obj *kustomizev1.Kustomization) {
+	r.remoteOutages.Delete(client.ObjectKeyFromObject(obj).String())
+}
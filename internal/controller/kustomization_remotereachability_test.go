@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestIsRemoteUnreachable(t *testing.T) {
+	dialErr := &url.Error{Op: "Get", URL: "https://remote:6443/api", Err: &net.OpError{
+		Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "remote"},
+	}}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no error", err: nil, want: false},
+		{name: "dial error", err: fmt.Errorf("failed to build kube client: %w", dialErr), want: true},
+		{name: "connection refused", err: fmt.Errorf("apply failed: %w", syscall.ECONNREFUSED), want: true},
+		{name: "connection reset", err: syscall.ECONNRESET, want: true},
+		{name: "timeout", err: &url.Error{Op: "Get", URL: "https://remote:6443/api", Err: timeoutError{}}, want: true},
+		{
+			name: "rejected apply",
+			err:  apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "app", errors.New("denied")),
+			want: false,
+		},
+		{name: "build error", err: errors.New("kustomize build failed"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isRemoteUnreachable(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+func TestWaitForRemote(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{
+		EventRecorder:                recorder,
+		DependencyRequeueInterval:    30 * time.Second,
+		RemoteUnreachableGracePeriod: 5 * time.Minute,
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", Generation: 2},
+	}
+	obj.Status.ObservedGeneration = 2
+	conditions.MarkTrue(obj, meta.ReadyCondition, meta.ReconciliationSucceededReason, "Applied revision: main@sha1:abc")
+	readyBefore := conditions.Get(obj, meta.ReadyCondition)
+	unreachable := fmt.Errorf("failed to build kube client: %w", syscall.ECONNREFUSED)
+	reachable := func() float64 {
+		return testutil.ToFloat64(remoteReachable.WithLabelValues("app", "apps"))
+	}
+
+	// During the grace period, the Ready condition is kept.
+	conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", unreachable)
+	result, kept := r.waitForRemote(ctx, obj, "main@sha1:def", "", readyBefore, unreachable)
+	g.Expect(kept).To(BeTrue())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+	g.Expect(conditions.Get(obj, meta.ReadyCondition)).To(Equal(readyBefore))
+	g.Expect(reachable()).To(BeZero())
+	g.Expect(recorder.Events).To(Receive(And(
+		HavePrefix("Normal"),
+		ContainSubstring("Remote cluster unreachable: failed to build kube client"),
+	)))
+
+	// The retries don't emit events.
+	_, kept = r.waitForRemote(ctx, obj, "main@sha1:def", "", readyBefore, unreachable)
+	g.Expect(kept).To(BeTrue())
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// After the grace period, the Kustomization is marked as not ready.
+	v, ok := r.remoteOutages.Load(client.ObjectKeyFromObject(obj).String())
+	g.Expect(ok).To(BeTrue())
+	v.(*remoteOutage).start = time.Now().Add(-10 * time.Minute)
+	_, kept = r.waitForRemote(ctx, obj, "main@sha1:def", "", readyBefore, unreachable)
+	g.Expect(kept).To(BeFalse())
+	g.Expect(conditions.IsFalse(obj, meta.ReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(kustomizev1.RemoteUnreachableReason))
+	g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(HavePrefix("Remote cluster unreachable for 10m0s"))
+	g.Expect(recorder.Events).To(Receive(HavePrefix("Warning")))
+	_, _ = r.waitForRemote(ctx, obj, "main@sha1:def", "", readyBefore, unreachable)
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The end of the outage is reported once.
+	r.endRemoteOutage(obj, "main@sha1:def", "")
+	g.Expect(reachable()).To(Equal(float64(1)))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("Remote cluster reachable after 10m0s")))
+	r.endRemoteOutage(obj, "main@sha1:def", "")
+	g.Expect(recorder.Events).ToNot(Receive())
+
+	// The Ready condition is not kept after a spec change.
+	obj.Generation = 3
+	_, kept = r.waitForRemote(ctx, obj, "main@sha1:def", "", readyBefore, unreachable)
+	g.Expect(kept).To(BeFalse())
+	g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(kustomizev1.RemoteUnreachableReason))
+	g.Expect(recorder.Events).To(Receive(HavePrefix("Warning")))
+
+	deleteRemoteReachableMetrics(obj)
+}
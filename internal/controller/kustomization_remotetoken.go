@@ -0,0 +1,244 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/auth"
+	authutils "github.com/fluxcd/pkg/auth/utils"
+	"github.com/fluxcd/pkg/cache"
+	runtimeClient "github.com/fluxcd/pkg/runtime/client"
+)
+
+// remoteTokenRefreshTimeout is the timeout of
+// the background refresh of a remote token.
+const remoteTokenRefreshTimeout = time.Minute
+
+// remoteTokenCacheEventsTotal counts the lookups of the remote cluster
+// credentials by result: hit, miss, refresh or refresh_failure.
+var remoteTokenCacheEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gotk_kustomization_remote_token_cache_events_total",
+	Help: "The total number of lookups and background refreshes of the remote cluster credentials of a kubeconfig ConfigMap.",
+}, []string{"event", "configmap", "namespace"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(remoteTokenCacheEventsTotal)
+}
+
+// remoteTokenCache holds the credentials of the remote clusters minted with
+// the workload identity providers of the kubeconfig ConfigMaps. A token is
+// refreshed in the background once half of its lifetime has passed, and it
+// is served until 80% of its lifetime has passed, like in the TokenCache.
+// Hence, the requests to the remote clusters don't wait on the token
+// endpoints of the providers as long as the token is used at least
+// once between 50% and 80% of its lifetime.
+type remoteTokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]*remoteToken
+}
+
+// remoteToken is a token held by the remoteTokenCache.
+type remoteToken struct {
+	conf       *auth.RESTConfig
+	issuedAt   time.Time
+	refreshing bool
+}
+
+// lifetime returns the lifetime of the token,
+// capped to the maximum duration of the TokenCache.
+func (t *remoteToken) lifetime() time.Duration {
+	return min(t.conf.ExpiresAt.Sub(t.issuedAt), cache.TokenMaxDuration)
+}
+
+// refreshAt returns the time from which the token is refreshed.
+func (t *remoteToken) refreshAt() time.Time {
+	return t.issuedAt.Add(t.lifetime() / 2)
+}
+
+// expireAt returns the time from which the token is no longer served.
+func (t *remoteToken) expireAt() time.Time {
+	return t.issuedAt.Add(t.lifetime() * 8 / 10)
+}
+
+// get returns the token stored under the given key if not expired, and
+// starts its background refresh if due. Otherwise, it fetches a new token.
+// The labels are the ConfigMap name and namespace reported in the metrics.
+func (c *remoteTokenCache) get(ctx context.Context, now time.Time, key string, labels []string,
+	fetch func(context.Context) (*auth.RESTConfig, error)) (*auth.RESTConfig, error) {
+	c.mu.Lock()
+	if t, ok := c.tokens[key]; ok && now.Before(t.expireAt()) {
+		if !t.refreshing && !now.Before(t.refreshAt()) {
+			t.refreshing = true
+			go c.refresh(context.WithoutCancel(ctx), key, labels, fetch)
+		}
+		c.mu.Unlock()
+		remoteTokenCacheEventsTotal.WithLabelValues(append([]string{"hit"}, labels...)...).Inc()
+		return t.conf, nil
+	}
+	c.mu.Unlock()
+
+	remoteTokenCacheEventsTotal.WithLabelValues(append([]string{"miss"}, labels...)...).Inc()
+	conf, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.store(now, key, conf)
+	return conf, nil
+}
+
+// refresh fetches a new token and replaces the stored one. On failure,
+// the stored token is served until it expires, and the refresh is
+// retried on the next lookup.
+func (c *remoteTokenCache) refresh(ctx context.Context, key string, labels []string,
+	fetch func(context.Context) (*auth.RESTConfig, error)) {
+	ctx, cancel := context.WithTimeout(ctx, remoteTokenRefreshTimeout)
+	defer cancel()
+
+	now := time.Now()
+	conf, err := fetch(ctx)
+	if err != nil {
+		ctrl.Log.WithName("remote-token-cache").Error(err, "failed to refresh remote cluster credentials",
+			"configmap", strings.Join(labels, "/"))
+		remoteTokenCacheEventsTotal.WithLabelValues(append([]string{"refresh_failure"}, labels...)...).Inc()
+		c.mu.Lock()
+		if t, ok := c.tokens[key]; ok {
+			t.refreshing = false
+		}
+		c.mu.Unlock()
+		return
+	}
+	remoteTokenCacheEventsTotal.WithLabelValues(append([]string{"refresh"}, labels...)...).Inc()
+	c.store(now, key, conf)
+}
+
+// store stores the token and removes the expired ones.
+func (c *remoteTokenCache) store(now time.Time, key string, conf *auth.RESTConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, t := range c.tokens {
+		if !now.Before(t.expireAt()) {
+			delete(c.tokens, k)
+		}
+	}
+	if c.tokens == nil {
+		c.tokens = make(map[string]*remoteToken)
+	}
+	c.tokens[key] = &remoteToken{conf: conf, issuedAt: now}
+}
+
+// getRefreshingRESTConfigFetcher returns a ProviderRESTConfigFetcher which
+// authenticates the requests to the remote cluster with the tokens of the
+// remoteTokenCache. It reads the provider settings from the kubeconfig
+// ConfigMap like the fetcher of the auth package.
+func (r *KustomizationReconciler) getRefreshingRESTConfigFetcher() runtimeClient.ProviderRESTConfigFetcher {
+	return func(ctx context.Context, ref meta.KubeConfigReference,
+		namespace string, ctrlClient client.Client) (*rest.Config, error) {
+		cmKey := client.ObjectKey{
+			Name:      ref.ConfigMapRef.Name,
+			Namespace: namespace,
+		}
+		var cm corev1.ConfigMap
+		if err := ctrlClient.Get(ctx, cmKey, &cm); err != nil {
+			return nil, fmt.Errorf("failed to get configmap %s: %w", cmKey.String(), err)
+		}
+
+		provider, err := authutils.ProviderByName[auth.RESTConfigProvider](cm.Data[meta.KubeConfigKeyProvider])
+		if err != nil {
+			return nil, err
+		}
+
+		opts := []auth.Option{
+			auth.WithClient(ctrlClient),
+			auth.WithServiceAccountNamespace(namespace),
+		}
+		if c, ok := cm.Data[meta.KubeConfigKeyCluster]; ok {
+			opts = append(opts, auth.WithClusterResource(c))
+		}
+		if a, ok := cm.Data[meta.KubeConfigKeyAddress]; ok {
+			opts = append(opts, auth.WithClusterAddress(a))
+		}
+		if ca, ok := cm.Data[meta.KubeConfigKeyCACert]; ok {
+			opts = append(opts, auth.WithCAData(ca))
+		}
+		if name, ok := cm.Data[meta.KubeConfigKeyServiceAccountName]; ok {
+			opts = append(opts, auth.WithServiceAccountName(name))
+		}
+		if a, ok := cm.Data[meta.KubeConfigKeyAudiences]; ok {
+			var audiences []string
+			for aud := range strings.SplitSeq(a, "\n") {
+				if aud = strings.TrimSpace(aud); aud != "" {
+					audiences = append(audiences, aud)
+				}
+			}
+			opts = append(opts, auth.WithAudiences(audiences...))
+		}
+
+		key := namespace + "/" + cm.Name + "/" + configMapDigest(&cm)
+		labels := []string{cm.Name, namespace}
+		getToken := func(ctx context.Context) (*auth.RESTConfig, error) {
+			return r.remoteTokens.get(ctx, time.Now(), key, labels, func(ctx context.Context) (*auth.RESTConfig, error) {
+				return auth.GetRESTConfig(ctx, provider, opts...)
+			})
+		}
+
+		conf, err := getToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		restConfig := &rest.Config{
+			Host:            conf.Host,
+			TLSClientConfig: rest.TLSClientConfig{CAData: conf.CAData},
+		}
+		restConfig.Wrap(func(base http.RoundTripper) http.RoundTripper {
+			return &remoteTokenRoundTripper{base: base, getToken: getToken}
+		})
+		return restConfig, nil
+	}
+}
+
+// remoteTokenRoundTripper is an http.RoundTripper which sets the bearer
+// token of the remoteTokenCache on every request to the remote cluster,
+// as the requests of the health checks may outlive a token.
+type remoteTokenRoundTripper struct {
+	base     http.RoundTripper
+	getToken func(context.Context) (*auth.RESTConfig, error)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *remoteTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	conf, err := rt.getToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+conf.BearerToken)
+	return rt.base.RoundTrip(req)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/auth"
+)
+
+func TestRemoteTokenCache(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	var minted atomic.Int32
+	var failing atomic.Bool
+	fetch := func(context.Context) (*auth.RESTConfig, error) {
+		if failing.Load() {
+			return nil, errors.New("token endpoint unavailable")
+		}
+		n := minted.Add(1)
+		return &auth.RESTConfig{
+			Host:        "https://remote:443",
+			BearerToken: fmt.Sprintf("token-%d", n),
+			ExpiresAt:   time.Now().Add(10 * time.Minute),
+		}, nil
+	}
+	labels := []string{"kubeconfig", "apps"}
+	c := &remoteTokenCache{}
+
+	t.Run("mints a token on miss", func(t *testing.T) {
+		conf, err := c.get(ctx, time.Now(), "key", labels, fetch)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conf.BearerToken).To(Equal("token-1"))
+	})
+
+	t.Run("serves the token before half of its lifetime", func(t *testing.T) {
+		conf, err := c.get(ctx, time.Now().Add(4*time.Minute), "key", labels, fetch)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conf.BearerToken).To(Equal("token-1"))
+		g.Consistently(minted.Load, 50*time.Millisecond).Should(BeEquivalentTo(1))
+	})
+
+	t.Run("serves the token while refreshing it in the background", func(t *testing.T) {
+		conf, err := c.get(ctx, time.Now().Add(6*time.Minute), "key", labels, fetch)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conf.BearerToken).To(Equal("token-1"))
+		g.Eventually(func() string {
+			conf, _ := c.get(ctx, time.Now(), "key", labels, fetch)
+			return conf.BearerToken
+		}).Should(Equal("token-2"))
+		g.Expect(minted.Load()).To(BeEquivalentTo(2))
+	})
+
+	t.Run("serves the token until it expires when the refresh fails", func(t *testing.T) {
+		failing.Store(true)
+		conf, err := c.get(ctx, time.Now().Add(6*time.Minute), "key", labels, fetch)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conf.BearerToken).To(Equal("token-2"))
+		g.Eventually(func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.tokens["key"].refreshing
+		}).Should(BeFalse())
+
+		_, err = c.get(ctx, time.Now().Add(9*time.Minute), "key", labels, fetch)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
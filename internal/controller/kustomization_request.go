@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
+	"github.com/fluxcd/cli-utils/pkg/object"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	runtimeCtrl "github.com/fluxcd/pkg/runtime/controller"
+	"github.com/fluxcd/pkg/runtime/patch"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+// reconcileRequest holds the one-off parameters of an on-demand
+// reconciliation requested with the reconcile.fluxcd.io/requestedAt
+// annotation.
+type reconcileRequest struct {
+	// force recreates the objects that can't be patched,
+	// as if spec.force was set to true.
+	force bool
+
+	// mode restricts the reconciliation to pruning, health checking,
+	// snapshotting, restoring or observing.
+	mode string
+
+	// manual is true if the requestedAt value has not been handled yet.
+	manual bool
+
+	// requestedAt is the value of the reconcile.fluxcd.io/requestedAt annotation.
+	requestedAt string
+
+	// requestedBy is the value of the requested-by annotation.
+	requestedBy string
+
+	// renderOnly is true if the ready Kustomization was enqueued for the
+	// change of a ConfigMap or Secret used only to render its manifests.
+	renderOnly bool
+}
+
+// getReconcileRequest returns the parameters of the pending reconcile request.
+// The force request is handled once per reconcile.fluxcd.io/forceAt value,
+// and must match the reconcile.fluxcd.io/requestedAt value. The mode is
+// honored only when the requestedAt value has not been handled yet.
+func getReconcileRequest(ctx context.Context, obj *kustomizev1.Kustomization) reconcileRequest {
+	req := reconcileRequest{
+		force: meta.ShouldHandleForceRequest(obj),
+	}
+
+	requestedAt, ok := meta.ReconcileAnnotationValue(obj.GetAnnotations())
+	if !ok || requestedAt == obj.Status.GetLastHandledReconcileRequest() {
+		return req
+	}
+	req.manual = true
+	req.requestedAt = requestedAt
+	req.requestedBy = obj.GetAnnotations()[kustomizev1.RequestedByAnnotation]
+
+	switch mode := obj.GetAnnotations()[kustomizev1.ReconcileModeAnnotation]; mode {
+	case "":
+	case kustomizev1.ReconcileModePruneOnly,
+		kustomizev1.ReconcileModeHealthOnly,
+		kustomizev1.ReconcileModeSkipHealthChecks,
+		kustomizev1.ReconcileModeSnapshot,
+		kustomizev1.ReconcileModeRestore,
+		kustomizev1.ReconcileModeObserve,
+		kustomizev1.ReconcileModeCompare:
+		req.mode = mode
+	default:
+		ctrl.LoggerFrom(ctx).Info("ignoring unsupported reconcile mode", "mode", mode)
+	}
+	return req
+}
+
+// reconcilePruneOnly deletes the objects of the old inventory that are no
+// longer part of the built manifests, without applying the manifests.
+func (r *KustomizationReconciler) reconcilePruneOnly(ctx context.Context,
+	manager *ssa.ResourceManager,
+	obj *kustomizev1.Kustomization,
+	revision string,
+	originRevision string,
+	oldInventory *kustomizev1.ResourceInventory,
+	objects []*unstructured.Unstructured) error {
+	desiredInventory := inventory.New()
+	inventory.Merge(desiredInventory, objects)
+
+	staleObjects, err := inventory.Diff(oldInventory, desiredInventory)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+	staleObjects = retainDelegatedCRDs(obj, staleObjects)
+
+	// Keep tracking the stale objects if garbage collection is disabled.
+	survivors := staleObjects
+	var pruneErr error
+	if obj.Spec.Prune {
+		_, survivors, pruneErr = r.prune(ctx, manager, obj, revision, originRevision, staleObjects)
+	}
+
+	// Keep tracking the objects that are still part of the manifests
+	// and the stale objects whose deletion failed.
+	stale := make(map[string]bool, len(staleObjects))
+	for _, u := range staleObjects {
+		stale[object.UnstructuredToObjMetadata(u).String()] = true
+	}
+	newInventory := inventory.New()
+	for _, entry := range oldInventory.Entries {
+		if !stale[entry.ID] {
+			newInventory.Entries = append(newInventory.Entries, entry)
+		}
+	}
+	inventory.Merge(newInventory, survivors)
+	if r.InventoryObjectRevisions {
+		_ = inventory.SetRevisions(newInventory, oldInventory, nil, nil, revision)
+	}
+	if err := r.setInventory(ctx, obj, newInventory); err != nil {
+		pruneErr = errors.Join(pruneErr, err)
+	}
+
+	if pruneErr != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.PruneFailedReason, "%s", pruneErr)
+		return pruneErr
+	}
+
+	conditions.MarkTrue(obj,
+		meta.ReadyCondition,
+		meta.ReconciliationSucceededReason,
+		"Pruned stale objects of revision: %s", revision)
+	return nil
+}
+
+// reconcileHealthOnly runs the health checks of the objects
+// of the last applied revision, without applying the manifests.
+func (r *KustomizationReconciler) reconcileHealthOnly(ctx context.Context,
+	kubeClient client.Client,
+	statusPoller *polling.StatusPoller,
+	patcher *patch.SerialPatcher,
+	obj *kustomizev1.Kustomization,
+	oldInventory *kustomizev1.ResourceInventory) error {
+	ctx = withAPIOperation(ctx, apiOperationHealth, false)
+	revision := obj.Status.LastAppliedRevision
+	originRevision := obj.Status.LastAppliedOriginRevision
+
+	objects, err := inventory.ListMetadata(oldInventory)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+	changeSet := ssa.NewChangeSet()
+	for _, o := range objects {
+		changeSet.Add(ssa.ChangeSetEntry{
+			ObjMetadata: o,
+			Subject:     ssautil.FmtObjMetadata(o),
+			Action:      ssa.UnchangedAction,
+		})
+	}
+
+	// Restrict the health checks to the live objects matching the wait selector.
+	waitObjects, err := getWaitObjects(ctx, kubeClient, obj, oldInventory)
+	if err == nil {
+		changeSet, err = selectWaitObjects(obj, changeSet, waitObjects)
+	}
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.HealthCheckFailedReason, "%s", err)
+		return err
+	}
+
+	resourceManager := ssa.NewResourceManager(kubeClient, statusPoller, ssa.Owner{
+		Field: r.ControllerName,
+		Group: kustomizev1.GroupVersion.Group,
+	})
+	if err := r.checkHealth(ctx,
+		resourceManager,
+		patcher,
+		obj,
+		revision,
+		originRevision,
+		false,
+		false,
+		changeSet,
+		nil); err != nil {
+		if errors.Is(err, &runtimeCtrl.QueueEventSource{}) {
+			return err
+		}
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.HealthCheckFailedReason, "%s", err)
+		return err
+	}
+
+	conditions.MarkTrue(obj,
+		meta.ReadyCondition,
+		meta.ReconciliationSucceededReason,
+		"Health check passed for revision: %s", revision)
+	return nil
+}
+
+// recordHealthCheckOverride records in status and events that the revision
+// was applied without running the health checks.
+func (r *KustomizationReconciler) recordHealthCheckOverride(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	revision string,
+	originRevision string,
+	req reconcileRequest) {
+	// The health of the applied objects is unknown.
+	conditions.Delete(obj, meta.HealthyCondition)
+
+	obj.Status.LastHealthCheckOverride = &kustomizev1.HealthCheckOverride{
+		RequestedAt: req.requestedAt,
+		RequestedBy: req.requestedBy,
+		Revision:    revision,
+		HandledAt:   metav1.Now(),
+	}
+
+	requestedBy := req.requestedBy
+	if requestedBy == "" {
+		requestedBy = "unknown"
+	}
+	msg := fmt.Sprintf("Health checks skipped for revision %s by emergency override requested by %s at %s",
+		revision, requestedBy, req.requestedAt)
+	ctrl.LoggerFrom(ctx).Info(msg)
+	r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+}
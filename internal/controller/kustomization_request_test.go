@@ -0,0 +1,239 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/testserver"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestKustomizationReconciler_ReconcileRequest(t *testing.T) {
+	g := NewWithT(t)
+	id := "req-" + randStringRunes(5)
+	revision := "v1.0.0"
+
+	err := createNamespace(id)
+	g.Expect(err).NotTo(HaveOccurred(), "failed to create test namespace")
+
+	manifests := func(names ...string) []testserver.File {
+		var files []testserver.File
+		for _, name := range names {
+			files = append(files, testserver.File{
+				Name: name + ".yaml",
+				Body: fmt.Sprintf(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %[1]s
+data:
+  key: "%[1]s"
+`, name),
+			})
+		}
+		return files
+	}
+
+	artifact, err := testServer.ArtifactFromFiles(manifests("first", "second"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	repositoryName := types.NamespacedName{
+		Name:      fmt.Sprintf("req-%s", randStringRunes(5)),
+		Namespace: id,
+	}
+
+	err = applyGitRepository(repositoryName, artifact, revision)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	kustomization := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("req-%s", randStringRunes(5)),
+			Namespace: id,
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: time.Hour},
+			Path:     "./",
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{
+				Name:      repositoryName.Name,
+				Namespace: repositoryName.Namespace,
+				Kind:      sourcev1.GitRepositoryKind,
+			},
+			TargetNamespace: id,
+			Prune:           true,
+		},
+	}
+
+	g.Expect(k8sClient.Create(context.Background(), kustomization)).To(Succeed())
+
+	resultK := &kustomizev1.Kustomization{}
+	g.Eventually(func() bool {
+		_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+		return resultK.Status.LastAppliedRevision == revision
+	}, timeout, time.Second).Should(BeTrue())
+
+	t.Run("prune-only deletes stale objects without applying", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch := client.MergeFrom(resultK.DeepCopy())
+		resultK.Spec.Suspend = true
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		artifact, err := testServer.ArtifactFromFiles(manifests("first", "third"))
+		g.Expect(err).NotTo(HaveOccurred())
+		err = applyGitRepository(repositoryName, artifact, "v2.0.0")
+		g.Expect(err).NotTo(HaveOccurred())
+
+		requestedAt := time.Now().Format(time.RFC3339Nano)
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch = client.MergeFrom(resultK.DeepCopy())
+		resultK.Spec.Suspend = false
+		resultK.SetAnnotations(map[string]string{
+			meta.ReconcileRequestAnnotation:     requestedAt,
+			kustomizev1.ReconcileModeAnnotation: kustomizev1.ReconcileModePruneOnly,
+		})
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return resultK.Status.LastHandledReconcileAt == requestedAt
+		}, timeout, time.Second).Should(BeTrue())
+
+		ready := apimeta.FindStatusCondition(resultK.Status.Conditions, meta.ReadyCondition)
+		g.Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+		g.Expect(ready.Message).To(HavePrefix("Pruned stale objects"))
+		g.Expect(resultK.Status.LastAppliedRevision).To(Equal(revision))
+		g.Expect(resultK.Status.Inventory.Entries).To(HaveLen(1))
+
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Name: "second", Namespace: id}, &corev1.ConfigMap{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Name: "third", Namespace: id}, &corev1.ConfigMap{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "first", Namespace: id}, &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	t.Run("health-only checks the last applied revision", func(t *testing.T) {
+		g := NewWithT(t)
+
+		requestedAt := time.Now().Format(time.RFC3339Nano)
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch := client.MergeFrom(resultK.DeepCopy())
+		resultK.SetAnnotations(map[string]string{
+			meta.ReconcileRequestAnnotation:     requestedAt,
+			kustomizev1.ReconcileModeAnnotation: kustomizev1.ReconcileModeHealthOnly,
+		})
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return resultK.Status.LastHandledReconcileAt == requestedAt
+		}, timeout, time.Second).Should(BeTrue())
+
+		ready := apimeta.FindStatusCondition(resultK.Status.Conditions, meta.ReadyCondition)
+		g.Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+		g.Expect(ready.Message).To(Equal("Health check passed for revision: " + revision))
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "third", Namespace: id}, &corev1.ConfigMap{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("applies the new revision on a regular request", func(t *testing.T) {
+		g := NewWithT(t)
+
+		requestedAt := time.Now().Format(time.RFC3339Nano)
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch := client.MergeFrom(resultK.DeepCopy())
+		resultK.SetAnnotations(map[string]string{
+			meta.ReconcileRequestAnnotation: requestedAt,
+			meta.ForceRequestAnnotation:     requestedAt,
+		})
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return resultK.Status.LastAppliedRevision == "v2.0.0"
+		}, timeout, time.Second).Should(BeTrue())
+
+		g.Expect(resultK.Status.LastHandledForceAt).To(Equal(requestedAt))
+		g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "third", Namespace: id}, &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	t.Run("skip-health-checks applies the new revision without health checks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch := client.MergeFrom(resultK.DeepCopy())
+		resultK.Spec.Suspend = true
+		resultK.Spec.Timeout = &metav1.Duration{Duration: 5 * time.Second}
+		resultK.Spec.HealthChecks = []meta.NamespacedObjectKindReference{
+			{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "missing",
+				Namespace:  id,
+			},
+		}
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		artifact, err := testServer.ArtifactFromFiles(manifests("first", "third", "fourth"))
+		g.Expect(err).NotTo(HaveOccurred())
+		err = applyGitRepository(repositoryName, artifact, "v3.0.0")
+		g.Expect(err).NotTo(HaveOccurred())
+
+		requestedAt := time.Now().Format(time.RFC3339Nano)
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch = client.MergeFrom(resultK.DeepCopy())
+		resultK.Spec.Suspend = false
+		resultK.SetAnnotations(map[string]string{
+			meta.ReconcileRequestAnnotation:     requestedAt,
+			kustomizev1.ReconcileModeAnnotation: kustomizev1.ReconcileModeSkipHealthChecks,
+			kustomizev1.RequestedByAnnotation:   "oncall@example.com",
+		})
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return resultK.Status.LastHandledReconcileAt == requestedAt
+		}, timeout, time.Second).Should(BeTrue())
+
+		g.Expect(conditions.IsReady(resultK)).To(BeTrue())
+		g.Expect(resultK.Status.LastAppliedRevision).To(Equal("v3.0.0"))
+		g.Expect(resultK.Status.LastHealthCheckOverride).NotTo(BeNil())
+		g.Expect(resultK.Status.LastHealthCheckOverride.RequestedAt).To(Equal(requestedAt))
+		g.Expect(resultK.Status.LastHealthCheckOverride.RequestedBy).To(Equal("oncall@example.com"))
+		g.Expect(resultK.Status.LastHealthCheckOverride.Revision).To(Equal("v3.0.0"))
+		g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "fourth", Namespace: id}, &corev1.ConfigMap{})).To(Succeed())
+
+		g.Eventually(func() []corev1.Event {
+			return getEvents(resultK.GetName(), nil)
+		}, timeout, time.Second).Should(ContainElement(HaveField("Message", ContainSubstring("requested by oncall@example.com"))))
+	})
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/testserver"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+func TestKustomizationReconciler_SecretInventoryPolicy(t *testing.T) {
+	g := NewWithT(t)
+	id := "sinv-" + randStringRunes(5)
+	revision := "v1.0.0"
+
+	err := createNamespace(id)
+	g.Expect(err).NotTo(HaveOccurred(), "failed to create test namespace")
+
+	manifests := func(name string) []testserver.File {
+		return []testserver.File{
+			{
+				Name: "config.yaml",
+				Body: fmt.Sprintf(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "%[1]s"
+data:
+  key: value
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: "%[1]s"
+stringData:
+  key: value
+`, name),
+			},
+		}
+	}
+
+	artifact, err := testServer.ArtifactFromFiles(manifests(id))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	repositoryName := types.NamespacedName{
+		Name:      fmt.Sprintf("sinv-%s", randStringRunes(5)),
+		Namespace: id,
+	}
+
+	err = applyGitRepository(repositoryName, artifact, revision)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	kustomization := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sinv-%s", randStringRunes(5)),
+			Namespace: id,
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: 2 * time.Minute},
+			Path:     "./",
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{
+				Name:      repositoryName.Name,
+				Namespace: repositoryName.Namespace,
+				Kind:      sourcev1.GitRepositoryKind,
+			},
+			TargetNamespace:       id,
+			Prune:                 true,
+			SecretInventoryPolicy: kustomizev1.SecretInventoryPolicyHash,
+		},
+	}
+
+	g.Expect(k8sClient.Create(context.Background(), kustomization)).To(Succeed())
+
+	resultK := &kustomizev1.Kustomization{}
+	inventorySecret := &corev1.Secret{}
+	inventorySecretKeyName := types.NamespacedName{Name: inventorySecretName(kustomization), Namespace: id}
+
+	t.Run("hashes secret entries in status", func(t *testing.T) {
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return isReconcileSuccess(resultK) && resultK.Status.LastAppliedRevision == revision
+		}, timeout, time.Second).Should(BeTrue())
+
+		g.Expect(resultK.Status.Inventory.Entries).To(HaveLen(2))
+		for _, entry := range resultK.Status.Inventory.Entries {
+			if entry.ID != fmt.Sprintf("%[1]s_%[1]s__ConfigMap", id) {
+				g.Expect(entry.ID).To(HavePrefix(id + "_sha256:"))
+			}
+		}
+
+		g.Expect(k8sClient.Get(context.Background(), inventorySecretKeyName, inventorySecret)).To(Succeed())
+		full := &kustomizev1.ResourceInventory{}
+		g.Expect(json.Unmarshal(inventorySecret.Data[inventorySecretKey], full)).To(Succeed())
+		g.Expect(full.Entries).To(ContainElement(kustomizev1.ResourceRef{
+			ID:      fmt.Sprintf("%[1]s_%[1]s__Secret", id),
+			Version: "v1",
+		}))
+	})
+
+	t.Run("prunes hashed secrets", func(t *testing.T) {
+		newName := id + "-new"
+		artifact, err := testServer.ArtifactFromFiles(manifests(newName))
+		g.Expect(err).NotTo(HaveOccurred())
+		revision = "v2.0.0"
+		g.Expect(applyGitRepository(repositoryName, artifact, revision)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return isReconcileSuccess(resultK) && resultK.Status.LastAppliedRevision == revision
+		}, timeout, time.Second).Should(BeTrue())
+
+		old := &corev1.Secret{}
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Name: id, Namespace: id}, old)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("restores plain entries", func(t *testing.T) {
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch := client.MergeFrom(resultK.DeepCopy())
+		resultK.Spec.SecretInventoryPolicy = kustomizev1.SecretInventoryPolicyPlain
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return isReconcileSuccess(resultK) && resultK.Status.ObservedGeneration == resultK.Generation
+		}, timeout, time.Second).Should(BeTrue())
+
+		g.Expect(resultK.Status.Inventory.Entries).To(ContainElement(kustomizev1.ResourceRef{
+			ID:      fmt.Sprintf("%[1]s_%[1]s-new__Secret", id),
+			Version: "v1",
+		}))
+
+		err := k8sClient.Get(context.Background(), inventorySecretKeyName, inventorySecret)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+}
+
+func TestSecretInventoryPolicy_OmitToPlain(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "uid"},
+		Spec:       kustomizev1.KustomizationSpec{SecretInventoryPolicy: kustomizev1.SecretInventoryPolicyOmit},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(obj).Build(),
+	}
+	full := &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+		{ID: "apps_settings__ConfigMap", Version: "v1"},
+		{ID: "apps_token__Secret", Version: "v1"},
+	}}
+
+	// The Secret entries are stored in the inventory Secret only.
+	g.Expect(r.setInventory(ctx, obj, full)).To(Succeed())
+	g.Expect(obj.Status.Inventory.Entries).To(HaveLen(1))
+	secret := &corev1.Secret{}
+	secretName := types.NamespacedName{Namespace: "apps", Name: inventorySecretName(obj)}
+	g.Expect(r.Get(ctx, secretName, secret)).To(Succeed())
+	g.Expect(secret.GetLabels()).To(Equal(map[string]string{inventorySecretLabel: "app"}))
+	g.Expect(obj.Status.InventorySecretName).To(Equal(secretName.Name))
+
+	// The entries of the inventory Secret are merged back with the Plain
+	// policy, and the Secret is deleted once they are recorded in status.
+	obj.Spec.SecretInventoryPolicy = kustomizev1.SecretInventoryPolicyPlain
+	inv, err := r.getInventory(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inv.Entries).To(ConsistOf(full.Entries))
+	g.Expect(r.setInventory(ctx, obj, inv)).To(Succeed())
+	g.Expect(obj.Status.Inventory.Entries).To(ConsistOf(full.Entries))
+	g.Expect(obj.Status.InventorySecretName).To(BeEmpty())
+	g.Expect(apierrors.IsNotFound(r.Get(ctx, secretName, secret))).To(BeTrue())
+
+	// The status inventory is used once the Secret is deleted.
+	inv, err = r.getInventory(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inventory.HasHashedEntries(inv)).To(BeFalse())
+	g.Expect(inv.Entries).To(ConsistOf(full.Entries))
+}
+
+func TestSecretInventoryPolicy_ForeignSecret(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "uid"},
+	}
+	foreign := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: inventorySecretName(obj), Namespace: "apps"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(obj, foreign).Build(),
+	}
+	inv := &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+		{ID: "apps_token__Secret", Version: "v1"},
+	}}
+	secret := &corev1.Secret{}
+
+	// The Secret isn't read nor deleted with the Plain policy.
+	got, err := r.getInventory(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Entries).To(BeEmpty())
+	g.Expect(r.setInventory(ctx, obj, inv)).To(Succeed())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(foreign), secret)).To(Succeed())
+	g.Expect(secret.Data).To(Equal(foreign.Data))
+
+	// The Secret isn't overwritten with the Hash policy.
+	obj.Spec.SecretInventoryPolicy = kustomizev1.SecretInventoryPolicyHash
+	err = r.setInventory(ctx, obj, inv)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not owned by the Kustomization"))
+	g.Expect(obj.Status.InventorySecretName).To(BeEmpty())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(foreign), secret)).To(Succeed())
+	g.Expect(secret.Data).To(Equal(foreign.Data))
+
+	// The Secret isn't read even if the status names it.
+	obj.Status.InventorySecretName = foreign.GetName()
+	_, err = r.getInventory(ctx, obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("not owned by the Kustomization"))
+
+	// The Secret isn't deleted when switching back to Plain.
+	obj.Spec.SecretInventoryPolicy = kustomizev1.SecretInventoryPolicyPlain
+	g.Expect(r.setInventory(ctx, obj, inv)).To(Succeed())
+	g.Expect(obj.Status.InventorySecretName).To(BeEmpty())
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(foreign), secret)).To(Succeed())
+}
+
+func TestSecretInventoryPolicy_PlainSecretNamedLikeHash(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps", UID: "uid"},
+		Spec:       kustomizev1.KustomizationSpec{SecretInventoryPolicy: kustomizev1.SecretInventoryPolicyPlain},
+	}
+	token := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sha256-5f1c", Namespace: "apps"},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(obj, token).Build(),
+	}
+	full := &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+		{ID: "apps_settings__ConfigMap", Version: "v1"},
+		{ID: "apps_sha256-5f1c__Secret", Version: "v1"},
+	}}
+
+	// The Secret entry is kept in the inventory, so that the Secret is pruned.
+	g.Expect(r.setInventory(ctx, obj, full)).To(Succeed())
+	g.Expect(obj.Status.Inventory.Entries).To(ConsistOf(full.Entries))
+	inv, err := r.getInventory(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inv.Entries).To(ConsistOf(full.Entries))
+	g.Expect(inventory.HasHashedEntries(inv)).To(BeFalse())
+}
+
+func TestFinalize_InventorySecretUnreadable(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+
+	newObj := func() *kustomizev1.Kustomization {
+		return &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "app",
+				Namespace:         "apps",
+				UID:               "uid",
+				DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+				Finalizers:        []string{kustomizev1.KustomizationFinalizer},
+			},
+			Spec: kustomizev1.KustomizationSpec{
+				Prune:                 true,
+				SecretInventoryPolicy: kustomizev1.SecretInventoryPolicyHash,
+			},
+			Status: kustomizev1.KustomizationStatus{
+				Inventory: &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+					{ID: "apps_settings__ConfigMap", Version: "v1"},
+				}},
+				InventorySecretName: "app-inventory",
+			},
+		}
+	}
+	recorder := record.NewFakeRecorder(32)
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.Secret); ok {
+					return apierrors.NewForbidden(corev1.Resource("secrets"), key.Name, fmt.Errorf("denied"))
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build(),
+		EventRecorder: recorder,
+	}
+
+	// The deletion is retried while the inventory can't be read.
+	obj := newObj()
+	_, err := r.finalize(ctx, obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(obj.GetFinalizers()).To(ContainElement(kustomizev1.KustomizationFinalizer))
+
+	// The finalizer is removed when the garbage collection is disabled.
+	obj = newObj()
+	obj.SetAnnotations(map[string]string{kustomizev1.DeletionGCAnnotation: kustomizev1.DisabledValue})
+	_, err = r.finalize(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetFinalizers()).To(BeEmpty())
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("ConfigMap/apps/settings")))
+
+	// The finalizer is removed when the deletion timeout is exceeded.
+	obj = newObj()
+	obj.Spec.DeletionTimeout = &metav1.Duration{Duration: time.Minute}
+	_, err = r.finalize(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetFinalizers()).To(BeEmpty())
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("deletion timeout of 1m0s exceeded")))
+}
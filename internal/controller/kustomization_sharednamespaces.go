@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// splitSharedNamespaces separates the Namespaces of the given objects which
+// are used by other Kustomizations targeting the same cluster, either as
+// target namespace or by the objects in their inventory, from the objects
+// that can be deleted. It returns the names of the other Kustomizations
+// using each shared Namespace. The Kustomizations being deleted are not
+// considered, as they won't use the Namespaces anymore.
+func (r *KustomizationReconciler) splitSharedNamespaces(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	objects []*unstructured.Unstructured) ([]*unstructured.Unstructured, map[string][]string, error) {
+	namespaces := make(map[string]struct{})
+	for _, u := range objects {
+		if u.GetAPIVersion() == "v1" && u.GetKind() == "Namespace" {
+			namespaces[u.GetName()] = struct{}{}
+		}
+	}
+	if len(namespaces) == 0 {
+		return objects, nil, nil
+	}
+
+	var list kustomizev1.KustomizationList
+	if err := r.List(ctx, &list); err != nil {
+		return nil, nil, fmt.Errorf("failed to list Kustomizations: %w", err)
+	}
+
+	users := make(map[string][]string)
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.GetUID() == obj.GetUID() ||
+			!other.GetDeletionTimestamp().IsZero() ||
+			!sameTargetCluster(obj, other) {
+			continue
+		}
+		for ns := range namespacesUsedBy(other) {
+			if _, ok := namespaces[ns]; ok {
+				users[ns] = append(users[ns], client.ObjectKeyFromObject(other).String())
+			}
+		}
+	}
+	if len(users) == 0 {
+		return objects, nil, nil
+	}
+
+	kept := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, u := range objects {
+		if _, ok := users[u.GetName()]; ok && u.GetAPIVersion() == "v1" && u.GetKind() == "Namespace" {
+			continue
+		}
+		kept = append(kept, u)
+	}
+	for ns := range users {
+		sort.Strings(users[ns])
+	}
+	return kept, users, nil
+}
+
+// namespacesUsedBy returns the target namespace of the Kustomization and
+// the namespaces of the objects in its inventory, including the Namespaces.
+func namespacesUsedBy(obj *kustomizev1.Kustomization) map[string]struct{} {
+	namespaces := make(map[string]struct{})
+	if obj.Spec.TargetNamespace != "" {
+		namespaces[obj.Spec.TargetNamespace] = struct{}{}
+	}
+	if obj.Status.Inventory == nil {
+		return namespaces
+	}
+	for _, entry := range obj.Status.Inventory.Entries {
+		m, err := object.ParseObjMetadata(entry.ID)
+		if err != nil {
+			continue
+		}
+		switch {
+		case m.Namespace != "":
+			namespaces[m.Namespace] = struct{}{}
+		case m.GroupKind.Group == "" && m.GroupKind.Kind == "Namespace":
+			namespaces[m.Name] = struct{}{}
+		}
+	}
+	return namespaces
+}
+
+// sameTargetCluster returns true if both Kustomizations apply their objects
+// to the same cluster, i.e. the cluster of the controller or the remote
+// cluster of the same kubeconfig.
+func sameTargetCluster(a, b *kustomizev1.Kustomization) bool {
+	if !isRemote(a) || !isRemote(b) {
+		return !isRemote(a) && !isRemote(b)
+	}
+	return a.GetNamespace() == b.GetNamespace() &&
+		apiequality.Semantic.DeepEqual(a.Spec.KubeConfig, b.Spec.KubeConfig) &&
+		apiequality.Semantic.DeepEqual(a.Spec.KubeConfigClusterRef, b.Spec.KubeConfigClusterRef)
+}
+
+// formatSharedNamespaces returns a message listing the shared
+// Namespaces along with the Kustomizations using them.
+func formatSharedNamespaces(users map[string][]string) string {
+	lines := make([]string, 0, len(users))
+	for ns, names := range users {
+		lines = append(lines, fmt.Sprintf("Namespace/%s used by %s", ns, strings.Join(names, ", ")))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
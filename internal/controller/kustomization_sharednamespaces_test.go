@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestSplitSharedNamespaces(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+
+	newKustomization := func(name string, entries ...string) *kustomizev1.Kustomization {
+		obj := &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "flux-system", UID: types.UID(name)},
+			Status:     kustomizev1.KustomizationStatus{Inventory: &kustomizev1.ResourceInventory{}},
+		}
+		for _, id := range entries {
+			obj.Status.Inventory.Entries = append(obj.Status.Inventory.Entries, kustomizev1.ResourceRef{ID: id, Version: "v1"})
+		}
+		return obj
+	}
+	newObject := func(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		return u
+	}
+
+	tenant := newKustomization("tenant", "_team-a__Namespace", "_team-b__Namespace", "_team-c__Namespace")
+	// Uses team-a with the objects of its inventory.
+	app := newKustomization("app", "team-a_web_apps_Deployment")
+	// Uses team-b as target namespace.
+	infra := newKustomization("infra")
+	infra.Spec.TargetNamespace = "team-b"
+	// Uses team-c on a remote cluster.
+	remote := newKustomization("remote", "team-c_web_apps_Deployment")
+	remote.Spec.KubeConfig = &meta.KubeConfigReference{SecretRef: &meta.SecretKeyReference{Name: "kubeconfig"}}
+	// Uses team-c but is being deleted.
+	deleting := newKustomization("deleting", "team-c_web_apps_Deployment")
+	deleting.Finalizers = []string{kustomizev1.KustomizationFinalizer}
+	deleting.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(tenant, app, infra, remote, deleting).Build(),
+	}
+
+	objects := []*unstructured.Unstructured{
+		newObject("v1", "Namespace", "", "team-a"),
+		newObject("v1", "Namespace", "", "team-b"),
+		newObject("v1", "Namespace", "", "team-c"),
+		newObject("v1", "ServiceAccount", "team-a", "team-a"),
+	}
+	kept, shared, err := r.splitSharedNamespaces(context.Background(), tenant, objects)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(kept).To(HaveLen(2))
+	g.Expect(kept[0].GetName()).To(Equal("team-c"))
+	g.Expect(kept[1].GetKind()).To(Equal("ServiceAccount"))
+	g.Expect(shared).To(Equal(map[string][]string{
+		"team-a": {"flux-system/app"},
+		"team-b": {"flux-system/infra"},
+	}))
+	g.Expect(formatSharedNamespaces(shared)).To(Equal(
+		"Namespace/team-a used by flux-system/app\nNamespace/team-b used by flux-system/infra"))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// isSkipDryRun returns true if the object is annotated
+// to be applied without a server-side dry-run.
+func isSkipDryRun(u *unstructured.Unstructured) bool {
+	return u.GetAnnotations()[kustomizev1.DryRunAnnotation] == kustomizev1.DisabledValue
+}
+
+// splitSkipDryRun splits the objects into the ones applied
+// after a server-side dry-run and the ones applied without.
+func splitSkipDryRun(objects []*unstructured.Unstructured) (dryRun, skipDryRun []*unstructured.Unstructured) {
+	for _, u := range objects {
+		if isSkipDryRun(u) {
+			skipDryRun = append(skipDryRun, u)
+		} else {
+			dryRun = append(dryRun, u)
+		}
+	}
+	return dryRun, skipDryRun
+}
+
+// applySkipDryRun applies the objects with server-side apply without the
+// dry-run request used to detect their drift, for the objects of the API
+// servers failing the dry-run requests, e.g. some aggregated APIs. The
+// objects are applied on every reconciliation, and reported as unchanged if
+// the apply didn't change them. The exclusion and if-not-present selectors
+// are honoured, while the drift ignore rules, the metadata cleanup and the
+// force policy are not.
+func (r *KustomizationReconciler) applySkipDryRun(ctx context.Context,
+	kubeClient client.Client,
+	objects []*unstructured.Unstructured,
+	opts ssa.ApplyOptions) (*ssa.ChangeSet, error) {
+	changeSet := ssa.NewChangeSet()
+	for _, u := range objects {
+		action, err := r.applySkipDryRunObject(ctx, kubeClient, u, opts)
+		if err != nil {
+			return changeSet, err
+		}
+		changeSet.Add(ssa.ChangeSetEntry{
+			ObjMetadata:  object.UnstructuredToObjMetadata(u),
+			GroupVersion: u.GroupVersionKind().Version,
+			Subject:      ssautil.FmtUnstructured(u),
+			Action:       action,
+		})
+	}
+	return changeSet, nil
+}
+
+// applySkipDryRunObject applies the object and returns the action taken.
+func (r *KustomizationReconciler) applySkipDryRunObject(ctx context.Context,
+	kubeClient client.Client,
+	u *unstructured.Unstructured,
+	opts ssa.ApplyOptions) (ssa.Action, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(u.GroupVersionKind())
+	err := kubeClient.Get(ctx, client.ObjectKeyFromObject(u), existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("%s query failed: %w", ssautil.FmtUnstructured(u), err)
+	}
+	found := err == nil
+
+	if ssautil.AnyInMetadata(u, opts.ExclusionSelector) ||
+		(found && ssautil.AnyInMetadata(existing, opts.ExclusionSelector)) ||
+		(found && ssautil.AnyInMetadata(u, opts.IfNotPresentSelector)) {
+		return ssa.SkippedAction, nil
+	}
+
+	applied := u.DeepCopy()
+	if err := kubeClient.Patch(ctx, applied, client.Apply,
+		client.ForceOwnership, client.FieldOwner(r.ControllerName)); err != nil {
+		return "", fmt.Errorf("%s apply failed: %w", ssautil.FmtUnstructured(u), err)
+	}
+
+	switch {
+	case !found:
+		return ssa.CreatedAction, nil
+	case equalIgnoringVersion(existing, applied):
+		return ssa.UnchangedAction, nil
+	default:
+		return ssa.ConfiguredAction, nil
+	}
+}
+
+// equalIgnoringVersion returns true if the objects are equal, except for
+// their resource version and managed fields.
+func equalIgnoringVersion(a, b *unstructured.Unstructured) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	for _, u := range []*unstructured.Unstructured{a, b} {
+		u.SetResourceVersion("")
+		u.SetManagedFields(nil)
+	}
+	return apiequality.Semantic.DeepEqual(a.Object, b.Object)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/ssa"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestApplySkipDryRun(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	r := &KustomizationReconciler{ControllerName: "kustomize-controller"}
+	kubeClient := fake.NewClientBuilder().Build()
+	opts := ssa.DefaultApplyOptions()
+	opts.ExclusionSelector = map[string]string{
+		"kustomize.toolkit.fluxcd.io/reconcile": kustomizev1.DisabledValue,
+	}
+
+	newObject := func(data map[string]any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":      "metrics-adapter",
+				"namespace": "apps",
+				"annotations": map[string]any{
+					kustomizev1.DryRunAnnotation: kustomizev1.DisabledValue,
+				},
+			},
+			"data": data,
+		}}
+	}
+
+	dryRun, skipDryRun := splitSkipDryRun([]*unstructured.Unstructured{
+		newObject(nil),
+		{Object: map[string]any{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]any{"name": "apps"}}},
+	})
+	g.Expect(dryRun).To(HaveLen(1))
+	g.Expect(skipDryRun).To(HaveLen(1))
+
+	changeSet, err := r.applySkipDryRun(ctx, kubeClient,
+		[]*unstructured.Unstructured{newObject(map[string]any{"a": "1"})}, opts)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changeSet.Entries).To(HaveLen(1))
+	g.Expect(changeSet.Entries[0].Action).To(Equal(ssa.CreatedAction))
+	g.Expect(changeSet.Entries[0].Subject).To(Equal("ConfigMap/apps/metrics-adapter"))
+
+	changeSet, err = r.applySkipDryRun(ctx, kubeClient,
+		[]*unstructured.Unstructured{newObject(map[string]any{"a": "2"})}, opts)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changeSet.Entries[0].Action).To(Equal(ssa.ConfiguredAction))
+
+	cm := &corev1.ConfigMap{}
+	g.Expect(kubeClient.Get(ctx, client.ObjectKey{Namespace: "apps", Name: "metrics-adapter"}, cm)).To(Succeed())
+	g.Expect(cm.Data).To(Equal(map[string]string{"a": "2"}))
+
+	changeSet, err = r.applySkipDryRun(ctx, kubeClient,
+		[]*unstructured.Unstructured{newObject(map[string]any{"a": "2"})}, opts)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changeSet.Entries[0].Action).To(Equal(ssa.UnchangedAction))
+
+	excluded := newObject(map[string]any{"a": "3"})
+	excluded.SetLabels(map[string]string{"kustomize.toolkit.fluxcd.io/reconcile": kustomizev1.DisabledValue})
+	changeSet, err = r.applySkipDryRun(ctx, kubeClient, []*unstructured.Unstructured{excluded}, opts)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changeSet.Entries[0].Action).To(Equal(ssa.SkippedAction))
+}
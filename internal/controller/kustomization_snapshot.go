@@ -0,0 +1,425 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/opencontainers/go-digest"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+// snapshotSecretKey is the data key of the snapshot Secret
+// holding the gzipped multi-doc YAML of the objects.
+const snapshotSecretKey = "snapshot.yaml.gz"
+
+// snapshotDataAnnotation is the annotation set on the Secrets of a snapshot
+// to record whether their data is encrypted or redacted.
+const snapshotDataAnnotation = "kustomize.toolkit.fluxcd.io/snapshot-data"
+
+const (
+	snapshotDataEncrypted = "encrypted"
+	snapshotDataRedacted  = "redacted"
+)
+
+const (
+	// maxManifestsSize is the max size in bytes of the decompressed
+	// manifests of the snapshot and export Secrets.
+	maxManifestsSize int64 = 64 << 20
+
+	// maxSecretDataSize is the max size in bytes of a decrypted value
+	// of the data of a Secret, which is bounded by the Secret size limit.
+	maxSecretDataSize int64 = corev1.MaxSecretSize
+)
+
+// snapshotSecretName returns the name of the Secret holding
+// the snapshot of the objects of the Kustomization.
+func snapshotSecretName(obj *kustomizev1.Kustomization) string {
+	return fmt.Sprintf("%s-snapshot", obj.GetName())
+}
+
+// reconcileSnapshot exports the live state of the objects of the inventory
+// to the snapshot Secret, without applying the manifests. The data of the
+// Secrets is encrypted with the age recipient of the
+// SnapshotAgeRecipientAnnotation, or redacted if not set.
+func (r *KustomizationReconciler) reconcileSnapshot(ctx context.Context,
+	kubeClient client.Client,
+	obj *kustomizev1.Kustomization,
+	oldInventory *kustomizev1.ResourceInventory) error {
+	log := ctrl.LoggerFrom(ctx)
+	revision := obj.Status.LastAppliedRevision
+	originRevision := obj.Status.LastAppliedOriginRevision
+
+	var recipient age.Recipient
+	if v := obj.GetAnnotations()[kustomizev1.SnapshotAgeRecipientAnnotation]; v != "" {
+		var err error
+		recipient, err = age.ParseX25519Recipient(strings.TrimSpace(v))
+		if err != nil {
+			err = fmt.Errorf("invalid snapshot age recipient: %w", err)
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+			return err
+		}
+	}
+
+	entries, err := inventory.List(oldInventory)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, entry := range entries {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(entry.GroupVersionKind())
+		if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(entry), live); err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Info("skipping the snapshot of a missing object", "object", ssautil.FmtUnstructured(entry))
+				continue
+			}
+			err = fmt.Errorf("failed to read %s: %w", ssautil.FmtUnstructured(entry), err)
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+			return err
+		}
+		if err := snapshotObject(live, recipient); err != nil {
+			err = fmt.Errorf("failed to snapshot %s: %w", ssautil.FmtUnstructured(entry), err)
+			conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+			return err
+		}
+		objects = append(objects, live)
+	}
+
+	manifests, err := ssautil.ObjectsToYAML(objects)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(manifests)); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if buf.Len() > corev1.MaxSecretSize {
+		err := fmt.Errorf("snapshot of %d objects is %d bytes compressed, which exceeds the %d bytes limit of Secrets",
+			len(objects), buf.Len(), corev1.MaxSecretSize)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotSecretName(obj),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.GetResourceVersion() != "" && !isOwnedBy(obj, secret) {
+			return fmt.Errorf("secret '%s' is not owned by the Kustomization", client.ObjectKeyFromObject(secret))
+		}
+		secret.Labels = map[string]string{
+			fmt.Sprintf("%s/name", kustomizev1.GroupVersion.Group):      obj.GetName(),
+			fmt.Sprintf("%s/namespace", kustomizev1.GroupVersion.Group): obj.GetNamespace(),
+		}
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{snapshotSecretKey: buf.Bytes()}
+		return controllerutil.SetOwnerReference(obj, secret, r.Client.Scheme())
+	}); err != nil {
+		err = fmt.Errorf("failed to store snapshot secret: %w", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	snapshot := &kustomizev1.Snapshot{
+		SecretName: secret.GetName(),
+		Revision:   revision,
+		Digest:     digest.FromString(manifests).String(),
+		Objects:    len(objects),
+		Encrypted:  recipient != nil,
+		Time:       metav1.Now(),
+	}
+	obj.Status.LastSnapshot = snapshot
+
+	msg := fmt.Sprintf("Snapshot %s of %d objects stored in Secret '%s'",
+		snapshot.Digest, snapshot.Objects, snapshot.SecretName)
+	log.Info(msg, "revision", revision)
+	r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+	conditions.MarkTrue(obj,
+		meta.ReadyCondition,
+		meta.ReconciliationSucceededReason,
+		"Snapshot taken for revision: %s", revision)
+	return nil
+}
+
+// isOwnedBy returns true if the Secret or ConfigMap generated for the
+// Kustomization is labelled with its name and is owned by it.
+func isOwnedBy(obj *kustomizev1.Kustomization, o client.Object) bool {
+	if o.GetLabels()[fmt.Sprintf("%s/name", kustomizev1.GroupVersion.Group)] != obj.GetName() {
+		return false
+	}
+	for _, ref := range o.GetOwnerReferences() {
+		if ref.UID == obj.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileRestore applies the objects of the snapshot Secret instead of the
+// manifests. The encrypted data of the Secrets is decrypted with the age
+// identities of the decryption Secret, and the redacted Secrets are skipped.
+func (r *KustomizationReconciler) reconcileRestore(ctx context.Context,
+	kubeClient client.Client,
+	obj *kustomizev1.Kustomization) error {
+	log := ctrl.LoggerFrom(ctx)
+	revision := obj.Status.LastAppliedRevision
+	originRevision := obj.Status.LastAppliedOriginRevision
+
+	objects, err := r.readSnapshot(ctx, obj)
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	var identities []age.Identity
+	var restored, redacted []*unstructured.Unstructured
+	for _, u := range objects {
+		switch u.GetAnnotations()[snapshotDataAnnotation] {
+		case snapshotDataRedacted:
+			redacted = append(redacted, u)
+			continue
+		case snapshotDataEncrypted:
+			if identities == nil {
+				if identities, err = r.snapshotIdentities(ctx, obj); err != nil {
+					conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+					return err
+				}
+			}
+			if err := decryptSnapshotData(u, identities); err != nil {
+				err = fmt.Errorf("failed to decrypt %s: %w", ssautil.FmtUnstructured(u), err)
+				conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+				return err
+			}
+		}
+		restored = append(restored, u)
+	}
+	if len(redacted) > 0 {
+		msg := fmt.Sprintf("skipped the restore of the redacted Secrets:\n%s", ssautil.FmtUnstructuredList(redacted))
+		log.Info(msg)
+		r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+	}
+
+	resourceManager := ssa.NewResourceManager(kubeClient, nil, ssa.Owner{
+		Field: r.ControllerName,
+		Group: kustomizev1.GroupVersion.Group,
+	})
+	changeSet, err := resourceManager.ApplyAllStaged(ctx, restored, ssa.DefaultApplyOptions())
+	if err != nil {
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+
+	msg := fmt.Sprintf("Snapshot restored from Secret '%s'", snapshotSecretName(obj))
+	if changeSet != nil && len(changeSet.Entries) > 0 {
+		msg = fmt.Sprintf("%s:\n%s", msg, changeSet.String())
+	}
+	log.Info(msg, "revision", revision)
+	r.event(obj, revision, originRevision, eventv1.EventSeverityInfo, msg, nil)
+	conditions.MarkTrue(obj,
+		meta.ReadyCondition,
+		meta.ReconciliationSucceededReason,
+		"Snapshot restored for revision: %s", revision)
+	return nil
+}
+
+// readSnapshot returns the objects of the snapshot Secret.
+func (r *KustomizationReconciler) readSnapshot(ctx context.Context,
+	obj *kustomizev1.Kustomization) ([]*unstructured.Unstructured, error) {
+	secretName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: snapshotSecretName(obj)}
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretName, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get snapshot secret '%s': %w", secretName, err)
+	}
+	if !isOwnedBy(obj, &secret) {
+		return nil, fmt.Errorf("snapshot secret '%s' is not owned by the Kustomization", secretName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(secret.Data[snapshotSecretKey]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot secret '%s': %w", secretName, err)
+	}
+	defer gz.Close()
+	manifests, err := readAllLimited(gz, maxManifestsSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot secret '%s': %w", secretName, err)
+	}
+	return ssautil.ReadObjects(bytes.NewReader(manifests))
+}
+
+// readAllLimited reads from r until EOF, failing if more
+// than the given limit of bytes is read.
+func readAllLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("data exceeds the %d bytes limit", limit)
+	}
+	return data, nil
+}
+
+// snapshotIdentities returns the age identities of the
+// decryption Secret of the Kustomization.
+func (r *KustomizationReconciler) snapshotIdentities(ctx context.Context,
+	obj *kustomizev1.Kustomization) ([]age.Identity, error) {
+	dec := obj.Spec.Decryption
+	if dec == nil || dec.SecretRef == nil {
+		return nil, fmt.Errorf("the snapshot holds encrypted Secrets, but no decryption Secret is specified")
+	}
+
+	secretName := types.NamespacedName{Namespace: obj.GetNamespace(), Name: dec.SecretRef.Name}
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretName, &secret); err != nil {
+		return nil, fmt.Errorf("cannot get decryption Secret '%s': %w", secretName, err)
+	}
+
+	var identities []age.Identity
+	for name, value := range secret.Data {
+		if filepath.Ext(name) != decryptor.DecryptionAgeExt {
+			continue
+		}
+		ids, err := age.ParseIdentities(bytes.NewReader(value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import '%s' data from decryption Secret '%s': %w", name, secretName, err)
+		}
+		identities = append(identities, ids...)
+	}
+	if len(identities) == 0 {
+		return nil, fmt.Errorf("decryption Secret '%s' holds no age identity", secretName)
+	}
+	return identities, nil
+}
+
+// snapshotObject removes from the live object the fields set by the API
+// server and the last applied configuration of kubectl, which holds the
+// data of Secrets in plain text, and encrypts the data of Secrets for the
+// given recipient, or redacts it if the recipient is nil.
+func snapshotObject(u *unstructured.Unstructured, recipient age.Recipient) error {
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation",
+		"creationTimestamp", "selfLink", "ownerReferences"} {
+		unstructured.RemoveNestedField(u.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(u.Object, "metadata", "annotations", corev1.LastAppliedConfigAnnotation)
+	unstructured.RemoveNestedField(u.Object, "status")
+
+	if u.GetAPIVersion() != "v1" || u.GetKind() != "Secret" {
+		return nil
+	}
+
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if recipient == nil {
+		unstructured.RemoveNestedField(u.Object, "data")
+		annotations[snapshotDataAnnotation] = snapshotDataRedacted
+		u.SetAnnotations(annotations)
+		return nil
+	}
+
+	data, _, err := unstructured.NestedStringMap(u.Object, "data")
+	if err != nil {
+		return err
+	}
+	for k, v := range data {
+		plain, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		aw := armor.NewWriter(&buf)
+		w, err := age.Encrypt(aw, recipient)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(plain); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		if err := aw.Close(); err != nil {
+			return err
+		}
+		data[k] = buf.String()
+	}
+	annotations[snapshotDataAnnotation] = snapshotDataEncrypted
+	u.SetAnnotations(annotations)
+	return unstructured.SetNestedStringMap(u.Object, data, "data")
+}
+
+// decryptSnapshotData decrypts the data of the given
+// snapshot Secret with the given age identities.
+func decryptSnapshotData(u *unstructured.Unstructured, identities []age.Identity) error {
+	data, _, err := unstructured.NestedStringMap(u.Object, "data")
+	if err != nil {
+		return err
+	}
+	for k, v := range data {
+		rd, err := age.Decrypt(armor.NewReader(strings.NewReader(v)), identities...)
+		if err != nil {
+			return err
+		}
+		plain, err := readAllLimited(rd, maxSecretDataSize)
+		if err != nil {
+			return err
+		}
+		data[k] = base64.StdEncoding.EncodeToString(plain)
+	}
+	annotations := u.GetAnnotations()
+	delete(annotations, snapshotDataAnnotation)
+	u.SetAnnotations(annotations)
+	return unstructured.SetNestedStringMap(u.Object, data, "data")
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"filippo.io/age"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestReconcileSnapshot(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	identity, err := age.GenerateX25519Identity()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Data:       map[string]string{"key": "value"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "apps",
+			Annotations: map[string]string{
+				corev1.LastAppliedConfigAnnotation: `{"data":{"password":"czNjcjN0"}}`,
+			},
+		},
+		Data: map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	decryption := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sops-age", Namespace: "flux-system"},
+		Data:       map[string][]byte{"identity.agekey": []byte(identity.String())},
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "flux-system",
+			UID:       "uid",
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{
+				Provider:  "sops",
+				SecretRef: &meta.LocalObjectReference{Name: "sops-age"},
+			},
+		},
+		Status: kustomizev1.KustomizationStatus{
+			LastAppliedRevision: "main@sha1:abc",
+			Inventory: &kustomizev1.ResourceInventory{
+				Entries: []kustomizev1.ResourceRef{
+					{ID: "apps_app__ConfigMap", Version: "v1"},
+					{ID: "apps_app__Secret", Version: "v1"},
+					{ID: "apps_missing__ConfigMap", Version: "v1"},
+				},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(obj, configMap, secret, decryption).Build()
+	r := &KustomizationReconciler{
+		Client:         kubeClient,
+		ControllerName: "kustomize-controller",
+		EventRecorder:  record.NewFakeRecorder(32),
+	}
+
+	// Without a recipient, the data of the Secrets is redacted.
+	g.Expect(r.reconcileSnapshot(ctx, kubeClient, obj, obj.Status.Inventory)).To(Succeed())
+	g.Expect(conditions.IsReady(obj)).To(BeTrue())
+	g.Expect(obj.Status.LastSnapshot).ToNot(BeNil())
+	g.Expect(obj.Status.LastSnapshot.SecretName).To(Equal("app-snapshot"))
+	g.Expect(obj.Status.LastSnapshot.Objects).To(Equal(2))
+	g.Expect(obj.Status.LastSnapshot.Encrypted).To(BeFalse())
+
+	objects, err := r.readSnapshot(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+	g.Expect(objects[0].GetResourceVersion()).To(BeEmpty())
+	g.Expect(objects[1].GetAnnotations()).To(HaveKeyWithValue(snapshotDataAnnotation, snapshotDataRedacted))
+	g.Expect(objects[1].GetAnnotations()).ToNot(HaveKey(corev1.LastAppliedConfigAnnotation))
+	_, found, _ := unstructured.NestedMap(objects[1].Object, "data")
+	g.Expect(found).To(BeFalse())
+
+	// With a recipient, the data of the Secrets is encrypted.
+	obj.SetAnnotations(map[string]string{
+		kustomizev1.SnapshotAgeRecipientAnnotation: identity.Recipient().String(),
+	})
+	g.Expect(r.reconcileSnapshot(ctx, kubeClient, obj, obj.Status.Inventory)).To(Succeed())
+	g.Expect(obj.Status.LastSnapshot.Encrypted).To(BeTrue())
+
+	objects, err = r.readSnapshot(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	encrypted := objects[1]
+	g.Expect(encrypted.GetAnnotations()).To(HaveKeyWithValue(snapshotDataAnnotation, snapshotDataEncrypted))
+	ciphertext, _, _ := unstructured.NestedString(encrypted.Object, "data", "password")
+	g.Expect(ciphertext).To(ContainSubstring("BEGIN AGE ENCRYPTED FILE"))
+
+	identities, err := r.snapshotIdentities(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(decryptSnapshotData(encrypted, identities)).To(Succeed())
+	g.Expect(encrypted.GetAnnotations()).ToNot(HaveKey(snapshotDataAnnotation))
+	password, _, _ := unstructured.NestedString(encrypted.Object, "data", "password")
+	g.Expect(password).To(Equal(base64.StdEncoding.EncodeToString([]byte("s3cr3t"))))
+
+	// An invalid recipient fails the snapshot.
+	obj.SetAnnotations(map[string]string{
+		kustomizev1.SnapshotAgeRecipientAnnotation: "invalid",
+	})
+	g.Expect(r.reconcileSnapshot(ctx, kubeClient, obj, obj.Status.Inventory)).ToNot(Succeed())
+	g.Expect(conditions.IsReady(obj)).To(BeFalse())
+}
+
+func TestReconcileSnapshot_SecretLimits(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	large := make([]byte, 2*corev1.MaxSecretSize)
+	_, err := rand.Read(large)
+	g.Expect(err).ToNot(HaveOccurred())
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		BinaryData: map[string][]byte{"blob": large},
+	}
+	foreign := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-snapshot", Namespace: "flux-system"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "flux-system", UID: "uid"},
+		Status: kustomizev1.KustomizationStatus{
+			Inventory: &kustomizev1.ResourceInventory{
+				Entries: []kustomizev1.ResourceRef{{ID: "apps_app__ConfigMap", Version: "v1"}},
+			},
+		},
+	}
+	other := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "flux-system", UID: "other-uid"},
+		Status: kustomizev1.KustomizationStatus{
+			Inventory: &kustomizev1.ResourceInventory{},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(obj, other, configMap, foreign).Build()
+	r := &KustomizationReconciler{
+		Client:         kubeClient,
+		ControllerName: "kustomize-controller",
+		EventRecorder:  record.NewFakeRecorder(32),
+	}
+
+	// A snapshot above the size limit of Secrets is not stored.
+	err = r.reconcileSnapshot(ctx, kubeClient, obj, obj.Status.Inventory)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("exceeds the 1048576 bytes limit of Secrets"))
+	g.Expect(conditions.IsReady(obj)).To(BeFalse())
+
+	// A Secret not owned by the Kustomization is left unchanged.
+	err = r.reconcileSnapshot(ctx, kubeClient, other, other.Status.Inventory)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not owned by the Kustomization"))
+	g.Expect(kubeClient.Get(ctx, client.ObjectKeyFromObject(foreign), foreign)).To(Succeed())
+	g.Expect(foreign.Data).To(HaveKey("token"))
+
+	_, err = r.readSnapshot(ctx, other)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not owned by the Kustomization"))
+
+	// A snapshot decompressing above the limit is not read.
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err = gz.Write(make([]byte, maxManifestsSize+1))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gz.Close()).To(Succeed())
+	bomb := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            snapshotSecretName(obj),
+			Namespace:       obj.GetNamespace(),
+			Labels:          map[string]string{kustomizev1.GroupVersion.Group + "/name": obj.GetName()},
+			OwnerReferences: []metav1.OwnerReference{{Name: obj.GetName(), UID: obj.GetUID()}},
+		},
+		Data: map[string][]byte{snapshotSecretKey: buf.Bytes()},
+	}
+	g.Expect(kubeClient.Create(ctx, bomb)).To(Succeed())
+	_, err = r.readSnapshot(ctx, obj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("exceeds the 67108864 bytes limit"))
+
+	// A Secret value decrypting above the limit is not read.
+	identity, err := age.GenerateX25519Identity()
+	g.Expect(err).ToNot(HaveOccurred())
+	encrypted := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": "Secret"}}
+	g.Expect(unstructured.SetNestedStringMap(encrypted.Object, map[string]string{
+		"blob": base64.StdEncoding.EncodeToString(make([]byte, maxSecretDataSize+1)),
+	}, "data")).To(Succeed())
+	g.Expect(snapshotObject(encrypted, identity.Recipient())).To(Succeed())
+	err = decryptSnapshotData(encrypted, []age.Identity{identity})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("exceeds the 1048576 bytes limit"))
+}
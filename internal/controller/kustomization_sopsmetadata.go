@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	"github.com/fluxcd/pkg/ssa"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+// sopsMetadataKey is the event metadata key holding the SOPS metadata
+// of the decrypted objects changed by the apply.
+var sopsMetadataKey = kustomizev1.GroupVersion.Group + "/sops"
+
+// sopsEventMetadata returns the event metadata with the SOPS metadata of the
+// decrypted objects changed by the apply, one '<subject> lastmodified=<time>
+// keys=<keys>' line per object, or nil if no decrypted object changed.
+func sopsEventMetadata(changeSet *ssa.ChangeSet,
+	sopsMetadata map[string]decryptor.SOPSMetadata) map[string]string {
+	if changeSet == nil || len(sopsMetadata) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, entry := range changeSet.Entries {
+		if !HasChanged(entry.Action) {
+			continue
+		}
+		if m, ok := sopsMetadata[entry.ObjMetadata.String()]; ok {
+			lines = append(lines, entry.Subject+" "+m.String())
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return map[string]string{sopsMetadataKey: strings.Join(lines, "\n")}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+func TestSOPSEventMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := func(name string, action ssa.Action) ssa.ChangeSetEntry {
+		return ssa.ChangeSetEntry{
+			ObjMetadata: object.ObjMetadata{
+				Namespace: "apps",
+				Name:      name,
+				GroupKind: schema.GroupKind{Kind: "Secret"},
+			},
+			Subject: "Secret/apps/" + name,
+			Action:  action,
+		}
+	}
+	changeSet := ssa.NewChangeSet()
+	changeSet.Add(secret("created", ssa.CreatedAction))
+	changeSet.Add(secret("unchanged", ssa.UnchangedAction))
+	changeSet.Add(secret("plain", ssa.ConfiguredAction))
+
+	lastModified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sopsMetadata := map[string]decryptor.SOPSMetadata{
+		"apps_created__Secret":   {LastModified: lastModified, Keys: []string{"age:age1abc", "pgp:FBC7B9E2"}},
+		"apps_unchanged__Secret": {LastModified: lastModified, Keys: []string{"age:age1abc"}},
+	}
+
+	g.Expect(sopsEventMetadata(changeSet, sopsMetadata)).To(Equal(map[string]string{
+		"kustomize.toolkit.fluxcd.io/sops": "Secret/apps/created lastmodified=2026-01-02T03:04:05Z keys=age:age1abc,pgp:FBC7B9E2",
+	}))
+	g.Expect(sopsEventMetadata(changeSet, nil)).To(BeNil())
+	g.Expect(sopsEventMetadata(nil, sopsMetadata)).To(BeNil())
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/patch"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+// reconcileModeApplySuspended is the mode of the reconciliations of the
+// Kustomizations suspended with the 'ApplyOnly' suspend mode. It can't
+// be requested with the ReconcileModeAnnotation.
+const reconcileModeApplySuspended = "apply-suspended"
+
+// reconcileApplySuspended reports the drift of the managed objects from the
+// manifests of the revision in the DriftDetected condition, and runs the
+// health checks of the last applied objects, without applying the manifests
+// or deleting the stale objects.
+func (r *KustomizationReconciler) reconcileApplySuspended(ctx context.Context,
+	manager *ssa.ResourceManager,
+	kubeClient client.Client,
+	statusPoller *polling.StatusPoller,
+	patcher *patch.SerialPatcher,
+	obj *kustomizev1.Kustomization,
+	revision string,
+	oldInventory *kustomizev1.ResourceInventory,
+	objects []*unstructured.Unstructured) error {
+	drift, err := r.detectDrift(ctx, manager, obj, oldInventory, objects)
+	if err != nil {
+		err = fmt.Errorf("failed to detect the drift: %w", err)
+		conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.ReconciliationFailedReason, "%s", err)
+		return err
+	}
+	if diff := drift.Diff(); diff != "" {
+		conditions.MarkTrue(obj, kustomizev1.DriftDetectedCondition, kustomizev1.ApplySuspendedReason,
+			"Drift detected from revision %s: %s\n%s", revision, drift.String(), diff)
+	} else {
+		conditions.Delete(obj, kustomizev1.DriftDetectedCondition)
+	}
+	ctrl.LoggerFrom(ctx).Info("apply is suspended, drift detected from revision: "+drift.String(),
+		"revision", revision)
+
+	if err := r.reconcileHealthOnly(ctx, kubeClient, statusPoller, patcher, obj, oldInventory); err != nil {
+		return err
+	}
+	conditions.MarkTrue(obj,
+		meta.ReadyCondition,
+		kustomizev1.ApplySuspendedReason,
+		"Apply suspended, health check passed for revision: %s", obj.Status.LastAppliedRevision)
+	return nil
+}
+
+// detectDrift dry-runs the apply of the objects with the options of the apply,
+// and returns the objects which would be created or configured, and the stale
+// objects of the inventory which would be deleted.
+func (r *KustomizationReconciler) detectDrift(ctx context.Context,
+	manager *ssa.ResourceManager,
+	obj *kustomizev1.Kustomization,
+	oldInventory *kustomizev1.ResourceInventory,
+	objects []*unstructured.Unstructured) (compareResult, error) {
+	var result compareResult
+	if err := r.prepareObjects(obj, objects); err != nil {
+		return result, err
+	}
+
+	diffOpts := ssa.DefaultDiffOptions()
+	diffOpts.Force = obj.Spec.Force
+	diffOpts.Exclusions = map[string]string{
+		fmt.Sprintf("%s/reconcile", kustomizev1.GroupVersion.Group): kustomizev1.DisabledValue,
+		fmt.Sprintf("%s/ssa", kustomizev1.GroupVersion.Group):       kustomizev1.IgnoreValue,
+	}
+	diffOpts.IfNotPresentSelector = map[string]string{
+		fmt.Sprintf("%s/ssa", kustomizev1.GroupVersion.Group): kustomizev1.IfNotPresentValue,
+	}
+	diffOpts.ForceSelector = map[string]string{
+		fmt.Sprintf("%s/force", kustomizev1.GroupVersion.Group): kustomizev1.EnabledValue,
+	}
+	diffOpts.DriftIgnoreRules = driftIgnoreRules(obj)
+	if r.SkipHPAReplicasDrift {
+		hpaRules, _ := r.hpaDriftIgnoreRules(ctx, manager.Client(), objects)
+		diffOpts.DriftIgnoreRules = append(diffOpts.DriftIgnoreRules, hpaRules...)
+	}
+
+	for _, u := range objects {
+		entry, _, _, err := manager.Diff(ctx, u, diffOpts)
+		switch {
+		case apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err):
+			// The namespace or the definition of the object is not applied yet.
+			result.Added = append(result.Added, ssautil.FmtUnstructured(u))
+		case err != nil:
+			return result, err
+		case entry.Action == ssa.CreatedAction:
+			result.Added = append(result.Added, ssautil.FmtUnstructured(u))
+		case entry.Action == ssa.ConfiguredAction:
+			result.Changed = append(result.Changed, ssautil.FmtUnstructured(u))
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if obj.Spec.Prune {
+		desiredInventory := inventory.New()
+		inventory.Merge(desiredInventory, objects)
+		staleObjects, err := inventory.Diff(oldInventory, desiredInventory)
+		if err != nil {
+			return result, err
+		}
+		for _, u := range retainDelegatedCRDs(obj, staleObjects) {
+			result.Removed = append(result.Removed, ssautil.FmtUnstructured(u))
+		}
+	}
+
+	slices.Sort(result.Added)
+	slices.Sort(result.Changed)
+	slices.Sort(result.Removed)
+	return result, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/testserver"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestKustomizationReconciler_SuspendApplyOnly(t *testing.T) {
+	g := NewWithT(t)
+	id := "suspend-" + randStringRunes(5)
+	revision := "v1.0.0"
+
+	err := createNamespace(id)
+	g.Expect(err).NotTo(HaveOccurred(), "failed to create test namespace")
+
+	manifests := func(names ...string) []testserver.File {
+		var files []testserver.File
+		for _, name := range names {
+			files = append(files, testserver.File{
+				Name: name + ".yaml",
+				Body: fmt.Sprintf(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %[1]s
+data:
+  key: "%[1]s"
+`, name),
+			})
+		}
+		return files
+	}
+
+	artifact, err := testServer.ArtifactFromFiles(manifests("first", "second"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	repositoryName := types.NamespacedName{
+		Name:      fmt.Sprintf("suspend-%s", randStringRunes(5)),
+		Namespace: id,
+	}
+
+	err = applyGitRepository(repositoryName, artifact, revision)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	kustomization := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("suspend-%s", randStringRunes(5)),
+			Namespace: id,
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: time.Hour},
+			Path:     "./",
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{
+				Name:      repositoryName.Name,
+				Namespace: repositoryName.Namespace,
+				Kind:      sourcev1.GitRepositoryKind,
+			},
+			TargetNamespace: id,
+			Prune:           true,
+		},
+	}
+
+	g.Expect(k8sClient.Create(context.Background(), kustomization)).To(Succeed())
+
+	resultK := &kustomizev1.Kustomization{}
+	g.Eventually(func() bool {
+		_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+		return resultK.Status.LastAppliedRevision == revision
+	}, timeout, time.Second).Should(BeTrue())
+
+	t.Run("reports the drift without applying", func(t *testing.T) {
+		g := NewWithT(t)
+
+		first := &corev1.ConfigMap{}
+		g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "first", Namespace: id}, first)).To(Succeed())
+		cmPatch := client.MergeFrom(first.DeepCopy())
+		first.Data["key"] = "drifted"
+		g.Expect(k8sClient.Patch(context.Background(), first, cmPatch)).To(Succeed())
+
+		artifact, err := testServer.ArtifactFromFiles(manifests("first", "third"))
+		g.Expect(err).NotTo(HaveOccurred())
+		err = applyGitRepository(repositoryName, artifact, "v2.0.0")
+		g.Expect(err).NotTo(HaveOccurred())
+
+		requestedAt := time.Now().Format(time.RFC3339Nano)
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch := client.MergeFrom(resultK.DeepCopy())
+		resultK.Spec.Suspend = true
+		resultK.Spec.SuspendMode = kustomizev1.SuspendModeApplyOnly
+		resultK.SetAnnotations(map[string]string{
+			meta.ReconcileRequestAnnotation: requestedAt,
+		})
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return resultK.Status.LastHandledReconcileAt == requestedAt
+		}, timeout, time.Second).Should(BeTrue())
+
+		ready := apimeta.FindStatusCondition(resultK.Status.Conditions, meta.ReadyCondition)
+		g.Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+		g.Expect(ready.Reason).To(Equal(kustomizev1.ApplySuspendedReason))
+		g.Expect(resultK.Status.LastAppliedRevision).To(Equal(revision))
+
+		drift := apimeta.FindStatusCondition(resultK.Status.Conditions, kustomizev1.DriftDetectedCondition)
+		g.Expect(drift).ToNot(BeNil())
+		g.Expect(drift.Message).To(ContainSubstring("1 added, 1 changed, 1 removed, 0 unchanged"))
+		g.Expect(drift.Message).To(ContainSubstring(fmt.Sprintf("+ ConfigMap/%s/third", id)))
+		g.Expect(drift.Message).To(ContainSubstring(fmt.Sprintf("~ ConfigMap/%s/first", id)))
+		g.Expect(drift.Message).To(ContainSubstring(fmt.Sprintf("- ConfigMap/%s/second", id)))
+
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Name: "third", Namespace: id}, &corev1.ConfigMap{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "second", Namespace: id}, &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	t.Run("applies the revision when resumed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch := client.MergeFrom(resultK.DeepCopy())
+		resultK.Spec.Suspend = false
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return resultK.Status.LastAppliedRevision == "v2.0.0"
+		}, timeout, time.Second).Should(BeTrue())
+
+		g.Expect(apimeta.FindStatusCondition(resultK.Status.Conditions, kustomizev1.DriftDetectedCondition)).To(BeNil())
+		g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "third", Namespace: id}, &corev1.ConfigMap{})).To(Succeed())
+	})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	runtimeCtrl "github.com/fluxcd/pkg/runtime/controller"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// guardrailKinds are the kinds of the objects allowed in a tenancy profile.
+var guardrailKinds = []schema.GroupKind{
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"},
+	{Group: "", Kind: "ResourceQuota"},
+	{Group: "", Kind: "LimitRange"},
+}
+
+// generateGuardrails adds to the built resources the guardrail objects of
+// the tenancy profile of the Kustomization, read from the tenancy profiles
+// ConfigMap and placed in the target namespace. The manifests are returned
+// re-encoded, or unchanged if no tenancy profile is requested.
+func (r *KustomizationReconciler) generateGuardrails(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	resources []byte) ([]byte, error) {
+	t := obj.Spec.Tenancy
+	if t == nil {
+		return resources, nil
+	}
+	if obj.Spec.TargetNamespace == "" {
+		return nil, fmt.Errorf("the tenancy profile requires the target namespace to be set")
+	}
+
+	name, ns := r.TenancyProfiles, os.Getenv(runtimeCtrl.EnvRuntimeNamespace)
+	if name == "" || ns == "" {
+		return nil, fmt.Errorf("tenancy profile '%s' requested but the controller flag --tenancy-profiles is not set", t.Profile)
+	}
+	cm := &corev1.ConfigMap{}
+	cmName := types.NamespacedName{Namespace: ns, Name: name}
+	if err := r.Get(ctx, cmName, cm); err != nil {
+		return nil, fmt.Errorf("failed to get tenancy profiles ConfigMap '%s': %w", cmName, err)
+	}
+	profile, ok := cm.Data[t.Profile]
+	if !ok {
+		return nil, fmt.Errorf("tenancy profile '%s' not found in ConfigMap '%s'", t.Profile, cmName)
+	}
+
+	guardrails, err := ssautil.ReadObjects(strings.NewReader(profile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenancy profile '%s': %w", t.Profile, err)
+	}
+	namespace := obj.Spec.TargetNamespace
+	for _, g := range guardrails {
+		if !isGuardrailKind(g) {
+			return nil, fmt.Errorf("tenancy profile '%s' contains %s, only NetworkPolicy, ResourceQuota and LimitRange objects are allowed",
+				t.Profile, ssautil.FmtUnstructured(g))
+		}
+		g.SetNamespace(namespace)
+	}
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader(resources))
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range guardrails {
+		for _, u := range objects {
+			if u.GroupVersionKind().GroupKind() == g.GroupVersionKind().GroupKind() &&
+				u.GetNamespace() == namespace && u.GetName() == g.GetName() {
+				return nil, fmt.Errorf("guardrail %s of tenancy profile '%s' conflicts with an object of the build output",
+					ssautil.FmtUnstructured(g), t.Profile)
+			}
+		}
+	}
+	objects = append(objects, guardrails...)
+
+	manifests, err := ssautil.ObjectsToYAML(objects)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(manifests), nil
+}
+
+// isGuardrailKind returns true if the object is of one of the guardrailKinds.
+func isGuardrailKind(u *unstructured.Unstructured) bool {
+	gk := u.GroupVersionKind().GroupKind()
+	for _, k := range guardrailKinds {
+		if gk == k {
+			return true
+		}
+	}
+	return false
+}
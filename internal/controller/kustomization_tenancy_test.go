@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestGenerateGuardrails(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	t.Setenv("RUNTIME_NAMESPACE", "flux-system")
+
+	profiles := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenancy-profiles", Namespace: "flux-system"},
+		Data: map[string]string{
+			"restricted": `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: default-deny
+spec:
+  podSelector: {}
+  policyTypes: [Ingress, Egress]
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+spec:
+  hard:
+    pods: "20"
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: limits
+  namespace: ignored
+spec:
+  limits:
+  - type: Container
+    default:
+      memory: 256Mi
+`,
+			"privileged": `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: admin
+`,
+		},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(profiles).Build(),
+	}
+
+	resources := []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: tenant
+`)
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "flux-system"},
+	}
+
+	// Without a tenancy profile the resources are left untouched.
+	out, err := r.generateGuardrails(ctx, obj, resources)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(out).To(Equal(resources))
+
+	obj.Spec.Tenancy = &kustomizev1.Tenancy{Profile: "restricted"}
+	_, err = r.generateGuardrails(ctx, obj, resources)
+	g.Expect(err).To(MatchError(ContainSubstring("requires the target namespace")))
+
+	obj.Spec.TargetNamespace = "tenant"
+	_, err = r.generateGuardrails(ctx, obj, resources)
+	g.Expect(err).To(MatchError(ContainSubstring("--tenancy-profiles is not set")))
+
+	r.TenancyProfiles = "tenancy-profiles"
+	out, err = r.generateGuardrails(ctx, obj, resources)
+	g.Expect(err).ToNot(HaveOccurred())
+	objects, err := ssautil.ReadObjects(bytes.NewReader(out))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(4))
+	var ids []string
+	for _, o := range objects {
+		ids = append(ids, ssautil.FmtUnstructured(o))
+	}
+	g.Expect(ids).To(ConsistOf(
+		"ConfigMap/tenant/app",
+		"NetworkPolicy/tenant/default-deny",
+		"ResourceQuota/tenant/quota",
+		"LimitRange/tenant/limits",
+	))
+
+	// The guardrails must not conflict with the build output.
+	conflicting := append(resources, []byte(`---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+  namespace: tenant
+`)...)
+	_, err = r.generateGuardrails(ctx, obj, conflicting)
+	g.Expect(err).To(MatchError(ContainSubstring("conflicts with an object of the build output")))
+
+	// Only the guardrail kinds are allowed in a profile.
+	obj.Spec.Tenancy.Profile = "privileged"
+	_, err = r.generateGuardrails(ctx, obj, resources)
+	g.Expect(err).To(MatchError(ContainSubstring("only NetworkPolicy, ResourceQuota and LimitRange objects are allowed")))
+
+	obj.Spec.Tenancy.Profile = "unknown"
+	_, err = r.generateGuardrails(ctx, obj, resources)
+	g.Expect(err).To(MatchError("tenancy profile 'unknown' not found in ConfigMap 'flux-system/tenancy-profiles'"))
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+// reconcileExpiration deletes the objects of the inventory once the TTL of
+// the Kustomization expired, marks it as expired and, depending on the
+// expiration policy, deletes the Kustomization. It returns true if the
+// Kustomization expired, in which case the reconciliation must stop.
+// If the TTL is extended after the expiration, the Expired condition is
+// removed and the reconciliation resumes.
+func (r *KustomizationReconciler) reconcileExpiration(ctx context.Context,
+	obj *kustomizev1.Kustomization) (bool, error) {
+	expiration := obj.GetExpirationTime()
+	if expiration.IsZero() || time.Now().Before(expiration) {
+		conditions.Delete(obj, kustomizev1.ExpiredCondition)
+		return false, nil
+	}
+	if conditions.IsTrue(obj, kustomizev1.ExpiredCondition) {
+		return true, nil
+	}
+
+	r.deleteCachedBuild(obj)
+	r.deleteMutationTracker(obj)
+	r.deleteDependencyWait(obj)
+	r.deleteHPAReplicasDrift(obj)
+	r.unwatchInventory(ctx, obj)
+	inv, err := r.getInventory(ctx, obj)
+	if err != nil {
+		return true, err
+	}
+	if objects, _ := inventory.List(inv); len(objects) > 0 {
+		if err := r.deleteInventoryObjects(ctx, obj, objects); err != nil {
+			return true, err
+		}
+	}
+	if err := r.setInventory(ctx, obj, inventory.New()); err != nil {
+		return true, err
+	}
+
+	msg := fmt.Sprintf("TTL of %s expired at %s, the objects of the inventory were deleted",
+		obj.Spec.TTL.Duration.String(), expiration.UTC().Format(time.RFC3339))
+	conditions.MarkTrue(obj, kustomizev1.ExpiredCondition, kustomizev1.TTLExpiredReason, "%s", msg)
+	conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.TTLExpiredReason, "%s", msg)
+	conditions.Delete(obj, meta.ReconcilingCondition)
+	obj.Status.ObservedGeneration = obj.Generation
+	ctrl.LoggerFrom(ctx).Info(msg)
+	r.event(obj, obj.Status.LastAppliedRevision, obj.Status.LastAppliedOriginRevision, eventv1.EventSeverityInfo, msg, nil)
+
+	if obj.GetExpirationPolicy() == kustomizev1.ExpirationPolicyDelete {
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return true, fmt.Errorf("failed to delete the expired Kustomization: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// requeueAtExpiration returns the given result with the requeue delay
+// shortened to the expiration of the TTL, if it expires earlier.
+func requeueAtExpiration(obj *kustomizev1.Kustomization, result ctrl.Result) ctrl.Result {
+	expiration := obj.GetExpirationTime()
+	if expiration.IsZero() || obj.Spec.Suspend ||
+		!obj.GetDeletionTimestamp().IsZero() ||
+		conditions.IsTrue(obj, kustomizev1.ExpiredCondition) {
+		return result
+	}
+	if result.Requeue && result.RequeueAfter == 0 {
+		return result
+	}
+	remaining := max(time.Until(expiration), time.Second)
+	if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
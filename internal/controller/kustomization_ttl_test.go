@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestReconcileExpiration(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "preview",
+			Labels: map[string]string{
+				kustomizev1.GroupVersion.Group + "/name":      "preview",
+				kustomizev1.GroupVersion.Group + "/namespace": "flux-system",
+			},
+		},
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "preview",
+			Namespace:         "flux-system",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			Finalizers:        []string{kustomizev1.KustomizationFinalizer},
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			TTL: &metav1.Duration{Duration: 3 * time.Hour},
+		},
+		Status: kustomizev1.KustomizationStatus{
+			Inventory: &kustomizev1.ResourceInventory{
+				Entries: []kustomizev1.ResourceRef{{ID: "preview_app__ConfigMap", Version: "v1"}},
+			},
+		},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "flux-system"}}
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(kustomizev1.AddToScheme(scheme)).To(Succeed())
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apimeta.RESTScopeNamespace)
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).
+			WithObjects(obj, configMap, namespace).Build(),
+		ControllerName: "kustomize-controller",
+		EventRecorder:  record.NewFakeRecorder(32),
+	}
+
+	// Before the expiration, the reconciliation is requeued at the expiration.
+	expired, err := r.reconcileExpiration(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expired).To(BeFalse())
+	result := requeueAtExpiration(obj, ctrl.Result{RequeueAfter: 10 * time.Hour})
+	g.Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	result = requeueAtExpiration(obj, ctrl.Result{RequeueAfter: 10 * time.Minute})
+	g.Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+
+	// On expiration, the objects of the inventory are deleted.
+	obj.Spec.TTL.Duration = time.Hour
+	expired, err = r.reconcileExpiration(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expired).To(BeTrue())
+	g.Expect(conditions.IsTrue(obj, kustomizev1.ExpiredCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(kustomizev1.TTLExpiredReason))
+	g.Expect(obj.Status.Inventory.Entries).To(BeEmpty())
+	err = r.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(requeueAtExpiration(obj, ctrl.Result{})).To(Equal(ctrl.Result{}))
+
+	// Extending the TTL resumes the reconciliation.
+	obj.Spec.TTL.Duration = 3 * time.Hour
+	expired, err = r.reconcileExpiration(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expired).To(BeFalse())
+	g.Expect(conditions.Has(obj, kustomizev1.ExpiredCondition)).To(BeFalse())
+
+	// With the Delete policy, the Kustomization deletes itself.
+	obj.Spec.TTL.Duration = time.Hour
+	obj.Spec.ExpirationPolicy = kustomizev1.ExpirationPolicyDelete
+	expired, err = r.reconcileExpiration(ctx, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expired).To(BeTrue())
+	latest := &kustomizev1.Kustomization{}
+	g.Expect(r.Get(ctx, client.ObjectKeyFromObject(obj), latest)).To(Succeed())
+	g.Expect(latest.GetDeletionTimestamp().IsZero()).To(BeFalse())
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	runtimeCtrl "github.com/fluxcd/pkg/runtime/controller"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// getUpgradeFreeze returns the message of the cluster upgrade freeze if the
+// UpgradeFreezeLease exists in the namespace of the controller, and has no
+// duration or was renewed within its duration, so that the freeze ends if
+// the upgrade automation stops renewing it. It returns an empty string if
+// there is no upgrade in progress.
+func (r *KustomizationReconciler) getUpgradeFreeze(ctx context.Context) (string, error) {
+	name, ns := r.UpgradeFreezeLease, os.Getenv(runtimeCtrl.EnvRuntimeNamespace)
+	if name == "" || ns == "" {
+		return "", nil
+	}
+
+	key := types.NamespacedName{Namespace: ns, Name: name}
+	lease := &coordinationv1.Lease{}
+	if err := r.APIReader.Get(ctx, key, lease); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get upgrade freeze Lease '%s': %w", key, err)
+	}
+	if lease.Spec.LeaseDurationSeconds != nil && isLeaseExpired(lease, time.Now()) {
+		return "", nil
+	}
+
+	msg := fmt.Sprintf("Cluster upgrade in progress, reconciliation paused by Lease '%s'", key)
+	if holder := ptr.Deref(lease.Spec.HolderIdentity, ""); holder != "" {
+		msg += " held by " + holder
+	}
+	return msg, nil
+}
+
+// waitForUpgradeFreeze sets the UpgradeFreeze condition with the given
+// message and requeues the reconciliation every DependencyRequeueInterval.
+// The Ready condition is kept if it is true for the current generation,
+// so that thousands of Kustomizations don't turn not ready during the
+// upgrade, otherwise it is set to false with the UpgradeFreeze reason.
+// It returns true if the Ready condition was kept.
+func (r *KustomizationReconciler) waitForUpgradeFreeze(ctx context.Context,
+	obj *kustomizev1.Kustomization, freeze string) (ctrl.Result, bool) {
+	msg := fmt.Sprintf("%s, retrying in %s", freeze, r.DependencyRequeueInterval.String())
+	ctrl.LoggerFrom(ctx).Info(msg)
+	conditions.MarkTrue(obj, kustomizev1.UpgradeFreezeCondition, kustomizev1.UpgradeFreezeReason, "%s", freeze)
+	result := ctrl.Result{RequeueAfter: r.DependencyRequeueInterval}
+
+	if ready := conditions.Get(obj, meta.ReadyCondition); ready != nil &&
+		ready.Status == metav1.ConditionTrue && obj.Status.ObservedGeneration == obj.Generation {
+		return result, true
+	}
+	conditions.MarkFalse(obj, meta.ReadyCondition, kustomizev1.UpgradeFreezeReason, "%s", msg)
+	return result, false
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestGetUpgradeFreeze(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	t.Setenv("RUNTIME_NAMESPACE", "flux-system")
+
+	kubeClient := fake.NewClientBuilder().Build()
+	r := &KustomizationReconciler{Client: kubeClient, APIReader: kubeClient}
+
+	// The freeze is disabled without a Lease name.
+	freeze, err := r.getUpgradeFreeze(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(freeze).To(BeEmpty())
+
+	// There is no upgrade in progress while the Lease doesn't exist.
+	r.UpgradeFreezeLease = "cluster-upgrade"
+	freeze, err = r.getUpgradeFreeze(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(freeze).To(BeEmpty())
+
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-upgrade", Namespace: "flux-system"},
+	}
+	g.Expect(kubeClient.Create(ctx, lease)).To(Succeed())
+	freeze, err = r.getUpgradeFreeze(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(freeze).To(Equal("Cluster upgrade in progress, reconciliation paused by Lease 'flux-system/cluster-upgrade'"))
+
+	// The freeze lasts while the Lease is renewed within its duration.
+	lease.Spec.HolderIdentity = ptr.To("upgrade-bot")
+	lease.Spec.LeaseDurationSeconds = ptr.To[int32](600)
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now()}
+	g.Expect(kubeClient.Update(ctx, lease)).To(Succeed())
+	freeze, err = r.getUpgradeFreeze(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(freeze).To(HaveSuffix("held by upgrade-bot"))
+
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now().Add(-time.Hour)}
+	g.Expect(kubeClient.Update(ctx, lease)).To(Succeed())
+	freeze, err = r.getUpgradeFreeze(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(freeze).To(BeEmpty())
+}
+
+func TestWaitForUpgradeFreeze(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	r := &KustomizationReconciler{DependencyRequeueInterval: 30 * time.Second}
+	freeze := "Cluster upgrade in progress"
+
+	// The Ready condition is kept if true for the current generation.
+	obj := &kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+	obj.Status.ObservedGeneration = 1
+	conditions.MarkTrue(obj, meta.ReadyCondition, meta.ReconciliationSucceededReason, "Applied revision: main@sha1:1a2b3c4d")
+	result, kept := r.waitForUpgradeFreeze(ctx, obj, freeze)
+	g.Expect(kept).To(BeTrue())
+	g.Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+	g.Expect(conditions.IsTrue(obj, meta.ReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(meta.ReconciliationSucceededReason))
+	g.Expect(conditions.IsTrue(obj, kustomizev1.UpgradeFreezeCondition)).To(BeTrue())
+	g.Expect(conditions.GetMessage(obj, kustomizev1.UpgradeFreezeCondition)).To(Equal(freeze))
+
+	// The Ready condition is set to false if the spec changed.
+	obj.Generation = 2
+	_, kept = r.waitForUpgradeFreeze(ctx, obj, freeze)
+	g.Expect(kept).To(BeFalse())
+	g.Expect(conditions.IsFalse(obj, meta.ReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, meta.ReadyCondition)).To(Equal(kustomizev1.UpgradeFreezeReason))
+	g.Expect(conditions.GetMessage(obj, meta.ReadyCondition)).To(Equal(freeze + ", retrying in 30s"))
+}
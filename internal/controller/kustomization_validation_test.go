@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/testserver"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -141,3 +142,78 @@ func TestKustomizationReconciler_Validation(t *testing.T) {
 		}, timeout, interval).Should(BeTrue())
 	})
 }
+
+func TestKustomizationReconciler_FieldValidation(t *testing.T) {
+	g := NewWithT(t)
+
+	id := "fval-" + randStringRunes(5)
+	revision := "v1.0.0"
+
+	err := createNamespace(id)
+	g.Expect(err).NotTo(HaveOccurred(), "failed to create test namespace")
+
+	artifact, err := testServer.ArtifactFromFiles([]testserver.File{
+		{
+			Name: "configmap.yaml",
+			Body: fmt.Sprintf(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %[1]s
+  namespace: %[1]s
+unknownField: value
+data:
+  key: value
+`, id),
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	repositoryName := types.NamespacedName{
+		Name:      fmt.Sprintf("fval-%s", randStringRunes(5)),
+		Namespace: id,
+	}
+
+	err = applyGitRepository(repositoryName, artifact, revision)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	kustomization := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("fval-%s", randStringRunes(5)),
+			Namespace: id,
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: 2 * time.Minute},
+			Path:     "./",
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{
+				Name:      repositoryName.Name,
+				Namespace: repositoryName.Namespace,
+				Kind:      sourcev1.GitRepositoryKind,
+			},
+			Validation: kustomizev1.FieldValidationStrict,
+		},
+	}
+	g.Expect(k8sClient.Create(context.Background(), kustomization)).To(Succeed())
+
+	resultK := &kustomizev1.Kustomization{}
+
+	t.Run("fails with strict validation", func(t *testing.T) {
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return isReconcileFailure(resultK)
+		}, timeout, interval).Should(BeTrue())
+		logStatus(t, resultK)
+	})
+
+	t.Run("applies with ignore validation", func(t *testing.T) {
+		g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)).To(Succeed())
+		patch := client.MergeFrom(resultK.DeepCopy())
+		resultK.Spec.Validation = kustomizev1.FieldValidationIgnore
+		g.Expect(k8sClient.Patch(context.Background(), resultK, patch)).To(Succeed())
+
+		g.Eventually(func() bool {
+			_ = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(kustomization), resultK)
+			return isReconcileSuccess(resultK) && resultK.Status.LastAppliedRevision == revision
+		}, timeout, interval).Should(BeTrue())
+	})
+}
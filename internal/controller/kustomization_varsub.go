@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	generator "github.com/fluxcd/pkg/kustomize"
+	runtimeCtrl "github.com/fluxcd/pkg/runtime/controller"
+
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+// withDefaultSubstitutions merges the variables of the cluster facts and of
+// the default substitutions ConfigMap into the post-build substitutions of the
+// given Kustomization. The cluster facts have the lowest precedence: they are
+// overridden by the default variables, which are overridden by the variables
+// loaded from spec.postBuild.substituteFrom, which are in turn overridden by
+// spec.postBuild.substitute. The returned object carries the merged variables
+// in spec.postBuild.substitute and no substituteFrom references, so that the
+// variables are not loaded again for every resource.
+func (r *KustomizationReconciler) withDefaultSubstitutions(ctx context.Context,
+	u unstructured.Unstructured) (unstructured.Unstructured, error) {
+	vars := make(map[string]string)
+
+	// The facts are of the cluster of the controller, not of the remote clusters.
+	_, kubeConfig, _ := unstructured.NestedMap(u.Object, "spec", "kubeConfig")
+	_, clusterRef, _ := unstructured.NestedMap(u.Object, "spec", "kubeConfigClusterRef")
+	if r.ClusterFacts != nil && !kubeConfig && !clusterRef {
+		facts, err := r.ClusterFacts.variables(ctx)
+		if err != nil {
+			return u, fmt.Errorf("failed to read the cluster facts: %w", err)
+		}
+		maps.Copy(vars, facts)
+	}
+
+	if name, ns := r.DefaultSubstituteFrom, os.Getenv(runtimeCtrl.EnvRuntimeNamespace); name != "" && ns != "" {
+		cm := &corev1.ConfigMap{}
+		cmName := types.NamespacedName{Namespace: ns, Name: name}
+		if err := r.Get(ctx, cmName, cm); err != nil && !apierrors.IsNotFound(err) {
+			return u, fmt.Errorf("failed to get default substitutions ConfigMap '%s': %w", cmName, err)
+		}
+		for k, v := range cm.Data {
+			vars[k] = strings.ReplaceAll(v, "\n", "")
+		}
+	}
+	if len(vars) == 0 {
+		return u, nil
+	}
+
+	objVars, err := generator.LoadVariables(ctx, r.Client, u)
+	if err != nil {
+		return u, fmt.Errorf("post build failed: %w", err)
+	}
+	for k, v := range objVars {
+		vars[k] = v
+	}
+
+	inline, _, err := unstructured.NestedStringMap(u.Object, "spec", "postBuild", "substitute")
+	if err != nil {
+		return u, fmt.Errorf("post build failed: %w", err)
+	}
+	for k, v := range inline {
+		vars[k] = v
+	}
+
+	out := *u.DeepCopy()
+	unstructured.RemoveNestedField(out.Object, "spec", "postBuild", "substituteFrom")
+	if err := unstructured.SetNestedStringMap(out.Object, vars, "spec", "postBuild", "substitute"); err != nil {
+		return u, fmt.Errorf("post build failed: %w", err)
+	}
+	return out, nil
+}
+
+// withResolvedSubstitutions resolves the post-build substitution variables
+// referencing the secrets of external secret stores with the given decryptor,
+// see decryptor.ResolveVariables. When a variable is resolved, the returned
+// object carries all the variables in spec.postBuild.substitute and no
+// substituteFrom references, as with withDefaultSubstitutions, so that the
+// secrets are fetched once per build.
+func (r *KustomizationReconciler) withResolvedSubstitutions(ctx context.Context,
+	dec *decryptor.Decryptor, u unstructured.Unstructured) (unstructured.Unstructured, error) {
+	vars, err := generator.LoadVariables(ctx, r.Client, u)
+	if err != nil {
+		return u, fmt.Errorf("post build failed: %w", err)
+	}
+	inline, _, err := unstructured.NestedStringMap(u.Object, "spec", "postBuild", "substitute")
+	if err != nil {
+		return u, fmt.Errorf("post build failed: %w", err)
+	}
+	maps.Copy(vars, inline)
+
+	resolved, err := dec.ResolveVariables(vars)
+	if err != nil {
+		return u, decryptionFailed(ctx, err)
+	}
+	if !resolved {
+		return u, nil
+	}
+
+	out := *u.DeepCopy()
+	unstructured.RemoveNestedField(out.Object, "spec", "postBuild", "substituteFrom")
+	if err := unstructured.SetNestedStringMap(out.Object, vars, "spec", "postBuild", "substitute"); err != nil {
+		return u, fmt.Errorf("post build failed: %w", err)
+	}
+	return out, nil
+}
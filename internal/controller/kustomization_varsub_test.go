@@ -28,10 +28,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
 )
 
 func TestKustomizationReconciler_Varsub(t *testing.T) {
@@ -433,7 +437,7 @@ metadata:
 			resultK := &kustomizev1.Kustomization{}
 			_ = k8sClient.Get(ctx, client.ObjectKeyFromObject(inputK), resultK)
 			for _, c := range resultK.Status.Conditions {
-				if c.Reason == meta.ReconciliationFailedReason && c.Status == metav1.ConditionFalse {
+				if c.Reason == kustomizev1.ValidationFailedReason && c.Status == metav1.ConditionFalse {
 					return true
 				}
 			}
@@ -717,3 +721,136 @@ metadata:
 	g.Expect(ready.Message).To(ContainSubstring("variable not set"))
 	g.Expect(k8sClient.Delete(context.Background(), &resultK)).To(Succeed())
 }
+
+func TestKustomizationReconciler_VarsubDefaults(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewWithT(t)
+	id := "vars-" + randStringRunes(5)
+	revision := "v1.0.0/" + randStringRunes(7)
+
+	err := createNamespace(id)
+	g.Expect(err).NotTo(HaveOccurred(), "failed to create test namespace")
+
+	defaults := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-vars",
+			Namespace: id,
+		},
+		Data: map[string]string{
+			"cluster_name":   "default-cluster",
+			"cluster_region": "default-region",
+		},
+	}
+	g.Expect(k8sClient.Create(ctx, defaults)).To(Succeed())
+
+	t.Setenv("RUNTIME_NAMESPACE", id)
+	reconciler.DefaultSubstituteFrom = defaults.Name
+	defer func() {
+		reconciler.DefaultSubstituteFrom = ""
+	}()
+
+	manifests := func(name string) []testserver.File {
+		return []testserver.File{
+			{
+				Name: "service-account.yaml",
+				Body: fmt.Sprintf(`
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: %[1]s
+  namespace: %[1]s
+  labels:
+    cluster: ${cluster_name}
+    region: ${cluster_region}
+`, name),
+			},
+		}
+	}
+
+	artifact, err := testServer.ArtifactFromFiles(manifests(id))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	repositoryName := types.NamespacedName{
+		Name:      randStringRunes(5),
+		Namespace: id,
+	}
+
+	err = applyGitRepository(repositoryName, artifact, revision)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	inputK := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      id,
+			Namespace: id,
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: reconciliationInterval},
+			Path:     "./",
+			Prune:    true,
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{
+				Kind: sourcev1.GitRepositoryKind,
+				Name: repositoryName.Name,
+			},
+			PostBuild: &kustomizev1.PostBuild{
+				Substitute: map[string]string{
+					"cluster_region": "override-region",
+				},
+			},
+		},
+	}
+	g.Expect(k8sClient.Create(ctx, inputK)).Should(Succeed())
+
+	resultSA := &corev1.ServiceAccount{}
+	t.Run("merges defaults with the lowest precedence", func(t *testing.T) {
+		g.Eventually(func() bool {
+			resultK := &kustomizev1.Kustomization{}
+			_ = k8sClient.Get(ctx, client.ObjectKeyFromObject(inputK), resultK)
+			return resultK.Status.LastAppliedRevision == revision
+		}, timeout, interval).Should(BeTrue())
+
+		g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: id, Namespace: id}, resultSA)).To(Succeed())
+		g.Expect(resultSA.Labels["cluster"]).To(Equal("default-cluster"))
+		g.Expect(resultSA.Labels["region"]).To(Equal("override-region"))
+	})
+}
+
+func TestWithResolvedSubstitutions(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	arn := "arn:aws:secretsmanager:eu-west-1:123456789012:secret:db-AbCdEf"
+	vars := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vars", Namespace: "apps"},
+		Data:       map[string][]byte{"password": []byte("aws-sm://" + arn + "#password")},
+	}
+	r := &KustomizationReconciler{Client: fake.NewClientBuilder().WithObjects(vars).Build()}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{Provider: decryptor.DecryptionProviderPlaceholder},
+			PostBuild: &kustomizev1.PostBuild{
+				Substitute:     map[string]string{"region": "eu-west-1"},
+				SubstituteFrom: []kustomizev1.SubstituteReference{{Kind: "Secret", Name: "vars"}},
+			},
+		},
+	}
+	k, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	dec, cleanup, err := decryptor.New(r.Client, obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(cleanup)
+
+	// The variables loaded from substituteFrom are resolved, and the
+	// failures are reported as decryption failures.
+	_, err = r.withResolvedSubstitutions(ctx, dec, unstructured.Unstructured{Object: k})
+	g.Expect(err).To(MatchError(ContainSubstring("failed to resolve substitution variable 'password'")))
+	g.Expect(failureReason(err, meta.BuildFailedReason)).To(Equal(kustomizev1.DecryptionFailedReason))
+
+	// The object is left untouched when no variable references a secret.
+	vars.Data["password"] = []byte("s3cr3t")
+	g.Expect(r.Client.Update(ctx, vars)).To(Succeed())
+	u, err := r.withResolvedSubstitutions(ctx, dec, unstructured.Unstructured{Object: k})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(u.Object).To(Equal(k))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// commitSignerRegexp matches the commit and the key ID in the message of the
+// SourceVerified condition of a GitRepository, e.g.
+// "verified signature of\n\t- commit '<sha>' with key '<key ID>'".
+var commitSignerRegexp = regexp.MustCompile(`commit '([0-9a-f]+)' with key '([^']+)'`)
+
+// verifyGitSignature returns an error if the commit of the revision of the
+// source was not verified by the GitRepository, or was signed with a key not
+// trusted by the Kustomization.
+func (r *KustomizationReconciler) verifyGitSignature(ctx context.Context,
+	obj *kustomizev1.Kustomization,
+	src sourcev1.Source) error {
+	v := obj.Spec.Verify
+	if v == nil || v.GitSignatures == nil {
+		return nil
+	}
+	repository, ok := src.(*sourcev1.GitRepository)
+	if !ok {
+		return fmt.Errorf("the verification of the Git signatures requires a %s source, got %s",
+			sourcev1.GitRepositoryKind, obj.Spec.SourceRef.Kind)
+	}
+
+	keyIDs, err := r.trustedKeyIDs(ctx, obj, v.GitSignatures.SecretRef.Name)
+	if err != nil {
+		return err
+	}
+
+	revision := src.GetArtifact().Revision
+	commit := revision[strings.LastIndex(revision, ":")+1:]
+	if !conditions.IsTrue(repository, sourcev1.SourceVerifiedCondition) {
+		return fmt.Errorf("the commit of revision '%s' was not verified by %s '%s'",
+			revision, sourcev1.GitRepositoryKind, client.ObjectKeyFromObject(repository))
+	}
+	m := commitSignerRegexp.FindStringSubmatch(conditions.GetMessage(repository, sourcev1.SourceVerifiedCondition))
+	if m == nil || m[1] != commit {
+		return fmt.Errorf("the signature of the commit of revision '%s' was not verified by %s '%s', the verification of the HEAD commit must be enabled",
+			revision, sourcev1.GitRepositoryKind, client.ObjectKeyFromObject(repository))
+	}
+	if !slices.Contains(keyIDs, strings.ToUpper(m[2])) {
+		return fmt.Errorf("the commit of revision '%s' is signed with key '%s', which is not trusted by the Kustomization",
+			revision, m[2])
+	}
+	return nil
+}
+
+// trustedKeyIDs returns the IDs of the primary keys of the armored OpenPGP
+// public keys held by the data entries of the named Secret.
+func (r *KustomizationReconciler) trustedKeyIDs(ctx context.Context,
+	obj *kustomizev1.Kustomization, name string) ([]string, error) {
+	secretKey := types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretKey, &secret); err != nil {
+		return nil, fmt.Errorf("unable to read trusted keys Secret '%s': %w", secretKey, err)
+	}
+
+	var keyIDs []string
+	for key, data := range secret.Data {
+		keyRing, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the keys of '%s' in Secret '%s': %w", key, secretKey, err)
+		}
+		for _, entity := range keyRing {
+			keyIDs = append(keyIDs, entity.PrimaryKey.KeyIdString())
+		}
+	}
+	if len(keyIDs) == 0 {
+		return nil, fmt.Errorf("no trusted keys found in Secret '%s'", secretKey)
+	}
+	return keyIDs, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestVerifyGitSignature(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	newArmoredKey := func(name string) (*openpgp.Entity, []byte) {
+		entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		var buf bytes.Buffer
+		w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(entity.Serialize(w)).To(Succeed())
+		g.Expect(w.Close()).To(Succeed())
+		return entity, buf.Bytes()
+	}
+	trusted, trustedKey := newArmoredKey("trusted")
+	untrusted, _ := newArmoredKey("untrusted")
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "trusted-keys", Namespace: "apps"},
+		Data:       map[string][]byte{"trusted.asc": trustedKey},
+	}
+	r := &KustomizationReconciler{
+		Client: fake.NewClientBuilder().WithObjects(secret).Build(),
+	}
+
+	const commit = "6c8a29a6d3e7c2b1ffb38f5ac2d1b2fc2e2a3e1d"
+	newRepository := func(signer *openpgp.Entity, verifiedCommit string) *sourcev1.GitRepository {
+		repository := &sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "apps"},
+			Status: sourcev1.GitRepositoryStatus{
+				Artifact: &meta.Artifact{Revision: "main@sha1:" + commit},
+			},
+		}
+		if signer != nil {
+			conditions.MarkTrue(repository, sourcev1.SourceVerifiedCondition, meta.SucceededReason,
+				"verified signature of\n\t- commit '%s' with key '%s'", verifiedCommit, signer.PrimaryKey.KeyIdString())
+		}
+		return repository
+	}
+
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{Kind: sourcev1.GitRepositoryKind, Name: "apps"},
+		},
+	}
+
+	// Without verification, any revision is accepted.
+	g.Expect(r.verifyGitSignature(ctx, obj, newRepository(nil, ""))).To(Succeed())
+
+	obj.Spec.Verify = &kustomizev1.Verification{
+		GitSignatures: &kustomizev1.GitSignatureVerification{
+			SecretRef: meta.LocalObjectReference{Name: "trusted-keys"},
+		},
+	}
+	g.Expect(r.verifyGitSignature(ctx, obj, newRepository(trusted, commit))).To(Succeed())
+
+	tests := []struct {
+		name    string
+		src     sourcev1.Source
+		wantErr string
+	}{
+		{
+			name:    "unverified commit",
+			src:     newRepository(nil, ""),
+			wantErr: fmt.Sprintf("the commit of revision 'main@sha1:%s' was not verified by GitRepository 'apps/apps'", commit),
+		},
+		{
+			name:    "verification of another commit",
+			src:     newRepository(trusted, "1a2b3c4d"),
+			wantErr: "the verification of the HEAD commit must be enabled",
+		},
+		{
+			name: "untrusted key",
+			src:  newRepository(untrusted, commit),
+			wantErr: fmt.Sprintf("is signed with key '%s', which is not trusted by the Kustomization",
+				untrusted.PrimaryKey.KeyIdString()),
+		},
+		{
+			name: "non-Git source",
+			src: &sourcev1.OCIRepository{
+				Status: sourcev1.OCIRepositoryStatus{Artifact: &meta.Artifact{Revision: "latest@sha256:" + commit}},
+			},
+			wantErr: "requires a GitRepository source",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(r.verifyGitSignature(ctx, obj, tt.src)).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+
+	// The trusted keys Secret must exist.
+	obj.Spec.Verify.GitSignatures.SecretRef.Name = "missing"
+	g.Expect(r.verifyGitSignature(ctx, obj, newRepository(trusted, commit))).
+		To(MatchError(ContainSubstring("unable to read trusted keys Secret 'apps/missing'")))
+}
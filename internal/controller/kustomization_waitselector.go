@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/inventory"
+)
+
+// selectWaitObjects returns the entries of the change set whose objects match
+// the wait selector of the Kustomization, so that only the selected objects
+// are health checked when spec.wait is enabled. The labels are read from the
+// given objects. The change set is returned unchanged if no selector is set.
+func selectWaitObjects(obj *kustomizev1.Kustomization, changeSet *ssa.ChangeSet,
+	objects []*unstructured.Unstructured) (*ssa.ChangeSet, error) {
+	if !obj.Spec.Wait || obj.Spec.WaitSelector == nil {
+		return changeSet, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(obj.Spec.WaitSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wait selector: %w", err)
+	}
+
+	selected := make(object.ObjMetadataSet, 0, len(objects))
+	for _, u := range objects {
+		if selector.Matches(labels.Set(u.GetLabels())) {
+			selected = append(selected, object.UnstructuredToObjMetadata(u))
+		}
+	}
+	result := ssa.NewChangeSet()
+	for _, entry := range changeSet.Entries {
+		if selected.Contains(entry.ObjMetadata) {
+			result.Add(entry)
+		}
+	}
+	return result, nil
+}
+
+// getWaitObjects returns the live objects of the inventory, to select the
+// objects to health check when the wait selector is set and the manifests
+// are not built, e.g. with the health-only reconcile mode. Only the metadata
+// of the objects is read, and the objects not found are returned without
+// labels.
+func getWaitObjects(ctx context.Context, kubeClient client.Client, obj *kustomizev1.Kustomization,
+	inv *kustomizev1.ResourceInventory) ([]*unstructured.Unstructured, error) {
+	if !obj.Spec.Wait || obj.Spec.WaitSelector == nil {
+		return nil, nil
+	}
+
+	objects, err := inventory.List(inv)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range objects {
+		m := &metav1.PartialObjectMetadata{}
+		m.SetGroupVersionKind(u.GroupVersionKind())
+		if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(u), m); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get '%s': %w", ssautil.FmtUnstructured(u), err)
+			}
+			continue
+		}
+		u.SetLabels(m.GetLabels())
+	}
+	return objects, nil
+}
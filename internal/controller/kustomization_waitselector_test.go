@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestSelectWaitObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	newObject := func(name string, labels map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind("Deployment")
+		u.SetNamespace("apps")
+		u.SetName(name)
+		u.SetLabels(labels)
+		return u
+	}
+	objects := []*unstructured.Unstructured{
+		newObject("api", map[string]string{"tier": "critical"}),
+		newObject("batch", map[string]string{"tier": "best-effort"}),
+		newObject("cron", nil),
+	}
+	changeSet := ssa.NewChangeSet()
+	for _, u := range objects {
+		changeSet.Add(ssa.ChangeSetEntry{ObjMetadata: object.UnstructuredToObjMetadata(u), Action: ssa.ConfiguredAction})
+	}
+
+	obj := &kustomizev1.Kustomization{
+		Spec: kustomizev1.KustomizationSpec{
+			Wait:         true,
+			WaitSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "critical"}},
+		},
+	}
+	selected, err := selectWaitObjects(obj, changeSet, objects)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(selected.Entries).To(HaveLen(1))
+	g.Expect(selected.Entries[0].ObjMetadata.Name).To(Equal("api"))
+
+	obj.Spec.WaitSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: metav1.LabelSelectorOpExists}},
+	}
+	selected, err = selectWaitObjects(obj, changeSet, objects)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(selected.Entries).To(HaveLen(2))
+
+	// The selector is ignored when wait is disabled.
+	obj.Spec.Wait = false
+	selected, err = selectWaitObjects(obj, changeSet, objects)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(selected).To(BeIdenticalTo(changeSet))
+
+	obj.Spec.Wait = true
+	obj.Spec.WaitSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Equals"}},
+	}
+	_, err = selectWaitObjects(obj, changeSet, objects)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid wait selector"))
+}
+
+func TestGetWaitObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	critical := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "critical", Namespace: "apps", Labels: map[string]string{"tier": "critical"}},
+	}
+	kubeClient := fake.NewClientBuilder().WithObjects(critical).Build()
+	inv := &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+		{ID: "apps_critical__ConfigMap", Version: "v1"},
+		{ID: "apps_deleted__ConfigMap", Version: "v1"},
+	}}
+	obj := &kustomizev1.Kustomization{
+		Spec: kustomizev1.KustomizationSpec{
+			Wait:         true,
+			WaitSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "critical"}},
+		},
+	}
+
+	objects, err := getWaitObjects(context.Background(), kubeClient, obj, inv)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+	g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("tier", "critical"))
+	g.Expect(objects[1].GetLabels()).To(BeEmpty())
+
+	obj.Spec.WaitSelector = nil
+	objects, err = getWaitObjects(context.Background(), kubeClient, obj, inv)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(BeNil())
+}
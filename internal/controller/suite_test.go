@@ -185,6 +185,7 @@ func TestMain(m *testing.M) {
 			ConcurrentSSA:             4,
 			DisallowedFieldManagers:   []string{overrideManagerName},
 			SOPSAgeSecret:             sopsAgeSecret,
+			SkipHPAReplicasDrift:      true,
 		}
 		if err := (reconciler).SetupWithManager(ctx, testEnv, KustomizationReconcilerOptions{
 			WatchConfigsPredicate:      predicate.Not(predicate.Funcs{}),
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"filippo.io/age/plugin"
+)
+
+// agePluginIdentityPrefix is the prefix of the identities of the age plugins,
+// e.g. 'AGE-PLUGIN-YUBIKEY-1...' for age-plugin-yubikey.
+const agePluginIdentityPrefix = "AGE-PLUGIN-"
+
+// agePluginUI is the UI of the age plugins invoked to unwrap the data keys.
+// As the controller runs unattended, the requests of the plugins for a value
+// or a confirmation, e.g. a PIN or a touch, fail, and their messages are
+// discarded.
+var agePluginUI = &plugin.ClientUI{
+	DisplayMessage: func(name, message string) error {
+		return nil
+	},
+	RequestValue: func(name, prompt string, secret bool) (string, error) {
+		return "", fmt.Errorf("age plugin '%s' requested a value, interactive plugins are not supported", name)
+	},
+	Confirm: func(name, prompt, yes, no string) (bool, error) {
+		return false, fmt.Errorf("age plugin '%s' requested a confirmation, interactive plugins are not supported", name)
+	},
+}
+
+// importAgeIdentities imports the age identities of the given data, one per
+// line. The plugin identities are unwrapped by the age-plugin-<name> binary
+// of the plugin, which must be found in the PATH of the controller.
+func (d *Decryptor) importAgeIdentities(data string) error {
+	var native []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, agePluginIdentityPrefix) {
+			native = append(native, line)
+			continue
+		}
+
+		identity, err := plugin.NewIdentity(line, agePluginUI)
+		if err != nil {
+			return fmt.Errorf("failed to parse age plugin identity: malformed identity")
+		}
+		binary := "age-plugin-" + identity.Name()
+		if _, err := exec.LookPath(binary); err != nil {
+			return fmt.Errorf("age plugin '%s' not found: '%s' must be in the PATH of the controller",
+				identity.Name(), binary)
+		}
+		d.ageIdentities = append(d.ageIdentities, identity)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return d.ageIdentities.Import(native...)
+}
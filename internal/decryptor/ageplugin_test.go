@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age/plugin"
+	. "github.com/onsi/gomega"
+)
+
+func TestDecryptor_importAgeIdentities(t *testing.T) {
+	g := NewWithT(t)
+
+	ageKey, err := os.ReadFile("testdata/age.txt")
+	g.Expect(err).ToNot(HaveOccurred())
+	pluginIdentity := plugin.EncodeIdentity("fake", []byte("slot-1"))
+
+	pluginDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(pluginDir, "age-plugin-fake"), []byte("#!/bin/sh\n"), 0o755)).To(Succeed())
+	t.Setenv("PATH", pluginDir)
+
+	d := &Decryptor{}
+	g.Expect(d.importAgeIdentities(string(ageKey) + "\n" + pluginIdentity + "\n")).To(Succeed())
+	g.Expect(d.ageIdentities).To(HaveLen(2))
+	g.Expect(d.ageIdentities[0]).To(BeAssignableToTypeOf(&plugin.Identity{}))
+	g.Expect(d.ageIdentities[0].(*plugin.Identity).Name()).To(Equal("fake"))
+
+	t.Setenv("PATH", t.TempDir())
+	d = &Decryptor{}
+	err = d.importAgeIdentities(pluginIdentity)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(Equal("age plugin 'fake' not found: 'age-plugin-fake' must be in the PATH of the controller"))
+
+	d = &Decryptor{}
+	err = d.importAgeIdentities("AGE-PLUGIN-FAKE-1INVALID")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).ToNot(ContainSubstring("INVALID"))
+
+	_, err = agePluginUI.RequestValue("fake", "PIN", true)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/getsops/sops/v3"
+	sopsaes "github.com/getsops/sops/v3/aes"
+	"github.com/getsops/sops/v3/cmd/sops/common"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/config"
+)
+
+const (
+	// maxEncryptedBinaryFileSize is the default max allowed file size in
+	// bytes of an encrypted file in the binary format.
+	maxEncryptedBinaryFileSize int64 = 100 << 20
+
+	// binaryChunkSize is the size of the chunks an encrypted
+	// file in the binary format is read in.
+	binaryChunkSize = 64 << 10
+
+	// binaryDataPrefix is the start of the encrypted value of the
+	// 'data' key of the JSON envelope of the binary format.
+	binaryDataPrefix = `"ENC[AES256_GCM,data:`
+)
+
+// errNotBinaryEnvelope is returned by sopsDecryptBinaryFile when the file
+// doesn't start with the encrypted 'data' key of the binary format.
+var errNotBinaryEnvelope = errors.New("not a SOPS binary envelope")
+
+// sopsDecryptBinaryFile decrypts the file encrypted in the SOPS binary format
+// in place. The file is read in chunks, with the ciphertext of the 'data' key
+// decoded into a single buffer decrypted in place, so that large files, e.g.
+// certificate bundles or keystores, are decrypted without holding copies of
+// the whole file in memory. It returns errNotBinaryEnvelope if the file
+// doesn't start with the encrypted 'data' key, as written by SOPS.
+func (d *Decryptor) sopsDecryptBinaryFile(path string, size int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReaderSize(f, binaryChunkSize)
+
+	for _, token := range []string{"{", `"data"`, ":", binaryDataPrefix} {
+		if err := expectJSONToken(r, token); err != nil {
+			return err
+		}
+	}
+
+	// Decode the ciphertext, up to the ',iv:' field of the encrypted value.
+	var ciphertext bytes.Buffer
+	ciphertext.Grow(base64.StdEncoding.DecodedLen(int(size)))
+	if _, err := io.Copy(&ciphertext, base64.NewDecoder(base64.StdEncoding, &delimReader{r: r, delim: ','})); err != nil {
+		return fmt.Errorf("failed to decode encrypted binary data: %w", err)
+	}
+	fields, err := r.ReadString(']')
+	if err != nil {
+		return fmt.Errorf("failed to read encrypted binary data: %w", err)
+	}
+	iv, tag, err := parseBinaryFields(fields)
+	if err != nil {
+		return err
+	}
+
+	// Load the metadata of the rest of the envelope, with an empty 'data' key.
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	envelope := append([]byte(`{"data": "`), rest...)
+	store := common.StoreForFormat(formats.Binary, config.NewStoresConfig())
+	tree, err := store.LoadEncryptedFile(envelope)
+	if err != nil {
+		return sopsUserErr("failed to load encrypted binary data", err)
+	}
+
+	dataKey, err := d.getDataKey(&tree.Metadata)
+	d.recordKeyProviders(tree.Metadata, err)
+	if err != nil {
+		return sopsUserErr("cannot get sops data key", err)
+	}
+
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return err
+	}
+	ciphertext.Write(tag)
+	data, err := gcm.Open(ciphertext.Bytes()[:0], iv, ciphertext.Bytes(), []byte("data:"))
+	if err != nil {
+		return fmt.Errorf("error decrypting sops tree: could not decrypt with AES_GCM: %w", err)
+	}
+
+	if d.checkSopsMac {
+		if err := verifyBinaryMac(tree.Metadata, dataKey, data); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing sops decrypted binary data to binary file: %w", err)
+	}
+	return nil
+}
+
+// verifyBinaryMac compares the MAC of the metadata with
+// the MAC of the decrypted data, computed like SOPS does.
+func verifyBinaryMac(metadata sops.Metadata, dataKey, data []byte) error {
+	hash := sha512.New()
+	if metadata.MACOnlyEncrypted {
+		hash.Write(sops.MACOnlyEncryptedInitialization)
+	}
+	hash.Write(data)
+	mac := fmt.Sprintf("%X", hash.Sum(nil))
+
+	originalMac, err := safeDecrypt(sopsaes.NewCipher().Decrypt(
+		metadata.MessageAuthenticationCode,
+		dataKey,
+		metadata.LastModified.Format(time.RFC3339),
+	))
+	if err != nil {
+		return sopsUserErr("failed to verify sops data integrity", err)
+	}
+	if originalMac != mac {
+		if originalMac == "" {
+			originalMac = "no MAC"
+		}
+		return fmt.Errorf("failed to verify sops data integrity: expected mac '%s', got '%s'", originalMac, mac)
+	}
+	return nil
+}
+
+// parseBinaryFields returns the iv and the tag of the fields following the
+// data of the encrypted value, e.g. 'iv:<iv>,tag:<tag>,type:str]'.
+func parseBinaryFields(fields string) (iv, tag []byte, err error) {
+	values := make(map[string]string)
+	for _, field := range strings.Split(strings.TrimSuffix(fields, "]"), ",") {
+		k, v, _ := strings.Cut(field, ":")
+		values[k] = v
+	}
+	if values["type"] != "str" {
+		return nil, nil, fmt.Errorf("invalid encrypted binary data: unexpected type '%s'", values["type"])
+	}
+	if iv, err = base64.StdEncoding.DecodeString(values["iv"]); err != nil || len(iv) == 0 {
+		return nil, nil, fmt.Errorf("invalid encrypted binary data: malformed iv")
+	}
+	if tag, err = base64.StdEncoding.DecodeString(values["tag"]); err != nil || len(tag) != 16 {
+		return nil, nil, fmt.Errorf("invalid encrypted binary data: malformed tag")
+	}
+	return iv, tag, nil
+}
+
+// expectJSONToken skips the JSON whitespace of the reader, and returns
+// errNotBinaryEnvelope if the next bytes are not the given token.
+func expectJSONToken(r *bufio.Reader, token string) error {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return errNotBinaryEnvelope
+		}
+		if !bytes.ContainsAny(b, " \t\n\r") {
+			break
+		}
+		_, _ = r.Discard(1)
+	}
+	next, err := r.Peek(len(token))
+	if err != nil || string(next) != token {
+		return errNotBinaryEnvelope
+	}
+	_, err = r.Discard(len(token))
+	return err
+}
+
+// delimReader reads from r up to the delimiter, which is discarded.
+type delimReader struct {
+	r     *bufio.Reader
+	delim byte
+	done  bool
+}
+
+func (d *delimReader) Read(p []byte) (int, error) {
+	if d.done {
+		return 0, io.EOF
+	}
+	if d.r.Buffered() == 0 {
+		if _, err := d.r.Peek(1); err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+	}
+	buf, err := d.r.Peek(min(len(p), d.r.Buffered()))
+	if err != nil {
+		return 0, err
+	}
+	if i := bytes.IndexByte(buf, d.delim); i >= 0 {
+		n := copy(p, buf[:i])
+		d.done = true
+		_, err = d.r.Discard(i + 1)
+		return n, err
+	}
+	n := copy(p, buf)
+	_, err = d.r.Discard(n)
+	return n, err
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	extage "filippo.io/age"
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	. "github.com/onsi/gomega"
+)
+
+func TestDecryptor_sopsDecryptBinaryFile(t *testing.T) {
+	id, err := extage.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := sops.Metadata{
+		KeyGroups: []sops.KeyGroup{
+			{&age.MasterKey{Recipient: id.Recipient().String()}},
+		},
+	}
+
+	// Larger than the chunks the file is read in.
+	data := make([]byte, 3*binaryChunkSize+7)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("decrypts the file in chunks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		d := &Decryptor{
+			checkSopsMac: true,
+			keyring:      keyring{ageIdentities: age.ParsedIdentities{id}},
+		}
+		encData, err := d.sopsEncryptWithFormat(metadata, data, formats.Binary, formats.Binary)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		path := filepath.Join(t.TempDir(), "keystore.jks")
+		g.Expect(os.WriteFile(path, encData, 0o600)).To(Succeed())
+		g.Expect(d.sopsDecryptBinaryFile(path, int64(len(encData)))).To(Succeed())
+
+		out, err := os.ReadFile(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(bytes.Equal(out, data)).To(BeTrue())
+	})
+
+	t.Run("fails on tampered data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		d := &Decryptor{keyring: keyring{ageIdentities: age.ParsedIdentities{id}}}
+		encData, err := d.sopsEncryptWithFormat(metadata, []byte("secret"), formats.Binary, formats.Binary)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		i := bytes.Index(encData, []byte(binaryDataPrefix)) + len(binaryDataPrefix)
+		tampered := bytes.Clone(encData)
+		if tampered[i] == 'A' {
+			tampered[i] = 'B'
+		} else {
+			tampered[i] = 'A'
+		}
+		path := filepath.Join(t.TempDir(), "keystore.jks")
+		g.Expect(os.WriteFile(path, tampered, 0o600)).To(Succeed())
+
+		err = d.sopsDecryptBinaryFile(path, int64(len(tampered)))
+		g.Expect(err).To(MatchError(ContainSubstring("could not decrypt with AES_GCM")))
+	})
+
+	t.Run("returns errNotBinaryEnvelope for other files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := filepath.Join(t.TempDir(), "keystore.jks")
+		g.Expect(os.WriteFile(path, []byte("plain"), 0o600)).To(Succeed())
+		g.Expect((&Decryptor{}).sopsDecryptBinaryFile(path, 5)).To(MatchError(errNotBinaryEnvelope))
+	})
+
+	t.Run("applies the binary file size limit", func(t *testing.T) {
+		g := NewWithT(t)
+
+		d := &Decryptor{
+			maxFileSize:       1 << 10,
+			maxBinaryFileSize: 1 << 30,
+			keyring:           keyring{ageIdentities: age.ParsedIdentities{id}},
+		}
+		encData, err := d.sopsEncryptWithFormat(metadata, data, formats.Binary, formats.Binary)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		path := filepath.Join(t.TempDir(), "keystore.jks")
+		g.Expect(os.WriteFile(path, encData, 0o600)).To(Succeed())
+		g.Expect(d.sopsDecryptFile(path, formats.Binary, formats.Binary)).To(Succeed())
+		out, err := os.ReadFile(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(bytes.Equal(out, data)).To(BeTrue())
+
+		g.Expect(os.WriteFile(path, encData, 0o600)).To(Succeed())
+		d.maxBinaryFileSize = 1 << 10
+		g.Expect(d.sopsDecryptFile(path, formats.Binary, formats.Binary)).To(
+			MatchError(ContainSubstring("exceeding limit (1024)")))
+	})
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/cmd/sops/common"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/config"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+// sopsConfigFileName is the name of the SOPS configuration file
+// holding the creation rules of the encrypted files.
+const sopsConfigFileName = ".sops.yaml"
+
+// CheckRecipients checks that the SOPS encrypted resources, generator
+// sources, patches and transformer configurations files of the Kustomization
+// file in the directory at the provided path, and of the resources and
+// components it refers to recursively, are encrypted only to the keys of the
+// creation rule matching them in the closest .sops.yaml file of the root.
+// It must be called before the files are decrypted. It returns one sorted
+// '<file>: encrypted to keys not in the creation rule of <.sops.yaml>: <keys>'
+// line per file encrypted to other keys, with the paths relative to the root.
+// The files which can't be parsed, and the files without a matching creation
+// rule with key groups, are not checked.
+func (d *Decryptor) CheckRecipients(path string) []string {
+	if d.kustomization.Spec.Decryption == nil ||
+		d.kustomization.Spec.Decryption.Provider != DecryptionProviderSOPS {
+		return nil
+	}
+
+	c := &recipientsChecker{
+		root:     d.root,
+		maxSize:  d.maxFileSize,
+		exclude:  d.excludePaths,
+		checked:  make(map[string]struct{}),
+		configs:  make(map[string]string),
+		mismatch: make(map[string]string),
+	}
+	_ = recurseKustomizationFiles(d.root, path, c.visit, make(map[string]struct{}))
+
+	var out []string
+	for _, line := range c.mismatch {
+		out = append(out, line)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// recipientsChecker implements CheckRecipients.
+type recipientsChecker struct {
+	root    string
+	maxSize int64
+	exclude []string
+	// checked holds the absolute paths of the checked files.
+	checked map[string]struct{}
+	// configs caches the absolute path of the closest .sops.yaml
+	// of the directories, empty if there is none.
+	configs map[string]string
+	// mismatch holds the warnings indexed by the absolute file path.
+	mismatch map[string]string
+}
+
+func (c *recipientsChecker) visit(root, path string, kus *kustypes.Kustomization) error {
+	visitRef := func(ref string, format formats.Format) error {
+		if isRemoteURL(ref) {
+			return nil
+		}
+		if !filepath.IsAbs(ref) {
+			ref = filepath.Join(path, ref)
+		}
+		c.check(ref, format)
+		return nil
+	}
+	for _, res := range kus.Resources {
+		_ = visitRef(res, formatForPath(res))
+	}
+	return visitKustomizationSources(kus, visitRef)
+}
+
+// check records a warning if the file at the given path is encrypted
+// to keys not in the matching creation rule.
+func (c *recipientsChecker) check(path string, format formats.Format) {
+	absPath, relPath, err := securePaths(c.root, path)
+	if err != nil || format == formats.Binary || isExcludedPath(c.exclude, relPath) {
+		return
+	}
+	if _, ok := c.checked[absPath]; ok {
+		return
+	}
+	c.checked[absPath] = struct{}{}
+
+	fi, err := os.Lstat(absPath)
+	if err != nil || !fi.Mode().IsRegular() || (c.maxSize > 0 && fi.Size() > c.maxSize) {
+		return
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil || !bytes.Contains(data, sopsFormatToMarkerBytes[format]) {
+		return
+	}
+	metadata, ok := loadSOPSMetadata(data, format)
+	if !ok {
+		return
+	}
+
+	confPath := c.configFor(filepath.Dir(absPath))
+	if confPath == "" {
+		return
+	}
+	rule, err := config.LoadCreationRuleForFile(confPath, absPath, nil)
+	if err != nil || rule == nil || len(rule.KeyGroups) == 0 {
+		return
+	}
+
+	expected := make(map[string]struct{})
+	for _, group := range rule.KeyGroups {
+		for _, key := range group {
+			expected[masterKeyID(key)] = struct{}{}
+		}
+	}
+	var unexpected []string
+	for _, group := range metadata.KeyGroups {
+		for _, key := range group {
+			id := masterKeyID(key)
+			if _, ok := expected[id]; !ok && !slices.Contains(unexpected, id) {
+				unexpected = append(unexpected, id)
+			}
+		}
+	}
+	if len(unexpected) == 0 {
+		return
+	}
+	slices.Sort(unexpected)
+	c.mismatch[absPath] = fmt.Sprintf("%s: encrypted to keys not in the creation rule of %s: %s",
+		relPath, stripRoot(c.root, confPath), strings.Join(unexpected, ","))
+}
+
+// configFor returns the absolute path of the closest .sops.yaml file
+// of the given directory and its parents inside the root, or an empty
+// string if there is none.
+func (c *recipientsChecker) configFor(dir string) string {
+	if confPath, ok := c.configs[dir]; ok {
+		return confPath
+	}
+
+	var confPath string
+	candidate, _, err := securePaths(c.root, filepath.Join(dir, sopsConfigFileName))
+	if err == nil {
+		fi, err := os.Lstat(candidate)
+		if err == nil && fi.Mode().IsRegular() && (c.maxSize <= 0 || fi.Size() <= c.maxSize) {
+			confPath = candidate
+		}
+	}
+	if parent := filepath.Dir(dir); confPath == "" && parent != dir && stripRoot(c.root, dir) != "." {
+		confPath = c.configFor(parent)
+	}
+	c.configs[dir] = confPath
+	return confPath
+}
+
+// loadSOPSMetadata returns the SOPS metadata of the given encrypted data.
+func loadSOPSMetadata(data []byte, format formats.Format) (metadata sops.Metadata, ok bool) {
+	defer func() {
+		// Malicious input can make SOPS panic, see sopsDecryptWithFormat.
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+
+	store := common.StoreForFormat(format, config.NewStoresConfig())
+	tree, err := store.LoadEncryptedFile(data)
+	if err != nil {
+		return sops.Metadata{}, false
+	}
+	return tree.Metadata, true
+}
+
+// masterKeyID returns the identifier of the given master key, in the
+// '<type>:<key>' format of SOPSMetadata.Keys, normalized for comparison.
+func masterKeyID(key interface {
+	TypeToIdentifier() string
+	ToString() string
+}) string {
+	return fmt.Sprintf("%s:%s", key.TypeToIdentifier(),
+		strings.ToLower(strings.ReplaceAll(key.ToString(), " ", "")))
+}
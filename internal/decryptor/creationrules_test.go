@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	extage "filippo.io/age"
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	. "github.com/onsi/gomega"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestDecryptor_CheckRecipients(t *testing.T) {
+	g := NewWithT(t)
+
+	var recipients []string
+	for range 3 {
+		id, err := extage.GenerateX25519Identity()
+		g.Expect(err).ToNot(HaveOccurred())
+		recipients = append(recipients, id.Recipient().String())
+	}
+	policy, apps, other := recipients[0], recipients[1], recipients[2]
+
+	root := t.TempDir()
+	d := &Decryptor{
+		root: root,
+		kustomization: &kustomizev1.Kustomization{
+			Spec: kustomizev1.KustomizationSpec{
+				Decryption: &kustomizev1.Decryption{Provider: DecryptionProviderSOPS},
+			},
+		},
+		excludePaths: []string{"excluded.yaml"},
+	}
+
+	encrypt := func(name string, data []byte, recipients ...string) {
+		t.Helper()
+		var group sops.KeyGroup
+		for _, r := range recipients {
+			group = append(group, &age.MasterKey{Recipient: r})
+		}
+		format := formats.FormatForPath(name)
+		out, err := d.sopsEncryptWithFormat(sops.Metadata{KeyGroups: []sops.KeyGroup{group}}, data, format, format)
+		g.Expect(err).ToNot(HaveOccurred())
+		writeTestFile(t, filepath.Join(root, name), out)
+	}
+
+	writeTestFile(t, filepath.Join(root, ".sops.yaml"), []byte(fmt.Sprintf(`creation_rules:
+  - path_regex: ^apps/
+    age: %s
+  - age: %s
+`, apps, policy)))
+	writeTestFile(t, filepath.Join(root, "kustomization.yaml"), []byte(`resources:
+  - secret.yaml
+  - mixed.yaml
+  - excluded.yaml
+  - plain.yaml
+  - apps
+secretGenerator:
+  - name: app
+    envs:
+      - app.env
+`))
+	writeTestFile(t, filepath.Join(root, "plain.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\n"))
+	encrypt("secret.yaml", []byte("apiVersion: v1\nkind: Secret\n"), policy)
+	encrypt("mixed.yaml", []byte("apiVersion: v1\nkind: Secret\n"), policy, other)
+	encrypt("excluded.yaml", []byte("apiVersion: v1\nkind: Secret\n"), other)
+	encrypt("app.env", []byte("key=value\n"), other)
+	writeTestFile(t, filepath.Join(root, "apps", "kustomization.yaml"), []byte("resources:\n  - secret.yaml\n  - nested\n"))
+	encrypt("apps/secret.yaml", []byte("apiVersion: v1\nkind: Secret\n"), policy)
+	// The closest .sops.yaml takes precedence.
+	writeTestFile(t, filepath.Join(root, "apps", "nested", ".sops.yaml"), []byte(fmt.Sprintf("creation_rules:\n  - age: %s\n", other)))
+	writeTestFile(t, filepath.Join(root, "apps", "nested", "kustomization.yaml"), []byte("resources:\n  - secret.yaml\n"))
+	encrypt("apps/nested/secret.yaml", []byte("apiVersion: v1\nkind: Secret\n"), other)
+
+	g.Expect(d.CheckRecipients(root)).To(Equal([]string{
+		fmt.Sprintf("app.env: encrypted to keys not in the creation rule of .sops.yaml: age:%s", other),
+		fmt.Sprintf("apps/secret.yaml: encrypted to keys not in the creation rule of .sops.yaml: age:%s", policy),
+		fmt.Sprintf("mixed.yaml: encrypted to keys not in the creation rule of .sops.yaml: age:%s", other),
+	}))
+
+	t.Run("without creation rules", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(os.Remove(filepath.Join(root, ".sops.yaml"))).To(Succeed())
+		g.Expect(d.CheckRecipients(root)).To(BeEmpty())
+	})
+
+	t.Run("without the SOPS provider", func(t *testing.T) {
+		g := NewWithT(t)
+		d := &Decryptor{root: root, kustomization: &kustomizev1.Kustomization{}}
+		g.Expect(d.CheckRecipients(root)).To(BeEmpty())
+	})
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -19,13 +19,17 @@ package decryptor
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
+	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -33,7 +37,6 @@ import (
 	"time"
 
 	gcpkmsapi "cloud.google.com/go/kms/apiv1"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/fluxcd/pkg/auth"
@@ -44,7 +47,6 @@ import (
 	"github.com/fluxcd/pkg/cache"
 	"github.com/getsops/sops/v3"
 	"github.com/getsops/sops/v3/aes"
-	"github.com/getsops/sops/v3/age"
 	"github.com/getsops/sops/v3/cmd/sops/common"
 	"github.com/getsops/sops/v3/cmd/sops/formats"
 	"github.com/getsops/sops/v3/config"
@@ -64,6 +66,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/health"
 	intawskms "github.com/fluxcd/kustomize-controller/internal/sops/awskms"
 	intazkv "github.com/fluxcd/kustomize-controller/internal/sops/azkv"
 	intkeyservice "github.com/fluxcd/kustomize-controller/internal/sops/keyservice"
@@ -72,6 +75,9 @@ import (
 const (
 	// DecryptionProviderSOPS is the SOPS provider name.
 	DecryptionProviderSOPS = "sops"
+	// DecryptionProviderPlaceholder is the name of the provider resolving
+	// the placeholders of the Secrets from external secret stores.
+	DecryptionProviderPlaceholder = "placeholder"
 	// DecryptionPGPExt is the extension of the file containing an armored PGP
 	// key.
 	DecryptionPGPExt = ".asc"
@@ -81,6 +87,13 @@ const (
 	// DecryptionVaultTokenFileName is the name of the file containing the
 	// OpenBao/Vault token.
 	DecryptionVaultTokenFileName = "sops.vault-token"
+	// DecryptionVaultNamespaceFileName is the name of the file containing
+	// the OpenBao/Vault Enterprise namespace of the transit engine.
+	DecryptionVaultNamespaceFileName = "sops.vault-namespace"
+	// DecryptionVaultTransitPathFileName is the name of the file containing
+	// the mount path of the OpenBao/Vault transit engine, overriding the
+	// engine path of the SOPS metadata.
+	DecryptionVaultTransitPathFileName = "sops.vault-transit-path"
 	// DecryptionAWSKmsFile is the name of the file containing the AWS KMS
 	// credentials.
 	DecryptionAWSKmsFile = "sops.aws-kms"
@@ -90,6 +103,16 @@ const (
 	// DecryptionGCPCredsFile is the name of the file containing the GCP
 	// credentials.
 	DecryptionGCPCredsFile = "sops.gcp-kms"
+	// DecryptionGCPImpersonateFile is the name of the file containing the
+	// email of the GCP service account impersonated to access GCP KMS.
+	DecryptionGCPImpersonateFile = "sops.gcp-kms-impersonate"
+	// DecryptionCABundleFile is the name of the file containing the PEM
+	// encoded CA certificates trusted for the requests to the key management
+	// services, in addition to the system certificates.
+	DecryptionCABundleFile = "sops.ca-bundle"
+	// DecryptionProxyURLFile is the name of the file containing the URL of
+	// the HTTP/S proxy for the requests to the key management services.
+	DecryptionProxyURLFile = "sops.proxy-url"
 	// maxEncryptedFileSize is the max allowed file size in bytes of an encrypted
 	// file.
 	maxEncryptedFileSize int64 = 5 << 20
@@ -120,9 +143,11 @@ var (
 	}
 )
 
-// Decryptor performs decryption operations for a v1.Kustomization.
-// The only supported decryption provider at present is
-// DecryptionProviderSOPS.
+// Decryptor performs decryption operations for a v1.Kustomization, by
+// delegating them to the Provider registered with the provider name of its
+// v1.Decryption spec. The built-in decryption providers are
+// DecryptionProviderSOPS, DecryptionProviderPlaceholder and
+// DecryptionProviderSealedSecrets.
 type Decryptor struct {
 	// root is the root for file system operations. Any (relative) path or
 	// symlink is not allowed to traverse outside this path.
@@ -132,9 +157,19 @@ type Decryptor struct {
 	// kustomization is the v1.Kustomization we are decrypting for.
 	// The v1.Decryption of the object is used to ImportKeys().
 	kustomization *kustomizev1.Kustomization
+	// provider is the Provider the decryption operations are delegated to,
+	// nil if the Kustomization has no registered decryption provider.
+	provider Provider
+	// excludePaths are the cleaned glob patterns of the paths relative to
+	// root of the files which are not decrypted.
+	excludePaths []string
 	// maxFileSize is the max size in bytes a file is allowed to have to be
 	// decrypted. Defaults to maxEncryptedFileSize.
 	maxFileSize int64
+	// maxBinaryFileSize is the max size in bytes a file in the binary format
+	// is allowed to have to be decrypted, as it is decrypted without being
+	// loaded as a SOPS tree. Defaults to maxEncryptedBinaryFileSize.
+	maxBinaryFileSize int64
 	// checkSopsMac instructs the decryptor to perform the SOPS data integrity
 	// check using the MAC. Not enabled by default, as arbitrary data gets
 	// injected into most resources, causing the integrity check to fail.
@@ -143,15 +178,16 @@ type Decryptor struct {
 	// tokenCache is the cache for token credentials.
 	tokenCache *cache.TokenCache
 
-	// gnuPGHome is the absolute path of the GnuPG home directory used to
-	// decrypt PGP data. When empty, the systems' GnuPG keyring is used.
-	// When set, ImportKeys() imports found PGP keys into this keyring.
-	gnuPGHome pgp.GnuPGHome
-	// ageIdentities is the set of age identities available to the decryptor.
-	ageIdentities age.ParsedIdentities
-	// vaultToken is the OpenBao/Vault token used to authenticate towards
-	// any Vault server.
-	vaultToken string
+	// keyring holds the keys and credentials imported by ImportKeys.
+	keyring
+	// keyrings caches the keyrings imported from the decryption Secrets
+	// for keyringsTTL, so that they are not imported by each Decryptor.
+	keyrings    *KeyringCache
+	keyringsTTL time.Duration
+	// cachedKeyring is the keyring of the keyrings in use by the Decryptor,
+	// released by the cleanup function returned by New.
+	cachedKeyring *cachedKeyring
+
 	// vaultK8sAuth obtains an OpenBao/Vault token for the Vault server
 	// at the given address using the Kubernetes auth method, by exchanging a
 	// token of the decryption ServiceAccount. It is only set when no static
@@ -162,15 +198,6 @@ type Decryptor struct {
 	// vaultK8sAuth. It also acts as an allowlist of trusted Vault servers.
 	// When nil, Vault ServiceAccount-token authentication is disabled.
 	vaultConfigMap *types.NamespacedName
-	// awsCredentialsProvider is the AWS credentials provider object used to authenticate
-	// towards any AWS KMS.
-	awsCredentialsProvider func(region string) awssdk.CredentialsProvider
-	// azureTokenCredential is the Azure credential token used to authenticate towards
-	// any Azure Key Vault.
-	azureTokenCredential azcore.TokenCredential
-	// gcpTokenSource is the GCP token source used to authenticate towards
-	// any GCP KMS.
-	gcpTokenSource oauth2.TokenSource
 
 	// keyServices are the SOPS keyservice.KeyServiceClient's available to the
 	// decryptor.
@@ -180,6 +207,29 @@ type Decryptor struct {
 	// sopsAgeSecret is the NamespacedName of the Secret containing
 	// a fallback SOPS age decryption key.
 	sopsAgeSecret *types.NamespacedName
+
+	// secretCache holds the last version read of the decryption Secrets,
+	// used when a Secret can't be read due to a transient error.
+	secretCache *SecretCache
+	// keysSecret is the Secret the keys were imported from by ImportKeys.
+	keysSecret *KeysSecret
+
+	// health records the outcome of the data key retrievals per
+	// SOPS key provider, e.g. to surface unreachable KMS services.
+	health *health.Tracker
+
+	// metadata holds the SOPS metadata of the objects decrypted
+	// by DecryptResource, indexed by their inventory ID.
+	metadata   map[string]*SOPSMetadata
+	metadataMu sync.Mutex
+
+	// placeholders holds the values resolved by resolvePlaceholders,
+	// indexed by placeholder, so that each value is fetched once.
+	placeholders map[string]string
+
+	// ctx is the context of the requests to the key management services.
+	// When nil, the requests are issued with a background context.
+	ctx context.Context
 }
 
 // New creates a new Decryptor, with a temporary GnuPG
@@ -189,16 +239,38 @@ func New(client client.Client, kustomization *kustomizev1.Kustomization, opts ..
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot create decryptor: %w", err)
 	}
-	cleanup := func() { _ = os.RemoveAll(gnuPGHome.String()) }
 	d := &Decryptor{
-		client:        client,
-		kustomization: kustomization,
-		maxFileSize:   maxEncryptedFileSize,
-		gnuPGHome:     gnuPGHome,
+		client:            client,
+		kustomization:     kustomization,
+		maxFileSize:       maxEncryptedFileSize,
+		maxBinaryFileSize: maxEncryptedBinaryFileSize,
+		keyring:           keyring{gnuPGHome: gnuPGHome},
 	}
 	for _, opt := range opts {
 		opt(d)
 	}
+	if dec := kustomization.Spec.Decryption; dec != nil {
+		d.provider = newProvider(dec.Provider, d)
+		for _, pattern := range dec.ExcludePaths {
+			pattern = path.Clean(filepath.ToSlash(pattern))
+			if _, err := path.Match(pattern, ""); err != nil {
+				_ = os.RemoveAll(gnuPGHome.String())
+				return nil, nil, fmt.Errorf("invalid decryption exclude path '%s': %w", pattern, err)
+			}
+			d.excludePaths = append(d.excludePaths, pattern)
+		}
+	}
+	cleanup := func() {
+		// The GnuPG home directory of a cached keyring is removed by the
+		// cache once the keyring is evicted and no longer in use.
+		if ck := d.cachedKeyring; ck != nil {
+			d.keyrings.release(ck)
+			if ck.gnuPGHome == gnuPGHome {
+				return
+			}
+		}
+		_ = os.RemoveAll(gnuPGHome.String())
+	}
 	return d, cleanup, nil
 }
 
@@ -219,8 +291,29 @@ func IsEncryptedSecret(object *unstructured.Unstructured) bool {
 	return false
 }
 
-// ImportKeys imports the DecryptionProviderSOPS keys from the data values of
-// the Secret referenced in the Kustomization's v1.Decryption spec.
+// IsUndecryptedResource checks if the given resource still holds SOPS
+// encrypted data: the SOPS metadata of an encrypted resource, or a data
+// entry of a Kubernetes Secret encrypted with SOPS.
+func IsUndecryptedResource(res *resource.Resource) bool {
+	if res == nil {
+		return false
+	}
+	if !res.Field("sops").IsNilOrEmpty() {
+		return true
+	}
+	if res.GetKind() == "Secret" {
+		for _, value := range res.GetDataMap() {
+			data, err := base64.StdEncoding.DecodeString(value)
+			if err == nil && detectFormatFromMarkerBytes(data) != unsupportedFormat {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ImportKeys imports the keys of the decryption provider from the data
+// values of the Secret referenced in the Kustomization's v1.Decryption spec.
 // It returns an error if the Secret cannot be retrieved, or if one of the
 // imports fails.
 // Imports do not have an effect after the first call to SopsDecryptWithFormat(),
@@ -228,105 +321,206 @@ func IsEncryptedSecret(object *unstructured.Unstructured) bool {
 // For the import of PGP keys, the Decryptor must be configured with
 // an absolute GnuPG home directory path.
 func (d *Decryptor) ImportKeys(ctx context.Context) error {
-	if d.kustomization.Spec.Decryption == nil ||
+	if d.provider == nil ||
 		(d.kustomization.Spec.Decryption.SecretRef == nil && d.sopsAgeSecret == nil) {
 		return nil
 	}
 
 	provider := d.kustomization.Spec.Decryption.Provider
-	switch provider {
-	case DecryptionProviderSOPS:
-		secretRef := d.kustomization.Spec.Decryption.SecretRef
-
-		// We handle the SOPS age global decryption separately, as most of the other
-		// decryption providers already support global decryption in other ways, and
-		// we don't want to introduce duplicate methods of achieving the same.
-		// Furthermore, allowing e.g. cloud provider credentials to be fetched
-		// from this global secret would prevent workload identity from working.
-		if secretRef == nil && d.sopsAgeSecret != nil {
-			var secret corev1.Secret
-			if err := d.client.Get(ctx, *d.sopsAgeSecret, &secret); err != nil {
-				if apierrors.IsNotFound(err) {
-					return err
-				}
-				return fmt.Errorf("cannot get %s SOPS age decryption Secret '%s': %w", provider, *d.sopsAgeSecret, err)
-			}
-			for name, value := range secret.Data {
-				if filepath.Ext(name) == DecryptionAgeExt {
-					if err := d.ageIdentities.Import(string(value)); err != nil {
-						return fmt.Errorf("failed to import '%s' data from %s SOPS age decryption Secret '%s': %w",
-							name, provider, *d.sopsAgeSecret, err)
-					}
-				}
+	secretRef := d.kustomization.Spec.Decryption.SecretRef
+
+	// We handle the SOPS age global decryption separately, as most of the other
+	// decryption providers already support global decryption in other ways, and
+	// we don't want to introduce duplicate methods of achieving the same.
+	// Furthermore, allowing e.g. cloud provider credentials to be fetched
+	// from this global secret would prevent workload identity from working.
+	if secretRef == nil && d.sopsAgeSecret != nil {
+		secret, err := d.getKeysSecret(ctx, *d.sopsAgeSecret)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return err
 			}
-			return nil
+			return fmt.Errorf("cannot get %s SOPS age decryption Secret '%s': %w", provider, *d.sopsAgeSecret, err)
 		}
+		ageSecret := secret.DeepCopy()
+		maps.DeleteFunc(ageSecret.Data, func(name string, _ []byte) bool {
+			return filepath.Ext(name) != DecryptionAgeExt
+		})
+		return d.provider.ImportKeys(ctx, ageSecret)
+	}
 
-		secretName := types.NamespacedName{
-			Namespace: d.kustomization.GetNamespace(),
-			Name:      secretRef.Name,
-		}
+	secretName := types.NamespacedName{
+		Namespace: d.kustomization.GetNamespace(),
+		Name:      secretRef.Name,
+	}
 
-		var secret corev1.Secret
-		if err := d.client.Get(ctx, secretName, &secret); err != nil {
-			if apierrors.IsNotFound(err) {
-				return err
-			}
-			return fmt.Errorf("cannot get %s decryption Secret '%s': %w", provider, secretName, err)
+	secret, err := d.getKeysSecret(ctx, secretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return err
 		}
+		return fmt.Errorf("cannot get %s decryption Secret '%s': %w", provider, secretName, err)
+	}
+	return d.provider.ImportKeys(ctx, secret)
+}
+
+// importSecretKeys imports the keys and the key management services
+// credentials of the given decryption Secret, shared by the built-in
+// providers.
+func (d *Decryptor) importSecretKeys(ctx context.Context, secret *corev1.Secret) error {
+	if d.keyrings == nil || secret.ResourceVersion == "" {
+		return d.importKeyring(ctx, secret)
+	}
+
+	key := newKeyringKey(secret)
+	if ck, ok := d.keyrings.acquire(key, secret.ResourceVersion); ok {
+		d.useCachedKeyring(ck)
+		return nil
+	}
+	if err := d.importKeyring(ctx, secret); err != nil {
+		return err
+	}
+	d.useCachedKeyring(d.keyrings.store(key, secret.ResourceVersion, d.keyringsTTL, d.keyring))
+	return nil
+}
+
+// importKeyring imports the keys and the key management services
+// credentials of the given decryption Secret into the keyring.
+func (d *Decryptor) importKeyring(ctx context.Context, secret *corev1.Secret) error {
+	provider := d.kustomization.Spec.Decryption.Provider
+	secretName := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
 
-		var err error
-		for name, value := range secret.Data {
-			switch filepath.Ext(name) {
-			case DecryptionPGPExt:
-				if err = d.gnuPGHome.Import(value); err != nil {
+	// The HTTP client is configured first, as the static credentials
+	// imported below use it to obtain their tokens.
+	if err := d.importHTTPClient(secret); err != nil {
+		return fmt.Errorf("failed to import HTTP client settings from %s decryption Secret '%s': %w",
+			provider, secretName, err)
+	}
+
+	var err error
+	for name, value := range secret.Data {
+		switch filepath.Ext(name) {
+		case DecryptionPGPExt:
+			if err = d.gnuPGHome.Import(value); err != nil {
+				return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
+			}
+			entities := readPGPKeyRing(value)
+			for _, e := range entities {
+				d.pgpFingerprints = append(d.pgpFingerprints, pgpFingerprint(e))
+			}
+			d.keyExpiries = append(d.keyExpiries, pgpKeyExpiries(name, entities)...)
+		case DecryptionAgeExt:
+			if err = d.importAgeIdentities(string(value)); err != nil {
+				return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
+			}
+		case filepath.Ext(DecryptionVaultTokenFileName):
+			if name == DecryptionVaultTokenFileName {
+				token := string(value)
+				token = strings.Trim(strings.TrimSpace(token), "\n")
+				d.vaultToken = token
+			}
+		case filepath.Ext(DecryptionVaultNamespaceFileName):
+			if name == DecryptionVaultNamespaceFileName {
+				d.vaultNamespace = strings.TrimSpace(string(value))
+			}
+		case filepath.Ext(DecryptionVaultTransitPathFileName):
+			if name == DecryptionVaultTransitPathFileName {
+				d.vaultTransitPath = strings.Trim(strings.TrimSpace(string(value)), "/")
+			}
+		case filepath.Ext(DecryptionAWSKmsFile):
+			if name == DecryptionAWSKmsFile {
+				awsCreds, err := intawskms.LoadStaticCredentialsFromYAML(value)
+				if err != nil {
 					return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
 				}
-			case DecryptionAgeExt:
-				if err = d.ageIdentities.Import(string(value)); err != nil {
+				d.awsCredentialsProvider = func(string) awssdk.CredentialsProvider { return awsCreds }
+				if awsCreds.Value.CanExpire {
+					d.keyExpiries = append(d.keyExpiries, KeyExpiry{
+						Key:     "aws_kms:" + awsCreds.Value.AccessKeyID,
+						Entry:   name,
+						Expires: awsCreds.Value.Expires,
+					})
+				}
+			}
+		case filepath.Ext(DecryptionAzureAuthFile):
+			if name == DecryptionAzureAuthFile {
+				conf := intazkv.AADConfig{}
+				if err = intazkv.LoadAADConfigFromBytes(value, &conf); err != nil {
 					return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
 				}
-			case filepath.Ext(DecryptionVaultTokenFileName):
-				if name == DecryptionVaultTokenFileName {
-					token := string(value)
-					token = strings.Trim(strings.TrimSpace(token), "\n")
-					d.vaultToken = token
+				azureToken, err := intazkv.TokenCredentialFromAADConfig(conf, d.httpClient)
+				if err != nil {
+					return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
 				}
-			case filepath.Ext(DecryptionAWSKmsFile):
-				if name == DecryptionAWSKmsFile {
-					awsCreds, err := intawskms.LoadStaticCredentialsFromYAML(value)
-					if err != nil {
-						return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
-					}
-					d.awsCredentialsProvider = func(string) awssdk.CredentialsProvider { return awsCreds }
+				d.azureTokenCredential = azureToken
+			}
+		case filepath.Ext(DecryptionGCPCredsFile):
+			if name == DecryptionGCPCredsFile {
+				credsCtx := ctx
+				if d.keyrings != nil {
+					// The token source of a cached keyring outlives the reconciliation.
+					credsCtx = context.WithoutCancel(ctx)
 				}
-			case filepath.Ext(DecryptionAzureAuthFile):
-				if name == DecryptionAzureAuthFile {
-					conf := intazkv.AADConfig{}
-					if err = intazkv.LoadAADConfigFromBytes(value, &conf); err != nil {
-						return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
-					}
-					azureToken, err := intazkv.TokenCredentialFromAADConfig(conf)
-					if err != nil {
-						return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
-					}
-					d.azureTokenCredential = azureToken
+				if d.httpClient != nil {
+					credsCtx = context.WithValue(credsCtx, oauth2.HTTPClient, d.httpClient)
 				}
-			case filepath.Ext(DecryptionGCPCredsFile):
-				if name == DecryptionGCPCredsFile {
-					creds, err := google.CredentialsFromJSON(ctx,
-						bytes.Trim(value, "\n"), gcpkmsapi.DefaultAuthScopes()...)
-					if err != nil {
-						return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
-					}
-					d.gcpTokenSource = creds.TokenSource
+				creds, err := google.CredentialsFromJSON(credsCtx,
+					bytes.Trim(value, "\n"), gcpkmsapi.DefaultAuthScopes()...)
+				if err != nil {
+					return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
+				}
+				d.gcpTokenSource = creds.TokenSource
+			}
+		case filepath.Ext(DecryptionGCPImpersonateFile):
+			if name == DecryptionGCPImpersonateFile {
+				email, err := parseGCPServiceAccountEmail(value)
+				if err != nil {
+					return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s': %w", name, provider, secretName, err)
 				}
+				d.gcpImpersonateServiceAccount = email
 			}
 		}
 	}
 	return nil
 }
 
+// importHTTPClient configures the HTTP client of the Decryptor from the
+// DecryptionCABundleFile and DecryptionProxyURLFile data of the given Secret.
+// The client is left unset when the Secret contains neither.
+func (d *Decryptor) importHTTPClient(secret *corev1.Secret) error {
+	caBundle, hasCABundle := secret.Data[DecryptionCABundleFile]
+	proxyURL, hasProxyURL := secret.Data[DecryptionProxyURLFile]
+	if !hasCABundle && !hasProxyURL {
+		return nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if hasCABundle {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return fmt.Errorf("no valid PEM certificates found in '%s'", DecryptionCABundleFile)
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	if hasProxyURL {
+		u, err := url.Parse(strings.TrimSpace(string(proxyURL)))
+		if err != nil {
+			// The error is not wrapped, as it may contain the proxy credentials.
+			return fmt.Errorf("invalid proxy URL in '%s'", DecryptionProxyURLFile)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL in '%s': a scheme and host are required", DecryptionProxyURLFile)
+		}
+		transport.Proxy = http.ProxyURL(u)
+		d.proxyURL = u
+	}
+	d.httpClient = &http.Client{Transport: transport}
+	return nil
+}
+
 // SetAuthOptions sets the authentication options for secret-less authentication
 // with cloud providers.
 func (d *Decryptor) SetAuthOptions(ctx context.Context) {
@@ -335,10 +529,13 @@ func (d *Decryptor) SetAuthOptions(ctx context.Context) {
 	}
 
 	switch d.kustomization.Spec.Decryption.Provider {
-	case DecryptionProviderSOPS:
+	case DecryptionProviderSOPS, DecryptionProviderPlaceholder:
 		opts := []auth.Option{
 			auth.WithClient(d.client),
 		}
+		if d.proxyURL != nil {
+			opts = append(opts, auth.WithProxyURL(*d.proxyURL))
+		}
 
 		saName := d.kustomization.Spec.Decryption.ServiceAccountName
 		if saName == "" {
@@ -386,6 +583,13 @@ func (d *Decryptor) SetAuthOptions(ctx context.Context) {
 			d.gcpTokenSource = gcp.NewTokenSource(ctx, gcpOpts...)
 		}
 
+		// Impersonate the GCP service account of the decryption Secret with
+		// the static credentials or the workload identity, so that a single
+		// identity can be scoped to the service account of each tenant.
+		if sa := d.gcpImpersonateServiceAccount; sa != "" {
+			d.gcpTokenSource = newGCPImpersonatedTokenSource(ctx, d.gcpTokenSource, sa)
+		}
+
 		// Configure OpenBao/Vault ServiceAccount-token auth. Unlike a
 		// static sops.vault-token (which takes precedence and is imported in
 		// ImportKeys), this exchanges a Kubernetes ServiceAccount token for a
@@ -457,7 +661,7 @@ func (d *Decryptor) SetAuthOptions(ctx context.Context) {
 				role := fmt.Sprintf("%s_%s", ns, name)
 
 				// Exchange the Kubernetes token for a Vault token.
-				token, err := vaultLogin(ctx, vaultAddress, loginPath, role, ksaToken.Token)
+				token, err := vaultLogin(ctx, d.httpClient, vaultAddress, loginPath, role, ksaToken.Token)
 				if err != nil {
 					return "", err
 				}
@@ -536,10 +740,13 @@ func (d *Decryptor) loadVaultLoginPaths(ctx context.Context) (map[string]string,
 // role, and returns the resulting Vault token. The login path is used verbatim,
 // so any JWT-backed auth method is supported. TLS and proxy settings are read
 // from the standard VAULT_* environment variables, with the address overridden
-// by vaultAddress.
-func vaultLogin(ctx context.Context, vaultAddress, loginPath, role, jwt string) (string, error) {
+// by vaultAddress, and the HTTP client overridden by hc when not nil.
+func vaultLogin(ctx context.Context, hc *http.Client, vaultAddress, loginPath, role, jwt string) (string, error) {
 	cfg := vaultapi.DefaultConfig()
 	cfg.Address = vaultAddress
+	if hc != nil {
+		cfg.HttpClient = hc
+	}
 	client, err := vaultapi.NewClient(cfg)
 	if err != nil {
 		return "", fmt.Errorf("failed to create Vault client for '%s': %w", vaultAddress, err)
@@ -560,7 +767,14 @@ func vaultLogin(ctx context.Context, vaultAddress, loginPath, role, jwt string)
 // for the input format, gathers the data key for it from the key service,
 // and then decrypts the file data with the retrieved data key.
 // It returns the decrypted bytes in the provided output format, or an error.
-func (d *Decryptor) SopsDecryptWithFormat(data []byte, inputFormat, outputFormat formats.Format) (_ []byte, err error) {
+func (d *Decryptor) SopsDecryptWithFormat(data []byte, inputFormat, outputFormat formats.Format) ([]byte, error) {
+	out, _, err := d.sopsDecryptWithFormat(data, inputFormat, outputFormat)
+	return out, err
+}
+
+// sopsDecryptWithFormat implements SopsDecryptWithFormat, and returns
+// the SOPS metadata of the decrypted data.
+func (d *Decryptor) sopsDecryptWithFormat(data []byte, inputFormat, outputFormat formats.Format) (_ []byte, _ sops.Metadata, err error) {
 	defer func() {
 		// It was discovered that malicious input and/or output instructions can
 		// make SOPS panic. Recover from this panic and return as an error.
@@ -574,18 +788,19 @@ func (d *Decryptor) SopsDecryptWithFormat(data []byte, inputFormat, outputFormat
 
 	tree, err := store.LoadEncryptedFile(data)
 	if err != nil {
-		return nil, sopsUserErr(fmt.Sprintf("failed to load encrypted %s data", sopsFormatToString[inputFormat]), err)
+		return nil, sops.Metadata{}, sopsUserErr(fmt.Sprintf("failed to load encrypted %s data", sopsFormatToString[inputFormat]), err)
 	}
 
-	metadataKey, err := tree.Metadata.GetDataKeyWithKeyServices(d.keyServiceServer(), sops.DefaultDecryptionOrder)
+	metadataKey, err := d.getDataKey(&tree.Metadata)
+	d.recordKeyProviders(tree.Metadata, err)
 	if err != nil {
-		return nil, sopsUserErr("cannot get sops data key", err)
+		return nil, sops.Metadata{}, sopsUserErr("cannot get sops data key", err)
 	}
 
 	cipher := aes.NewCipher()
 	mac, err := safeDecrypt(tree.Decrypt(metadataKey, cipher))
 	if err != nil {
-		return nil, sopsUserErr("error decrypting sops tree", err)
+		return nil, sops.Metadata{}, sopsUserErr("error decrypting sops tree", err)
 	}
 
 	if d.checkSopsMac {
@@ -599,192 +814,234 @@ func (d *Decryptor) SopsDecryptWithFormat(data []byte, inputFormat, outputFormat
 			tree.Metadata.LastModified.Format(time.RFC3339),
 		))
 		if err != nil {
-			return nil, sopsUserErr("failed to verify sops data integrity", err)
+			return nil, sops.Metadata{}, sopsUserErr("failed to verify sops data integrity", err)
 		}
 		if originalMac != mac {
 			// If the file has an empty MAC, display "no MAC"
 			if originalMac == "" {
 				originalMac = "no MAC"
 			}
-			return nil, fmt.Errorf("failed to verify sops data integrity: expected mac '%s', got '%s'", originalMac, mac)
+			return nil, sops.Metadata{}, fmt.Errorf("failed to verify sops data integrity: expected mac '%s', got '%s'", originalMac, mac)
 		}
 	}
 
 	outputStore := common.StoreForFormat(outputFormat, config.NewStoresConfig())
 	out, err := outputStore.EmitPlainFile(tree.Branches)
 	if err != nil {
-		return nil, sopsUserErr(fmt.Sprintf("failed to emit encrypted %s file as decrypted %s",
+		return nil, sops.Metadata{}, sopsUserErr(fmt.Sprintf("failed to emit encrypted %s file as decrypted %s",
 			sopsFormatToString[inputFormat], sopsFormatToString[outputFormat]), err)
 	}
-	return out, err
+	return out, tree.Metadata, err
+}
+
+// recordKeyProviders records the outcome of the data key retrieval for
+// every key provider the data is encrypted with.
+func (d *Decryptor) recordKeyProviders(metadata sops.Metadata, err error) {
+	if d.health == nil {
+		return
+	}
+	providers := make(map[string]struct{})
+	for _, group := range metadata.KeyGroups {
+		for _, key := range group {
+			providers[key.TypeToIdentifier()] = struct{}{}
+		}
+	}
+	for provider := range providers {
+		d.health.RecordObject(health.SubsystemDecryptionPrefix+provider,
+			client.ObjectKeyFromObject(d.kustomization).String(), err)
+	}
 }
 
 // DecryptResource attempts to decrypt the provided resource with the
 // decryption provider specified on the Kustomization, overwriting the resource
 // with the decrypted data.
-// It has special support for Kubernetes Secrets with encrypted data entries
-// while decrypting with DecryptionProviderSOPS, to allow individual data entries
-// injected by e.g. a Kustomize secret generator to be decrypted
 func (d *Decryptor) DecryptResource(res *resource.Resource) (*resource.Resource, error) {
-	if res == nil ||
-		d.kustomization.Spec.Decryption == nil ||
-		d.kustomization.Spec.Decryption.Provider == "" ||
-		IsDecryptionDisabled(res.GetAnnotations()) {
+	if res == nil || d.provider == nil || IsDecryptionDisabled(res.GetAnnotations()) {
 		return nil, nil
 	}
-
-	switch d.kustomization.Spec.Decryption.Provider {
-	case DecryptionProviderSOPS:
-		switch {
-		case isSOPSEncryptedResource(res):
-			// As we are expecting to decrypt right before applying, we do not
-			// care about keeping any other data (e.g. comments) around.
-			// We can therefore simply work with JSON, which saves us from e.g.
-			// JSON -> YAML -> JSON transformations.
-			out, err := res.MarshalJSON()
-			if err != nil {
-				return nil, err
-			}
-
-			data, err := d.SopsDecryptWithFormat(out, formats.Json, formats.Json)
-			if err != nil {
-				return nil, fmt.Errorf("failed to decrypt and format '%s/%s' %s data: %w",
-					res.GetNamespace(), res.GetName(), res.GetKind(), err)
-			}
-
-			err = res.UnmarshalJSON(data)
-			if err != nil {
-				return nil, fmt.Errorf("failed to unmarshal decrypted '%s/%s' %s to JSON: %w",
-					res.GetNamespace(), res.GetName(), res.GetKind(), err)
-			}
-			return res, nil
-		case res.GetKind() == "Secret":
-			dataMap := res.GetDataMap()
-			for key, value := range dataMap {
-				data, err := base64.StdEncoding.DecodeString(value)
-				if err != nil {
-					// If we fail to base64 decode, it is (very) likely to be a
-					// user input error. Instead of failing here, let it bubble
-					// up during the actual build.
-					continue
-				}
-
-				if inF := detectFormatFromMarkerBytes(data); inF != unsupportedFormat {
-					outF := formatForPath(key)
-					out, err := d.SopsDecryptWithFormat(data, inF, outF)
-					if err != nil {
-						return nil, fmt.Errorf("failed to decrypt and format '%s/%s' Secret field '%s': %w",
-							res.GetNamespace(), res.GetName(), key, err)
-					}
-					dataMap[key] = base64.StdEncoding.EncodeToString(out)
-				}
-			}
-			res.SetDataMap(dataMap)
-			return res, nil
-		}
-	}
-	return nil, nil
+	return d.provider.DecryptResource(res)
 }
 
-// DecryptSources attempts to decrypt all types.SecretArgs FileSources and
-// EnvSources a Kustomization file in the directory at the provided path refers
-// to, before walking recursively over all other resources it refers to.
+// DecryptSources attempts to decrypt all types.SecretArgs and
+// types.ConfigMapArgs FileSources and EnvSources, patches and transformer
+// configurations a Kustomization file in the directory at the provided path
+// refers to, before walking recursively over all other resources and
+// components it refers to.
 // It ignores resource references which refer to absolute or relative paths
 // outside the working directory of the decryptor, but returns any decryption
 // error.
 func (d *Decryptor) DecryptSources(path string) error {
-	if d.kustomization.Spec.Decryption == nil || d.kustomization.Spec.Decryption.Provider != DecryptionProviderSOPS {
+	if d.provider == nil {
 		return nil
 	}
 
 	decrypted, visited := make(map[string]struct{}, 0), make(map[string]struct{}, 0)
-	visit := d.decryptKustomizationSources(decrypted)
+	visit := decryptKustomizationSources(d.provider, d.excludePaths, decrypted)
 	return recurseKustomizationFiles(d.root, path, visit, visited)
 }
 
+// DecryptFiles attempts to decrypt in place the YAML, JSON, dotenv and INI
+// files of the directory at the provided path, walking it recursively, or the
+// file at the provided path. Symlinks and files in other formats are ignored.
+// It returns an error if the path is outside the working directory of the
+// decryptor, or if any of the files fails to be decrypted.
+func (d *Decryptor) DecryptFiles(path string) error {
+	if d.provider == nil {
+		return nil
+	}
+
+	absPath, _, err := securePaths(d.root, path)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(absPath, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return securePathErr(d.root, err)
+		}
+		if isExcludedPath(d.excludePaths, stripRoot(d.root, p)) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		format := formatForPath(p)
+		if format == formats.Binary {
+			return nil
+		}
+		if err := d.provider.DecryptFile(p, format, format); err != nil {
+			return fmt.Errorf("failed to decrypt '%s': %w", stripRoot(d.root, p), securePathErr(d.root, err))
+		}
+		return nil
+	})
+}
+
 // decryptKustomizationSources returns a visitKustomization implementation
-// which attempts to decrypt any EnvSources entry it finds in the Kustomization
-// file with which it is called.
+// which attempts to decrypt with the given Provider the generator sources,
+// patches and transformer configurations files it finds in the Kustomization
+// file with which it is called, except the files matching the exclude
+// patterns.
 // After decrypting successfully, it adds the absolute path of the file to the
 // given map.
-func (d *Decryptor) decryptKustomizationSources(visited map[string]struct{}) visitKustomization {
+func decryptKustomizationSources(provider Provider, exclude []string, visited map[string]struct{}) visitKustomization {
 	return func(root, path string, kus *kustypes.Kustomization) error {
-		visitRef := func(sourcePath string, format formats.Format) error {
+		return visitKustomizationSources(kus, func(sourcePath string, format formats.Format) error {
 			if !filepath.IsAbs(sourcePath) {
 				sourcePath = filepath.Join(path, sourcePath)
 			}
-			absRef, _, err := securePaths(root, sourcePath)
+			absRef, relRef, err := securePaths(root, sourcePath)
 			if err != nil {
 				return err
 			}
 			if _, ok := visited[absRef]; ok {
 				return nil
 			}
-			if err := d.sopsDecryptFile(absRef, format, format); err != nil {
+			if isExcludedPath(exclude, relRef) {
+				return nil
+			}
+			if err := provider.DecryptFile(absRef, format, format); err != nil {
 				return securePathErr(root, err)
 			}
 			// Explicitly set _after_ the decryption operation, this makes
 			// visited work as a list of actually decrypted files
 			visited[absRef] = struct{}{}
 			return nil
-		}
+		})
+	}
+}
 
-		// Iterate over all SecretGenerator entries in the Kustomization file and attempt to decrypt their FileSources and EnvSources.
-		for _, gen := range kus.SecretGenerator {
-			for _, fileSrc := range gen.FileSources {
-				// Split the source path from any associated key, defaulting to the key if not specified.
-				parts := strings.SplitN(fileSrc, "=", 2)
-				key := parts[0]
-				var filePath string
-				if len(parts) > 1 {
-					filePath = parts[1]
-				} else {
-					filePath = key
-				}
-				// Visit the file reference and attempt to decrypt it.
-				if err := visitRef(filePath, formatForPath(key)); err != nil {
-					return err
-				}
+// visitKustomizationSources calls visitRef with the path, relative to the
+// directory of the Kustomization file or absolute, and the format of the
+// generator sources, patches and transformer configurations files of the
+// given Kustomization.
+func visitKustomizationSources(kus *kustypes.Kustomization, visitRef func(sourcePath string, format formats.Format) error) error {
+	visitKvPairSources := func(sources kustypes.KvPairSources) error {
+		for _, fileSrc := range sources.FileSources {
+			// Split the source path from any associated key, defaulting to the key if not specified.
+			parts := strings.SplitN(fileSrc, "=", 2)
+			key := parts[0]
+			var filePath string
+			if len(parts) > 1 {
+				filePath = parts[1]
+			} else {
+				filePath = key
 			}
-			for _, envFile := range gen.EnvSources {
-				// Determine the format for the environment file, defaulting to Dotenv if not specified.
-				format := formatForPath(envFile)
-				if format == formats.Binary {
-					// Default to dotenv
-					format = formats.Dotenv
-				}
-				// Visit the environment file reference and attempt to decrypt it.
-				if err := visitRef(envFile, format); err != nil {
-					return err
-				}
+			// Visit the file reference and attempt to decrypt it.
+			if err := visitRef(filePath, formatForPath(key)); err != nil {
+				return err
 			}
 		}
-		// Iterate over all patches in the Kustomization file and attempt to decrypt their paths if they are encrypted.
-		for _, patch := range kus.Patches {
-			if patch.Path == "" {
-				continue
-			}
-
-			if isRemoteURL(patch.Path) {
-				continue
+		for _, envFile := range sources.EnvSources {
+			// Determine the format for the environment file, defaulting to Dotenv if not specified.
+			format := formatForPath(envFile)
+			if format == formats.Binary {
+				// Default to dotenv
+				format = formats.Dotenv
 			}
-
-			// Determine the format for the patch, defaulting to YAML if not specified.
-			format := formatForPath(patch.Path)
-			// Visit the patch reference and attempt to decrypt it.
-			if err := visitRef(patch.Path, format); err != nil {
+			// Visit the environment file reference and attempt to decrypt it.
+			if err := visitRef(envFile, format); err != nil {
 				return err
 			}
 		}
 		return nil
 	}
+	visitFile := func(filePath string) error {
+		if filePath == "" || isRemoteURL(filePath) {
+			return nil
+		}
+		// Determine the format for the file from its extension.
+		return visitRef(filePath, formatForPath(filePath))
+	}
+
+	// Iterate over all SecretGenerator and ConfigMapGenerator entries in the Kustomization
+	// file and attempt to decrypt their FileSources and EnvSources.
+	for _, gen := range kus.SecretGenerator {
+		if err := visitKvPairSources(gen.KvPairSources); err != nil {
+			return err
+		}
+	}
+	for _, gen := range kus.ConfigMapGenerator {
+		if err := visitKvPairSources(gen.KvPairSources); err != nil {
+			return err
+		}
+	}
+	// Iterate over all patches in the Kustomization file and attempt to decrypt their paths if they are encrypted.
+	for _, patch := range kus.Patches {
+		if err := visitFile(patch.Path); err != nil {
+			return err
+		}
+	}
+	for _, patch := range kus.PatchesJson6902 {
+		if err := visitFile(patch.Path); err != nil {
+			return err
+		}
+	}
+	for _, patch := range kus.PatchesStrategicMerge {
+		// Inline patches span multiple lines, only the file references are decrypted.
+		if strings.Contains(string(patch), "\n") {
+			continue
+		}
+		if err := visitFile(string(patch)); err != nil {
+			return err
+		}
+	}
+	// Iterate over all transformer configuration files and attempt to decrypt them.
+	for _, configuration := range kus.Configurations {
+		if err := visitFile(configuration); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // sopsDecryptFile attempts to decrypt the file at the given path using SOPS'
 // store for the provided input format, and writes it back to the path using
 // the store for the output format.
 // Path must be absolute and a regular file, the file is not allowed to exceed
-// the maxFileSize.
+// the maxFileSize, or the maxBinaryFileSize for files in the binary format,
+// which are decrypted by sopsDecryptBinaryFile.
 //
 // NB: The method only does the simple checks described above and does not
 // verify whether the path provided is inside the working directory. Boundary
@@ -798,7 +1055,16 @@ func (d *Decryptor) sopsDecryptFile(path string, inputFormat, outputFormat forma
 	if !fi.Mode().IsRegular() {
 		return fmt.Errorf("cannot decrypt irregular file as it has file mode type bits set")
 	}
-	if fileSize := fi.Size(); d.maxFileSize > 0 && fileSize > d.maxFileSize {
+	fileSize := fi.Size()
+	if inputFormat == formats.Binary && outputFormat == formats.Binary {
+		if d.maxBinaryFileSize > 0 && fileSize > d.maxBinaryFileSize {
+			return fmt.Errorf("cannot decrypt file with size (%d bytes) exceeding limit (%d)", fileSize, d.maxBinaryFileSize)
+		}
+		if err := d.sopsDecryptBinaryFile(path, fileSize); !errors.Is(err, errNotBinaryEnvelope) {
+			return err
+		}
+	}
+	if d.maxFileSize > 0 && fileSize > d.maxFileSize {
 		return fmt.Errorf("cannot decrypt file with size (%d bytes) exceeding limit (%d)", fileSize, d.maxFileSize)
 	}
 
@@ -881,11 +1147,19 @@ func (d *Decryptor) loadKeyServiceServer() {
 		intkeyservice.WithGnuPGHome(d.gnuPGHome),
 		intkeyservice.WithVaultToken(d.vaultToken),
 		intkeyservice.WithVaultK8sAuth{TokenFunc: d.vaultK8sAuth},
+		intkeyservice.WithVaultNamespace(d.vaultNamespace),
+		intkeyservice.WithVaultTransitPath(d.vaultTransitPath),
 		intkeyservice.WithAgeIdentities(d.ageIdentities),
 		intkeyservice.WithAWSCredentialsProvider{CredentialsProvider: d.awsCredentialsProvider},
 		intkeyservice.WithAzureTokenCredential{TokenCredential: d.azureTokenCredential},
 		intkeyservice.WithGCPTokenSource{TokenSource: d.gcpTokenSource},
 	}
+	if d.ctx != nil {
+		serverOpts = append(serverOpts, intkeyservice.WithContext{Context: d.ctx})
+	}
+	if d.httpClient != nil {
+		serverOpts = append(serverOpts, intkeyservice.WithHTTPClient{Client: d.httpClient})
+	}
 	server := intkeyservice.NewServer(serverOpts...)
 	d.keyServices = append(make([]keyservice.KeyServiceClient, 0), keyservice.NewCustomLocalClient(server))
 }
@@ -1075,6 +1349,22 @@ func securePaths(root, path string) (string, string, error) {
 	return secureAbsPath, stripRoot(root, secureAbsPath), nil
 }
 
+// isExcludedPath returns true if the given path relative to the root, or one
+// of its parent directories, matches any of the given glob patterns.
+func isExcludedPath(patterns []string, relPath string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	for p := path.Clean(filepath.ToSlash(relPath)); p != "." && p != "/"; p = path.Dir(p) {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func stripRoot(root, path string) string {
 	sepStr := string(filepath.Separator)
 	root, path = filepath.Clean(sepStr+root), filepath.Clean(sepStr+path)
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// KeyExpiry describes an imported decryption key or credential with a known
+// expiry.
+type KeyExpiry struct {
+	// Key identifies the key, prefixed with its SOPS key type,
+	// e.g. 'pgp:<fingerprint>'.
+	Key string
+	// Entry is the name of the decryption Secret entry holding the key.
+	Entry string
+	// Expires is the time at which the key expires.
+	Expires time.Time
+}
+
+// KeyExpiries returns the keys and credentials with a known expiry imported
+// by ImportKeys.
+func (d *Decryptor) KeyExpiries() []KeyExpiry {
+	return d.keyExpiries
+}
+
+// pgpKeyExpiries returns the expiry of the primary keys with a lifetime of
+// the given PGP entities.
+func pgpKeyExpiries(entry string, entities openpgp.EntityList) []KeyExpiry {
+	var expiries []KeyExpiry
+	for _, e := range entities {
+		sig, _ := e.PrimarySelfSignature()
+		if sig == nil || sig.KeyLifetimeSecs == nil || *sig.KeyLifetimeSecs == 0 {
+			continue
+		}
+		expiries = append(expiries, KeyExpiry{
+			Key:     pgpFingerprint(e),
+			Entry:   entry,
+			Expires: e.PrimaryKey.CreationTime.Add(time.Duration(*sig.KeyLifetimeSecs) * time.Second),
+		})
+	}
+	return expiries
+}
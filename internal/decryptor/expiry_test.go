@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestPGPKeyExpiries(t *testing.T) {
+	g := NewWithT(t)
+
+	armoredKey := func(lifetime uint32) ([]byte, *openpgp.Entity) {
+		e, err := openpgp.NewEntity("flux", "", "flux@example.com", &packet.Config{KeyLifetimeSecs: lifetime})
+		g.Expect(err).ToNot(HaveOccurred())
+		var buf bytes.Buffer
+		w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(e.Serialize(w)).To(Succeed())
+		g.Expect(w.Close()).To(Succeed())
+		return buf.Bytes(), e
+	}
+
+	data, e := armoredKey(3600)
+	expiries := pgpKeyExpiries("sops.asc", readPGPKeyRing(data))
+	g.Expect(expiries).To(HaveLen(1))
+	g.Expect(expiries[0].Key).To(Equal(fmt.Sprintf("pgp:%X", e.PrimaryKey.Fingerprint)))
+	g.Expect(expiries[0].Entry).To(Equal("sops.asc"))
+	g.Expect(expiries[0].Expires).To(BeTemporally("==", e.PrimaryKey.CreationTime.Add(time.Hour)))
+
+	data, _ = armoredKey(0)
+	g.Expect(pgpKeyExpiries("sops.asc", readPGPKeyRing(data))).To(BeEmpty())
+
+	g.Expect(readPGPKeyRing([]byte("not a key"))).To(BeEmpty())
+}
+
+func TestDecryptor_KeyExpiries(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sops-keys", Namespace: "apps"},
+		Data: map[string][]byte{
+			DecryptionAWSKmsFile: []byte(`
+aws_access_key_id: AKIAEXAMPLE
+aws_secret_access_key: secret
+aws_session_token: token
+aws_session_expiration: 2026-01-02T03:04:05Z
+`),
+		},
+	}
+	kus := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{
+				Provider:  DecryptionProviderSOPS,
+				SecretRef: &meta.LocalObjectReference{Name: "sops-keys"},
+			},
+		},
+	}
+	d, cleanup, err := New(fake.NewClientBuilder().WithObjects(secret).Build(), kus)
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(cleanup)
+
+	g.Expect(d.ImportKeys(context.TODO())).To(Succeed())
+	g.Expect(d.KeyExpiries()).To(ConsistOf(KeyExpiry{
+		Key:     "aws_kms:AKIAEXAMPLE",
+		Entry:   DecryptionAWSKmsFile,
+		Expires: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	gcpkmsapi "cloud.google.com/go/kms/apiv1"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+// gcpImpersonatedTokenSource is a GCP token source issuing the tokens of the
+// impersonated service account through the IAM Credentials API, using the
+// tokens of the base token source to authenticate. The impersonated token
+// source is created on the first request, so that its errors are returned
+// with the token.
+type gcpImpersonatedTokenSource struct {
+	ctx             context.Context
+	base            oauth2.TokenSource
+	targetPrincipal string
+
+	once sync.Once
+	ts   oauth2.TokenSource
+	err  error
+}
+
+// newGCPImpersonatedTokenSource returns a token source impersonating the
+// given service account with the credentials of the base token source, or
+// with the Application Default Credentials if base is nil.
+func newGCPImpersonatedTokenSource(ctx context.Context,
+	base oauth2.TokenSource, targetPrincipal string) *gcpImpersonatedTokenSource {
+	return &gcpImpersonatedTokenSource{
+		ctx:             ctx,
+		base:            base,
+		targetPrincipal: targetPrincipal,
+	}
+}
+
+// Token returns a token of the impersonated service account.
+func (s *gcpImpersonatedTokenSource) Token() (*oauth2.Token, error) {
+	s.once.Do(func() {
+		var opts []option.ClientOption
+		if s.base != nil {
+			opts = append(opts, option.WithTokenSource(s.base))
+		}
+		s.ts, s.err = impersonate.CredentialsTokenSource(s.ctx, impersonate.CredentialsConfig{
+			TargetPrincipal: s.targetPrincipal,
+			Scopes:          gcpkmsapi.DefaultAuthScopes(),
+		}, opts...)
+	})
+	if s.err != nil {
+		return nil, fmt.Errorf("failed to impersonate GCP service account '%s': %w", s.targetPrincipal, s.err)
+	}
+	return s.ts.Token()
+}
+
+// parseGCPServiceAccountEmail returns the trimmed email of a GCP service
+// account, or an error if the value isn't an email address.
+func parseGCPServiceAccountEmail(value []byte) (string, error) {
+	email := strings.TrimSpace(string(value))
+	if name, domain, ok := strings.Cut(email, "@"); !ok || name == "" || domain == "" ||
+		strings.ContainsAny(email, " \t\n/") {
+		return "", fmt.Errorf("invalid service account email '%s'", email)
+	}
+	return email, nil
+}
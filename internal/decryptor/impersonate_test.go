@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+)
+
+type errTokenSource struct{}
+
+func (errTokenSource) Token() (*oauth2.Token, error) {
+	return nil, errors.New("base credentials unavailable")
+}
+
+func TestGCPImpersonatedTokenSource_Token(t *testing.T) {
+	g := NewWithT(t)
+
+	ts := newGCPImpersonatedTokenSource(context.Background(), errTokenSource{},
+		"tenant-a@project.iam.gserviceaccount.com")
+	_, err := ts.Token()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("base credentials unavailable"))
+
+	ts = newGCPImpersonatedTokenSource(context.Background(), errTokenSource{}, "")
+	_, err = ts.Token()
+	g.Expect(err).To(MatchError(ContainSubstring("failed to impersonate GCP service account ''")))
+}
+
+func TestParseGCPServiceAccountEmail(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "tenant-a@project.iam.gserviceaccount.com\n", want: "tenant-a@project.iam.gserviceaccount.com"},
+		{value: "tenant-a", wantErr: true},
+		{value: "@project.iam.gserviceaccount.com", wantErr: true},
+		{value: "tenant a@project.iam.gserviceaccount.com", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseGCPServiceAccountEmail([]byte(tt.value))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/shamir"
+)
+
+// KeyGroupsError is returned when the data key of SOPS data encrypted with
+// multiple key groups can't be recovered, as fewer key groups than required
+// by the Shamir threshold can be decrypted with the imported keys.
+type KeyGroupsError struct {
+	// Required is the number of key groups required to recover the data key.
+	Required int
+	// Satisfied is the number of key groups decrypted.
+	Satisfied int
+	// Unsatisfied are the key groups which can't be decrypted, formatted as
+	// '<index> (<type>:<key>, ...)', e.g. '1 (age:age1..., pgp:<fingerprint>)'.
+	Unsatisfied []string
+}
+
+func (e *KeyGroupsError) Error() string {
+	return fmt.Sprintf("%d of %d required key groups satisfied, unsatisfied key groups: %s",
+		e.Satisfied, e.Required, strings.Join(e.Unsatisfied, ", "))
+}
+
+// getDataKey retrieves the data key of the SOPS metadata. The key groups of
+// data encrypted with multiple key groups are decrypted one by one until the
+// Shamir threshold is met, so that the key groups which can't be decrypted
+// are returned in a KeyGroupsError when the threshold isn't met.
+func (d *Decryptor) getDataKey(metadata *sops.Metadata) ([]byte, error) {
+	if len(metadata.KeyGroups) < 2 {
+		return metadata.GetDataKeyWithKeyServices(d.keyServiceServer(), sops.DefaultDecryptionOrder)
+	}
+
+	// The threshold defaults to all the key groups when encrypting.
+	required := metadata.ShamirThreshold
+	if required <= 0 || required > len(metadata.KeyGroups) {
+		required = len(metadata.KeyGroups)
+	}
+
+	var parts [][]byte
+	var unsatisfied []string
+	for i, group := range metadata.KeyGroups {
+		m := sops.Metadata{KeyGroups: []sops.KeyGroup{group}}
+		part, err := m.GetDataKeyWithKeyServices(d.keyServiceServer(), sops.DefaultDecryptionOrder)
+		if err != nil {
+			unsatisfied = append(unsatisfied, formatKeyGroup(i, group))
+			continue
+		}
+		parts = append(parts, part)
+		if len(parts) == required {
+			break
+		}
+	}
+	if len(parts) < required {
+		return nil, &KeyGroupsError{
+			Required:    required,
+			Satisfied:   len(parts),
+			Unsatisfied: unsatisfied,
+		}
+	}
+
+	dataKey, err := shamir.Combine(parts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get data key from shamir parts: %w", err)
+	}
+	metadata.DataKey = dataKey
+	return dataKey, nil
+}
+
+// formatKeyGroup returns the index of the key group with
+// the type and public identifier of each of its keys.
+func formatKeyGroup(index int, group sops.KeyGroup) string {
+	keys := make([]string, 0, len(group))
+	for _, key := range group {
+		keys = append(keys, key.TypeToIdentifier()+":"+key.ToString())
+	}
+	return fmt.Sprintf("%d (%s)", index, strings.Join(keys, ", "))
+}
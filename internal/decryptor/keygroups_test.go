@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	extage "filippo.io/age"
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestDecryptor_getDataKey(t *testing.T) {
+	g := NewWithT(t)
+
+	ids := make([]*extage.X25519Identity, 3)
+	groups := make([]sops.KeyGroup, 3)
+	for i := range ids {
+		id, err := extage.GenerateX25519Identity()
+		g.Expect(err).ToNot(HaveOccurred())
+		ids[i] = id
+		groups[i] = sops.KeyGroup{&age.MasterKey{Recipient: id.Recipient().String()}}
+	}
+
+	data := []byte("{\"key\": \"value\"}\n")
+	encData, err := (&Decryptor{}).sopsEncryptWithFormat(sops.Metadata{
+		KeyGroups:       groups,
+		ShamirThreshold: 2,
+	}, data, formats.Json, formats.Json)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tests := []struct {
+		name        string
+		identities  []*extage.X25519Identity
+		unsatisfied []string
+	}{
+		{
+			name:       "threshold met",
+			identities: []*extage.X25519Identity{ids[0], ids[2]},
+		},
+		{
+			name:       "threshold not met",
+			identities: []*extage.X25519Identity{ids[1]},
+			unsatisfied: []string{
+				fmt.Sprintf("0 (age:%s)", ids[0].Recipient()),
+				fmt.Sprintf("2 (age:%s)", ids[2].Recipient()),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var parsed age.ParsedIdentities
+			for _, id := range tt.identities {
+				parsed = append(parsed, id)
+			}
+			d := &Decryptor{keyring: keyring{ageIdentities: parsed}}
+
+			out, err := d.SopsDecryptWithFormat(encData, formats.Json, formats.Json)
+			if tt.unsatisfied == nil {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(out).To(MatchJSON(data))
+				return
+			}
+
+			var groupsErr *KeyGroupsError
+			g.Expect(errors.As(err, &groupsErr)).To(BeTrue())
+			g.Expect(groupsErr.Required).To(Equal(2))
+			g.Expect(groupsErr.Satisfied).To(Equal(1))
+			g.Expect(groupsErr.Unsatisfied).To(Equal(tt.unsatisfied))
+			g.Expect(err.Error()).To(ContainSubstring("1 of 2 required key groups satisfied"))
+		})
+	}
+
+	t.Run("identities of multiple groups in a single Secret entry", func(t *testing.T) {
+		g := NewWithT(t)
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sops-keys", Namespace: "apps"},
+			Data: map[string][]byte{
+				"groups" + DecryptionAgeExt: []byte(ids[0].String() + "\n" + ids[1].String()),
+			},
+		}
+		kus := &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+			Spec: kustomizev1.KustomizationSpec{
+				Decryption: &kustomizev1.Decryption{
+					Provider:  DecryptionProviderSOPS,
+					SecretRef: &meta.LocalObjectReference{Name: "sops-keys"},
+				},
+			},
+		}
+		d, cleanup, err := New(fake.NewClientBuilder().WithObjects(secret).Build(), kus)
+		g.Expect(err).ToNot(HaveOccurred())
+		t.Cleanup(cleanup)
+		g.Expect(d.ImportKeys(context.TODO())).To(Succeed())
+
+		out, err := d.SopsDecryptWithFormat(encData, formats.Json, formats.Json)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(out).To(MatchJSON(data))
+	})
+}
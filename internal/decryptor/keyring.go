@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/pgp"
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// keyring holds the keys and the key management services credentials
+// imported from a decryption Secret.
+type keyring struct {
+	// gnuPGHome is the absolute path of the GnuPG home directory used to
+	// decrypt PGP data. When empty, the systems' GnuPG keyring is used.
+	// When set, ImportKeys() imports found PGP keys into this keyring.
+	gnuPGHome pgp.GnuPGHome
+	// ageIdentities is the set of age identities available to the decryptor.
+	ageIdentities age.ParsedIdentities
+	// pgpFingerprints are the fingerprints of the PGP keys imported into
+	// the gnuPGHome, prefixed with the SOPS key type.
+	pgpFingerprints []string
+	// vaultToken is the OpenBao/Vault token used to authenticate towards
+	// any Vault server.
+	vaultToken string
+	// vaultNamespace is the OpenBao/Vault Enterprise namespace of the
+	// transit engine requests.
+	vaultNamespace string
+	// vaultTransitPath is the mount path of the OpenBao/Vault transit
+	// engine, overriding the engine path of the SOPS metadata.
+	vaultTransitPath string
+	// awsCredentialsProvider is the AWS credentials provider object used to authenticate
+	// towards any AWS KMS.
+	awsCredentialsProvider func(region string) awssdk.CredentialsProvider
+	// azureTokenCredential is the Azure credential token used to authenticate towards
+	// any Azure Key Vault.
+	azureTokenCredential azcore.TokenCredential
+	// gcpTokenSource is the GCP token source used to authenticate towards
+	// any GCP KMS.
+	gcpTokenSource oauth2.TokenSource
+	// gcpImpersonateServiceAccount is the email of the GCP service account
+	// impersonated with the gcpTokenSource to access any GCP KMS.
+	gcpImpersonateServiceAccount string
+	// proxyURL is the URL of the HTTP/S proxy for the requests to the key
+	// management services, imported from DecryptionProxyURLFile.
+	proxyURL *url.URL
+	// httpClient is the HTTP client for the requests to the key management
+	// services, configured with the imported CA bundle and proxy URL.
+	// When nil, the default HTTP client of each service is used.
+	httpClient *http.Client
+	// keyExpiries are the keys and credentials with a known expiry
+	// imported by ImportKeys.
+	keyExpiries []KeyExpiry
+}
+
+// KeyringCache holds the keyrings imported from the decryption Secrets,
+// indexed by Secret, so that the keys of an unchanged version of a Secret
+// are imported once instead of by each reconciliation. It is safe for
+// concurrent use, and its zero value is ready to use.
+type KeyringCache struct {
+	mu       sync.Mutex
+	keyrings map[keyringKey]*cachedKeyring
+}
+
+// keyringKey is the key of a keyring of the KeyringCache.
+type keyringKey struct {
+	types.NamespacedName
+	// entries are the names of the imported entries of the Secret, as
+	// the SOPS age fallback Secret is imported without its other entries.
+	entries string
+}
+
+func newKeyringKey(secret *corev1.Secret) keyringKey {
+	entries := make([]string, 0, len(secret.Data))
+	for name := range secret.Data {
+		entries = append(entries, name)
+	}
+	slices.Sort(entries)
+	return keyringKey{
+		NamespacedName: types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name},
+		entries:        strings.Join(entries, "/"),
+	}
+}
+
+// cachedKeyring is a keyring of the KeyringCache, imported from a version
+// of a Secret. Its GnuPG home directory is removed once the keyring is
+// evicted and no longer in use by a Decryptor.
+type cachedKeyring struct {
+	keyring
+	resourceVersion string
+	expires         time.Time
+	users           int
+	evicted         bool
+}
+
+// acquire returns the keyring imported from the given version of the
+// Secret, if cached and not expired, and marks it as in use.
+func (c *KeyringCache) acquire(key keyringKey, resourceVersion string) (*cachedKeyring, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ck, ok := c.keyrings[key]
+	if !ok {
+		return nil, false
+	}
+	if ck.resourceVersion != resourceVersion || !time.Now().Before(ck.expires) {
+		c.evict(key, ck)
+		return nil, false
+	}
+	ck.users++
+	return ck, true
+}
+
+// store caches the keyring imported from the given version of the Secret
+// for the given TTL, evicting the expired keyrings, and marks it as in use.
+func (c *KeyringCache) store(key keyringKey, resourceVersion string, ttl time.Duration, kr keyring) *cachedKeyring {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, ck := range c.keyrings {
+		if k == key || !now.Before(ck.expires) {
+			c.evict(k, ck)
+		}
+	}
+	if c.keyrings == nil {
+		c.keyrings = make(map[keyringKey]*cachedKeyring)
+	}
+
+	// The slices are clipped, so that appending to the keyring of a
+	// Decryptor does not modify the cached one.
+	kr.ageIdentities = slices.Clip(kr.ageIdentities)
+	kr.pgpFingerprints = slices.Clip(kr.pgpFingerprints)
+	kr.keyExpiries = slices.Clip(kr.keyExpiries)
+	ck := &cachedKeyring{
+		keyring:         kr,
+		resourceVersion: resourceVersion,
+		expires:         now.Add(ttl),
+		users:           1,
+	}
+	c.keyrings[key] = ck
+	return ck
+}
+
+// release marks the keyring as no longer in use by a Decryptor.
+func (c *KeyringCache) release(ck *cachedKeyring) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ck.users--
+	if ck.evicted && ck.users == 0 {
+		ck.cleanup()
+	}
+}
+
+// evict removes the keyring from the cache. It must be called with the
+// lock held.
+func (c *KeyringCache) evict(key keyringKey, ck *cachedKeyring) {
+	delete(c.keyrings, key)
+	ck.evicted = true
+	if ck.users == 0 {
+		ck.cleanup()
+	}
+}
+
+func (ck *cachedKeyring) cleanup() {
+	if ck.gnuPGHome != "" {
+		_ = os.RemoveAll(ck.gnuPGHome.String())
+	}
+}
+
+// useCachedKeyring sets the keyring of the Decryptor to the cached one.
+func (d *Decryptor) useCachedKeyring(ck *cachedKeyring) {
+	d.keyring = ck.keyring
+	d.cachedKeyring = ck
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/getsops/sops/v3/pgp"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestKeyringCache(t *testing.T) {
+	g := NewWithT(t)
+
+	home := pgp.GnuPGHome(t.TempDir())
+	key := keyringKey{NamespacedName: types.NamespacedName{Namespace: "apps", Name: "sops-keys"}}
+	c := &KeyringCache{}
+
+	_, ok := c.acquire(key, "1")
+	g.Expect(ok).To(BeFalse())
+
+	stored := c.store(key, "1", time.Hour, keyring{gnuPGHome: home, vaultToken: "token"})
+	acquired, ok := c.acquire(key, "1")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(acquired).To(BeIdenticalTo(stored))
+	g.Expect(acquired.vaultToken).To(Equal("token"))
+	c.release(stored)
+
+	// A new version of the Secret evicts the keyring, whose GnuPG home
+	// is removed once released by the last Decryptor using it.
+	_, ok = c.acquire(key, "2")
+	g.Expect(ok).To(BeFalse())
+	g.Expect(home.String()).To(BeADirectory())
+	c.release(acquired)
+	g.Expect(home.String()).ToNot(BeADirectory())
+
+	// An expired keyring is evicted.
+	home = pgp.GnuPGHome(t.TempDir())
+	c.release(c.store(key, "2", 0, keyring{gnuPGHome: home}))
+	g.Expect(home.String()).To(BeADirectory())
+	_, ok = c.acquire(key, "2")
+	g.Expect(ok).To(BeFalse())
+	g.Expect(home.String()).ToNot(BeADirectory())
+}
+
+func TestDecryptor_ImportKeys_keyringCache(t *testing.T) {
+	g := NewWithT(t)
+
+	ageKey, err := os.ReadFile("testdata/age.txt")
+	g.Expect(err).ToNot(HaveOccurred())
+	pgpKey, err := os.ReadFile("testdata/pgp.asc")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sops-keys", Namespace: "apps"},
+		Data: map[string][]byte{
+			"identity" + DecryptionAgeExt: ageKey,
+			"private" + DecryptionPGPExt:  pgpKey,
+		},
+	}
+	kus := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{
+				Provider:  DecryptionProviderSOPS,
+				SecretRef: &meta.LocalObjectReference{Name: "sops-keys"},
+			},
+		},
+	}
+	kubeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	keyrings := &KeyringCache{}
+	importKeys := func() (*Decryptor, func()) {
+		d, cleanup, err := New(kubeClient, kus, WithKeyringCache(keyrings, time.Hour))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(d.ImportKeys(context.TODO())).To(Succeed())
+		return d, cleanup
+	}
+
+	first, cleanup := importKeys()
+	g.Expect(first.ageIdentities).To(HaveLen(1))
+	g.Expect(first.pgpFingerprints).ToNot(BeEmpty())
+	cleanup()
+	g.Expect(first.gnuPGHome.String()).To(BeADirectory())
+
+	// The keys of the unchanged Secret are reused, with their GnuPG home.
+	second, cleanup := importKeys()
+	g.Expect(second.ageIdentities[0]).To(BeIdenticalTo(first.ageIdentities[0]))
+	g.Expect(second.gnuPGHome).To(Equal(first.gnuPGHome))
+	g.Expect(second.pgpFingerprints).To(Equal(first.pgpFingerprints))
+	defer cleanup()
+
+	// The keys of a new version of the Secret are imported.
+	secret.Data["other"+DecryptionAgeExt] = ageKey
+	g.Expect(kubeClient.Update(context.TODO(), secret)).To(Succeed())
+	third, cleanup := importKeys()
+	defer cleanup()
+	g.Expect(third.ageIdentities).To(HaveLen(2))
+	g.Expect(third.gnuPGHome).ToNot(Equal(first.gnuPGHome))
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/getsops/sops/v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/kustomize/api/resource"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+)
+
+// SOPSMetadata holds the non-sensitive SOPS metadata of a decrypted object,
+// to correlate the applied object with the encryption audit trail.
+type SOPSMetadata struct {
+	// LastModified is the latest time the data of the object was encrypted.
+	LastModified time.Time
+
+	// Keys holds the sorted identifiers of the master keys the data keys
+	// are encrypted with, e.g. the age recipients, the PGP fingerprints
+	// or the KMS key ARNs, prefixed with the key type.
+	Keys []string
+}
+
+// String returns the metadata in the 'lastmodified=<time> keys=<keys>' format.
+func (m SOPSMetadata) String() string {
+	return fmt.Sprintf("lastmodified=%s keys=%s",
+		m.LastModified.UTC().Format(time.RFC3339), strings.Join(m.Keys, ","))
+}
+
+// add merges the metadata of a SOPS document into m.
+func (m *SOPSMetadata) add(metadata sops.Metadata) {
+	if metadata.LastModified.After(m.LastModified) {
+		m.LastModified = metadata.LastModified
+	}
+	for _, group := range metadata.KeyGroups {
+		for _, key := range group {
+			id := fmt.Sprintf("%s:%s", key.TypeToIdentifier(), key.ToString())
+			if !slices.Contains(m.Keys, id) {
+				m.Keys = append(m.Keys, id)
+			}
+		}
+	}
+	slices.Sort(m.Keys)
+}
+
+// recordMetadata records the SOPS metadata of the decrypted resource.
+func (d *Decryptor) recordMetadata(res *resource.Resource, metadata sops.Metadata) {
+	gvk := res.GetGvk()
+	id := object.ObjMetadata{
+		Namespace: res.GetNamespace(),
+		Name:      res.GetName(),
+		GroupKind: schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind},
+	}.String()
+
+	d.metadataMu.Lock()
+	defer d.metadataMu.Unlock()
+	if d.metadata == nil {
+		d.metadata = make(map[string]*SOPSMetadata)
+	}
+	m, ok := d.metadata[id]
+	if !ok {
+		m = &SOPSMetadata{}
+		d.metadata[id] = m
+	}
+	m.add(metadata)
+}
+
+// DecryptedMetadata returns the SOPS metadata of the objects decrypted by
+// DecryptResource, indexed by their inventory ID.
+func (d *Decryptor) DecryptedMetadata() map[string]SOPSMetadata {
+	d.metadataMu.Lock()
+	defer d.metadataMu.Unlock()
+	out := make(map[string]SOPSMetadata, len(d.metadata))
+	for id, m := range d.metadata {
+		out[id] = SOPSMetadata{LastModified: m.LastModified, Keys: slices.Clone(m.Keys)}
+	}
+	return out
+}
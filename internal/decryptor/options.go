@@ -17,9 +17,14 @@ limitations under the License.
 package decryptor
 
 import (
+	"context"
+	"time"
+
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/fluxcd/pkg/cache"
+
+	"github.com/fluxcd/kustomize-controller/internal/health"
 )
 
 // Option is a functional option for configuring the Decryptor.
@@ -49,6 +54,22 @@ func WithSOPSAgeSecret(name, namespace string) Option {
 	}
 }
 
+// WithMaxBinaryFileSize sets the max size in bytes of the files in the SOPS
+// binary format to decrypt, zero removes the limit.
+func WithMaxBinaryFileSize(size int64) Option {
+	return func(o *Decryptor) {
+		o.maxBinaryFileSize = size
+	}
+}
+
+// WithSecretCache sets the cache of the last version read of the decryption
+// Secrets, used when a Secret can't be read due to a transient error.
+func WithSecretCache(secretCache *SecretCache) Option {
+	return func(o *Decryptor) {
+		o.secretCache = secretCache
+	}
+}
+
 // WithVaultConfigMap sets the ConfigMap (by name and namespace)
 // mapping Vault/OpenBao addresses to the Kubernetes auth mount to use for each,
 // used to authenticate for SOPS decryption. The ConfigMap also acts as an
@@ -62,3 +83,28 @@ func WithVaultConfigMap(name, namespace string) Option {
 		}
 	}
 }
+
+// WithHealth sets the tracker recording the outcome of the data key
+// retrievals per SOPS key provider.
+func WithHealth(tracker *health.Tracker) Option {
+	return func(o *Decryptor) {
+		o.health = tracker
+	}
+}
+
+// WithContext sets the context of the requests to the key management
+// services, e.g. to bound the decryption with the reconciliation deadline.
+func WithContext(ctx context.Context) Option {
+	return func(o *Decryptor) {
+		o.ctx = ctx
+	}
+}
+
+// WithKeyringCache sets the cache of the keyrings imported from the
+// decryption Secrets, and the duration for which a keyring is reused.
+func WithKeyringCache(keyrings *KeyringCache, ttl time.Duration) Option {
+	return func(o *Decryptor) {
+		o.keyrings = keyrings
+		o.keyringsTTL = ttl
+	}
+}
@@ -0,0 +1,376 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	vaultapi "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+const (
+	// placeholderPrefix is the prefix of the Secret values referencing
+	// a secret of an external secret store.
+	placeholderPrefix = "ref+"
+	// placeholderVault is the scheme of the placeholders referencing a
+	// field of an OpenBao/Vault secret, in the form
+	// 'ref+vault://<host>[:<port>]/<path>#<field>'.
+	placeholderVault = "vault"
+	// placeholderAWSSecrets is the scheme of the placeholders referencing an
+	// AWS Secrets Manager secret, in the form 'ref+awssecrets://<arn>', or
+	// 'ref+awssecrets://<arn>#<field>' for a field of a JSON secret.
+	placeholderAWSSecrets = "awssecrets"
+	// awsSecretsManagerVariablePrefix is the prefix of the post-build
+	// substitution variables referencing an AWS Secrets Manager secret, in
+	// the form 'aws-sm://<arn>', or 'aws-sm://<arn>#<field>' for a field of
+	// a JSON secret.
+	awsSecretsManagerVariablePrefix = "aws-sm://"
+)
+
+// placeholder is a parsed reference to a secret of an external secret store.
+type placeholder struct {
+	// scheme is the kind of secret store, placeholderVault or
+	// placeholderAWSSecrets.
+	scheme string
+	// ref is the 'host[:port]/path' of the Vault secret, or the ARN of
+	// the AWS Secrets Manager secret.
+	ref string
+	// field is the field of the secret holding the value, empty for the
+	// whole value of an AWS Secrets Manager secret.
+	field string
+}
+
+// parsePlaceholder parses the given Secret value as a placeholder. It returns
+// false if the value is not a placeholder, and an error if the placeholder is
+// malformed.
+func parsePlaceholder(value string) (*placeholder, bool, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, placeholderPrefix) {
+		return nil, false, nil
+	}
+	scheme, rest, ok := strings.Cut(strings.TrimPrefix(value, placeholderPrefix), "://")
+	if !ok {
+		return nil, true, fmt.Errorf("invalid placeholder '%s': missing '://'", value)
+	}
+	ref, field, _ := strings.Cut(rest, "#")
+	p := &placeholder{scheme: scheme, ref: ref, field: field}
+
+	switch scheme {
+	case placeholderVault:
+		host, path, _ := strings.Cut(ref, "/")
+		if host == "" || path == "" || field == "" {
+			return nil, true, fmt.Errorf("invalid placeholder '%s': expected 'ref+vault://<host>/<path>#<field>'", value)
+		}
+	case placeholderAWSSecrets:
+		if _, err := awsSecretRegion(ref); err != nil {
+			return nil, true, fmt.Errorf("invalid placeholder '%s': %w", value, err)
+		}
+	default:
+		return nil, true, fmt.Errorf("invalid placeholder '%s': unsupported secret store '%s', must be '%s' or '%s'",
+			value, scheme, placeholderVault, placeholderAWSSecrets)
+	}
+	return p, true, nil
+}
+
+// awsSecretRegion returns the region of the AWS Secrets Manager secret
+// with the given ARN.
+func awsSecretRegion(arn string) (string, error) {
+	parts := strings.SplitN(arn, ":", 7)
+	if len(parts) != 7 || parts[0] != "arn" || parts[2] != "secretsmanager" || parts[3] == "" {
+		return "", fmt.Errorf("'%s' is not an AWS Secrets Manager secret ARN", arn)
+	}
+	return parts[3], nil
+}
+
+// placeholderProvider is the DecryptionProviderPlaceholder Provider,
+// resolving the placeholders of the Secrets from external secret stores.
+type placeholderProvider struct {
+	d *Decryptor
+}
+
+func newPlaceholderProvider(d *Decryptor) Provider {
+	return &placeholderProvider{d: d}
+}
+
+// ImportKeys imports the secret stores credentials of the decryption Secret.
+func (p *placeholderProvider) ImportKeys(ctx context.Context, secret *corev1.Secret) error {
+	return p.d.importSecretKeys(ctx, secret)
+}
+
+// DecryptResource resolves the placeholders of the Secret resources.
+func (p *placeholderProvider) DecryptResource(res *resource.Resource) (*resource.Resource, error) {
+	if res.GetKind() != "Secret" {
+		return nil, nil
+	}
+	return p.d.resolvePlaceholders(res)
+}
+
+// DecryptFile is a no-op, as the placeholders are only resolved in the
+// Secrets of the build.
+func (p *placeholderProvider) DecryptFile(string, formats.Format, formats.Format) error {
+	return nil
+}
+
+// resolvePlaceholders replaces the placeholders of the data and stringData
+// entries of the given Secret with the values fetched from the external
+// secret stores. It returns nil if the Secret contains no placeholder.
+func (d *Decryptor) resolvePlaceholders(res *resource.Resource) (*resource.Resource, error) {
+	in, err := res.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(in); err != nil {
+		return nil, err
+	}
+
+	var resolved bool
+	for _, field := range []string{"data", "stringData"} {
+		entries, _, err := unstructured.NestedStringMap(u.Object, field)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range entries {
+			if field == "data" {
+				b, err := base64.StdEncoding.DecodeString(value)
+				if err != nil {
+					// Let the invalid data bubble up during the apply.
+					continue
+				}
+				value = string(b)
+			}
+			p, ok, err := parsePlaceholder(value)
+			if !ok {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve '%s/%s' Secret field '%s': %w",
+					res.GetNamespace(), res.GetName(), key, err)
+			}
+			out, err := d.resolvePlaceholder(p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve '%s/%s' Secret field '%s': %w",
+					res.GetNamespace(), res.GetName(), key, err)
+			}
+			if field == "data" {
+				out = base64.StdEncoding.EncodeToString([]byte(out))
+			}
+			entries[key] = out
+			resolved = true
+		}
+		if len(entries) > 0 {
+			if err := unstructured.SetNestedStringMap(u.Object, entries, field); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !resolved {
+		return nil, nil
+	}
+
+	out, err := u.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if err := res.UnmarshalJSON(out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resolved '%s/%s' Secret to JSON: %w",
+			res.GetNamespace(), res.GetName(), err)
+	}
+	return res, nil
+}
+
+// ResolveVariables replaces the values of the given post-build substitution
+// variables referencing an AWS Secrets Manager secret with the values fetched
+// with the credentials of the placeholder provider. It returns true if any
+// variable was resolved. The variables are left untouched with the other
+// decryption providers.
+func (d *Decryptor) ResolveVariables(vars map[string]string) (bool, error) {
+	if d.kustomization.Spec.Decryption == nil ||
+		d.kustomization.Spec.Decryption.Provider != DecryptionProviderPlaceholder {
+		return false, nil
+	}
+
+	var resolved bool
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		ref, ok := strings.CutPrefix(strings.TrimSpace(vars[name]), awsSecretsManagerVariablePrefix)
+		if !ok {
+			continue
+		}
+		arn, field, _ := strings.Cut(ref, "#")
+		if _, err := awsSecretRegion(arn); err != nil {
+			return false, fmt.Errorf("invalid substitution variable '%s': %w", name, err)
+		}
+		value, err := d.resolvePlaceholder(&placeholder{scheme: placeholderAWSSecrets, ref: arn, field: field})
+		if err != nil {
+			return false, fmt.Errorf("failed to resolve substitution variable '%s': %w", name, err)
+		}
+		// The newlines are removed as for the variables loaded from Secrets.
+		vars[name] = strings.ReplaceAll(value, "\n", "")
+		resolved = true
+	}
+	return resolved, nil
+}
+
+// resolvePlaceholder fetches the value referenced by the placeholder,
+// reusing the value already fetched for the same placeholder.
+func (d *Decryptor) resolvePlaceholder(p *placeholder) (string, error) {
+	key := p.scheme + "://" + p.ref + "#" + p.field
+	if value, ok := d.placeholders[key]; ok {
+		return value, nil
+	}
+
+	ctx := d.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var value string
+	var err error
+	switch p.scheme {
+	case placeholderVault:
+		value, err = d.resolveVaultPlaceholder(ctx, p)
+	case placeholderAWSSecrets:
+		value, err = d.resolveAWSSecretsPlaceholder(ctx, p)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if d.placeholders == nil {
+		d.placeholders = make(map[string]string)
+	}
+	d.placeholders[key] = value
+	return value, nil
+}
+
+// resolveVaultPlaceholder reads the field of the OpenBao/Vault secret
+// referenced by the placeholder, authenticating with the static Vault token
+// of the decryption Secret, or with the ServiceAccount token auth.
+// The fields of the KV version 2 secrets are read from their data.
+func (d *Decryptor) resolveVaultPlaceholder(ctx context.Context, p *placeholder) (string, error) {
+	host, path, _ := strings.Cut(p.ref, "/")
+	address := "https://" + host
+
+	token := d.vaultToken
+	if token == "" && d.vaultK8sAuth != nil {
+		var err error
+		if token, err = d.vaultK8sAuth(ctx, address); err != nil {
+			return "", err
+		}
+	}
+	if token == "" {
+		return "", fmt.Errorf("no Vault token available for '%s', set '%s' in the decryption Secret or configure the Vault ServiceAccount token auth",
+			address, DecryptionVaultTokenFileName)
+	}
+
+	cfg := vaultapi.DefaultConfig()
+	cfg.Address = address
+	if d.httpClient != nil {
+		cfg.HttpClient = d.httpClient
+	}
+	client, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Vault client for '%s': %w", address, err)
+	}
+	client.SetToken(token)
+
+	secret, err := client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Vault secret '%s' from '%s': %w", path, address, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("Vault secret '%s' not found in '%s'", path, address)
+	}
+	data := secret.Data
+	if kv2, ok := data["data"].(map[string]any); ok && data["metadata"] != nil {
+		data = kv2
+	}
+	value, ok := data[p.field]
+	if !ok {
+		return "", fmt.Errorf("field '%s' not found in Vault secret '%s'", p.field, path)
+	}
+	return placeholderValue(value)
+}
+
+// resolveAWSSecretsPlaceholder reads the AWS Secrets Manager secret
+// referenced by the placeholder, or the field of its JSON value, with the
+// static credentials of the decryption Secret or the workload identity.
+func (d *Decryptor) resolveAWSSecretsPlaceholder(ctx context.Context, p *placeholder) (string, error) {
+	if d.awsCredentialsProvider == nil {
+		return "", fmt.Errorf("no AWS credentials available for '%s'", p.ref)
+	}
+	region, err := awsSecretRegion(p.ref)
+	if err != nil {
+		return "", err
+	}
+	opts := secretsmanager.Options{
+		Region:      region,
+		Credentials: d.awsCredentialsProvider(region),
+	}
+	if d.httpClient != nil {
+		opts.HTTPClient = d.httpClient
+	}
+	out, err := secretsmanager.New(opts).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: &p.ref,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get AWS secret '%s': %w", p.ref, err)
+	}
+
+	var value string
+	switch {
+	case out.SecretString != nil:
+		value = *out.SecretString
+	default:
+		value = string(out.SecretBinary)
+	}
+	if p.field == "" {
+		return value, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("AWS secret '%s' is not a JSON object: %w", p.ref, err)
+	}
+	field, ok := fields[p.field]
+	if !ok {
+		return "", fmt.Errorf("field '%s' not found in AWS secret '%s'", p.field, p.ref)
+	}
+	return placeholderValue(field)
+}
+
+// placeholderValue returns the string of the given secret field, or its
+// JSON encoding if the field is not a string.
+func placeholderValue(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
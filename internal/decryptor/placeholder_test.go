@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/kustomize/api/provider"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestParsePlaceholder(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *placeholder
+		wantErr string
+	}{
+		{
+			name:  "plain value",
+			value: "s3cr3t",
+		},
+		{
+			name:  "vault",
+			value: "ref+vault://vault.example.com:8200/secret/data/app#password",
+			want:  &placeholder{scheme: "vault", ref: "vault.example.com:8200/secret/data/app", field: "password"},
+		},
+		{
+			name:  "aws secret",
+			value: "ref+awssecrets://arn:aws:secretsmanager:eu-west-1:123456789012:secret:app-AbCdEf",
+			want:  &placeholder{scheme: "awssecrets", ref: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:app-AbCdEf"},
+		},
+		{
+			name:  "aws secret field",
+			value: "ref+awssecrets://arn:aws:secretsmanager:eu-west-1:123456789012:secret:app-AbCdEf#password",
+			want: &placeholder{scheme: "awssecrets",
+				ref: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:app-AbCdEf", field: "password"},
+		},
+		{
+			name:    "vault without field",
+			value:   "ref+vault://vault.example.com/secret/app",
+			wantErr: "expected 'ref+vault://<host>/<path>#<field>'",
+		},
+		{
+			name:    "invalid ARN",
+			value:   "ref+awssecrets://app#password",
+			wantErr: "is not an AWS Secrets Manager secret ARN",
+		},
+		{
+			name:    "unsupported store",
+			value:   "ref+gcpsecrets://projects/app/secrets/password",
+			wantErr: "unsupported secret store 'gcpsecrets'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p, ok, err := parsePlaceholder(tt.value)
+			if tt.wantErr != "" {
+				g.Expect(ok).To(BeTrue())
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(Equal(tt.want != nil))
+			g.Expect(p).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDecryptor_resolvePlaceholders(t *testing.T) {
+	g := NewWithT(t)
+
+	var reads atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" || r.URL.Path != "/v1/secret/data/app" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		reads.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"data":{"username":"admin","password":"s3cr3t"},"metadata":{"version":1}}}`))
+	}))
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	kus := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{Provider: DecryptionProviderPlaceholder},
+		},
+	}
+	d, cleanup, err := New(fake.NewClientBuilder().Build(), kus)
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(cleanup)
+	d.httpClient = srv.Client()
+
+	resourceFactory := provider.NewDefaultDepProvider().GetResourceFactory()
+	secret, err := resourceFactory.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "app", "namespace": "apps"},
+		"data": map[string]any{
+			"password": base64.StdEncoding.EncodeToString([]byte("ref+vault://" + host + "/secret/data/app#password")),
+			"plain":    base64.StdEncoding.EncodeToString([]byte("value")),
+		},
+		"stringData": map[string]any{
+			"username": "ref+vault://" + host + "/secret/data/app#username",
+			"again":    "ref+vault://" + host + "/secret/data/app#password",
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The Vault token is required.
+	_, err = d.DecryptResource(secret)
+	g.Expect(err).To(MatchError(ContainSubstring("no Vault token available")))
+
+	d.vaultToken = "s.token"
+	out, err := d.DecryptResource(secret)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(out).ToNot(BeNil())
+	g.Expect(out.GetDataMap()).To(Equal(map[string]string{
+		"password": base64.StdEncoding.EncodeToString([]byte("s3cr3t")),
+		"plain":    base64.StdEncoding.EncodeToString([]byte("value")),
+	}))
+	stringData, err := out.GetFieldValue("stringData")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(stringData).To(Equal(map[string]any{"username": "admin", "again": "s3cr3t"}))
+
+	// Each placeholder is fetched once.
+	g.Expect(reads.Load()).To(BeEquivalentTo(2))
+
+	// Secrets without placeholders are left untouched.
+	plain, err := resourceFactory.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "plain", "namespace": "apps"},
+		"stringData": map[string]any{"key": "value"},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	out, err = d.DecryptResource(plain)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(out).To(BeNil())
+}
+
+func TestDecryptor_ResolveVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	arn := "arn:aws:secretsmanager:eu-west-1:123456789012:secret:db-AbCdEf"
+	kus := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{Provider: DecryptionProviderPlaceholder},
+		},
+	}
+	d, cleanup, err := New(fake.NewClientBuilder().Build(), kus)
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(cleanup)
+
+	// The AWS credentials are required.
+	_, err = d.ResolveVariables(map[string]string{"password": "aws-sm://" + arn + "#password"})
+	g.Expect(err).To(MatchError(ContainSubstring("failed to resolve substitution variable 'password'")))
+	g.Expect(err).To(MatchError(ContainSubstring("no AWS credentials available")))
+
+	_, err = d.ResolveVariables(map[string]string{"password": "aws-sm://arn:aws:s3:::bucket"})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid substitution variable 'password'")))
+
+	// The values already fetched for the build are reused.
+	d.placeholders = map[string]string{
+		"awssecrets://" + arn + "#password": "s3cr3t",
+		"awssecrets://" + arn + "#":         "line1\nline2",
+	}
+	vars := map[string]string{
+		"password": " aws-sm://" + arn + "#password",
+		"all":      "aws-sm://" + arn,
+		"plain":    "value",
+	}
+	resolved, err := d.ResolveVariables(vars)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resolved).To(BeTrue())
+	g.Expect(vars).To(Equal(map[string]string{
+		"password": "s3cr3t",
+		"all":      "line1line2",
+		"plain":    "value",
+	}))
+
+	resolved, err = d.ResolveVariables(map[string]string{"plain": "value"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resolved).To(BeFalse())
+
+	// The variables are left untouched with the other providers.
+	d.kustomization.Spec.Decryption.Provider = DecryptionProviderSOPS
+	vars = map[string]string{"password": "aws-sm://" + arn}
+	resolved, err = d.ResolveVariables(vars)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resolved).To(BeFalse())
+	g.Expect(vars["password"]).To(Equal("aws-sm://" + arn))
+}
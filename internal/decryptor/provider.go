@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// Provider is a decryption provider, to which the Decryptor delegates the
+// import of the keys and the decryption of the resources and files of the
+// Kustomizations with the provider name in their v1.Decryption spec.
+type Provider interface {
+	// ImportKeys imports the keys and credentials of the provider from the
+	// data of the decryption Secret.
+	ImportKeys(ctx context.Context, secret *corev1.Secret) error
+	// DecryptResource decrypts the given resource, overwriting it with the
+	// decrypted data. It returns nil if the resource is not encrypted.
+	DecryptResource(res *resource.Resource) (*resource.Resource, error)
+	// DecryptFile decrypts the file at the given absolute path, referenced
+	// by a Kustomization file, in place. The file is left unchanged if it
+	// is not encrypted.
+	DecryptFile(path string, inputFormat, outputFormat formats.Format) error
+}
+
+// ProviderFactory returns the Provider of the given Decryptor.
+type ProviderFactory func(d *Decryptor) Provider
+
+var (
+	providersMu sync.RWMutex
+	// providers is the registry of the decryption providers, indexed by name.
+	providers = map[string]ProviderFactory{
+		DecryptionProviderSOPS:          newSOPSProvider,
+		DecryptionProviderPlaceholder:   newPlaceholderProvider,
+		DecryptionProviderSealedSecrets: newSealedSecretsProvider,
+	}
+)
+
+// RegisterProvider registers the factory of the decryption provider with the
+// given name, e.g. from the init function of the package implementing it.
+// The name must be allowed by the provider enum of the v1.Decryption spec.
+// It panics if the factory is nil, or if a provider is already registered
+// with the name.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if factory == nil {
+		panic(fmt.Sprintf("decryptor: nil factory for provider '%s'", name))
+	}
+	if _, ok := providers[name]; ok {
+		panic(fmt.Sprintf("decryptor: provider '%s' already registered", name))
+	}
+	providers[name] = factory
+}
+
+// ProviderNames returns the sorted names of the registered decryption
+// providers.
+func ProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return slices.Sorted(maps.Keys(providers))
+}
+
+// newProvider returns the Provider registered with the given name, or nil
+// if there is none.
+func newProvider(name string, d *Decryptor) Provider {
+	providersMu.RLock()
+	factory, ok := providers[name]
+	providersMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return factory(d)
+}
+
+// sopsProvider is the DecryptionProviderSOPS Provider, decrypting the SOPS
+// encrypted resources and files.
+type sopsProvider struct {
+	d *Decryptor
+}
+
+func newSOPSProvider(d *Decryptor) Provider {
+	return &sopsProvider{d: d}
+}
+
+// ImportKeys imports the SOPS keys and the key management services
+// credentials of the decryption Secret.
+func (p *sopsProvider) ImportKeys(ctx context.Context, secret *corev1.Secret) error {
+	return p.d.importSecretKeys(ctx, secret)
+}
+
+// DecryptResource decrypts the SOPS encrypted resource. It has special
+// support for Kubernetes Secrets with encrypted data entries, to allow
+// individual data entries injected by e.g. a Kustomize secret generator to be
+// decrypted.
+func (p *sopsProvider) DecryptResource(res *resource.Resource) (*resource.Resource, error) {
+	switch {
+	case isSOPSEncryptedResource(res):
+		// As we are expecting to decrypt right before applying, we do not
+		// care about keeping any other data (e.g. comments) around.
+		// We can therefore simply work with JSON, which saves us from e.g.
+		// JSON -> YAML -> JSON transformations.
+		out, err := res.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		data, metadata, err := p.d.sopsDecryptWithFormat(out, formats.Json, formats.Json)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt and format '%s/%s' %s data: %w",
+				res.GetNamespace(), res.GetName(), res.GetKind(), err)
+		}
+
+		err = res.UnmarshalJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal decrypted '%s/%s' %s to JSON: %w",
+				res.GetNamespace(), res.GetName(), res.GetKind(), err)
+		}
+		p.d.recordMetadata(res, metadata)
+		return res, nil
+	case res.GetKind() == "Secret":
+		dataMap := res.GetDataMap()
+		for key, value := range dataMap {
+			data, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				// If we fail to base64 decode, it is (very) likely to be a
+				// user input error. Instead of failing here, let it bubble
+				// up during the actual build.
+				continue
+			}
+
+			if inF := detectFormatFromMarkerBytes(data); inF != unsupportedFormat {
+				outF := formatForPath(key)
+				out, metadata, err := p.d.sopsDecryptWithFormat(data, inF, outF)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decrypt and format '%s/%s' Secret field '%s': %w",
+						res.GetNamespace(), res.GetName(), key, err)
+				}
+				dataMap[key] = base64.StdEncoding.EncodeToString(out)
+				p.d.recordMetadata(res, metadata)
+			}
+		}
+		res.SetDataMap(dataMap)
+		return res, nil
+	}
+	return nil, nil
+}
+
+// DecryptFile decrypts the SOPS encrypted file.
+func (p *sopsProvider) DecryptFile(path string, inputFormat, outputFormat formats.Format) error {
+	return p.d.sopsDecryptFile(path, inputFormat, outputFormat)
+}
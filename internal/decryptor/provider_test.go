@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/kustomize/api/provider"
+	"sigs.k8s.io/kustomize/api/resource"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// fakeProvider records the calls of the Decryptor.
+type fakeProvider struct {
+	secret    *corev1.Secret
+	resources []string
+	files     []string
+}
+
+func (p *fakeProvider) ImportKeys(_ context.Context, secret *corev1.Secret) error {
+	p.secret = secret
+	return nil
+}
+
+func (p *fakeProvider) DecryptResource(res *resource.Resource) (*resource.Resource, error) {
+	p.resources = append(p.resources, res.GetName())
+	return res, nil
+}
+
+func (p *fakeProvider) DecryptFile(path string, _, _ formats.Format) error {
+	p.files = append(p.files, filepath.Base(path))
+	return nil
+}
+
+func TestRegisterProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	fp := &fakeProvider{}
+	RegisterProvider("fake", func(*Decryptor) Provider { return fp })
+	t.Cleanup(func() {
+		providersMu.Lock()
+		delete(providers, "fake")
+		providersMu.Unlock()
+	})
+	g.Expect(func() {
+		RegisterProvider("fake", func(*Decryptor) Provider { return fp })
+	}).To(Panic())
+	g.Expect(func() { RegisterProvider("nil", nil) }).To(Panic())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "apps"},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+	kus := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{
+				Provider:  "fake",
+				SecretRef: &meta.LocalObjectReference{Name: "keys"},
+			},
+		},
+	}
+	root := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(root, "kustomization.yaml"), []byte(`
+secretGenerator:
+- name: app
+  envs:
+  - app.env
+`), 0o600)).To(Succeed())
+
+	d, cleanup, err := New(fake.NewClientBuilder().WithObjects(secret).Build(), kus, WithRoot(root))
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(cleanup)
+
+	g.Expect(d.ImportKeys(context.TODO())).To(Succeed())
+	g.Expect(fp.secret).ToNot(BeNil())
+	g.Expect(fp.secret.Data).To(HaveKeyWithValue("key", []byte("value")))
+
+	g.Expect(d.DecryptSources(root)).To(Succeed())
+	g.Expect(fp.files).To(ConsistOf("app.env"))
+
+	res, err := provider.NewDefaultDepProvider().GetResourceFactory().FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "app"},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	got, err := d.DecryptResource(res)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(res))
+	g.Expect(fp.resources).To(ConsistOf("app"))
+
+	// The resources with decryption disabled are not delegated.
+	res.SetAnnotations(map[string]string{"kustomize.toolkit.fluxcd.io/decrypt": "disabled"})
+	got, err = d.DecryptResource(res)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(BeNil())
+	g.Expect(fp.resources).To(HaveLen(1))
+}
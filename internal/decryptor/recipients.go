@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	extage "filippo.io/age"
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// KeyRecipients returns the recipients of the age identities and the
+// fingerprints of the PGP keys imported by ImportKeys, each prefixed with its
+// SOPS key type, e.g. 'age:age1...' or 'pgp:<fingerprint>', sorted and
+// without duplicates.
+func (d *Decryptor) KeyRecipients() []string {
+	var recipients []string
+	for _, identity := range d.ageIdentities {
+		if x25519, ok := identity.(*extage.X25519Identity); ok {
+			recipients = append(recipients, "age:"+x25519.Recipient().String())
+		}
+	}
+	recipients = append(recipients, d.pgpFingerprints...)
+	slices.Sort(recipients)
+	return slices.Compact(recipients)
+}
+
+// readPGPKeyRing reads the armored or binary PGP key ring. The key rings
+// which can't be parsed are ignored, as they were imported by GnuPG.
+func readPGPKeyRing(data []byte) openpgp.EntityList {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		if entities, err = openpgp.ReadKeyRing(bytes.NewReader(data)); err != nil {
+			return nil
+		}
+	}
+	return entities
+}
+
+// pgpFingerprint returns the fingerprint of the primary key of the entity,
+// prefixed with the SOPS key type.
+func pgpFingerprint(e *openpgp.Entity) string {
+	return fmt.Sprintf("pgp:%X", e.PrimaryKey.Fingerprint)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestDecryptor_KeyRecipients(t *testing.T) {
+	g := NewWithT(t)
+
+	pgpKey, err := os.ReadFile("testdata/pgp.asc")
+	g.Expect(err).ToNot(HaveOccurred())
+	ageKey, err := os.ReadFile("testdata/age.txt")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sops-keys", Namespace: "apps"},
+		Data: map[string][]byte{
+			"pgp" + DecryptionPGPExt: pgpKey,
+			"age" + DecryptionAgeExt: ageKey,
+			"dup" + DecryptionAgeExt: ageKey,
+		},
+	}
+	kus := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{
+				Provider:  DecryptionProviderSOPS,
+				SecretRef: &meta.LocalObjectReference{Name: "sops-keys"},
+			},
+		},
+	}
+	d, cleanup, err := New(fake.NewClientBuilder().WithObjects(secret).Build(), kus)
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(cleanup)
+
+	g.Expect(d.KeyRecipients()).To(BeEmpty())
+	g.Expect(d.ImportKeys(context.TODO())).To(Succeed())
+	g.Expect(d.KeyRecipients()).To(Equal([]string{
+		"age:age1l44xcng8dqj32nlv6d930qvvrny05hglzcv9qpc7kxjc6902ma4qufys29",
+		"pgp:35C1A64CD7FC0AB6EB66756B2445463C3234ECE1",
+	}))
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DecryptionProviderSealedSecrets is the name of the provider unsealing
+	// the Bitnami SealedSecrets.
+	DecryptionProviderSealedSecrets = "sealed-secrets"
+	// DecryptionSealedSecretsKeyExt is the extension of the files containing
+	// the PEM encoded RSA private keys of the sealed-secrets controller,
+	// e.g. the 'tls.key' entry of its key Secrets.
+	DecryptionSealedSecretsKeyExt = ".key"
+
+	// sealedSecretsGroup is the API group of the SealedSecrets.
+	sealedSecretsGroup = "bitnami.com"
+	// sealedSecretClusterWideAnnotation is the annotation of the
+	// SealedSecrets which can be unsealed with any name and namespace.
+	sealedSecretClusterWideAnnotation = "sealedsecrets.bitnami.com/cluster-wide"
+	// sealedSecretNamespaceWideAnnotation is the annotation of the
+	// SealedSecrets which can be unsealed with any name in their namespace.
+	sealedSecretNamespaceWideAnnotation = "sealedsecrets.bitnami.com/namespace-wide"
+)
+
+// sealedSecret is the subset of the Bitnami SealedSecret unsealed into
+// a Secret.
+type sealedSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		Template struct {
+			metav1.ObjectMeta `json:"metadata,omitempty"`
+			Type              corev1.SecretType `json:"type,omitempty"`
+			Data              map[string]string `json:"data,omitempty"`
+			Immutable         *bool             `json:"immutable,omitempty"`
+		} `json:"template,omitempty"`
+		EncryptedData map[string]string `json:"encryptedData"`
+	} `json:"spec"`
+}
+
+// sealedSecretsProvider is the DecryptionProviderSealedSecrets Provider,
+// unsealing the SealedSecrets into Secrets with the private keys of the
+// sealed-secrets controller imported from the decryption Secret, so that the
+// SealedSecrets can be applied without running the sealed-secrets controller.
+type sealedSecretsProvider struct {
+	d *Decryptor
+	// keys are the imported private keys.
+	keys []*rsa.PrivateKey
+}
+
+func newSealedSecretsProvider(d *Decryptor) Provider {
+	return &sealedSecretsProvider{d: d}
+}
+
+// ImportKeys imports the RSA private keys of the DecryptionSealedSecretsKeyExt
+// entries of the decryption Secret.
+func (p *sealedSecretsProvider) ImportKeys(_ context.Context, secret *corev1.Secret) error {
+	for name, value := range secret.Data {
+		if filepath.Ext(name) != DecryptionSealedSecretsKeyExt {
+			continue
+		}
+		keys, err := parseRSAPrivateKeys(value)
+		if err != nil {
+			return fmt.Errorf("failed to import '%s' data from %s decryption Secret '%s/%s': %w",
+				name, DecryptionProviderSealedSecrets, secret.Namespace, secret.Name, err)
+		}
+		p.keys = append(p.keys, keys...)
+	}
+	return nil
+}
+
+// DecryptResource unseals the SealedSecret resource into a Secret, with the
+// name and namespace of the SealedSecret, and the metadata and type of its
+// template.
+func (p *sealedSecretsProvider) DecryptResource(res *resource.Resource) (*resource.Resource, error) {
+	if res.GetGvk().Group != sealedSecretsGroup || res.GetKind() != "SealedSecret" {
+		return nil, nil
+	}
+	in, err := res.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var ss sealedSecret
+	if err := yaml.Unmarshal(in, &ss); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal '%s/%s' SealedSecret: %w", res.GetNamespace(), res.GetName(), err)
+	}
+	if len(ss.Spec.Template.Data) > 0 {
+		return nil, fmt.Errorf("failed to unseal '%s/%s' SealedSecret: templated data is not supported",
+			res.GetNamespace(), res.GetName())
+	}
+	if len(p.keys) == 0 {
+		return nil, fmt.Errorf("failed to unseal '%s/%s' SealedSecret: no private key found in the decryption Secret",
+			res.GetNamespace(), res.GetName())
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ss.GetName(),
+			Namespace:   ss.GetNamespace(),
+			Labels:      ss.Spec.Template.Labels,
+			Annotations: ss.Spec.Template.Annotations,
+		},
+		Type:      ss.Spec.Template.Type,
+		Immutable: ss.Spec.Template.Immutable,
+		Data:      make(map[string][]byte, len(ss.Spec.EncryptedData)),
+	}
+	label := sealedSecretLabel(&ss)
+	for key, value := range ss.Spec.EncryptedData {
+		ciphertext, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unseal '%s/%s' SealedSecret field '%s': %w",
+				res.GetNamespace(), res.GetName(), key, err)
+		}
+		plaintext, err := hybridDecrypt(p.keys, ciphertext, label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unseal '%s/%s' SealedSecret field '%s': %w",
+				res.GetNamespace(), res.GetName(), key, err)
+		}
+		secret.Data[key] = plaintext
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	out, err := u.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if err := res.UnmarshalJSON(out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal unsealed '%s/%s' Secret to JSON: %w",
+			res.GetNamespace(), res.GetName(), err)
+	}
+	return res, nil
+}
+
+// DecryptFile is a no-op, as the SealedSecrets are only unsealed in the
+// resources of the build.
+func (p *sealedSecretsProvider) DecryptFile(string, formats.Format, formats.Format) error {
+	return nil
+}
+
+// sealedSecretLabel returns the OAEP label the SealedSecret is sealed with,
+// according to its scope: empty for cluster-wide SealedSecrets, the
+// namespace for namespace-wide ones, and the namespace and name otherwise.
+func sealedSecretLabel(ss *sealedSecret) []byte {
+	switch {
+	case ss.GetAnnotations()[sealedSecretClusterWideAnnotation] == "true":
+		return []byte("")
+	case ss.GetAnnotations()[sealedSecretNamespaceWideAnnotation] == "true":
+		return []byte(ss.GetNamespace())
+	default:
+		return []byte(ss.GetNamespace() + "/" + ss.GetName())
+	}
+}
+
+// hybridDecrypt decrypts the ciphertext sealed by the sealed-secrets
+// controller, i.e. the 2 bytes length of the RSA-OAEP encrypted session key,
+// the encrypted session key, and the data encrypted with AES-GCM with the
+// session key and a zero nonce, with the first of the keys that can decrypt
+// the session key.
+func hybridDecrypt(keys []*rsa.PrivateKey, ciphertext, label []byte) ([]byte, error) {
+	if len(ciphertext) < 2 {
+		return nil, errors.New("ciphertext too short")
+	}
+	rsaLen := int(binary.BigEndian.Uint16(ciphertext))
+	if len(ciphertext) < rsaLen+2 {
+		return nil, errors.New("ciphertext too short")
+	}
+	rsaCiphertext, aesCiphertext := ciphertext[2:rsaLen+2], ciphertext[rsaLen+2:]
+
+	for _, key := range keys {
+		sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, rsaCiphertext, label)
+		if err != nil {
+			continue
+		}
+		block, err := aes.NewCipher(sessionKey)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		return aead.Open(nil, make([]byte, aead.NonceSize()), aesCiphertext, nil)
+	}
+	return nil, errors.New("no private key could decrypt the data, or the name, namespace or scope of the SealedSecret changed")
+}
+
+// parseRSAPrivateKeys parses the PKCS #1 or PKCS #8 RSA private keys of the
+// given PEM data.
+func parseRSAPrivateKeys(data []byte) ([]*rsa.PrivateKey, error) {
+	var keys []*rsa.PrivateKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			rsaKey, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("unsupported %T private key, must be RSA", key)
+			}
+			keys = append(keys, rsaKey)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded RSA private key found")
+	}
+	return keys, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/kustomize/api/provider"
+	"sigs.k8s.io/kustomize/api/resource"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// hybridEncrypt seals the plaintext like the sealed-secrets controller.
+func hybridEncrypt(t *testing.T, pub *rsa.PublicKey, plaintext, label []byte) string {
+	g := NewWithT(t)
+	sessionKey := make([]byte, 32)
+	_, err := rand.Read(sessionKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	block, err := aes.NewCipher(sessionKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	aead, err := cipher.NewGCM(block)
+	g.Expect(err).ToNot(HaveOccurred())
+	rsaCiphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, sessionKey, label)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ciphertext := binary.BigEndian.AppendUint16(nil, uint16(len(rsaCiphertext)))
+	ciphertext = append(ciphertext, rsaCiphertext...)
+	ciphertext = aead.Seal(ciphertext, make([]byte, aead.NonceSize()), plaintext, nil)
+	return base64.StdEncoding.EncodeToString(ciphertext)
+}
+
+func TestDecryptor_DecryptResource_sealedSecrets(t *testing.T) {
+	g := NewWithT(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).ToNot(HaveOccurred())
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).ToNot(HaveOccurred())
+	otherKeyDER, err := x509.MarshalPKCS8PrivateKey(otherKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	otherKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: otherKeyDER})
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sealed-secrets-keys", Namespace: "apps"},
+		Data: map[string][]byte{
+			"tls.key":     keyPEM,
+			"old-tls.key": otherKeyPEM,
+			"tls.crt":     []byte("ignored"),
+		},
+	}
+	kus := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{
+				Provider:  DecryptionProviderSealedSecrets,
+				SecretRef: &meta.LocalObjectReference{Name: "sealed-secrets-keys"},
+			},
+		},
+	}
+	d, cleanup, err := New(fake.NewClientBuilder().WithObjects(secret).Build(), kus)
+	g.Expect(err).ToNot(HaveOccurred())
+	t.Cleanup(cleanup)
+	g.Expect(d.ImportKeys(context.TODO())).To(Succeed())
+
+	factory := provider.NewDefaultDepProvider().GetResourceFactory()
+	newSealedSecret := func(annotations map[string]any, encryptedData map[string]any) *resource.Resource {
+		res, err := factory.FromMap(map[string]any{
+			"apiVersion": "bitnami.com/v1alpha1",
+			"kind":       "SealedSecret",
+			"metadata": map[string]any{
+				"name":        "db",
+				"namespace":   "apps",
+				"annotations": annotations,
+			},
+			"spec": map[string]any{
+				"encryptedData": encryptedData,
+				"template": map[string]any{
+					"metadata": map[string]any{"labels": map[string]any{"app": "db"}},
+					"type":     "kubernetes.io/basic-auth",
+				},
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		return res
+	}
+
+	t.Run("unseals strict scope", func(t *testing.T) {
+		g := NewWithT(t)
+		res := newSealedSecret(nil, map[string]any{
+			"password": hybridEncrypt(t, &key.PublicKey, []byte("s3cr3t"), []byte("apps/db")),
+			"username": hybridEncrypt(t, &otherKey.PublicKey, []byte("admin"), []byte("apps/db")),
+		})
+		got, err := d.DecryptResource(res)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(got.GetApiVersion()).To(Equal("v1"))
+		g.Expect(got.GetKind()).To(Equal("Secret"))
+		g.Expect(got.GetName()).To(Equal("db"))
+		g.Expect(got.GetNamespace()).To(Equal("apps"))
+		g.Expect(got.GetLabels()).To(HaveKeyWithValue("app", "db"))
+		g.Expect(got.GetDataMap()).To(Equal(map[string]string{
+			"password": base64.StdEncoding.EncodeToString([]byte("s3cr3t")),
+			"username": base64.StdEncoding.EncodeToString([]byte("admin")),
+		}))
+		m, err := got.Map()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(m).To(HaveKeyWithValue("type", "kubernetes.io/basic-auth"))
+		g.Expect(m).ToNot(HaveKey("spec"))
+	})
+
+	t.Run("unseals namespace and cluster wide scopes", func(t *testing.T) {
+		g := NewWithT(t)
+		res := newSealedSecret(map[string]any{sealedSecretNamespaceWideAnnotation: "true"}, map[string]any{
+			"password": hybridEncrypt(t, &key.PublicKey, []byte("s3cr3t"), []byte("apps")),
+		})
+		_, err := d.DecryptResource(res)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		res = newSealedSecret(map[string]any{sealedSecretClusterWideAnnotation: "true"}, map[string]any{
+			"password": hybridEncrypt(t, &key.PublicKey, []byte("s3cr3t"), nil),
+		})
+		_, err = d.DecryptResource(res)
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("fails for another name", func(t *testing.T) {
+		g := NewWithT(t)
+		res := newSealedSecret(nil, map[string]any{
+			"password": hybridEncrypt(t, &key.PublicKey, []byte("s3cr3t"), []byte("apps/other")),
+		})
+		_, err := d.DecryptResource(res)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to unseal 'apps/db' SealedSecret field 'password'")))
+	})
+
+	t.Run("ignores other resources", func(t *testing.T) {
+		g := NewWithT(t)
+		res, err := factory.FromMap(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]any{"name": "db"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		got, err := d.DecryptResource(res)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(BeNil())
+	})
+}
+
+func TestParseRSAPrivateKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := parseRSAPrivateKeys([]byte("not a key"))
+	g.Expect(err).To(MatchError("no PEM encoded RSA private key found"))
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).ToNot(HaveOccurred())
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	keys, err := parseRSAPrivateKeys(append(keyPEM, keyPEM...))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keys).To(HaveLen(2))
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// secretReadBackoff is the backoff of the retries of the decryption Secret
+// reads failing with a transient error.
+var secretReadBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+// KeysSecret describes the Secret the decryption keys were imported from.
+type KeysSecret struct {
+	types.NamespacedName
+
+	// ResourceVersion is the resourceVersion of the Secret
+	// the keys were imported from.
+	ResourceVersion string
+
+	// Cached is true if the Secret couldn't be read due to a transient
+	// error, and the keys were imported from its last read version.
+	Cached bool
+}
+
+// SecretCache holds the last version read of the decryption Secrets, used
+// when a Secret can't be read due to a transient error. It is safe for
+// concurrent use, and its zero value is ready to use.
+type SecretCache struct {
+	secrets sync.Map
+}
+
+func (c *SecretCache) load(key types.NamespacedName) (*corev1.Secret, bool) {
+	if c == nil {
+		return nil, false
+	}
+	v, ok := c.secrets.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return v.(*corev1.Secret), true
+}
+
+func (c *SecretCache) store(secret *corev1.Secret) {
+	if c == nil {
+		return
+	}
+	c.secrets.Store(types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, secret.DeepCopy())
+}
+
+func (c *SecretCache) delete(key types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.secrets.Delete(key)
+}
+
+// KeysSecret returns the Secret the keys were imported from by ImportKeys,
+// or nil if the keys were not imported from a Secret.
+func (d *Decryptor) KeysSecret() *KeysSecret {
+	return d.keysSecret
+}
+
+// getKeysSecret reads the decryption Secret, retrying the reads failing with
+// a transient error. If the Secret still can't be read, its last version
+// read is returned from the SecretCache, if any. The Secret is recorded as
+// the one the keys are imported from.
+func (d *Decryptor) getKeysSecret(ctx context.Context, key types.NamespacedName) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	err := retry.OnError(secretReadBackoff, isTransientReadError, func() error {
+		return d.client.Get(ctx, key, &secret)
+	})
+	switch {
+	case err == nil:
+		d.secretCache.store(&secret)
+		d.keysSecret = &KeysSecret{NamespacedName: key, ResourceVersion: secret.ResourceVersion}
+		return &secret, nil
+	case apierrors.IsNotFound(err):
+		d.secretCache.delete(key)
+	case isTransientReadError(err):
+		if cached, ok := d.secretCache.load(key); ok {
+			d.keysSecret = &KeysSecret{NamespacedName: key, ResourceVersion: cached.ResourceVersion, Cached: true}
+			return cached, nil
+		}
+	}
+	return nil, err
+}
+
+// isTransientReadError returns true if the read failed due to an error
+// expected to resolve by itself, e.g. a throttled request or an API server
+// unavailable during an upgrade.
+func isTransientReadError(err error) bool {
+	var netErr net.Error
+	return apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decryptor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func TestDecryptor_ImportKeys_secretReadRetries(t *testing.T) {
+	secretReadBackoff = wait.Backoff{Steps: 3, Duration: time.Millisecond}
+	t.Cleanup(func() {
+		secretReadBackoff = wait.Backoff{Steps: 4, Duration: 500 * time.Millisecond, Factor: 2, Jitter: 0.1}
+	})
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sops-keys", Namespace: "apps"},
+		Data:       map[string][]byte{DecryptionVaultTokenFileName: []byte("token")},
+	}
+	kus := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "apps"},
+		Spec: kustomizev1.KustomizationSpec{
+			Decryption: &kustomizev1.Decryption{
+				Provider:  DecryptionProviderSOPS,
+				SecretRef: &meta.LocalObjectReference{Name: "sops-keys"},
+			},
+		},
+	}
+
+	// failures is the number of reads to fail, and readErr their error.
+	var failures int
+	var readErr error
+	kubeClient := fake.NewClientBuilder().WithObjects(secret).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if failures > 0 {
+				failures--
+				return readErr
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+	secretCache := &SecretCache{}
+	importKeys := func() (*Decryptor, error) {
+		d, cleanup, err := New(kubeClient, kus, WithSecretCache(secretCache))
+		if err != nil {
+			return nil, err
+		}
+		t.Cleanup(cleanup)
+		return d, d.ImportKeys(context.TODO())
+	}
+	unavailable := apierrors.NewServiceUnavailable("etcd leader changed")
+
+	t.Run("retries the transient errors", func(t *testing.T) {
+		g := NewWithT(t)
+		failures, readErr = 2, unavailable
+		d, err := importKeys()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(d.vaultToken).To(Equal("token"))
+		g.Expect(d.KeysSecret()).ToNot(BeNil())
+		g.Expect(d.KeysSecret().Name).To(Equal("sops-keys"))
+		g.Expect(d.KeysSecret().ResourceVersion).ToNot(BeEmpty())
+		g.Expect(d.KeysSecret().Cached).To(BeFalse())
+	})
+
+	t.Run("falls back to the last version read", func(t *testing.T) {
+		g := NewWithT(t)
+		failures, readErr = 10, unavailable
+		d, err := importKeys()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(d.vaultToken).To(Equal("token"))
+		g.Expect(d.KeysSecret().Cached).To(BeTrue())
+	})
+
+	t.Run("does not retry the other errors", func(t *testing.T) {
+		g := NewWithT(t)
+		failures, readErr = 1, apierrors.NewForbidden(corev1.Resource("secrets"), "sops-keys", errors.New("denied"))
+		_, err := importKeys()
+		g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+	})
+
+	t.Run("forgets the deleted Secrets", func(t *testing.T) {
+		g := NewWithT(t)
+		failures = 0
+		g.Expect(kubeClient.Delete(context.TODO(), secret)).To(Succeed())
+		_, err := importKeys()
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		failures, readErr = 10, unavailable
+		_, err = importKeys()
+		g.Expect(err).To(MatchError(ContainSubstring("etcd leader changed")))
+	})
+}
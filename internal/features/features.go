@@ -67,6 +67,84 @@ const (
 	// reports the defaulted field as "field not declared in schema" when
 	// validating managed fields against the old version's schema.
 	MigrateAPIVersion = "MigrateAPIVersion"
+
+	// SkipHPAReplicasDrift controls whether the drift correction of the
+	// spec.replicas field should be skipped for objects targeted by a
+	// HorizontalPodAutoscaler.
+	//
+	// When enabled, the replicas set by the autoscaler are preserved and an
+	// informational event is emitted instead of reverting them to the value
+	// declared in the manifests.
+	SkipHPAReplicasDrift = "SkipHPAReplicasDrift"
+
+	// CacheBuildOnManualReconcile controls whether the controller keeps the
+	// last build result of every Kustomization in memory, and reuses it when
+	// a manual reconcile is requested for the same source revision and spec.
+	//
+	// This speeds up on-demand reconciliations of large Kustomizations at the
+	// cost of increased memory usage.
+	CacheBuildOnManualReconcile = "CacheBuildOnManualReconcile"
+
+	// ApplyJournal controls whether the controller records the progress of
+	// the apply in a ConfigMap owned by the Kustomization, to resume the
+	// reconciliation interrupted by a controller crash or restart without
+	// re-applying a fully applied revision.
+	ApplyJournal = "ApplyJournal"
+
+	// CacheApplyReads controls whether the reads issued by the apply for the
+	// kinds listed with the --apply-read-cache-kinds flag are served from
+	// the shared informer cache of the controller instead of the API server.
+	//
+	// This reduces the read requests of frequently reconciled Kustomizations
+	// at the cost of watching the cached kinds cluster-wide.
+	CacheApplyReads = "CacheApplyReads"
+
+	// WatchInventoryKinds controls whether the controller watches the
+	// metadata of the kinds present in the inventories of the Kustomizations
+	// with the Immediate drift correction mode, to reconcile a Kustomization
+	// as soon as one of its objects is modified by another field manager
+	// or deleted.
+	//
+	// The watches are restricted to the objects labeled by the controller,
+	// and are stopped when no Kustomization manages objects of a kind anymore.
+	WatchInventoryKinds = "WatchInventoryKinds"
+
+	// RefreshRemoteTokens controls whether the controller refreshes in the
+	// background the credentials of the remote clusters minted with the
+	// workload identity provider of a kubeconfig ConfigMap, once half of
+	// their lifetime has passed, instead of minting them on the first
+	// request after their expiration.
+	//
+	// This keeps the token endpoints of the providers off the critical path
+	// of the reconciliations in fleets with many remote clusters.
+	RefreshRemoteTokens = "RefreshRemoteTokens"
+
+	// SkipUnchangedConfigRenders controls whether the controller skips the
+	// apply and the health checks of a Kustomization reconciled for the
+	// change of a ConfigMap or Secret used only to render its manifests,
+	// e.g. with post-build substitutions, when the rendered manifests are
+	// identical to the last successfully applied ones.
+	//
+	// This avoids no-op reconciliations triggered by changes of unrelated
+	// keys of shared ConfigMaps and Secrets.
+	SkipUnchangedConfigRenders = "SkipUnchangedConfigRenders"
+
+	// ClusterFactsSubstitution controls whether the controller injects the
+	// facts of the cluster it runs in, i.e. the Kubernetes version, the range
+	// of the number of nodes and the platform provider of the nodes, as
+	// variables into the post-build substitutions.
+	//
+	// This allows overlays to toggle features by cluster capability without
+	// maintaining a ConfigMap of the cluster facts.
+	ClusterFactsSubstitution = "ClusterFactsSubstitution"
+
+	// InventoryObjectRevisions controls whether the controller records in
+	// the inventory entries the source revision at which each object last
+	// changed, along with a digest of its manifest.
+	//
+	// This increases the size of the Kustomization status proportionally
+	// to the number of managed objects.
+	InventoryObjectRevisions = "InventoryObjectRevisions"
 )
 
 var features = map[string]bool{
@@ -103,6 +181,33 @@ var features = map[string]bool{
 	// MigrateAPIVersion
 	// opt-in from v1.8.4
 	MigrateAPIVersion: false,
+	// SkipHPAReplicasDrift
+	// opt-in from v1.10
+	SkipHPAReplicasDrift: false,
+	// CacheBuildOnManualReconcile
+	// opt-in from v1.10
+	CacheBuildOnManualReconcile: false,
+	// ApplyJournal
+	// opt-in from v1.10
+	ApplyJournal: false,
+	// CacheApplyReads
+	// opt-in from v1.10
+	CacheApplyReads: false,
+	// WatchInventoryKinds
+	// opt-in from v1.10
+	WatchInventoryKinds: false,
+	// RefreshRemoteTokens
+	// opt-in from v1.10
+	RefreshRemoteTokens: false,
+	// SkipUnchangedConfigRenders
+	// opt-in from v1.10
+	SkipUnchangedConfigRenders: false,
+	// ClusterFactsSubstitution
+	// opt-in from v1.10
+	ClusterFactsSubstitution: false,
+	// InventoryObjectRevisions
+	// opt-in from v1.10
+	InventoryObjectRevisions: false,
 }
 
 func init() {
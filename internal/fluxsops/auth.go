@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fluxsops
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultTokenAudience is the default audience of the
+	// ServiceAccount tokens of the callers.
+	DefaultTokenAudience = "flux-sops"
+
+	// authorizationKey is the metadata key of the bearer token of the callers.
+	authorizationKey = "authorization"
+
+	// healthServicePrefix is the prefix of the methods of the health
+	// service, which are served without authentication for the probes.
+	healthServicePrefix = "/grpc.health.v1.Health/"
+
+	// serviceAccountPrefix is the prefix of the usernames of ServiceAccounts.
+	serviceAccountPrefix = "system:serviceaccount:"
+)
+
+// caller is the ServiceAccount of an authenticated caller.
+type caller struct {
+	namespace string
+	name      string
+	// anyNamespace is true if the caller is trusted to request the
+	// decryption for the objects of any namespace.
+	anyNamespace bool
+}
+
+// String returns the '<namespace>/<name>' reference of the ServiceAccount.
+func (c caller) String() string {
+	return c.namespace + "/" + c.name
+}
+
+type callerKey struct{}
+
+// callerFrom returns the caller added to the context by the Authenticator.
+func callerFrom(ctx context.Context) (caller, bool) {
+	c, ok := ctx.Value(callerKey{}).(caller)
+	return c, ok
+}
+
+// Authenticator authenticates the callers of the server with the TokenReview
+// API, from the ServiceAccount token sent as a bearer token in the
+// 'authorization' metadata of the requests.
+type Authenticator struct {
+	client         client.Client
+	audience       string
+	trustedCallers []string
+}
+
+// NewAuthenticator returns an Authenticator reviewing the tokens with the
+// given client and audience. The trusted callers, in the '<namespace>/<name>'
+// format, are allowed to request the decryption for the objects of any
+// namespace, while the other callers are restricted to their own namespace.
+func NewAuthenticator(client client.Client, audience string, trustedCallers []string) *Authenticator {
+	return &Authenticator{
+		client:         client,
+		audience:       audience,
+		trustedCallers: trustedCallers,
+	}
+}
+
+// UnaryInterceptor authenticates the caller of the request, and adds its
+// identity to the context of the handler. The health service is served
+// without authentication.
+func (a *Authenticator) UnaryInterceptor(ctx context.Context, req any,
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+		return handler(ctx, req)
+	}
+	c, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, callerKey{}, c), req)
+}
+
+// authenticate returns the ServiceAccount of the bearer token of the request.
+func (a *Authenticator) authenticate(ctx context.Context) (caller, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationKey)
+	if len(values) == 0 {
+		return caller{}, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return caller{}, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{a.audience},
+		},
+	}
+	if err := a.client.Create(ctx, review); err != nil {
+		return caller{}, status.Error(codes.Unavailable, fmt.Sprintf("failed to review the token: %s", err))
+	}
+	if !review.Status.Authenticated || !slices.Contains(review.Status.Audiences, a.audience) {
+		return caller{}, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	ref, ok := strings.CutPrefix(review.Status.User.Username, serviceAccountPrefix)
+	namespace, name, found := strings.Cut(ref, ":")
+	if !ok || !found || namespace == "" || name == "" {
+		return caller{}, status.Errorf(codes.PermissionDenied,
+			"user '%s' is not a ServiceAccount", review.Status.User.Username)
+	}
+	c := caller{namespace: namespace, name: name}
+	c.anyNamespace = slices.Contains(a.trustedCallers, c.String())
+	return c, nil
+}
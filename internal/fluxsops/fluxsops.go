@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fluxsops implements the flux-sops subcommand of the controller,
+// which serves the decryption of artifacts over gRPC, so that the other
+// Flux controllers, e.g. run in the same pod, decrypt the SOPS encrypted
+// files of their artifacts without holding the decryption keys.
+package fluxsops
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/auth"
+	runtimeClient "github.com/fluxcd/pkg/runtime/client"
+	runtimeCtrl "github.com/fluxcd/pkg/runtime/controller"
+	feathelper "github.com/fluxcd/pkg/runtime/features"
+	"github.com/fluxcd/pkg/runtime/logger"
+
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+	"github.com/fluxcd/kustomize-controller/internal/features"
+)
+
+// Command is the name of the flux-sops subcommand.
+const Command = "flux-sops"
+
+// unixPrefix is the prefix of the listen addresses of Unix domain sockets.
+const unixPrefix = "unix://"
+
+// Run runs the flux-sops subcommand with the given arguments, i.e. the ones
+// following the subcommand name, until a termination signal is received,
+// and returns the exit code.
+func Run(args []string, stderr io.Writer) int {
+	var (
+		listenAddress                   string
+		tlsCertFile                     string
+		tlsKeyFile                      string
+		tokenAudience                   string
+		trustedCallers                  []string
+		root                            string
+		sopsAgeSecret                   string
+		defaultDecryptionServiceAccount string
+		maxBinaryFileSize               int64
+		keysCacheTTL                    time.Duration
+		clientOptions                   runtimeClient.Options
+		logOptions                      logger.Options
+		featureGates                    feathelper.FeatureGates
+	)
+
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: kustomize-controller %s [flags]\n\n", Command)
+		fmt.Fprintln(stderr, "Serve the decryption of the artifacts under the root directory over gRPC.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&listenAddress, "listen-address", unixPrefix+"/run/flux-sops/flux-sops.sock",
+		"The address the gRPC server binds to, either 'unix://<path>' for a Unix domain socket or '<host>:<port>', which requires --tls-cert-file and --tls-key-file.")
+	flags.StringVar(&tlsCertFile, "tls-cert-file", "",
+		"The TLS certificate file of the server, required to listen on a TCP address.")
+	flags.StringVar(&tlsKeyFile, "tls-key-file", "",
+		"The TLS key file of the server, required to listen on a TCP address.")
+	flags.StringVar(&tokenAudience, "token-audience", DefaultTokenAudience,
+		"The audience of the ServiceAccount tokens authenticating the callers.")
+	flags.StringArrayVar(&trustedCallers, "trusted-caller", []string{},
+		"ServiceAccount, in the '<namespace>/<name>' format, allowed to request the decryption for the objects of any namespace. The other callers are restricted to their own namespace.")
+	flags.StringVar(&root, "root", "/artifacts",
+		"The directory the artifacts to decrypt are stored under, e.g. a volume shared with the calling controllers.")
+	flags.StringVar(&sopsAgeSecret, "sops-age-secret", "",
+		"The name of a Kubernetes secret in the RUNTIME_NAMESPACE containing a SOPS age decryption key for fallback usage.")
+	flags.StringVar(&defaultDecryptionServiceAccount, auth.ControllerFlagDefaultDecryptionServiceAccount, "",
+		"Default service account used for decryption.")
+	flags.Int64Var(&maxBinaryFileSize, "decryption-max-binary-file-size", 100<<20,
+		"The maximum size in bytes of the SOPS encrypted files in the binary format. Zero removes the limit.")
+	flags.DurationVar(&keysCacheTTL, "decryption-keys-cache-ttl", 5*time.Minute,
+		"The duration for which the keys imported from a version of a decryption Secret are reused. Zero disables the cache.")
+	clientOptions.BindFlags(flags)
+	logOptions.BindFlags(flags)
+	featureGates.BindFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	logger.SetLogger(logger.NewLogger(logOptions))
+	log := ctrl.Log.WithName(Command)
+
+	if !strings.HasPrefix(listenAddress, unixPrefix) && (tlsCertFile == "" || tlsKeyFile == "") {
+		log.Error(errors.New("--tls-cert-file and --tls-key-file are required to listen on a TCP address"),
+			"invalid configuration")
+		return 1
+	}
+
+	if err := featureGates.WithLogger(log).SupportedFeatures(features.FeatureGates()); err != nil {
+		log.Error(err, "unable to load feature gates")
+		return 1
+	}
+	switch enabled, err := features.Enabled(auth.FeatureGateObjectLevelWorkloadIdentity); {
+	case err != nil:
+		log.Error(err, "unable to check feature gate "+auth.FeatureGateObjectLevelWorkloadIdentity)
+		return 1
+	case enabled:
+		auth.EnableObjectLevelWorkloadIdentity()
+	}
+	if defaultDecryptionServiceAccount != "" {
+		auth.SetDefaultDecryptionServiceAccount(defaultDecryptionServiceAccount)
+	}
+	if auth.InconsistentObjectLevelConfiguration() {
+		log.Error(auth.ErrInconsistentObjectLevelConfiguration, "invalid configuration")
+		return 1
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kubeClient, err := client.New(runtimeClient.GetConfigOrDie(clientOptions), client.Options{Scheme: scheme})
+	if err != nil {
+		log.Error(err, "unable to create the Kubernetes client")
+		return 1
+	}
+
+	var (
+		secrets  decryptor.SecretCache
+		keyrings decryptor.KeyringCache
+	)
+	opts := []decryptor.Option{
+		decryptor.WithSecretCache(&secrets),
+		decryptor.WithMaxBinaryFileSize(maxBinaryFileSize),
+	}
+	if keysCacheTTL > 0 {
+		opts = append(opts, decryptor.WithKeyringCache(&keyrings, keysCacheTTL))
+	}
+	if ns := os.Getenv(runtimeCtrl.EnvRuntimeNamespace); sopsAgeSecret != "" && ns != "" {
+		opts = append(opts, decryptor.WithSOPSAgeSecret(sopsAgeSecret, ns))
+	}
+
+	serverOpts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(NewAuthenticator(kubeClient, tokenAudience, trustedCallers).UnaryInterceptor),
+	}
+	if tlsCertFile != "" || tlsKeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(tlsCertFile, tlsKeyFile)
+		if err != nil {
+			log.Error(err, "unable to load the TLS certificate")
+			return 1
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	NewServer(kubeClient, root, log, opts...).Register(grpcServer)
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+
+	lis, err := listen(listenAddress)
+	if err != nil {
+		log.Error(err, "unable to listen", "address", listenAddress)
+		return 1
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
+	log.Info("serving the decryption of the artifacts", "address", listenAddress, "root", root)
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Error(err, "failed to serve")
+		return 1
+	}
+	return 0
+}
+
+// listen returns a listener on the given Unix domain socket or TCP address.
+// A stale socket, e.g. left by a previous run, is removed.
+func listen(address string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(address, unixPrefix)
+	if !ok {
+		return net.Listen("tcp", address)
+	}
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fluxsops
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+const (
+	// ServiceName is the full name of the gRPC service of the server.
+	ServiceName = "flux.sops.v1.Decryptor"
+
+	// DecryptArtifactMethod is the full name of the method decrypting
+	// the files of an artifact.
+	DecryptArtifactMethod = "/" + ServiceName + "/DecryptArtifact"
+
+	// defaultName is the name of the Kustomization the decryptor is created
+	// for when the request doesn't name the object it is issued for.
+	defaultName = "flux-sops"
+)
+
+// DecryptArtifactRequest is the request of the DecryptArtifact method.
+type DecryptArtifactRequest struct {
+	// Path is the path of the artifact directory or file to decrypt in
+	// place, relative to the root of the server.
+	Path string `json:"path"`
+	// Namespace is the namespace of the decryption Secret and ServiceAccount,
+	// i.e. the namespace of the object the request is issued for. Defaults
+	// to the namespace of the caller, other namespaces are only allowed for
+	// the trusted callers.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object the request is issued for, e.g. a
+	// HelmRelease, used to identify the request in the logs.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Decryption is the decryption spec of the object, with the semantics
+	// of the spec.decryption of the Kustomizations.
+	Decryption *kustomizev1.Decryption `json:"decryption"`
+}
+
+// DecryptArtifactResponse is the response of the DecryptArtifact method.
+type DecryptArtifactResponse struct{}
+
+// decryptorServer is the interface of the handlers of the service.
+type decryptorServer interface {
+	DecryptArtifact(ctx context.Context, req *DecryptArtifactRequest) (*DecryptArtifactResponse, error)
+}
+
+// serviceDesc describes the gRPC service, whose messages are encoded
+// with the json codec.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*decryptorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DecryptArtifact",
+			Handler:    decryptArtifactHandler,
+		},
+	},
+	Metadata: "flux-sops",
+}
+
+func decryptArtifactHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	req := &DecryptArtifactRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(decryptorServer).DecryptArtifact(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DecryptArtifactMethod,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(decryptorServer).DecryptArtifact(ctx, req.(*DecryptArtifactRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+// Server decrypts in place the files of the artifacts under its root, e.g.
+// a volume shared with the controller issuing the requests, with the keys
+// of the decryption spec of the requests.
+type Server struct {
+	client client.Client
+	root   string
+	opts   []decryptor.Option
+	log    logr.Logger
+}
+
+// NewServer returns a Server decrypting the artifacts under the given root,
+// reading the decryption Secrets with the given client, and creating the
+// decryptors with the given options.
+func NewServer(client client.Client, root string, log logr.Logger, opts ...decryptor.Option) *Server {
+	return &Server{
+		client: client,
+		root:   root,
+		opts:   opts,
+		log:    log,
+	}
+}
+
+// Register registers the service of the server with the given registrar.
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	registrar.RegisterService(&serviceDesc, s)
+}
+
+// DecryptArtifact decrypts in place the YAML, JSON, dotenv and INI files
+// of the artifact at the path of the request, with the keys of its
+// decryption spec. The caller must be authenticated by the Authenticator,
+// and the namespace of the request must be the namespace of the caller,
+// unless the caller is trusted.
+func (s *Server) DecryptArtifact(ctx context.Context, req *DecryptArtifactRequest) (*DecryptArtifactResponse, error) {
+	c, ok := callerFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = c.namespace
+	}
+	if namespace != c.namespace && !c.anyNamespace {
+		return nil, status.Errorf(codes.PermissionDenied,
+			"caller '%s' is not allowed to decrypt for the namespace '%s'", c, namespace)
+	}
+
+	switch {
+	case req.Path == "":
+		return nil, status.Error(codes.InvalidArgument, "path is required")
+	case req.Decryption == nil:
+		return nil, status.Error(codes.InvalidArgument, "decryption is required")
+	case !slices.Contains(decryptor.ProviderNames(), req.Decryption.Provider):
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported decryption provider '%s'", req.Decryption.Provider)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = defaultName
+	}
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       kustomizev1.KustomizationSpec{Decryption: req.Decryption},
+	}
+	log := s.log.WithValues("name", name, "namespace", namespace, "path", req.Path, "caller", c.String())
+
+	opts := append(slices.Clone(s.opts), decryptor.WithRoot(s.root), decryptor.WithContext(ctx))
+	dec, cleanup, err := decryptor.New(s.client, obj, opts...)
+	if err != nil {
+		log.Error(err, "failed to create the decryptor")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer cleanup()
+
+	if err := dec.ImportKeys(ctx); err != nil {
+		log.Error(err, "failed to import the decryption keys")
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	dec.SetAuthOptions(ctx)
+
+	if err := dec.DecryptFiles(req.Path); err != nil {
+		log.Error(err, "failed to decrypt the artifact")
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	log.V(1).Info("decrypted the artifact")
+	return &DecryptArtifactResponse{}, nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fluxsops
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+)
+
+func newTestConn(t *testing.T, srv *Server) *grpc.ClientConn {
+	t.Helper()
+
+	// The tokens are reviewed as the ones of the ServiceAccounts they name.
+	kubeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review, ok := obj.(*authenticationv1.TokenReview)
+			if !ok {
+				return c.Create(ctx, obj, opts...)
+			}
+			if username, ok := strings.CutPrefix(review.Spec.Token, "valid:"); ok {
+				review.Status = authenticationv1.TokenReviewStatus{
+					Authenticated: true,
+					User:          authenticationv1.UserInfo{Username: username},
+					Audiences:     review.Spec.Audiences,
+				}
+			}
+			return nil
+		},
+	}).Build()
+	auth := NewAuthenticator(kubeClient, DefaultTokenAudience, []string{"flux-system/helm-controller"})
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(auth.UnaryInterceptor))
+	srv.Register(grpcServer)
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	go func() { _ = grpcServer.Serve(lis) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///flux-sops",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestServer_DecryptArtifact(t *testing.T) {
+	g := NewWithT(t)
+
+	ageKey, err := os.ReadFile("testdata/age.txt")
+	g.Expect(err).ToNot(HaveOccurred())
+	encData, err := os.ReadFile("testdata/podinfo/values.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	root := t.TempDir()
+	valuesPath := filepath.Join(root, "podinfo", "values.yaml")
+	g.Expect(os.MkdirAll(filepath.Dir(valuesPath), 0o700)).To(Succeed())
+	g.Expect(os.WriteFile(valuesPath, encData, 0o600)).To(Succeed())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sops-keys", Namespace: "apps"},
+		Data: map[string][]byte{
+			"identity" + decryptor.DecryptionAgeExt: ageKey,
+		},
+	}
+	srv := NewServer(fake.NewClientBuilder().WithObjects(secret).Build(), root, logr.Discard())
+	conn := newTestConn(t, srv)
+
+	decryption := &kustomizev1.Decryption{
+		Provider:  decryptor.DecryptionProviderSOPS,
+		SecretRef: &meta.LocalObjectReference{Name: "sops-keys"},
+	}
+	tests := []struct {
+		name     string
+		token    string
+		req      *DecryptArtifactRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "missing token",
+			req:      &DecryptArtifactRequest{Path: "podinfo", Decryption: decryption},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid token",
+			token:    "invalid",
+			req:      &DecryptArtifactRequest{Path: "podinfo", Decryption: decryption},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "caller not a ServiceAccount",
+			token:    "valid:alice",
+			req:      &DecryptArtifactRequest{Path: "podinfo", Decryption: decryption},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:  "namespace of another caller",
+			token: "valid:system:serviceaccount:other:helm-controller",
+			req: &DecryptArtifactRequest{
+				Path:       "podinfo",
+				Namespace:  "apps",
+				Decryption: decryption,
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "missing path",
+			token:    "valid:system:serviceaccount:apps:helm-controller",
+			req:      &DecryptArtifactRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:  "unsupported provider",
+			token: "valid:system:serviceaccount:apps:helm-controller",
+			req: &DecryptArtifactRequest{
+				Path:       "podinfo",
+				Decryption: &kustomizev1.Decryption{Provider: "unknown"},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:  "missing decryption Secret",
+			token: "valid:system:serviceaccount:flux-system:helm-controller",
+			req: &DecryptArtifactRequest{
+				Path:       "podinfo",
+				Namespace:  "other",
+				Decryption: decryption,
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:  "missing artifact",
+			token: "valid:system:serviceaccount:apps:helm-controller",
+			req: &DecryptArtifactRequest{
+				Path:       "missing",
+				Decryption: decryption,
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:  "decrypts the artifact",
+			token: "valid:system:serviceaccount:flux-system:helm-controller",
+			req: &DecryptArtifactRequest{
+				Path:       "podinfo",
+				Namespace:  "apps",
+				Name:       "podinfo",
+				Decryption: decryption,
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, authorizationKey, "Bearer "+tt.token)
+			}
+			err := conn.Invoke(ctx, DecryptArtifactMethod, tt.req, &DecryptArtifactResponse{})
+			g.Expect(status.Code(err)).To(Equal(tt.wantCode), "%v", err)
+		})
+	}
+
+	// The health service is served without authentication.
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{},
+		grpc.CallContentSubtype("proto"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	b, err := os.ReadFile(valuesPath)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(b)).ToNot(ContainSubstring("ENC["))
+	g.Expect(string(b)).ToNot(ContainSubstring("sops:"))
+}
+
+func TestListen(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "flux-sops.sock")
+	lis, err := listen(unixPrefix + path)
+	g.Expect(err).ToNot(HaveOccurred())
+	// Leave the socket behind, as on an unclean shutdown.
+	lis.(*net.UnixListener).SetUnlinkOnClose(false)
+	g.Expect(lis.Close()).To(Succeed())
+
+	lis, err = listen(unixPrefix + path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(lis.Close()).To(Succeed())
+
+	// Other files are not removed.
+	g.Expect(os.WriteFile(path, nil, 0o600)).To(Succeed())
+	_, err = listen(unixPrefix + path)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRun_RequiresTLSForTCP(t *testing.T) {
+	g := NewWithT(t)
+
+	var stderr strings.Builder
+	g.Expect(Run([]string{"--listen-address=127.0.0.1:9090"}, &stderr)).To(Equal(1))
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	// SubsystemSource is the subsystem downloading the source artifacts.
+	SubsystemSource = "source"
+
+	// SubsystemDecryptionPrefix prefixes the subsystems of the SOPS
+	// key providers, e.g. 'decryption/age' or 'decryption/hc_vault'.
+	SubsystemDecryptionPrefix = "decryption/"
+
+	// SubsystemAPIDiscovery is the subsystem discovering the
+	// Kubernetes API groups and resources.
+	SubsystemAPIDiscovery = "apiDiscovery"
+
+	// SubsystemWorkQueue is the work queue of the controller.
+	SubsystemWorkQueue = "workQueue"
+)
+
+// RecordDiscovery discovers the Kubernetes API groups every interval until
+// the context is canceled, and records the outcome for the API discovery
+// subsystem, whose LastUpdateTime tells the freshness of the discovery.
+// The discovery runs in the background, so that requesting the report
+// doesn't issue requests to the Kubernetes API server.
+func (t *Tracker) RecordDiscovery(ctx context.Context, client discovery.DiscoveryInterface, interval time.Duration) {
+	wait.UntilWithContext(ctx, func(context.Context) {
+		if _, err := client.ServerGroups(); err != nil {
+			t.Record(SubsystemAPIDiscovery, fmt.Errorf("failed to discover API groups: %w", err))
+			return
+		}
+		t.Record(SubsystemAPIDiscovery, nil)
+	}, interval)
+}
+
+// WorkQueueCheck returns a Check that fails if the work queue of the given
+// controller is saturated, i.e. all its workers are busy while objects are
+// waiting in the queue. The check reads the controller-runtime metrics
+// from the given gatherer.
+func WorkQueueCheck(gatherer prometheus.Gatherer, controller string) Check {
+	return func(_ context.Context) error {
+		families, err := gatherer.Gather()
+		if err != nil {
+			return fmt.Errorf("failed to gather metrics: %w", err)
+		}
+
+		var depth, active, workers float64
+		for _, mf := range families {
+			switch mf.GetName() {
+			case "workqueue_depth":
+				depth = sumGauges(mf, controller)
+			case "controller_runtime_active_workers":
+				active = sumGauges(mf, controller)
+			case "controller_runtime_max_concurrent_reconciles":
+				workers = sumGauges(mf, controller)
+			}
+		}
+
+		if workers > 0 && active >= workers && depth > 0 {
+			return &reasonError{
+				reason: "WorkQueueSaturated",
+				err: fmt.Errorf("work queue saturated: %.0f/%.0f workers busy, %.0f objects waiting",
+					active, workers, depth),
+			}
+		}
+		return nil
+	}
+}
+
+// sumGauges sums the gauges of the metric family labeled with the controller.
+func sumGauges(mf *dto.MetricFamily, controller string) float64 {
+	var sum float64
+	for _, m := range mf.GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetName() == "controller" && l.GetValue() == controller {
+				sum += m.GetGauge().GetValue()
+				break
+			}
+		}
+	}
+	return sum
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// DetailPath is the path of the endpoint serving the health report.
+const DetailPath = "/healthz/detail"
+
+// ProbeHandler returns the handler of the health probe server, serving the
+// liveness and readiness probes at /healthz and /readyz, as served by the
+// controller-runtime manager, and the health report at DetailPath.
+func (t *Tracker) ProbeHandler() http.Handler {
+	mux := http.NewServeMux()
+	for _, path := range []string{"/healthz", "/readyz"} {
+		h := http.StripPrefix(path, &healthz.Handler{
+			Checks: map[string]healthz.Checker{"ping": healthz.Ping},
+		})
+		mux.Handle(path, h)
+		mux.Handle(path+"/", h)
+	}
+	mux.Handle(DetailPath, t)
+	return mux
+}
+
+// Status is the health status of a subsystem.
+type Status string
+
+const (
+	// StatusHealthy signals that the subsystem works as expected.
+	StatusHealthy Status = "Healthy"
+	// StatusDegraded signals that the last operation or check
+	// of the subsystem failed.
+	StatusDegraded Status = "Degraded"
+)
+
+// SubsystemReport is the health report of a subsystem.
+type SubsystemReport struct {
+	// Status is the health status of the subsystem.
+	Status Status `json:"status"`
+
+	// Reason is the reason of the failure of a degraded subsystem, e.g.
+	// 'Timeout' or the reason returned by the Kubernetes API. The errors
+	// are not served, as they may hold details of the key providers.
+	Reason string `json:"reason,omitempty"`
+
+	// DegradedObjects is the number of objects for which the last
+	// operation of the subsystem failed, e.g. the Kustomizations whose
+	// artifact download failed.
+	DegradedObjects int `json:"degradedObjects,omitempty"`
+
+	// LastUpdateTime is the time of the last operation or check.
+	LastUpdateTime time.Time `json:"lastUpdateTime"`
+}
+
+// Report is the health report of the controller.
+type Report struct {
+	// Status is Degraded if any of the subsystems is degraded.
+	Status Status `json:"status"`
+
+	// Subsystems holds the report of every subsystem by name.
+	Subsystems map[string]SubsystemReport `json:"subsystems"`
+}
+
+// Check is a function evaluated every time the report is requested.
+type Check func(ctx context.Context) error
+
+// Tracker holds the health of the controller subsystems. The health of a
+// subsystem is either recorded from the outcome of its operations, e.g.
+// artifact downloads, or evaluated by a Check when the report is requested.
+// The outcomes are recorded per object, so that a subsystem is reported
+// as degraded while its last operation failed for any of the objects.
+type Tracker struct {
+	mu       sync.RWMutex
+	recorded map[string]map[string]SubsystemReport
+	checks   map[string]Check
+	now      func() time.Time
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		recorded: make(map[string]map[string]SubsystemReport),
+		checks:   make(map[string]Check),
+		now:      time.Now,
+	}
+}
+
+// Record records the outcome of an operation of the given subsystem
+// which isn't specific to an object. It is a no-op on a nil Tracker.
+func (t *Tracker) Record(subsystem string, err error) {
+	t.RecordObject(subsystem, "", err)
+}
+
+// RecordObject records the outcome of an operation of the given subsystem
+// for the given object, replacing the previous outcome for this object.
+// It is a no-op on a nil Tracker.
+func (t *Tracker) RecordObject(subsystem, object string, err error) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.recorded[subsystem] == nil {
+		t.recorded[subsystem] = make(map[string]SubsystemReport)
+	}
+	t.recorded[subsystem][object] = newSubsystemReport(err, t.now())
+}
+
+// Forget removes the outcomes recorded for the given object, e.g. when the
+// object is deleted. It is a no-op on a nil Tracker.
+func (t *Tracker) Forget(object string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for subsystem, objects := range t.recorded {
+		delete(objects, object)
+		if len(objects) == 0 {
+			delete(t.recorded, subsystem)
+		}
+	}
+}
+
+// AddCheck registers a check evaluated for the given subsystem
+// every time the report is requested.
+func (t *Tracker) AddCheck(subsystem string, check Check) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.checks[subsystem] = check
+}
+
+// Report returns the health report of all subsystems.
+func (t *Tracker) Report(ctx context.Context) Report {
+	t.mu.RLock()
+	subsystems := make(map[string]SubsystemReport, len(t.recorded)+len(t.checks))
+	for name, objects := range t.recorded {
+		subsystems[name] = aggregate(objects)
+	}
+	checks := make(map[string]Check, len(t.checks))
+	for name, check := range t.checks {
+		checks[name] = check
+	}
+	t.mu.RUnlock()
+
+	for name, check := range checks {
+		subsystems[name] = newSubsystemReport(check(ctx), t.now())
+	}
+
+	report := Report{Status: StatusHealthy, Subsystems: subsystems}
+	for _, r := range subsystems {
+		if r.Status != StatusHealthy {
+			report.Status = StatusDegraded
+			break
+		}
+	}
+	return report
+}
+
+// aggregate returns the report of a subsystem from the reports recorded
+// per object. The subsystem is degraded if any object is degraded, with
+// the reason of the latest failure.
+func aggregate(objects map[string]SubsystemReport) SubsystemReport {
+	out := SubsystemReport{Status: StatusHealthy}
+	var lastFailure time.Time
+	for object, r := range objects {
+		if r.LastUpdateTime.After(out.LastUpdateTime) {
+			out.LastUpdateTime = r.LastUpdateTime
+		}
+		if r.Status == StatusHealthy {
+			continue
+		}
+		out.Status = StatusDegraded
+		if object != "" {
+			out.DegradedObjects++
+		}
+		if out.Reason == "" || r.LastUpdateTime.After(lastFailure) {
+			out.Reason = r.Reason
+			lastFailure = r.LastUpdateTime
+		}
+	}
+	return out
+}
+
+// ServeHTTP writes the health report in JSON format. The response status
+// code is always 200, as a degraded subsystem doesn't prevent the
+// controller from reconciling objects that don't depend on it.
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	b, err := json.MarshalIndent(t.Report(req.Context()), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+func newSubsystemReport(err error, now time.Time) SubsystemReport {
+	r := SubsystemReport{Status: StatusHealthy, LastUpdateTime: now}
+	if err != nil {
+		r.Status = StatusDegraded
+		r.Reason = reasonForError(err)
+	}
+	return r
+}
+
+// reasonError is an error with the reason reported for the subsystem.
+type reasonError struct {
+	reason string
+	err    error
+}
+
+func (e *reasonError) Error() string { return e.err.Error() }
+
+func (e *reasonError) Unwrap() error { return e.err }
+
+// reasonForError returns the reason of the failure reported for a subsystem.
+func reasonForError(err error) string {
+	var re *reasonError
+	switch {
+	case errors.As(err, &re):
+		return re.reason
+	case errors.Is(err, context.DeadlineExceeded):
+		return string(metav1.StatusReasonTimeout)
+	case apierrors.ReasonForError(err) != metav1.StatusReasonUnknown:
+		return string(apierrors.ReasonForError(err))
+	default:
+		return "Failed"
+	}
+}
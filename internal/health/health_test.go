@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestTracker_Report(t *testing.T) {
+	g := NewWithT(t)
+
+	var nilTracker *Tracker
+	nilTracker.Record(SubsystemSource, errors.New("ignored"))
+
+	tracker := NewTracker()
+	tracker.Record(SubsystemSource, nil)
+	tracker.AddCheck(SubsystemAPIDiscovery, func(context.Context) error { return nil })
+
+	report := tracker.Report(context.Background())
+	g.Expect(report.Status).To(Equal(StatusHealthy))
+	g.Expect(report.Subsystems).To(HaveKey(SubsystemSource))
+	g.Expect(report.Subsystems).To(HaveKey(SubsystemAPIDiscovery))
+
+	tracker.Record(SubsystemDecryptionPrefix+"kms", errors.New("connection refused"))
+
+	rec := httptest.NewRecorder()
+	tracker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DetailPath, nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	g.Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+	var got Report
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &got)).To(Succeed())
+	g.Expect(got.Status).To(Equal(StatusDegraded))
+	g.Expect(got.Subsystems["decryption/kms"].Status).To(Equal(StatusDegraded))
+	g.Expect(got.Subsystems["decryption/kms"].Reason).To(Equal("Failed"))
+	g.Expect(rec.Body.String()).ToNot(ContainSubstring("connection refused"))
+	g.Expect(got.Subsystems[SubsystemSource].Status).To(Equal(StatusHealthy))
+}
+
+func TestTracker_RecordObject(t *testing.T) {
+	g := NewWithT(t)
+
+	tracker := NewTracker()
+	tracker.RecordObject(SubsystemSource, "apps/app", apierrors.NewNotFound(schema.GroupResource{}, "artifact"))
+	tracker.RecordObject(SubsystemSource, "apps/infra", nil)
+
+	// The success of another object doesn't hide the failure.
+	report := tracker.Report(context.Background())
+	g.Expect(report.Status).To(Equal(StatusDegraded))
+	g.Expect(report.Subsystems[SubsystemSource].Status).To(Equal(StatusDegraded))
+	g.Expect(report.Subsystems[SubsystemSource].Reason).To(Equal("NotFound"))
+	g.Expect(report.Subsystems[SubsystemSource].DegradedObjects).To(Equal(1))
+
+	// The subsystem recovers once the failing object succeeds.
+	tracker.RecordObject(SubsystemSource, "apps/app", nil)
+	report = tracker.Report(context.Background())
+	g.Expect(report.Subsystems[SubsystemSource].Status).To(Equal(StatusHealthy))
+	g.Expect(report.Subsystems[SubsystemSource].DegradedObjects).To(BeZero())
+
+	// The outcomes of deleted objects are forgotten.
+	tracker.RecordObject(SubsystemSource, "apps/app", errors.New("failed"))
+	tracker.Forget("apps/app")
+	report = tracker.Report(context.Background())
+	g.Expect(report.Status).To(Equal(StatusHealthy))
+	tracker.Forget("apps/infra")
+	g.Expect(tracker.Report(context.Background()).Subsystems).ToNot(HaveKey(SubsystemSource))
+}
+
+func TestTracker_ProbeHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	tracker := NewTracker()
+	tracker.Record(SubsystemAPIDiscovery, nil)
+	handler := tracker.ProbeHandler()
+
+	for _, path := range []string{"/healthz", "/readyz", "/readyz/ping"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		g.Expect(rec.Code).To(Equal(http.StatusOK), path)
+		g.Expect(rec.Body.String()).To(Equal("ok"), path)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DetailPath, nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+	var got Report
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &got)).To(Succeed())
+	g.Expect(got.Subsystems).To(HaveKey(SubsystemAPIDiscovery))
+}
+
+func TestTracker_RecordDiscovery(t *testing.T) {
+	g := NewWithT(t)
+
+	client := fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	client.AddReactor("get", "group", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewServiceUnavailable("unavailable")
+	})
+
+	tracker := NewTracker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.RecordDiscovery(ctx, &client, time.Hour)
+
+	g.Eventually(func() Status {
+		return tracker.Report(context.Background()).Subsystems[SubsystemAPIDiscovery].Status
+	}).Should(Equal(StatusDegraded))
+
+	// The report doesn't run the discovery.
+	calls := len(client.Actions())
+	tracker.Report(context.Background())
+	g.Expect(client.Actions()).To(HaveLen(calls))
+}
+
+func TestReasonForError(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(reasonForError(errors.New("secret key"))).To(Equal("Failed"))
+	g.Expect(reasonForError(fmt.Errorf("failed: %w", context.DeadlineExceeded))).To(Equal("Timeout"))
+	g.Expect(reasonForError(apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "sops", errors.New("denied")))).
+		To(Equal("Forbidden"))
+	g.Expect(reasonForError(&reasonError{reason: "WorkQueueSaturated", err: errors.New("saturated")})).
+		To(Equal("WorkQueueSaturated"))
+}
+
+func TestWorkQueueCheck(t *testing.T) {
+	g := NewWithT(t)
+
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "workqueue_depth"},
+		[]string{"name", "controller", "priority"})
+	active := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "controller_runtime_active_workers"},
+		[]string{"controller"})
+	workers := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "controller_runtime_max_concurrent_reconciles"},
+		[]string{"controller"})
+	registry.MustRegister(depth, active, workers)
+
+	check := WorkQueueCheck(registry, "kustomization")
+
+	workers.WithLabelValues("kustomization").Set(4)
+	active.WithLabelValues("kustomization").Set(4)
+	depth.WithLabelValues("kustomization", "kustomization", "0").Set(0)
+	depth.WithLabelValues("other", "other", "0").Set(10)
+	g.Expect(check(context.Background())).To(Succeed())
+
+	active.WithLabelValues("kustomization").Set(2)
+	depth.WithLabelValues("kustomization", "kustomization", "0").Set(5)
+	g.Expect(check(context.Background())).To(Succeed())
+
+	active.WithLabelValues("kustomization").Set(4)
+	err := check(context.Background())
+	g.Expect(err).To(MatchError("work queue saturated: 4/4 workers busy, 5 objects waiting"))
+	g.Expect(reasonForError(err)).To(Equal("WorkQueueSaturated"))
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/engine"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/event"
+	kstatusreaders "github.com/fluxcd/cli-utils/pkg/kstatus/polling/statusreaders"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+// deploymentThreshold holds a compiled HealthCheckThreshold.
+type deploymentThreshold struct {
+	selector      *jsondiff.SelectorRegex
+	minReadyRatio float64
+}
+
+// deploymentStatusReader computes the status of Deployments taking into
+// account the readiness thresholds of the Kustomization.
+type deploymentStatusReader struct {
+	genericStatusReader engine.StatusReader
+}
+
+// NewDeploymentStatusReader compiles the given readiness thresholds and
+// returns a constructor for a status reader that considers a Deployment
+// healthy once the ratio of its updated and ready replicas reaches the
+// minimum ready ratio of the first matching threshold.
+func NewDeploymentStatusReader(thresholds []kustomizev1.HealthCheckThreshold) (func(apimeta.RESTMapper) engine.StatusReader, error) {
+	compiled, err := compileThresholds(thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(mapper apimeta.RESTMapper) engine.StatusReader {
+		return &deploymentStatusReader{
+			genericStatusReader: kstatusreaders.NewGenericStatusReader(mapper, func(u *unstructured.Unstructured) (*status.Result, error) {
+				return deploymentConditions(u, compiled)
+			}),
+		}
+	}, nil
+}
+
+// compileThresholds parses the ratios and compiles the target selectors
+// of the given readiness thresholds.
+func compileThresholds(thresholds []kustomizev1.HealthCheckThreshold) ([]deploymentThreshold, error) {
+	compiled := make([]deploymentThreshold, 0, len(thresholds))
+	for i, t := range thresholds {
+		ratio, err := strconv.ParseFloat(t.MinReadyRatio, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("invalid minReadyRatio '%s' in health check threshold %d", t.MinReadyRatio, i)
+		}
+
+		var selector *jsondiff.SelectorRegex
+		if t.Target != nil {
+			selector, err = jsondiff.NewSelectorRegex(&jsondiff.Selector{
+				Group:              t.Target.Group,
+				Version:            t.Target.Version,
+				Kind:               t.Target.Kind,
+				Name:               t.Target.Name,
+				Namespace:          t.Target.Namespace,
+				AnnotationSelector: t.Target.AnnotationSelector,
+				LabelSelector:      t.Target.LabelSelector,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("invalid target in health check threshold %d: %w", i, err)
+			}
+		}
+
+		compiled = append(compiled, deploymentThreshold{
+			selector:      selector,
+			minReadyRatio: ratio,
+		})
+	}
+
+	return compiled, nil
+}
+
+func (d *deploymentStatusReader) Supports(gk schema.GroupKind) bool {
+	return gk == appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind()
+}
+
+func (d *deploymentStatusReader) ReadStatus(ctx context.Context, reader engine.ClusterReader, resource object.ObjMetadata) (*event.ResourceStatus, error) {
+	return d.genericStatusReader.ReadStatus(ctx, reader, resource)
+}
+
+func (d *deploymentStatusReader) ReadStatusForObject(ctx context.Context, reader engine.ClusterReader, resource *unstructured.Unstructured) (*event.ResourceStatus, error) {
+	return d.genericStatusReader.ReadStatusForObject(ctx, reader, resource)
+}
+
+// deploymentConditions computes the kstatus of the Deployment and, if the
+// rollout is still in progress, reports the Deployment as Current when the
+// updated and ready replicas reach the minimum ready ratio of the first
+// matching threshold. Failures such as an exceeded progress deadline and
+// unobserved generations are never relaxed.
+func deploymentConditions(u *unstructured.Unstructured, thresholds []deploymentThreshold) (*status.Result, error) {
+	res, err := status.Compute(u)
+	if err != nil || res.Status != status.InProgressStatus {
+		return res, err
+	}
+
+	var threshold *deploymentThreshold
+	for i := range thresholds {
+		if thresholds[i].selector.MatchUnstructured(u) {
+			threshold = &thresholds[i]
+			break
+		}
+	}
+	if threshold == nil {
+		return res, nil
+	}
+
+	obj := u.UnstructuredContent()
+	generation := status.GetIntField(obj, ".metadata.generation", 0)
+	observedGeneration := status.GetIntField(obj, ".status.observedGeneration", -1)
+	if observedGeneration < generation {
+		return res, nil
+	}
+
+	specReplicas := status.GetIntField(obj, ".spec.replicas", 1)
+	updatedReplicas := status.GetIntField(obj, ".status.updatedReplicas", 0)
+	readyReplicas := status.GetIntField(obj, ".status.readyReplicas", 0)
+
+	minReady := int(math.Ceil(threshold.minReadyRatio * float64(specReplicas)))
+	if min(updatedReplicas, readyReplicas) < minReady {
+		return res, nil
+	}
+
+	message := fmt.Sprintf("Deployment is available. Updated and ready: %d/%d (min ready: %d)",
+		min(updatedReplicas, readyReplicas), specReplicas, minReady)
+	return &status.Result{
+		Status:     status.CurrentStatus,
+		Message:    message,
+		Conditions: []status.Condition{},
+	}, nil
+}
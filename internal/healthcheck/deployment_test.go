@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	"github.com/fluxcd/pkg/apis/kustomize"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func newDeployment(name string, generation, observedGeneration, replicas, updated, ready int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":       name,
+			"namespace":  "default",
+			"generation": generation,
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+		},
+		"status": map[string]interface{}{
+			"observedGeneration": observedGeneration,
+			"replicas":           replicas,
+			"updatedReplicas":    updated,
+			"readyReplicas":      ready,
+			"availableReplicas":  ready,
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":   "Available",
+					"status": "True",
+				},
+			},
+		},
+	}}
+}
+
+func Test_DeploymentConditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds []kustomizev1.HealthCheckThreshold
+		obj        *unstructured.Unstructured
+		want       status.Status
+	}{
+		{
+			name:       "current when ratio is reached",
+			thresholds: []kustomizev1.HealthCheckThreshold{{MinReadyRatio: "0.9"}},
+			obj:        newDeployment("app", 2, 2, 10, 9, 9),
+			want:       status.CurrentStatus,
+		},
+		{
+			name:       "in progress when ready replicas are below ratio",
+			thresholds: []kustomizev1.HealthCheckThreshold{{MinReadyRatio: "0.9"}},
+			obj:        newDeployment("app", 2, 2, 10, 10, 8),
+			want:       status.InProgressStatus,
+		},
+		{
+			name:       "in progress when updated replicas are below ratio",
+			thresholds: []kustomizev1.HealthCheckThreshold{{MinReadyRatio: "0.9"}},
+			obj:        newDeployment("app", 2, 2, 10, 8, 10),
+			want:       status.InProgressStatus,
+		},
+		{
+			name:       "in progress when generation is not observed",
+			thresholds: []kustomizev1.HealthCheckThreshold{{MinReadyRatio: "0.5"}},
+			obj:        newDeployment("app", 3, 2, 10, 10, 10),
+			want:       status.InProgressStatus,
+		},
+		{
+			name: "in progress when target does not match",
+			thresholds: []kustomizev1.HealthCheckThreshold{{
+				MinReadyRatio: "0.5",
+				Target:        &kustomize.Selector{Name: "other"},
+			}},
+			obj:  newDeployment("app", 2, 2, 10, 9, 9),
+			want: status.InProgressStatus,
+		},
+		{
+			name: "first matching threshold wins",
+			thresholds: []kustomizev1.HealthCheckThreshold{
+				{
+					MinReadyRatio: "1",
+					Target:        &kustomize.Selector{Name: "app"},
+				},
+				{
+					MinReadyRatio: "0.5",
+				},
+			},
+			obj:  newDeployment("app", 2, 2, 10, 9, 9),
+			want: status.InProgressStatus,
+		},
+		{
+			name:       "current without threshold when fully rolled out",
+			thresholds: nil,
+			obj:        newDeployment("app", 2, 2, 3, 3, 3),
+			want:       status.CurrentStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctor, err := NewDeploymentStatusReader(tt.thresholds)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ctor).NotTo(BeNil())
+
+			compiled, err := compileThresholds(tt.thresholds)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			res, err := deploymentConditions(tt.obj, compiled)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(res.Status).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_NewDeploymentStatusReader_Invalid(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewDeploymentStatusReader([]kustomizev1.HealthCheckThreshold{{MinReadyRatio: "1.5"}})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = NewDeploymentStatusReader([]kustomizev1.HealthCheckThreshold{{
+		MinReadyRatio: "0.9",
+		Target:        &kustomize.Selector{Name: "(["},
+	}})
+	g.Expect(err).To(HaveOccurred())
+}
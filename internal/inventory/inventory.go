@@ -17,7 +17,13 @@ limitations under the License.
 package inventory
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -76,6 +82,85 @@ func Merge(inv *kustomizev1.ResourceInventory, objects []*unstructured.Unstructu
 	}
 }
 
+// digestLength is the number of hex characters of the digests of the
+// manifests recorded in the inventory entries.
+const digestLength = 16
+
+// SetRevisions records in the inventory entries of the given objects the
+// revision at which they last changed, i.e. the given revision if the digest
+// of their manifest differs from the one recorded in the old inventory, or
+// the revision recorded in the old inventory otherwise.
+// As no digest is recorded for Secrets, they are considered changed when
+// the change set reports them as created or configured.
+// The entries of other objects keep their revision, or the one recorded in
+// the old inventory.
+func SetRevisions(inv, oldInv *kustomizev1.ResourceInventory,
+	objects []*unstructured.Unstructured, set *ssa.ChangeSet, revision string) error {
+	if inv == nil {
+		return nil
+	}
+
+	oldEntries := make(map[string]kustomizev1.ResourceRef)
+	if oldInv != nil {
+		for _, e := range oldInv.Entries {
+			oldEntries[e.ID] = e
+		}
+	}
+	desired := make(map[string]*unstructured.Unstructured, len(objects))
+	for _, obj := range objects {
+		desired[object.UnstructuredToObjMetadata(obj).String()] = obj
+	}
+	actions := make(map[string]ssa.Action)
+	if set != nil {
+		for _, e := range set.Entries {
+			actions[e.ObjMetadata.String()] = e.Action
+		}
+	}
+
+	for i, entry := range inv.Entries {
+		old, tracked := oldEntries[entry.ID]
+		obj, ok := desired[entry.ID]
+		if !ok {
+			if entry.Revision == "" && tracked {
+				inv.Entries[i].Revision = old.Revision
+				inv.Entries[i].Digest = old.Digest
+			}
+			continue
+		}
+
+		var digest string
+		changed := !tracked || old.Revision == ""
+		if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+			action, found := actions[entry.ID]
+			changed = changed || !found || action == ssa.CreatedAction || action == ssa.ConfiguredAction
+		} else {
+			var err error
+			if digest, err = manifestDigest(obj); err != nil {
+				return fmt.Errorf("failed to compute the digest of '%s': %w", entry.ID, err)
+			}
+			changed = changed || old.Digest != digest
+		}
+
+		inv.Entries[i].Digest = digest
+		inv.Entries[i].Revision = revision
+		if !changed {
+			inv.Entries[i].Revision = old.Revision
+		}
+	}
+	return nil
+}
+
+// manifestDigest returns the truncated SHA-256 digest of the JSON encoded
+// manifest of the given object.
+func manifestDigest(obj *unstructured.Unstructured) (string, error) {
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:digestLength], nil
+}
+
 // List returns the inventory entries as unstructured.Unstructured objects.
 func List(inv *kustomizev1.ResourceInventory) ([]*unstructured.Unstructured, error) {
 	objects := make([]*unstructured.Unstructured, 0)
@@ -162,6 +247,85 @@ func Diff(inv *kustomizev1.ResourceInventory, target *kustomizev1.ResourceInvent
 	return objects, nil
 }
 
+// hashedNamePrefix marks the inventory entries of Secret objects whose
+// name has been replaced with a digest of the entry ID. The colon is not
+// allowed in the names of Secrets, so that the hashed entries can't be
+// mistaken for the entries of Secrets with a similar name.
+const hashedNamePrefix = "sha256:"
+
+// Redact returns a copy of the inventory in which the entries of Secret
+// objects are hashed or omitted according to the given policy. The entries
+// are hashed with HMAC-SHA256 keyed with the given key, so that the names
+// of the Secrets can't be guessed by hashing candidate names.
+func Redact(inv *kustomizev1.ResourceInventory, policy string, key []byte) *kustomizev1.ResourceInventory {
+	out := New()
+	if inv == nil {
+		return out
+	}
+
+	for _, entry := range inv.Entries {
+		objMetadata, err := object.ParseObjMetadata(entry.ID)
+		if err != nil || objMetadata.GroupKind.Group != "" || objMetadata.GroupKind.Kind != "Secret" {
+			out.Entries = append(out.Entries, entry)
+			continue
+		}
+
+		switch policy {
+		case kustomizev1.SecretInventoryPolicyOmit:
+			continue
+		case kustomizev1.SecretInventoryPolicyHash:
+			mac := hmac.New(sha256.New, key)
+			mac.Write([]byte(entry.ID))
+			objMetadata.Name = fmt.Sprintf("%s%x", hashedNamePrefix, mac.Sum(nil))
+			entry.ID = objMetadata.String()
+			out.Entries = append(out.Entries, entry)
+		default:
+			out.Entries = append(out.Entries, entry)
+		}
+	}
+
+	return out
+}
+
+// HasHashedEntries returns true if the inventory contains Secret entries
+// that have been hashed by Redact.
+func HasHashedEntries(inv *kustomizev1.ResourceInventory) bool {
+	if inv == nil {
+		return false
+	}
+	for _, entry := range inv.Entries {
+		if isHashed(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutHashedEntries returns a copy of the inventory without the
+// Secret entries that have been hashed by Redact.
+func WithoutHashedEntries(inv *kustomizev1.ResourceInventory) *kustomizev1.ResourceInventory {
+	out := New()
+	if inv == nil {
+		return out
+	}
+	for _, entry := range inv.Entries {
+		if !isHashed(entry) {
+			out.Entries = append(out.Entries, entry)
+		}
+	}
+	return out
+}
+
+func isHashed(entry kustomizev1.ResourceRef) bool {
+	objMetadata, err := object.ParseObjMetadata(entry.ID)
+	if err != nil {
+		return false
+	}
+	return objMetadata.GroupKind.Group == "" &&
+		objMetadata.GroupKind.Kind == "Secret" &&
+		strings.HasPrefix(objMetadata.Name, hashedNamePrefix)
+}
+
 // ReferenceToObjMetadataSet transforms a NamespacedObjectKindReference to an ObjMetadataSet.
 func ReferenceToObjMetadataSet(cr []meta.NamespacedObjectKindReference) (object.ObjMetadataSet, error) {
 	var objects []object.ObjMetadata
@@ -24,8 +24,11 @@ import (
 	"github.com/fluxcd/pkg/ssa"
 	ssautil "github.com/fluxcd/pkg/ssa/utils"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/fluxcd/cli-utils/pkg/object"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 )
 
 func Test_Inventory(t *testing.T) {
@@ -94,6 +97,70 @@ func Test_Inventory(t *testing.T) {
 	})
 }
 
+func Test_Redact(t *testing.T) {
+	g := NewWithT(t)
+
+	set, err := readManifest("testdata/inventory1.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	inv := New()
+	g.Expect(AddChangeSet(inv, set)).To(Succeed())
+	g.Expect(HasHashedEntries(inv)).To(BeFalse())
+
+	secretID := "test_test2__Secret"
+	key := []byte("key")
+	inv.Entries = append(inv.Entries, kustomizev1.ResourceRef{ID: secretID, Version: "v1"})
+
+	t.Run("keeps secrets with plain policy", func(t *testing.T) {
+		g := NewWithT(t)
+		out := Redact(inv, kustomizev1.SecretInventoryPolicyPlain, key)
+		g.Expect(out.Entries).To(Equal(inv.Entries))
+	})
+
+	t.Run("keeps secrets named like hashed entries", func(t *testing.T) {
+		g := NewWithT(t)
+		named := &kustomizev1.ResourceInventory{Entries: []kustomizev1.ResourceRef{
+			{ID: "test_sha256-5f1c__Secret", Version: "v1"},
+		}}
+		out := Redact(named, kustomizev1.SecretInventoryPolicyPlain, key)
+		g.Expect(HasHashedEntries(out)).To(BeFalse())
+		g.Expect(WithoutHashedEntries(out).Entries).To(Equal(named.Entries))
+	})
+
+	t.Run("omits secrets", func(t *testing.T) {
+		g := NewWithT(t)
+		out := Redact(inv, kustomizev1.SecretInventoryPolicyOmit, key)
+		g.Expect(out.Entries).To(HaveLen(len(inv.Entries) - 1))
+		for _, e := range out.Entries {
+			g.Expect(e.ID).ToNot(Equal(secretID))
+		}
+		g.Expect(HasHashedEntries(out)).To(BeFalse())
+	})
+
+	t.Run("hashes secrets", func(t *testing.T) {
+		g := NewWithT(t)
+		out := Redact(inv, kustomizev1.SecretInventoryPolicyHash, key)
+		g.Expect(out.Entries).To(HaveLen(len(inv.Entries)))
+		g.Expect(HasHashedEntries(out)).To(BeTrue())
+
+		hashed := out.Entries[len(out.Entries)-1]
+		g.Expect(hashed.ID).ToNot(ContainSubstring("test2"))
+		g.Expect(hashed.ID).To(HavePrefix("test_sha256:"))
+		g.Expect(hashed.ID).To(HaveSuffix("__Secret"))
+		g.Expect(hashed.Version).To(Equal("v1"))
+
+		_, err := ListMetadata(out)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Hashing is deterministic for a given key.
+		g.Expect(Redact(inv, kustomizev1.SecretInventoryPolicyHash, key)).To(Equal(out))
+		g.Expect(Redact(inv, kustomizev1.SecretInventoryPolicyHash, []byte("other"))).ToNot(Equal(out))
+
+		stripped := WithoutHashedEntries(out)
+		g.Expect(stripped.Entries).To(HaveLen(len(inv.Entries) - 1))
+	})
+}
+
 func readManifest(manifest string) (*ssa.ChangeSet, error) {
 	data, err := os.ReadFile(manifest)
 	if err != nil {
@@ -119,3 +186,88 @@ func readManifest(manifest string) (*ssa.ChangeSet, error) {
 
 	return cs, nil
 }
+
+func Test_SetRevisions(t *testing.T) {
+	g := NewWithT(t)
+
+	newObject := func(kind, name, value string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind(kind)
+		u.SetName(name)
+		u.SetNamespace("default")
+		_ = unstructured.SetNestedField(u.Object, value, "data", "key")
+		return u
+	}
+	apply := func(oldInv *kustomizev1.ResourceInventory, revision string, objects []*unstructured.Unstructured,
+		actions map[string]ssa.Action) *kustomizev1.ResourceInventory {
+		set := ssa.NewChangeSet()
+		for _, obj := range objects {
+			set.Add(ssa.ChangeSetEntry{
+				ObjMetadata:  object.UnstructuredToObjMetadata(obj),
+				GroupVersion: "v1",
+				Action:       actions[obj.GetName()],
+			})
+		}
+		inv := New()
+		g.Expect(AddChangeSet(inv, set)).To(Succeed())
+		g.Expect(SetRevisions(inv, oldInv, objects, set, revision)).To(Succeed())
+		return inv
+	}
+	revisions := func(inv *kustomizev1.ResourceInventory) map[string]string {
+		out := make(map[string]string)
+		for _, e := range inv.Entries {
+			out[e.ID] = e.Revision
+		}
+		return out
+	}
+
+	inv1 := apply(nil, "v1", []*unstructured.Unstructured{
+		newObject("ConfigMap", "app", "a"),
+		newObject("ConfigMap", "env", "a"),
+		newObject("Secret", "token", "a"),
+	}, map[string]ssa.Action{"app": ssa.CreatedAction, "env": ssa.CreatedAction, "token": ssa.CreatedAction})
+	g.Expect(revisions(inv1)).To(Equal(map[string]string{
+		"default_app__ConfigMap": "v1",
+		"default_env__ConfigMap": "v1",
+		"default_token__Secret":  "v1",
+	}))
+	for _, e := range inv1.Entries {
+		if e.ID == "default_token__Secret" {
+			g.Expect(e.Digest).To(BeEmpty())
+		} else {
+			g.Expect(e.Digest).To(HaveLen(digestLength))
+		}
+	}
+
+	// Only the objects whose manifest changed are recorded at the new revision.
+	inv2 := apply(inv1, "v2", []*unstructured.Unstructured{
+		newObject("ConfigMap", "app", "b"),
+		newObject("ConfigMap", "env", "a"),
+		newObject("Secret", "token", "a"),
+	}, map[string]ssa.Action{"app": ssa.ConfiguredAction, "env": ssa.ConfiguredAction, "token": ssa.UnchangedAction})
+	g.Expect(revisions(inv2)).To(Equal(map[string]string{
+		"default_app__ConfigMap": "v2",
+		"default_env__ConfigMap": "v1",
+		"default_token__Secret":  "v1",
+	}))
+
+	// The Secrets are recorded at the new revision when configured.
+	inv3 := apply(inv2, "v3", []*unstructured.Unstructured{
+		newObject("ConfigMap", "app", "b"),
+		newObject("Secret", "token", "b"),
+	}, map[string]ssa.Action{"app": ssa.UnchangedAction, "token": ssa.ConfiguredAction})
+	g.Expect(revisions(inv3)).To(Equal(map[string]string{
+		"default_app__ConfigMap": "v2",
+		"default_token__Secret":  "v3",
+	}))
+
+	// The stale objects re-tracked after a failed prune keep their revision.
+	Merge(inv3, []*unstructured.Unstructured{newObject("ConfigMap", "env", "a")})
+	g.Expect(SetRevisions(inv3, inv2, nil, nil, "v3")).To(Succeed())
+	g.Expect(revisions(inv3)).To(Equal(map[string]string{
+		"default_app__ConfigMap": "v2",
+		"default_env__ConfigMap": "v1",
+		"default_token__Secret":  "v3",
+	}))
+}
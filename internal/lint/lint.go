@@ -0,0 +1,223 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lint implements the lint subcommand of the controller, which
+// builds a Kustomization from a local directory with the semantics of the
+// reconciliation, so that repositories can be validated in CI.
+package lint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/controller"
+)
+
+// Command is the name of the lint subcommand.
+const Command = "lint"
+
+const (
+	defaultName      = "lint"
+	defaultNamespace = "default"
+)
+
+// Run runs the lint subcommand with the given arguments, i.e. the ones
+// following the subcommand name, and returns the exit code.
+func Run(args []string, stdout, stderr io.Writer) int {
+	var (
+		kustomizationFile          string
+		objectsFiles               []string
+		output                     string
+		noRemoteBases              bool
+		strictSubstitutions        bool
+		decryptionKeyExpiryWarning time.Duration
+	)
+
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: kustomize-controller %s [flags] <path>\n\n", Command)
+		fmt.Fprintln(stderr, "Build the Kustomization from the sources at <path> as the controller does.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&kustomizationFile, "kustomization", "",
+		"The file containing the Kustomization to build, defaults to building the root of <path>.")
+	flags.StringSliceVar(&objectsFiles, "objects", nil,
+		"The files containing the objects referenced by the Kustomization, e.g. the decryption Secret and the substituteFrom ConfigMaps and Secrets.")
+	flags.StringVarP(&output, "output", "o", "",
+		"Print the built objects in the given format, the only format supported is 'yaml'.")
+	flags.BoolVar(&noRemoteBases, "no-remote-bases", false,
+		"Disallow remote bases usage in Kustomize overlays.")
+	flags.BoolVar(&strictSubstitutions, "strict-substitutions", false,
+		"Fail the build if a variable without a default value is declared in a manifest but is not found in the substitutions.")
+	flags.DurationVar(&decryptionKeyExpiryWarning, "decryption-key-expiry-warning", 14*24*time.Hour,
+		"The time before the expiry of a decryption key at which a warning is reported.")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	if output != "" && output != "yaml" {
+		fmt.Fprintf(stderr, "invalid output format '%s', must be 'yaml'\n", output)
+		return 2
+	}
+
+	obj, err := readKustomization(kustomizationFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "✗ %s\n", err)
+		return 1
+	}
+	kubeClient, err := newClient(obj.GetNamespace(), objectsFiles)
+	if err != nil {
+		fmt.Fprintf(stderr, "✗ %s\n", err)
+		return 1
+	}
+
+	r := &controller.KustomizationReconciler{
+		Client:                     kubeClient,
+		EventRecorder:              &record.FakeRecorder{},
+		NoRemoteBases:              noRemoteBases,
+		StrictSubstitutions:        strictSubstitutions,
+		DecryptionKeyExpiryWarning: decryptionKeyExpiryWarning,
+	}
+	objects, warnings, err := r.Lint(context.Background(), obj, flags.Arg(0))
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "⚠ %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "✗ %s\n", err)
+		return 1
+	}
+
+	if output == "yaml" {
+		manifests, err := ssautil.ObjectsToYAML(objects)
+		if err != nil {
+			fmt.Fprintf(stderr, "✗ %s\n", err)
+			return 1
+		}
+		fmt.Fprint(stdout, manifests)
+	}
+	fmt.Fprintf(stderr, "✔ built %d objects\n", len(objects))
+	return 0
+}
+
+// readKustomization reads the Kustomization from the given file, or returns
+// a Kustomization building the root of the sources if the file is empty.
+func readKustomization(file string) (*kustomizev1.Kustomization, error) {
+	obj := &kustomizev1.Kustomization{}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the Kustomization: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(data, obj); err != nil {
+			return nil, fmt.Errorf("failed to decode the Kustomization from '%s': %w", file, err)
+		}
+		if obj.Kind != kustomizev1.KustomizationKind {
+			return nil, fmt.Errorf("'%s' does not contain a %s", file, kustomizev1.KustomizationKind)
+		}
+	}
+	if obj.Name == "" {
+		obj.Name = defaultName
+	}
+	if obj.Namespace == "" {
+		obj.Namespace = defaultNamespace
+	}
+	return obj, nil
+}
+
+// newClient returns a client serving the objects read from the given files,
+// defaulting their namespace to the namespace of the Kustomization.
+func newClient(namespace string, files []string) (client.Client, error) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kustomizev1.AddToScheme(scheme)
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the objects: %w", err)
+		}
+		objects, err := ssautil.ReadObjects(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the objects from '%s': %w", file, err)
+		}
+		for _, u := range objects {
+			obj, err := toTyped(scheme, u)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode the objects from '%s': %w", file, err)
+			}
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(namespace)
+			}
+			builder = builder.WithObjects(obj)
+		}
+	}
+	return builder.Build(), nil
+}
+
+func toTyped(scheme *runtime.Scheme, u *unstructured.Unstructured) (client.Object, error) {
+	gvk := u.GroupVersionKind()
+	o, err := scheme.New(gvk)
+	if err != nil {
+		return nil, fmt.Errorf("'%s/%s': %w", gvk.Kind, u.GetName(), err)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, o); err != nil {
+		return nil, fmt.Errorf("'%s/%s': %w", gvk.Kind, u.GetName(), err)
+	}
+	obj, ok := o.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("'%s/%s' is not an object", gvk.Kind, u.GetName())
+	}
+
+	// Merge the stringData of the Secrets into their data, as the API server does.
+	if secret, ok := obj.(*corev1.Secret); ok && len(secret.StringData) > 0 {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte, len(secret.StringData))
+		}
+		for k, v := range secret.StringData {
+			secret.Data[k] = []byte(v)
+		}
+		secret.StringData = nil
+	}
+	return obj, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRun(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	g.Expect(os.MkdirAll(repo, 0o755)).To(Succeed())
+	files := map[string]string{
+		"repo/app.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  token: ${token}
+`,
+		"ks.yaml": `
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: app
+  namespace: apps
+spec:
+  path: ./
+  postBuild:
+    substituteFrom:
+    - kind: Secret
+      name: vars
+`,
+		"objects.yaml": `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: vars
+stringData:
+  token: secret
+`,
+	}
+	for name, content := range files {
+		g.Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)).To(Succeed())
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--kustomization", filepath.Join(dir, "ks.yaml"),
+		"--objects", filepath.Join(dir, "objects.yaml"), "-o", "yaml", repo}, &stdout, &stderr)
+	g.Expect(code).To(Equal(0), stderr.String())
+	g.Expect(stdout.String()).To(ContainSubstring("token: secret"))
+	g.Expect(stderr.String()).To(ContainSubstring("built 1 objects"))
+
+	stdout.Reset()
+	stderr.Reset()
+	code = Run([]string{"--kustomization", filepath.Join(dir, "ks.yaml"), repo}, &stdout, &stderr)
+	g.Expect(code).To(Equal(1))
+	g.Expect(stdout.String()).To(BeEmpty())
+	g.Expect(stderr.String()).To(ContainSubstring("not found"))
+
+	stderr.Reset()
+	code = Run([]string{"--output", "json", repo}, &stdout, &stderr)
+	g.Expect(code).To(Equal(2))
+
+	code = Run(nil, &stdout, &stderr)
+	g.Expect(code).To(Equal(2))
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	celtypes "github.com/google/cel-go/common/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fluxcd/pkg/runtime/cel"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+const (
+	selfName      = "self"
+	namespaceName = "ns"
+)
+
+// Rule is a CEL validation rule evaluated against Kustomization objects.
+type Rule struct {
+	// Name is the ConfigMap key of the rule.
+	Name string `json:"-"`
+
+	// Expression is a CEL expression evaluating to true when the
+	// Kustomization complies with the rule. The Kustomization is available
+	// as the 'self' variable and its Namespace as the 'ns' variable.
+	Expression string `json:"expression"`
+
+	// Message is returned when the expression evaluates to false.
+	Message string `json:"message,omitempty"`
+
+	expr *cel.Expression
+}
+
+// ViolationError is returned when a Kustomization violates one or more rules.
+type ViolationError struct {
+	Errs []error
+}
+
+func (e *ViolationError) Error() string {
+	return errors.Join(e.Errs...).Error()
+}
+
+func (e *ViolationError) Unwrap() []error {
+	return e.Errs
+}
+
+// Rules is a set of compiled validation rules.
+type Rules []Rule
+
+// Parse compiles the rules from the data of a ConfigMap. Each key holds
+// a rule with the 'expression' and 'message' fields in YAML format.
+func Parse(data map[string]string) (Rules, error) {
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := make(Rules, 0, len(names))
+	for _, name := range names {
+		rule := Rule{Name: name}
+		if err := yaml.Unmarshal([]byte(data[name]), &rule); err != nil {
+			return nil, fmt.Errorf("failed to parse rule '%s': %w", name, err)
+		}
+		if rule.Expression == "" {
+			return nil, fmt.Errorf("rule '%s' has no expression", name)
+		}
+
+		expr, err := cel.NewExpression(rule.Expression,
+			cel.WithCompile(),
+			cel.WithOutputType(celtypes.BoolType),
+			cel.WithStructVariables(selfName, namespaceName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile rule '%s': %w", name, err)
+		}
+		rule.expr = expr
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Load reads and compiles the rules from the given ConfigMap.
+// A missing ConfigMap results in no rules.
+func Load(ctx context.Context, reader client.Reader, name types.NamespacedName) (Rules, error) {
+	cm := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, name, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get validation rules ConfigMap '%s': %w", name, err)
+	}
+	return Parse(cm.Data)
+}
+
+// Validate evaluates the rules against the given Kustomization and its
+// Namespace, and returns a ViolationError listing the violated rules.
+func (r Rules) Validate(ctx context.Context, obj *kustomizev1.Kustomization, ns *corev1.Namespace) error {
+	if len(r) == 0 {
+		return nil
+	}
+
+	selfMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s object to map: %w", selfName, err)
+	}
+	nsMap := map[string]any{}
+	if ns != nil {
+		if nsMap, err = runtime.DefaultUnstructuredConverter.ToUnstructured(ns); err != nil {
+			return fmt.Errorf("failed to convert %s object to map: %w", namespaceName, err)
+		}
+	}
+	vars := map[string]any{
+		selfName:      selfMap,
+		namespaceName: nsMap,
+	}
+
+	var errs []error
+	for _, rule := range r {
+		ok, err := rule.expr.EvaluateBoolean(ctx, vars)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule '%s' failed: %w", rule.Name, err))
+			continue
+		}
+		if !ok {
+			msg := rule.Message
+			if msg == "" {
+				msg = fmt.Sprintf("expression '%s' evaluated to false", rule.Expression)
+			}
+			errs = append(errs, fmt.Errorf("rule '%s' violated: %s", rule.Name, msg))
+		}
+	}
+	if len(errs) > 0 {
+		return &ViolationError{Errs: errs}
+	}
+	return nil
+}
+
+// ValidateObject loads the rules from the given ConfigMap and evaluates
+// them against the Kustomization and its Namespace.
+func ValidateObject(ctx context.Context, reader client.Reader,
+	rulesName types.NamespacedName, obj *kustomizev1.Kustomization) error {
+	rules, err := Load(ctx, reader, rulesName)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	ns := &corev1.Namespace{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, ns); err != nil {
+		return fmt.Errorf("failed to get namespace '%s': %w", obj.GetNamespace(), err)
+	}
+	return rules.Validate(ctx, obj, ns)
+}
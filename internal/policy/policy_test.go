@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+const pruneInProdRule = `
+expression: |
+  !has(ns.metadata.labels) ||
+  ns.metadata.labels[?'env'].orValue('') != 'prod' ||
+  (has(self.spec.prune) && self.spec.prune)
+message: prune must be true in prod namespaces
+`
+
+func TestParse(t *testing.T) {
+	g := NewWithT(t)
+
+	rules, err := Parse(map[string]string{
+		"prune-in-prod": pruneInProdRule,
+		"has-path":      `expression: "has(self.spec.path)"`,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rules).To(HaveLen(2))
+	g.Expect(rules[0].Name).To(Equal("has-path"))
+	g.Expect(rules[1].Name).To(Equal("prune-in-prod"))
+
+	_, err = Parse(map[string]string{"empty": `message: no expression`})
+	g.Expect(err).To(MatchError(ContainSubstring("rule 'empty' has no expression")))
+
+	_, err = Parse(map[string]string{"invalid": `expression: "self.spec.prune +"`})
+	g.Expect(err).To(MatchError(ContainSubstring("failed to compile rule 'invalid'")))
+
+	_, err = Parse(map[string]string{"not-bool": `expression: "self.metadata.name"`})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestValidateObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "prod",
+		Labels: map[string]string{"env": "prod"},
+	}}
+	dev := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}}
+	rulesName := types.NamespacedName{Namespace: "flux-system", Name: "rules"}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: rulesName.Namespace, Name: rulesName.Name},
+		Data:       map[string]string{"prune-in-prod": pruneInProdRule},
+	}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(prod, dev, cm).Build()
+
+	newObj := func(namespace string, prune bool) *kustomizev1.Kustomization {
+		return &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: namespace},
+			Spec:       kustomizev1.KustomizationSpec{Prune: prune},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		rules   types.NamespacedName
+		obj     *kustomizev1.Kustomization
+		wantErr string
+	}{
+		{
+			name:  "allows prune in prod",
+			rules: rulesName,
+			obj:   newObj("prod", true),
+		},
+		{
+			name:  "allows no prune in dev",
+			rules: rulesName,
+			obj:   newObj("dev", false),
+		},
+		{
+			name:    "rejects no prune in prod",
+			rules:   rulesName,
+			obj:     newObj("prod", false),
+			wantErr: "rule 'prune-in-prod' violated: prune must be true in prod namespaces",
+		},
+		{
+			name:  "ignores missing ConfigMap",
+			rules: types.NamespacedName{Namespace: "flux-system", Name: "missing"},
+			obj:   newObj("prod", false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := ValidateObject(context.Background(), kubeClient, tt.rules, tt.obj)
+			if tt.wantErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			var violation *ViolationError
+			g.Expect(errors.As(err, &violation)).To(BeTrue())
+		})
+	}
+}
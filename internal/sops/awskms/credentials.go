@@ -18,6 +18,7 @@ package awskms
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"sigs.k8s.io/yaml"
@@ -25,15 +26,24 @@ import (
 
 // LoadStaticCredentialsFromYAML parses the given YAML and returns a
 // credentials.StaticCredentialsProvider that can be used to authenticate with
-// AWS, or an error if the YAML could not be parsed.
+// AWS, or an error if the YAML could not be parsed. The credentials expire
+// at the optional aws_session_expiration time of temporary credentials, e.g.
+// issued by STS.
 func LoadStaticCredentialsFromYAML(b []byte) (credentials.StaticCredentialsProvider, error) {
 	d := struct {
 		AccessKeyID     string `json:"aws_access_key_id"`
 		SecretAccessKey string `json:"aws_secret_access_key"`
 		SessionToken    string `json:"aws_session_token"`
+		// SessionExpiration is the RFC 3339 expiry of the session token.
+		SessionExpiration *time.Time `json:"aws_session_expiration"`
 	}{}
 	if err := yaml.Unmarshal(b, &d); err != nil {
 		return credentials.StaticCredentialsProvider{}, fmt.Errorf("failed to unmarshal AWS credentials file: %w", err)
 	}
-	return credentials.NewStaticCredentialsProvider(d.AccessKeyID, d.SecretAccessKey, d.SessionToken), nil
+	provider := credentials.NewStaticCredentialsProvider(d.AccessKeyID, d.SecretAccessKey, d.SessionToken)
+	if d.SessionExpiration != nil {
+		provider.Value.CanExpire = true
+		provider.Value.Expires = *d.SessionExpiration
+	}
+	return provider, nil
 }
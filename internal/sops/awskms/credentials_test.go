@@ -19,6 +19,7 @@ package awskms
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 )
@@ -40,3 +41,23 @@ aws_session_token: test-token
 	g.Expect(creds.SecretAccessKey).To(Equal("test-secret"))
 	g.Expect(creds.SessionToken).To(Equal("test-token"))
 }
+
+func TestLoadStaticCredentialsFromYAML_sessionExpiration(t *testing.T) {
+	g := NewWithT(t)
+	credsYaml := []byte(`
+aws_access_key_id: test-id
+aws_secret_access_key: test-secret
+aws_session_token: test-token
+aws_session_expiration: 2026-01-02T03:04:05Z
+`)
+	credsProvider, err := LoadStaticCredentialsFromYAML(credsYaml)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	creds, err := credsProvider.Retrieve(context.TODO())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(creds.CanExpire).To(BeTrue())
+	g.Expect(creds.Expires).To(BeTemporally("==", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	_, err = LoadStaticCredentialsFromYAML([]byte("aws_session_expiration: tomorrow"))
+	g.Expect(err).To(HaveOccurred())
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"net/http"
 	"unicode/utf16"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -77,15 +78,23 @@ type AZConfig struct {
 //   - azidentity.ManagedIdentityCredential for a User ID, when a `clientId`
 //     field but no `tenantId` is found.
 //
+// The token requests are issued with the given HTTP client, or the default
+// HTTP client when nil.
+//
 // If no set of credentials is found or the azcore.TokenCredential can not be
 // created, an error is returned.
-func TokenCredentialFromAADConfig(c AADConfig) (token azcore.TokenCredential, err error) {
+func TokenCredentialFromAADConfig(c AADConfig, hc *http.Client) (token azcore.TokenCredential, err error) {
+	clientOptions := azcore.ClientOptions{
+		Cloud: c.GetCloudConfig(),
+	}
+	if hc != nil {
+		clientOptions.Transport = hc
+	}
+
 	if c.TenantID != "" && c.ClientID != "" {
 		if c.ClientSecret != "" {
 			return azidentity.NewClientSecretCredential(c.TenantID, c.ClientID, c.ClientSecret, &azidentity.ClientSecretCredentialOptions{
-				ClientOptions: azcore.ClientOptions{
-					Cloud: c.GetCloudConfig(),
-				},
+				ClientOptions: clientOptions,
 			})
 		}
 		if c.ClientCertificate != "" {
@@ -95,9 +104,7 @@ func TokenCredentialFromAADConfig(c AADConfig) (token azcore.TokenCredential, er
 			}
 			return azidentity.NewClientCertificateCredential(c.TenantID, c.ClientID, certs, pk, &azidentity.ClientCertificateCredentialOptions{
 				SendCertificateChain: c.ClientCertificateSendChain,
-				ClientOptions: azcore.ClientOptions{
-					Cloud: c.GetCloudConfig(),
-				},
+				ClientOptions:        clientOptions,
 			})
 		}
 	}
@@ -105,13 +112,12 @@ func TokenCredentialFromAADConfig(c AADConfig) (token azcore.TokenCredential, er
 	switch {
 	case c.Tenant != "" && c.AppID != "" && c.Password != "":
 		return azidentity.NewClientSecretCredential(c.Tenant, c.AppID, c.Password, &azidentity.ClientSecretCredentialOptions{
-			ClientOptions: azcore.ClientOptions{
-				Cloud: c.GetCloudConfig(),
-			},
+			ClientOptions: clientOptions,
 		})
 	case c.ClientID != "":
 		return azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
-			ID: azidentity.ClientID(c.ClientID),
+			ClientOptions: azcore.ClientOptions{Transport: clientOptions.Transport},
+			ID:            azidentity.ClientID(c.ClientID),
 		})
 	default:
 		return nil, fmt.Errorf("invalid data: requires a '%s' field, a combination of '%s', '%s' and '%s', or '%s', '%s' and '%s'",
@@ -158,7 +158,7 @@ func TestTokenFromAADConfig(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			got, err := TokenCredentialFromAADConfig(tt.config)
+			got, err := TokenCredentialFromAADConfig(tt.config, nil)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(got).To(BeNil())
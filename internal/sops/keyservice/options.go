@@ -18,13 +18,13 @@ package keyservice
 
 import (
 	"context"
+	"net/http"
 
 	extage "filippo.io/age"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/getsops/sops/v3/age"
 	"github.com/getsops/sops/v3/azkv"
-	"github.com/getsops/sops/v3/gcpkms"
 	"github.com/getsops/sops/v3/hcvault"
 	"github.com/getsops/sops/v3/keyservice"
 	awskms "github.com/getsops/sops/v3/kms"
@@ -56,6 +56,24 @@ func (o WithVaultToken) ApplyToServer(s *Server) {
 	s.vaultToken = hcvault.Token(o)
 }
 
+// WithVaultNamespace configures the OpenBao/Vault Enterprise namespace of
+// the transit engine requests on the Server.
+type WithVaultNamespace string
+
+// ApplyToServer applies this configuration to the given Server.
+func (o WithVaultNamespace) ApplyToServer(s *Server) {
+	s.vaultNamespace = string(o)
+}
+
+// WithVaultTransitPath configures the mount path of the OpenBao/Vault
+// transit engine on the Server, overriding the engine path of the keys.
+type WithVaultTransitPath string
+
+// ApplyToServer applies this configuration to the given Server.
+func (o WithVaultTransitPath) ApplyToServer(s *Server) {
+	s.vaultTransitPath = string(o)
+}
+
 // WithVaultK8sAuth configures a function that obtains an OpenBao/Vault
 // token by authenticating with the Kubernetes auth method, on the Server. The
 // function receives the Vault address of the data key being processed, as the
@@ -99,7 +117,7 @@ type WithGCPTokenSource struct {
 
 // ApplyToServer applies this configuration to the given Server.
 func (o WithGCPTokenSource) ApplyToServer(s *Server) {
-	s.gcpTokenSource = gcpkms.NewTokenSource(o.TokenSource)
+	s.gcpTokenSource = o.TokenSource
 }
 
 // WithAzureTokenCredential configures the Azure credential token on the Server.
@@ -121,3 +139,26 @@ type WithDefaultServer struct {
 func (o WithDefaultServer) ApplyToServer(s *Server) {
 	s.defaultServer = o.Server
 }
+
+// WithContext configures the context of the requests to the key management
+// services on the Server, e.g. to bound them with the reconciliation deadline.
+type WithContext struct {
+	Context context.Context
+}
+
+// ApplyToServer applies this configuration to the given Server.
+func (o WithContext) ApplyToServer(s *Server) {
+	s.ctx = o.Context
+}
+
+// WithHTTPClient configures the HTTP client of the requests to the AWS KMS,
+// Azure Key Vault, GCP KMS and OpenBao/Vault services on the Server, e.g. to
+// trust a custom CA bundle or to send the requests through a proxy.
+type WithHTTPClient struct {
+	Client *http.Client
+}
+
+// ApplyToServer applies this configuration to the given Server.
+func (o WithHTTPClient) ApplyToServer(s *Server) {
+	s.httpClient = o.Client
+}
@@ -18,7 +18,10 @@ package keyservice
 
 import (
 	"fmt"
+	"net/http"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
 	"github.com/getsops/sops/v3/age"
 	"github.com/getsops/sops/v3/azkv"
 	"github.com/getsops/sops/v3/gcpkms"
@@ -27,7 +30,10 @@ import (
 	awskms "github.com/getsops/sops/v3/kms"
 	"github.com/getsops/sops/v3/logging"
 	"github.com/getsops/sops/v3/pgp"
+	vaultapi "github.com/hashicorp/vault/api"
 	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
 )
 
 // Server is a key service server that uses SOPS MasterKeys to fulfill
@@ -63,6 +69,15 @@ type Server struct {
 	// defaultServer.
 	vaultK8sAuth func(ctx context.Context, vaultAddress string) (string, error)
 
+	// vaultNamespace is the OpenBao/Vault Enterprise namespace set on the
+	// OpenBao/Vault requests. When empty, the root namespace is used.
+	vaultNamespace string
+
+	// vaultTransitPath is the mount path of the OpenBao/Vault transit engine
+	// used for the OpenBao/Vault requests, overriding the engine path of the
+	// keys. When empty, the engine path of the keys is used.
+	vaultTransitPath string
+
 	// azureTokenCredential is the credential token used for Encrypt and Decrypt
 	// operations of Azure Key Vault requests.
 	// When nil, the request will be handled by defaultServer.
@@ -75,11 +90,21 @@ type Server struct {
 
 	// gcpTokenSource is the token source used for Encrypt and Decrypt
 	// operations of GCP KMS requests.
-	gcpTokenSource gcpkms.TokenSource
+	gcpTokenSource oauth2.TokenSource
+
+	// httpClient is the HTTP client used for Encrypt and Decrypt operations
+	// of AWS KMS, Azure Key Vault, GCP KMS and OpenBao/Vault requests.
+	// When nil, the default HTTP client of each service is used.
+	httpClient *http.Client
 
 	// defaultServer is the fallback server, used to handle any request that
 	// is not eligible to be handled by this Server.
 	defaultServer keyservice.KeyServiceServer
+
+	// ctx is the context of the requests to the key management services.
+	// SOPS issues the requests with a background context, which is replaced
+	// by ctx when set, so that the requests honor its deadline.
+	ctx context.Context
 }
 
 // NewServer constructs a new Server, configuring it with the provided options
@@ -108,6 +133,7 @@ func NewServer(options ...ServerOption) keyservice.KeyServiceServer {
 // Encrypt takes an encrypt request and encrypts the provided plaintext with
 // the provided key, returning the encrypted result.
 func (ks Server) Encrypt(ctx context.Context, req *keyservice.EncryptRequest) (*keyservice.EncryptResponse, error) {
+	ctx = ks.requestContext(ctx)
 	key := req.Key
 	switch k := key.KeyType.(type) {
 	case *keyservice.Key_PgpKey:
@@ -137,7 +163,7 @@ func (ks Server) Encrypt(ctx context.Context, req *keyservice.EncryptRequest) (*
 			}, nil
 		}
 	case *keyservice.Key_KmsKey:
-		cipherText, err := ks.encryptWithAWSKMS(k.KmsKey, req.Plaintext)
+		cipherText, err := ks.encryptWithAWSKMS(ctx, k.KmsKey, req.Plaintext)
 		if err != nil {
 			return nil, err
 		}
@@ -145,7 +171,7 @@ func (ks Server) Encrypt(ctx context.Context, req *keyservice.EncryptRequest) (*
 			Ciphertext: cipherText,
 		}, nil
 	case *keyservice.Key_AzureKeyvaultKey:
-		ciphertext, err := ks.encryptWithAzureKeyVault(k.AzureKeyvaultKey, req.Plaintext)
+		ciphertext, err := ks.encryptWithAzureKeyVault(ctx, k.AzureKeyvaultKey, req.Plaintext)
 		if err != nil {
 			return nil, err
 		}
@@ -153,7 +179,7 @@ func (ks Server) Encrypt(ctx context.Context, req *keyservice.EncryptRequest) (*
 			Ciphertext: ciphertext,
 		}, nil
 	case *keyservice.Key_GcpKmsKey:
-		ciphertext, err := ks.encryptWithGCPKMS(k.GcpKmsKey, req.Plaintext)
+		ciphertext, err := ks.encryptWithGCPKMS(ctx, k.GcpKmsKey, req.Plaintext)
 		if err != nil {
 			return nil, err
 		}
@@ -170,6 +196,7 @@ func (ks Server) Encrypt(ctx context.Context, req *keyservice.EncryptRequest) (*
 // Decrypt takes a decrypt request and decrypts the provided ciphertext with
 // the provided key, returning the decrypted result.
 func (ks Server) Decrypt(ctx context.Context, req *keyservice.DecryptRequest) (*keyservice.DecryptResponse, error) {
+	ctx = ks.requestContext(ctx)
 	key := req.Key
 	switch k := key.KeyType.(type) {
 	case *keyservice.Key_PgpKey:
@@ -199,7 +226,7 @@ func (ks Server) Decrypt(ctx context.Context, req *keyservice.DecryptRequest) (*
 			}, nil
 		}
 	case *keyservice.Key_KmsKey:
-		plaintext, err := ks.decryptWithAWSKMS(k.KmsKey, req.Ciphertext)
+		plaintext, err := ks.decryptWithAWSKMS(ctx, k.KmsKey, req.Ciphertext)
 		if err != nil {
 			return nil, err
 		}
@@ -207,7 +234,7 @@ func (ks Server) Decrypt(ctx context.Context, req *keyservice.DecryptRequest) (*
 			Plaintext: plaintext,
 		}, nil
 	case *keyservice.Key_AzureKeyvaultKey:
-		plaintext, err := ks.decryptWithAzureKeyVault(k.AzureKeyvaultKey, req.Ciphertext)
+		plaintext, err := ks.decryptWithAzureKeyVault(ctx, k.AzureKeyvaultKey, req.Ciphertext)
 		if err != nil {
 			return nil, err
 		}
@@ -215,7 +242,7 @@ func (ks Server) Decrypt(ctx context.Context, req *keyservice.DecryptRequest) (*
 			Plaintext: plaintext,
 		}, nil
 	case *keyservice.Key_GcpKmsKey:
-		plaintext, err := ks.decryptWithGCPKMS(k.GcpKmsKey, req.Ciphertext)
+		plaintext, err := ks.decryptWithGCPKMS(ctx, k.GcpKmsKey, req.Ciphertext)
 		if err != nil {
 			return nil, err
 		}
@@ -280,7 +307,7 @@ func (ks *Server) decryptWithAge(key *keyservice.AgeKey, ciphertext []byte) ([]b
 func (ks *Server) encryptWithHCVault(ctx context.Context, key *keyservice.VaultKey, plaintext []byte) ([]byte, error) {
 	vaultKey := hcvault.MasterKey{
 		VaultAddress: key.VaultAddress,
-		EnginePath:   key.EnginePath,
+		EnginePath:   ks.vaultEnginePath(key),
 		KeyName:      key.KeyName,
 	}
 	token, err := ks.resolveVaultToken(ctx, key.VaultAddress)
@@ -288,7 +315,10 @@ func (ks *Server) encryptWithHCVault(ctx context.Context, key *keyservice.VaultK
 		return nil, err
 	}
 	token.ApplyToMasterKey(&vaultKey)
-	if err := vaultKey.Encrypt(plaintext); err != nil {
+	if hc := ks.vaultHTTPClient(); hc != nil {
+		hcvault.NewHTTPClient(hc).ApplyToMasterKey(&vaultKey)
+	}
+	if err := vaultKey.EncryptContext(ctx, plaintext); err != nil {
 		return nil, err
 	}
 	return []byte(vaultKey.EncryptedKey), nil
@@ -297,7 +327,7 @@ func (ks *Server) encryptWithHCVault(ctx context.Context, key *keyservice.VaultK
 func (ks *Server) decryptWithHCVault(ctx context.Context, key *keyservice.VaultKey, ciphertext []byte) ([]byte, error) {
 	vaultKey := hcvault.MasterKey{
 		VaultAddress: key.VaultAddress,
-		EnginePath:   key.EnginePath,
+		EnginePath:   ks.vaultEnginePath(key),
 		KeyName:      key.KeyName,
 	}
 	vaultKey.EncryptedKey = string(ciphertext)
@@ -306,10 +336,67 @@ func (ks *Server) decryptWithHCVault(ctx context.Context, key *keyservice.VaultK
 		return nil, err
 	}
 	token.ApplyToMasterKey(&vaultKey)
-	plaintext, err := vaultKey.Decrypt()
+	if hc := ks.vaultHTTPClient(); hc != nil {
+		hcvault.NewHTTPClient(hc).ApplyToMasterKey(&vaultKey)
+	}
+	plaintext, err := vaultKey.DecryptContext(ctx)
 	return plaintext, err
 }
 
+// vaultEnginePath returns the mount path of the transit engine of the
+// OpenBao/Vault key, unless overridden on the Server.
+func (ks *Server) vaultEnginePath(key *keyservice.VaultKey) string {
+	if ks.vaultTransitPath != "" {
+		return ks.vaultTransitPath
+	}
+	return key.EnginePath
+}
+
+// vaultHTTPClient returns the HTTP client of the OpenBao/Vault requests,
+// which sets the namespace header on the requests if a namespace is
+// configured on the Server.
+func (ks *Server) vaultHTTPClient() *http.Client {
+	if ks.vaultNamespace == "" {
+		return ks.httpClient
+	}
+	hc := ks.httpClient
+	if hc == nil {
+		hc = vaultapi.DefaultConfig().HttpClient
+	}
+	c := *hc
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c.Transport = &vaultNamespaceTransport{namespace: ks.vaultNamespace, base: transport}
+	return &c
+}
+
+// vaultNamespaceTransport sets the OpenBao/Vault Enterprise namespace
+// header on the requests which don't set it.
+type vaultNamespaceTransport struct {
+	namespace string
+	base      http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *vaultNamespaceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get(vaultapi.NamespaceHeaderName) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(vaultapi.NamespaceHeaderName, t.namespace)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// requestContext returns the context of the Server if set,
+// or else the given context.
+func (ks Server) requestContext(ctx context.Context) context.Context {
+	if ks.ctx != nil {
+		return ks.ctx
+	}
+	return ctx
+}
+
 // resolveVaultToken returns the OpenBao/Vault token to use for the
 // Vault server at the given address. A statically configured token takes
 // precedence; otherwise the token is obtained via the Kubernetes auth method.
@@ -324,68 +411,102 @@ func (ks *Server) resolveVaultToken(ctx context.Context, vaultAddress string) (h
 	return hcvault.Token(token), nil
 }
 
-func (ks *Server) encryptWithAWSKMS(key *keyservice.KmsKey, plaintext []byte) ([]byte, error) {
+func (ks *Server) encryptWithAWSKMS(ctx context.Context, key *keyservice.KmsKey, plaintext []byte) ([]byte, error) {
 	awsKey := kmsKeyToMasterKey(key)
 	ks.awsCredentialsProvider(key.Arn).ApplyToMasterKey(&awsKey)
-	if err := awsKey.Encrypt(plaintext); err != nil {
+	if ks.httpClient != nil {
+		awskms.NewHTTPClient(ks.httpClient).ApplyToMasterKey(&awsKey)
+	}
+	if err := awsKey.EncryptContext(ctx, plaintext); err != nil {
 		return nil, err
 	}
 	return []byte(awsKey.EncryptedKey), nil
 }
 
-func (ks *Server) decryptWithAWSKMS(key *keyservice.KmsKey, cipherText []byte) ([]byte, error) {
+func (ks *Server) decryptWithAWSKMS(ctx context.Context, key *keyservice.KmsKey, cipherText []byte) ([]byte, error) {
 	awsKey := kmsKeyToMasterKey(key)
 	awsKey.EncryptedKey = string(cipherText)
 	ks.awsCredentialsProvider(key.Arn).ApplyToMasterKey(&awsKey)
-	return awsKey.Decrypt()
+	if ks.httpClient != nil {
+		awskms.NewHTTPClient(ks.httpClient).ApplyToMasterKey(&awsKey)
+	}
+	return awsKey.DecryptContext(ctx)
 }
 
-func (ks *Server) encryptWithAzureKeyVault(key *keyservice.AzureKeyVaultKey, plaintext []byte) ([]byte, error) {
+func (ks *Server) encryptWithAzureKeyVault(ctx context.Context, key *keyservice.AzureKeyVaultKey, plaintext []byte) ([]byte, error) {
 	azureKey := azkv.MasterKey{
 		VaultURL: key.VaultUrl,
 		Name:     key.Name,
 		Version:  key.Version,
 	}
 	ks.azureTokenCredential.ApplyToMasterKey(&azureKey)
-	if err := azureKey.Encrypt(plaintext); err != nil {
+	ks.applyAzureClientOptions(&azureKey)
+	if err := azureKey.EncryptContext(ctx, plaintext); err != nil {
 		return nil, err
 	}
 	return []byte(azureKey.EncryptedKey), nil
 }
 
-func (ks *Server) decryptWithAzureKeyVault(key *keyservice.AzureKeyVaultKey, ciphertext []byte) ([]byte, error) {
+func (ks *Server) decryptWithAzureKeyVault(ctx context.Context, key *keyservice.AzureKeyVaultKey, ciphertext []byte) ([]byte, error) {
 	azureKey := azkv.MasterKey{
 		VaultURL: key.VaultUrl,
 		Name:     key.Name,
 		Version:  key.Version,
 	}
 	ks.azureTokenCredential.ApplyToMasterKey(&azureKey)
+	ks.applyAzureClientOptions(&azureKey)
 	azureKey.EncryptedKey = string(ciphertext)
-	plaintext, err := azureKey.Decrypt()
+	plaintext, err := azureKey.DecryptContext(ctx)
 	return plaintext, err
 }
 
-func (ks *Server) encryptWithGCPKMS(key *keyservice.GcpKmsKey, plaintext []byte) ([]byte, error) {
+func (ks *Server) encryptWithGCPKMS(ctx context.Context, key *keyservice.GcpKmsKey, plaintext []byte) ([]byte, error) {
 	gcpKey := gcpkms.MasterKey{
 		ResourceID: key.ResourceId,
 	}
-	ks.gcpTokenSource.ApplyToMasterKey(&gcpKey)
-	if err := gcpKey.Encrypt(plaintext); err != nil {
+	ks.applyGCPClientOptions(&gcpKey)
+	if err := gcpKey.EncryptContext(ctx, plaintext); err != nil {
 		return nil, err
 	}
 	return gcpKey.EncryptedDataKey(), nil
 }
 
-func (ks *Server) decryptWithGCPKMS(key *keyservice.GcpKmsKey, ciphertext []byte) ([]byte, error) {
+func (ks *Server) decryptWithGCPKMS(ctx context.Context, key *keyservice.GcpKmsKey, ciphertext []byte) ([]byte, error) {
 	gcpKey := gcpkms.MasterKey{
 		ResourceID: key.ResourceId,
 	}
-	ks.gcpTokenSource.ApplyToMasterKey(&gcpKey)
+	ks.applyGCPClientOptions(&gcpKey)
 	gcpKey.EncryptedKey = string(ciphertext)
-	plaintext, err := gcpKey.Decrypt()
+	plaintext, err := gcpKey.DecryptContext(ctx)
 	return plaintext, err
 }
 
+// applyAzureClientOptions configures the Azure Key Vault client of the given
+// key to use the HTTP client of the Server, if set.
+func (ks *Server) applyAzureClientOptions(key *azkv.MasterKey) {
+	if ks.httpClient == nil {
+		return
+	}
+	azkv.NewClientOptions(&azkeys.ClientOptions{
+		ClientOptions: azcore.ClientOptions{Transport: ks.httpClient},
+	}).ApplyToMasterKey(key)
+}
+
+// applyGCPClientOptions configures the token source of the given key, and
+// the HTTP client of the Server if set. As the GCP KMS client ignores the
+// token source when given an HTTP client, the requests are then issued with
+// the REST client and authorized by the transport of the HTTP client.
+func (ks *Server) applyGCPClientOptions(key *gcpkms.MasterKey) {
+	gcpkms.NewTokenSource(ks.gcpTokenSource).ApplyToMasterKey(key)
+	if ks.httpClient == nil || ks.gcpTokenSource == nil {
+		return
+	}
+	hc := *ks.httpClient
+	hc.Transport = &oauth2.Transport{Source: ks.gcpTokenSource, Base: ks.httpClient.Transport}
+	gcpkms.ClientOptions{option.WithHTTPClient(&hc)}.ApplyToMasterKey(key)
+	gcpkms.UseRESTClient{}.ApplyToMasterKey(key)
+}
+
 func kmsKeyToMasterKey(key *keyservice.KmsKey) awskms.MasterKey {
 	ctx := make(map[string]*string)
 	for k, v := range key.Context {
@@ -18,6 +18,8 @@ package keyservice
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -152,6 +154,85 @@ func TestServer_EncryptDecrypt_HCVault_Fallback(t *testing.T) {
 	g.Expect(fallback.encryptReqs).To(HaveLen(0))
 }
 
+func TestServer_WithContext(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var requestCtx context.Context
+	s := NewServer(WithContext{Context: ctx}, WithVaultK8sAuth{
+		TokenFunc: func(ctx context.Context, _ string) (string, error) {
+			requestCtx = ctx
+			return "", ctx.Err()
+		},
+	})
+
+	key := KeyFromMasterKey(hcvault.NewMasterKey("https://example.com", "engine-path", "key-name"))
+	_, err := s.Decrypt(context.TODO(), &keyservice.DecryptRequest{
+		Key: &key,
+	})
+	g.Expect(err).To(MatchError(context.Canceled))
+	g.Expect(requestCtx).To(BeIdenticalTo(ctx))
+}
+
+func TestServer_WithHTTPClient(t *testing.T) {
+	g := NewWithT(t)
+
+	var requests int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	// The server certificate is only trusted by the client of the test server.
+	s := NewServer(WithVaultToken("token"), WithHTTPClient{Client: srv.Client()})
+	key := KeyFromMasterKey(hcvault.NewMasterKey(srv.URL, "engine-path", "key-name"))
+	_, err := s.Decrypt(context.TODO(), &keyservice.DecryptRequest{
+		Key: &key,
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("Code: 403"))
+	g.Expect(requests).To(Equal(1))
+
+	s = NewServer(WithVaultToken("token"))
+	_, err = s.Decrypt(context.TODO(), &keyservice.DecryptRequest{
+		Key: &key,
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("certificate")))
+	g.Expect(requests).To(Equal(1))
+}
+
+func TestServer_WithVaultNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	var namespace, path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace = r.Header.Get("X-Vault-Namespace")
+		path = r.URL.Path
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	key := KeyFromMasterKey(hcvault.NewMasterKey(srv.URL, "transit", "key-name"))
+	s := NewServer(WithVaultToken("token"), WithVaultNamespace("team-a"), WithVaultTransitPath("team-a-transit"))
+	_, err := s.Decrypt(context.TODO(), &keyservice.DecryptRequest{
+		Key: &key,
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("Code: 403")))
+	g.Expect(namespace).To(Equal("team-a"))
+	g.Expect(path).To(Equal("/v1/team-a-transit/decrypt/key-name"))
+
+	// Without a namespace and transit path, the key metadata is used as is.
+	s = NewServer(WithVaultToken("token"))
+	_, err = s.Decrypt(context.TODO(), &keyservice.DecryptRequest{
+		Key: &key,
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("Code: 403")))
+	g.Expect(namespace).To(BeEmpty())
+	g.Expect(path).To(Equal("/v1/transit/decrypt/key-name"))
+}
+
 func TestServer_EncryptDecrypt_awskms(t *testing.T) {
 	g := NewWithT(t)
 	s := NewServer(WithAWSCredentialsProvider{
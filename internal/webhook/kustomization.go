@@ -0,0 +1,236 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
+	"github.com/fluxcd/kustomize-controller/internal/policy"
+)
+
+// KustomizationWebhook defaults and validates Kustomization objects
+// at admission time.
+type KustomizationWebhook struct {
+	// Client is used to look up Namespaces and Kustomizations.
+	Client client.Reader
+
+	// MinInterval is the lowest interval accepted for the reconciliation
+	// and retry intervals. Zero disables the check.
+	MinInterval time.Duration
+
+	// NoCrossNamespaceRefs rejects source references to other namespaces.
+	NoCrossNamespaceRefs bool
+
+	// SpecValidationRules is the ConfigMap holding the platform
+	// validation rules. An empty name disables the rules.
+	SpecValidationRules types.NamespacedName
+}
+
+// SetupWithManager registers the mutating and validating webhooks
+// of the Kustomization kind with the manager webhook server.
+func (w *KustomizationWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &kustomizev1.Kustomization{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// Default sets the interval and prune fields from the annotations of the
+// Kustomization namespace when they are not specified. Objects being
+// deleted are left as-is, so that the removal of their finalizer is
+// never rejected.
+func (w *KustomizationWebhook) Default(ctx context.Context, obj *kustomizev1.Kustomization) error {
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	namespace := obj.GetNamespace()
+	req, reqErr := admission.RequestFromContext(ctx)
+	if namespace == "" && reqErr == nil {
+		namespace = req.Namespace
+	}
+
+	ns := &corev1.Namespace{}
+	if err := w.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get namespace '%s': %w", namespace, err)
+	}
+	annotations := ns.GetAnnotations()
+
+	// An interval explicitly set to zero disables the periodic reconciliation,
+	// hence the default applies only when the interval is not specified.
+	if v, ok := annotations[kustomizev1.DefaultIntervalAnnotation]; ok && obj.Spec.Interval.Duration == 0 &&
+		(reqErr != nil || !hasSpecField(req.Object.Raw, "interval")) {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid '%s' annotation on namespace '%s': %w",
+				kustomizev1.DefaultIntervalAnnotation, namespace, err)
+		}
+		obj.Spec.Interval = metav1.Duration{Duration: interval}
+	}
+
+	// The prune field is a boolean, hence its presence can only be
+	// determined from the raw object of the admission request.
+	if v, ok := annotations[kustomizev1.DefaultPruneAnnotation]; ok && reqErr == nil && !hasSpecField(req.Object.Raw, "prune") {
+		prune, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid '%s' annotation on namespace '%s': %w",
+				kustomizev1.DefaultPruneAnnotation, namespace, err)
+		}
+		obj.Spec.Prune = prune
+	}
+
+	return nil
+}
+
+// ValidateCreate validates the Kustomization on creation.
+func (w *KustomizationWebhook) ValidateCreate(ctx context.Context, obj *kustomizev1.Kustomization) (admission.Warnings, error) {
+	return nil, w.validate(ctx, obj)
+}
+
+// ValidateUpdate validates the Kustomization on update when its spec
+// changed. The updates of the metadata and status, e.g. the removal of the
+// finalizer of an object being deleted, are always allowed, so that raising
+// the interval floor or adding a validation rule can't leave existing
+// objects stuck.
+func (w *KustomizationWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *kustomizev1.Kustomization) (admission.Warnings, error) {
+	if !newObj.GetDeletionTimestamp().IsZero() || equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec) {
+		return nil, nil
+	}
+	return nil, w.validate(ctx, newObj)
+}
+
+// ValidateDelete allows the deletion of any Kustomization.
+func (w *KustomizationWebhook) ValidateDelete(_ context.Context, _ *kustomizev1.Kustomization) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (w *KustomizationWebhook) validate(ctx context.Context, obj *kustomizev1.Kustomization) error {
+	var errs []error
+
+	if w.MinInterval > 0 {
+		// A zero interval disables the periodic reconciliation.
+		if d := obj.Spec.Interval.Duration; d != 0 && d < w.MinInterval {
+			errs = append(errs, fmt.Errorf("spec.interval '%s' is lower than the minimum allowed interval '%s'",
+				obj.Spec.Interval.Duration, w.MinInterval))
+		}
+		if ri := obj.Spec.RetryInterval; ri != nil && ri.Duration < w.MinInterval {
+			errs = append(errs, fmt.Errorf("spec.retryInterval '%s' is lower than the minimum allowed interval '%s'",
+				ri.Duration, w.MinInterval))
+		}
+	}
+
+	if d := obj.Spec.Decryption; d != nil && !slices.Contains(decryptor.ProviderNames(), d.Provider) {
+		errs = append(errs, fmt.Errorf("spec.decryption.provider '%s' is not supported, must be one of '%s'",
+			d.Provider, strings.Join(decryptor.ProviderNames(), "', '")))
+	}
+
+	if sel := obj.Spec.WaitSelector; sel != nil {
+		if _, err := metav1.LabelSelectorAsSelector(sel); err != nil {
+			errs = append(errs, fmt.Errorf("spec.waitSelector is invalid: %w", err))
+		}
+	}
+
+	if ns := obj.Spec.SourceRef.Namespace; w.NoCrossNamespaceRefs && ns != "" && ns != obj.GetNamespace() {
+		errs = append(errs, fmt.Errorf("can't access '%s/%s/%s', cross-namespace references have been blocked",
+			obj.Spec.SourceRef.Kind, ns, obj.Spec.SourceRef.Name))
+	}
+
+	if err := w.validateDependsOn(ctx, obj); err != nil {
+		errs = append(errs, err)
+	}
+
+	if w.SpecValidationRules.Name != "" {
+		if err := policy.ValidateObject(ctx, w.Client, w.SpecValidationRules, obj); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateDependsOn walks the dependency graph of the Kustomization and
+// returns an error if the Kustomization depends on itself, directly or
+// through other Kustomizations. Dependencies that don't exist yet are skipped.
+func (w *KustomizationWebhook) validateDependsOn(ctx context.Context, obj *kustomizev1.Kustomization) error {
+	self := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	visited := make(map[types.NamespacedName]bool)
+
+	var visit func(deps []kustomizev1.DependencyReference, namespace string, path []string) error
+	visit = func(deps []kustomizev1.DependencyReference, namespace string, path []string) error {
+		for _, dep := range deps {
+			depName := types.NamespacedName{Namespace: dep.Namespace, Name: dep.Name}
+			if depName.Namespace == "" {
+				depName.Namespace = namespace
+			}
+
+			depPath := append(path[:len(path):len(path)], depName.String())
+			if depName == self {
+				return fmt.Errorf("circular dependency detected: %s", strings.Join(depPath, " -> "))
+			}
+			if visited[depName] {
+				continue
+			}
+			visited[depName] = true
+
+			var depObj kustomizev1.Kustomization
+			if err := w.Client.Get(ctx, depName, &depObj); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("failed to get dependency '%s': %w", depName, err)
+			}
+			if err := visit(depObj.Spec.DependsOn, depName.Namespace, depPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return visit(obj.Spec.DependsOn, self.Namespace, []string{self.String()})
+}
+
+// hasSpecField reports whether the raw Kustomization object sets the given spec field.
+func hasSpecField(raw []byte, field string) bool {
+	obj := make(map[string]any)
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return false
+	}
+	_, found, _ := unstructured.NestedFieldNoCopy(obj, "spec", field)
+	return found
+}
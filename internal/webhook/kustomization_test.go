@@ -0,0 +1,255 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+)
+
+func newTestClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kustomizev1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func newKustomization(name string, dependsOn ...string) *kustomizev1.Kustomization {
+	obj := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "apps",
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: 10 * time.Minute},
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{
+				Kind: "GitRepository",
+				Name: "repo",
+			},
+		},
+	}
+	for _, dep := range dependsOn {
+		obj.Spec.DependsOn = append(obj.Spec.DependsOn, kustomizev1.DependencyReference{Name: dep})
+	}
+	return obj
+}
+
+func TestKustomizationWebhook_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook *KustomizationWebhook
+		objects []client.Object
+		obj     func() *kustomizev1.Kustomization
+		wantErr string
+	}{
+		{
+			name:    "accepts valid object",
+			webhook: &KustomizationWebhook{MinInterval: time.Minute},
+			objects: []client.Object{newKustomization("infra")},
+			obj: func() *kustomizev1.Kustomization {
+				return newKustomization("app", "infra")
+			},
+		},
+		{
+			name:    "rejects interval below the floor",
+			webhook: &KustomizationWebhook{MinInterval: time.Minute},
+			obj: func() *kustomizev1.Kustomization {
+				obj := newKustomization("app")
+				obj.Spec.Interval = metav1.Duration{Duration: 10 * time.Second}
+				return obj
+			},
+			wantErr: "spec.interval '10s' is lower than the minimum allowed interval '1m0s'",
+		},
+		{
+			name:    "accepts zero interval",
+			webhook: &KustomizationWebhook{MinInterval: time.Minute},
+			obj: func() *kustomizev1.Kustomization {
+				obj := newKustomization("app")
+				obj.Spec.Interval = metav1.Duration{}
+				return obj
+			},
+		},
+		{
+			name:    "rejects retry interval below the floor",
+			webhook: &KustomizationWebhook{MinInterval: time.Minute},
+			obj: func() *kustomizev1.Kustomization {
+				obj := newKustomization("app")
+				obj.Spec.RetryInterval = &metav1.Duration{Duration: 30 * time.Second}
+				return obj
+			},
+			wantErr: "spec.retryInterval '30s'",
+		},
+		{
+			name:    "rejects unsupported decryption provider",
+			webhook: &KustomizationWebhook{},
+			obj: func() *kustomizev1.Kustomization {
+				obj := newKustomization("app")
+				obj.Spec.Decryption = &kustomizev1.Decryption{Provider: "vault"}
+				return obj
+			},
+			wantErr: "spec.decryption.provider 'vault' is not supported",
+		},
+		{
+			name:    "rejects invalid wait selector",
+			webhook: &KustomizationWebhook{},
+			obj: func() *kustomizev1.Kustomization {
+				obj := newKustomization("app")
+				obj.Spec.WaitSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Equals"}},
+				}
+				return obj
+			},
+			wantErr: "spec.waitSelector is invalid",
+		},
+		{
+			name:    "rejects cross-namespace source reference",
+			webhook: &KustomizationWebhook{NoCrossNamespaceRefs: true},
+			obj: func() *kustomizev1.Kustomization {
+				obj := newKustomization("app")
+				obj.Spec.SourceRef.Namespace = "flux-system"
+				return obj
+			},
+			wantErr: "cross-namespace references have been blocked",
+		},
+		{
+			name:    "rejects self dependency",
+			webhook: &KustomizationWebhook{},
+			obj: func() *kustomizev1.Kustomization {
+				return newKustomization("app", "app")
+			},
+			wantErr: "circular dependency detected: apps/app -> apps/app",
+		},
+		{
+			name:    "rejects transitive dependency cycle",
+			webhook: &KustomizationWebhook{},
+			objects: []client.Object{
+				newKustomization("infra", "crds"),
+				newKustomization("crds", "app"),
+			},
+			obj: func() *kustomizev1.Kustomization {
+				return newKustomization("app", "infra")
+			},
+			wantErr: "circular dependency detected: apps/app -> apps/infra -> apps/crds -> apps/app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tt.webhook.Client = newTestClient(tt.objects...)
+
+			_, err := tt.webhook.ValidateCreate(context.Background(), tt.obj())
+			if tt.wantErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+			}
+		})
+	}
+}
+
+func TestKustomizationWebhook_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+	w := &KustomizationWebhook{Client: newTestClient(), MinInterval: time.Hour}
+
+	oldObj := newKustomization("app")
+	oldObj.Finalizers = []string{kustomizev1.KustomizationFinalizer}
+
+	// Metadata updates of objects predating the interval floor are allowed.
+	newObj := oldObj.DeepCopy()
+	newObj.Labels = map[string]string{"team": "apps"}
+	_, err := w.ValidateUpdate(context.Background(), oldObj, newObj)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The finalizer of objects being deleted can be removed.
+	now := metav1.Now()
+	oldObj.DeletionTimestamp = &now
+	newObj = oldObj.DeepCopy()
+	newObj.Finalizers = nil
+	_, err = w.ValidateUpdate(context.Background(), oldObj, newObj)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Spec updates are validated.
+	oldObj.DeletionTimestamp = nil
+	newObj = oldObj.DeepCopy()
+	newObj.Spec.Prune = true
+	_, err = w.ValidateUpdate(context.Background(), oldObj, newObj)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("lower than the minimum allowed interval"))
+}
+
+func TestKustomizationWebhook_Default(t *testing.T) {
+	g := NewWithT(t)
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "apps",
+			Annotations: map[string]string{
+				kustomizev1.DefaultIntervalAnnotation: "30m",
+				kustomizev1.DefaultPruneAnnotation:    "true",
+			},
+		},
+	}
+	w := &KustomizationWebhook{Client: newTestClient(ns)}
+
+	withRequest := func(raw string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Namespace: "apps",
+				Object:    runtime.RawExtension{Raw: []byte(raw)},
+			},
+		})
+	}
+
+	obj := newKustomization("app")
+	obj.Spec.Interval = metav1.Duration{}
+	g.Expect(w.Default(withRequest(`{"spec":{}}`), obj)).To(Succeed())
+	g.Expect(obj.Spec.Interval.Duration).To(Equal(30 * time.Minute))
+	g.Expect(obj.Spec.Prune).To(BeTrue())
+
+	obj = newKustomization("app")
+	g.Expect(w.Default(withRequest(`{"spec":{"interval":"10m","prune":false}}`), obj)).To(Succeed())
+	g.Expect(obj.Spec.Interval.Duration).To(Equal(10 * time.Minute))
+	g.Expect(obj.Spec.Prune).To(BeFalse())
+
+	obj = newKustomization("app")
+	obj.Spec.Interval = metav1.Duration{}
+	g.Expect(w.Default(withRequest(`{"spec":{"interval":"0s"}}`), obj)).To(Succeed())
+	g.Expect(obj.Spec.Interval.Duration).To(BeZero())
+
+	obj = newKustomization("app")
+	obj.Spec.Interval = metav1.Duration{}
+	now := metav1.Now()
+	obj.DeletionTimestamp = &now
+	g.Expect(w.Default(withRequest(`{"spec":{}}`), obj)).To(Succeed())
+	g.Expect(obj.Spec.Interval.Duration).To(BeZero())
+}
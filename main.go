@@ -17,13 +17,22 @@ limitations under the License.
 package main
 
 import (
+	"expvar"
 	"fmt"
+	"maps"
+	"net"
+	"net/http"
 	"os"
+	"runtime/debug"
+	"strings"
 	"time"
 
 	flag "github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/azure"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -32,8 +41,11 @@ import (
 	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/clusterreader"
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/engine"
@@ -49,13 +61,19 @@ import (
 	"github.com/fluxcd/pkg/runtime/logger"
 	"github.com/fluxcd/pkg/runtime/metrics"
 	"github.com/fluxcd/pkg/runtime/pprof"
-	"github.com/fluxcd/pkg/runtime/probes"
 	ssautils "github.com/fluxcd/pkg/ssa/utils"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/kustomize-controller/internal/browser"
+	"github.com/fluxcd/kustomize-controller/internal/capabilities"
 	"github.com/fluxcd/kustomize-controller/internal/controller"
+	"github.com/fluxcd/kustomize-controller/internal/decryptor"
 	"github.com/fluxcd/kustomize-controller/internal/features"
+	"github.com/fluxcd/kustomize-controller/internal/fluxsops"
+	"github.com/fluxcd/kustomize-controller/internal/health"
+	"github.com/fluxcd/kustomize-controller/internal/lint"
+	"github.com/fluxcd/kustomize-controller/internal/webhook"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -75,17 +93,36 @@ func init() {
 }
 
 func main() {
+	// Run the lint subcommand instead of the controller if requested.
+	if len(os.Args) > 1 && os.Args[1] == lint.Command {
+		os.Exit(lint.Run(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	// Run the flux-sops gRPC server instead of the controller if requested.
+	if len(os.Args) > 1 && os.Args[1] == fluxsops.Command {
+		os.Exit(fluxsops.Run(os.Args[2:], os.Stderr))
+	}
+
 	const (
 		tokenCacheDefaultMaxSize = 100
 	)
 
 	var (
 		metricsAddr                     string
+		metricsSecure                   bool
+		metricsCertDir                  string
+		enablePprof                     bool
+		enableExpvar                    bool
+		enableInventoryBrowser          bool
+		memoryLimit                     string
+		gcPercent                       int
 		eventsAddr                      string
 		healthAddr                      string
 		concurrent                      int
 		concurrentSSA                   int
 		requeueDependency               time.Duration
+		dependencyWaitTimeout           time.Duration
+		shutdownDrainTimeout            time.Duration
+		remoteUnreachableGracePeriod    time.Duration
 		clientOptions                   runtimeClient.Options
 		kubeConfigOpts                  runtimeClient.KubeConfigOptions
 		logOptions                      logger.Options
@@ -95,35 +132,105 @@ func main() {
 		intervalJitterOptions           jitter.IntervalOptions
 		aclOptions                      acl.Options
 		noRemoteBases                   bool
+		allowKustomizeAlphaPlugins      bool
+		kustomizeHelmCommand            string
 		httpRetry                       int
+		applyRetries                    int
 		defaultServiceAccount           string
+		crdServiceAccount               string
+		crdServiceAccountNamespaces     []string
 		defaultDecryptionServiceAccount string
 		defaultKubeConfigServiceAccount string
 		sopsAgeSecret                   string
 		sopsVaultConfigMap              string
+		decryptionKeyExpiryWarning      time.Duration
+		decryptionKeysCacheTTL          time.Duration
+		decryptionMaxBinaryFileSize     int64
+		defaultSubstituteFrom           string
+		enableWebhook                   bool
+		webhookPort                     int
+		webhookCertDir                  string
+		webhookMinInterval              time.Duration
+		specValidationRules             string
+		tenancyProfiles                 string
+		upgradeFreezeLease              string
 		featureGates                    feathelper.FeatureGates
 		disallowedFieldManagers         []string
 		tokenCacheOptions               pkgcache.TokenFlags
 		customApplyStageKinds           string
+		ephemeralObjectLabels           map[string]string
+		namespaceLabels                 map[string]string
+		applyReadCacheKinds             string
+		applyReadCacheMaxStaleness      time.Duration
+		kubeConfigQPS                   float32
+		kubeConfigBurst                 int
+		kubeConfigTimeout               time.Duration
+		kubeConfigClientTTL             time.Duration
+		driftCheckUser                  string
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.BoolVar(&metricsSecure, "metrics-secure", false, "Serve the metrics and debug endpoints over HTTPS, restricted to clients authenticated and authorized by the Kubernetes API server.")
+	flag.StringVar(&metricsCertDir, "metrics-cert-dir", "", "The directory containing the TLS certificate (tls.crt) and key (tls.key) of the secure metrics server. A self-signed certificate is generated when empty.")
+	flag.BoolVar(&enablePprof, "enable-pprof", false, "Serve the pprof profiling endpoints under /debug/pprof on the metrics address. Requires --metrics-secure.")
+	flag.BoolVar(&enableExpvar, "enable-expvar", false, "Serve the expvar endpoint at /debug/vars on the metrics address. Requires --metrics-secure.")
+	flag.BoolVar(&enableInventoryBrowser, "enable-inventory-browser", false, "Serve the inventory of the Kustomizations with the live status of their objects at /inventory/<namespace>/<name> on the metrics address. Requires --metrics-secure.")
+	flag.StringVar(&memoryLimit, "memory-limit", "", "The soft memory limit of the Go runtime as a Kubernetes quantity, e.g. '900Mi'. It takes precedence over the GOMEMLIMIT environment variable.")
+	flag.IntVar(&gcPercent, "gc-percent", 100, "The garbage collection target percentage of the Go runtime. It takes precedence over the GOGC environment variable when set.")
 	flag.StringVar(&eventsAddr, "events-addr", "", "The address of the events receiver.")
 	flag.StringVar(&healthAddr, "health-addr", ":9440", "The address the health endpoint binds to.")
 	flag.IntVar(&concurrent, "concurrent", 4, "The number of concurrent kustomize reconciles.")
 	flag.IntVar(&concurrentSSA, "concurrent-ssa", 4, "The number of concurrent server-side apply operations.")
 	flag.DurationVar(&requeueDependency, "requeue-dependency", 30*time.Second, "The interval at which failing dependencies are reevaluated.")
+	flag.DurationVar(&dependencyWaitTimeout, "dependency-wait-timeout", time.Hour, "The time after which a warning event is emitted for the Kustomizations whose dependencies are still not ready. Zero disables the warning.")
+	flag.DurationVar(&remoteUnreachableGracePeriod, "remote-unreachable-grace-period", 5*time.Minute, "The time the remote cluster of a Kustomization may be unreachable before it is marked as not ready. Meanwhile, the Ready condition is kept and the reconciliation is retried at the dependency requeue interval.")
+	flag.DurationVar(&shutdownDrainTimeout, "shutdown-drain-timeout", 45*time.Second, "The time given to the in-flight applies to complete and persist their progress when the controller shuts down. Zero aborts the applies immediately.")
 	flag.BoolVar(&noRemoteBases, "no-remote-bases", false,
 		"Disallow remote bases usage in Kustomize overlays. When this flag is enabled, all resources must refer to local files included in the source artifact.")
+	flag.BoolVar(&allowKustomizeAlphaPlugins, "allow-kustomize-alpha-plugins", false,
+		"Allow the Kustomizations to enable the kustomize alpha plugins with spec.buildOptions.enableAlphaPlugins, including the exec KRM functions.")
+	flag.StringVar(&kustomizeHelmCommand, "kustomize-helm-command", "",
+		"The path of the Helm binary used to inflate the Helm charts of the Kustomizations enabling spec.buildOptions.enableHelm. When empty, the Helm charts inflation is not allowed.")
 	flag.IntVar(&httpRetry, "http-retry", 9, "The maximum number of retries when failing to fetch artifacts over HTTP.")
+	flag.IntVar(&applyRetries, "apply-retries", 3, "The maximum number of retries, with an exponential backoff, of the objects failing to apply with a transient error, e.g. an unavailable admission webhook, before failing the reconciliation. Zero disables the retries.")
 	flag.StringVar(&defaultServiceAccount, auth.ControllerFlagDefaultServiceAccount, "", "Default service account used for impersonation.")
+	flag.StringVar(&crdServiceAccount, "crd-service-account", "", "The service account impersonated by the Kustomizations generated to apply the CustomResourceDefinitions delegated with spec.crdPolicy set to Delegate, in the namespaces allowed with --crd-service-account-namespace.")
+	flag.StringArrayVar(&crdServiceAccountNamespaces, "crd-service-account-namespace", []string{}, "Namespace in which the Kustomizations generated for the delegated CustomResourceDefinitions impersonate the --crd-service-account.")
 	flag.StringVar(&defaultDecryptionServiceAccount, auth.ControllerFlagDefaultDecryptionServiceAccount, "", "Default service account used for decryption.")
 	flag.StringVar(&defaultKubeConfigServiceAccount, auth.ControllerFlagDefaultKubeConfigServiceAccount, "", "Default service account used for kubeconfig.")
 	flag.StringVar(&sopsAgeSecret, "sops-age-secret", "", "The name of a Kubernetes secret in the RUNTIME_NAMESPACE containing a SOPS age decryption key for fallback usage.")
 	flag.StringVar(&sopsVaultConfigMap, "sops-vault-configmap", "", "The name of a ConfigMap in the RUNTIME_NAMESPACE configuring the OpenBao/Vault instances (address and login path) trusted for SOPS decryption. It acts as an allowlist of trusted Vault servers. When empty, SOPS decryption via Vault ServiceAccount-token authentication is disabled.")
+	flag.DurationVar(&decryptionKeyExpiryWarning, "decryption-key-expiry-warning", 14*24*time.Hour, "The time before the expiry of the PGP keys or AWS session credentials of a decryption Secret from which the DecryptionKeyExpiring condition is set and a warning event is emitted. Zero disables the check.")
+	flag.Int64Var(&decryptionMaxBinaryFileSize, "decryption-max-binary-file-size", 100<<20, "The maximum size in bytes of the SOPS encrypted files in the binary format, e.g. keystores referenced by secretGenerator, which are decrypted in chunks. Zero removes the limit.")
+	flag.DurationVar(&decryptionKeysCacheTTL, "decryption-keys-cache-ttl", 5*time.Minute, "The duration for which the keys imported from a version of a decryption Secret are reused, instead of being imported by each reconciliation. Zero disables the cache.")
+	flag.StringVar(&defaultSubstituteFrom, "default-substitute-from", "", "The name of a ConfigMap in the RUNTIME_NAMESPACE holding default post-build substitution variables. The variables are merged with the lowest precedence into the substitutions of every Kustomization that has spec.postBuild set.")
+	flag.StringVar(&specValidationRules, "spec-validation-rules", "", "The name of a ConfigMap in the RUNTIME_NAMESPACE holding CEL validation rules evaluated against the Kustomization specs at admission and reconcile time.")
+	flag.StringVar(&tenancyProfiles, "tenancy-profiles", "", "The name of a ConfigMap in the RUNTIME_NAMESPACE holding the tenancy profiles, each key being a profile name and each value the multi-doc YAML of the NetworkPolicy, ResourceQuota and LimitRange objects added to the target namespace of the Kustomizations with spec.tenancy set.")
+	flag.StringVar(&upgradeFreezeLease, "upgrade-freeze-lease", "", "The name of a Lease in the RUNTIME_NAMESPACE signalling a cluster upgrade. While the Lease exists and is not expired, the reconciliations are paused and the Kustomizations report the UpgradeFreeze condition.")
+	flag.BoolVar(&enableWebhook, "enable-webhook", false, "Enable the admission webhook server that defaults and validates Kustomizations.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the admission webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "", "The directory containing the TLS certificate (tls.crt) and key (tls.key) of the admission webhook server.")
+	flag.DurationVar(&webhookMinInterval, "webhook-min-interval", 0, "The minimum reconciliation and retry interval accepted by the admission webhook. Zero disables the check.")
 	flag.StringArrayVar(&disallowedFieldManagers, "override-manager", []string{}, "Field manager disallowed to perform changes on managed resources.")
 	flag.StringVar(&customApplyStageKinds, "custom-apply-stage-kinds", "", "A comma-separated list of GroupKind (e.g., 'rbac.authorization.k8s.io/Role,some.group.io/SomeResource') "+
 		"resources to be applied in a custom stage during server-side apply running after CRDs and before all namespaced resources not in this list.")
+	flag.StringToStringVar(&ephemeralObjectLabels, "ephemeral-object-labels", map[string]string{}, "Labels (e.g. 'velero.io/exclude-from-backup=true') set on the Jobs, CronJobs and Pods applied by the controller, "+
+		"and on their pod templates, to exclude the ephemeral objects from the cluster backups.")
+	flag.StringToStringVar(&namespaceLabels, "namespace-labels", map[string]string{}, "Labels (e.g. 'pod-security.kubernetes.io/enforce=restricted') set on the Namespaces applied by the controller, "+
+		"overriding the labels set in the manifests and in the Kustomizations.")
+	flag.StringVar(&applyReadCacheKinds, "apply-read-cache-kinds", "apps/Deployment,Service,ServiceAccount", "A comma-separated list of GroupKind (e.g., 'apps/Deployment,Service') "+
+		"resources read from the shared informer cache during server-side apply when the CacheApplyReads feature gate is enabled.")
+	flag.DurationVar(&applyReadCacheMaxStaleness, "apply-read-cache-max-staleness", 30*time.Second, "The time after a write during which an object is read from the API server "+
+		"instead of the shared informer cache if the cache doesn't hold the written version.")
+	flag.Float32Var(&kubeConfigQPS, "kubeconfig-qps", 0, "The maximum queries per second to the remote clusters targeted with a kubeconfig. "+
+		"Zero uses the client-go default, or no rate limiting with API Priority and Fairness.")
+	flag.IntVar(&kubeConfigBurst, "kubeconfig-burst", 0, "The maximum burst of queries to the remote clusters targeted with a kubeconfig. Zero uses the client-go default.")
+	flag.DurationVar(&kubeConfigTimeout, "kubeconfig-timeout", 30*time.Second, "The timeout of the requests to the remote clusters targeted with a kubeconfig.")
+	flag.StringVar(&driftCheckUser, "drift-check-user", "", "The user impersonated by the server-side apply dry-run requests detecting the drift "+
+		"of the last applied revision, so that API Priority and Fairness FlowSchemas can assign them a lower priority level. "+
+		"The user must be allowed to get and patch the managed objects. When empty, the controller identity is used.")
+	flag.DurationVar(&kubeConfigClientTTL, "kubeconfig-client-ttl", 10*time.Minute, "The time a client of a remote cluster targeted with a kubeconfig is kept unused "+
+		"for reuse by the next reconciliations, along with its connections and discovery cache. Zero disables the reuse.")
 
 	clientOptions.BindFlags(flag.CommandLine)
 	logOptions.BindFlags(flag.CommandLine)
@@ -140,6 +247,20 @@ func main() {
 
 	logger.SetLogger(logger.NewLogger(logOptions))
 
+	kubeConfigOpts.Timeout = &kubeConfigTimeout
+
+	if memoryLimit != "" {
+		limit, err := resource.ParseQuantity(memoryLimit)
+		if err != nil {
+			setupLog.Error(err, "unable to parse memory limit")
+			os.Exit(1)
+		}
+		debug.SetMemoryLimit(limit.Value())
+	}
+	if flag.CommandLine.Changed("gc-percent") {
+		debug.SetGCPercent(gcPercent)
+	}
+
 	ctx := ctrl.SetupSignalHandler()
 
 	if err := featureGates.WithLogger(setupLog).SupportedFeatures(features.FeatureGates()); err != nil {
@@ -207,9 +328,10 @@ func main() {
 	}
 
 	restConfig := runtimeClient.GetConfigOrDie(clientOptions)
+	restConfig.WarningHandlerWithContext = controller.NewAPIWarningHandler()
+	restConfig.Wrap(controller.NewAPIOperationWrapper(controllerName, driftCheckUser))
 	mgrConfig := ctrl.Options{
 		Scheme:                        scheme,
-		HealthProbeBindAddress:        healthAddr,
 		LeaderElection:                leaderElectionOptions.Enable,
 		LeaderElectionReleaseOnCancel: leaderElectionOptions.ReleaseOnCancel,
 		LeaseDuration:                 &leaderElectionOptions.LeaseDuration,
@@ -229,7 +351,7 @@ func main() {
 		},
 		Metrics: metricsserver.Options{
 			BindAddress:   metricsAddr,
-			ExtraHandlers: pprof.GetHandlers(),
+			ExtraHandlers: make(map[string]http.Handler),
 		},
 		Controller: ctrlcfg.Controller{
 			MaxConcurrentReconciles: concurrent,
@@ -237,6 +359,32 @@ func main() {
 		},
 	}
 
+	// Give the runnables enough time to drain the in-flight
+	// reconciliations and patch the status on shutdown.
+	if shutdownDrainTimeout > 0 {
+		mgrConfig.GracefulShutdownTimeout = ptr.To(shutdownDrainTimeout + 10*time.Second)
+	}
+
+	if metricsSecure {
+		setSecureMetricsServing(&mgrConfig.Metrics, metricsCertDir)
+	}
+	if err := setDebugHandlers(&mgrConfig.Metrics, enablePprof, enableExpvar); err != nil {
+		setupLog.Error(err, "unable to serve the debug endpoints")
+		os.Exit(1)
+	}
+	if enableInventoryBrowser && !metricsSecure {
+		setupLog.Error(fmt.Errorf("--enable-inventory-browser requires --metrics-secure"),
+			"unable to serve the inventory browser")
+		os.Exit(1)
+	}
+
+	if enableWebhook {
+		mgrConfig.WebhookServer = ctrlwebhook.NewServer(ctrlwebhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		})
+	}
+
 	if watchNamespace != "" {
 		mgrConfig.Cache.DefaultNamespaces = map[string]ctrlcache.Config{
 			watchNamespace: ctrlcache.Config{},
@@ -249,7 +397,87 @@ func main() {
 		os.Exit(1)
 	}
 
-	probes.SetupChecks(mgr, setupLog)
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	healthTracker := health.NewTracker()
+	healthTracker.AddCheck(health.SubsystemWorkQueue,
+		health.WorkQueueCheck(ctrlmetrics.Registry, strings.ToLower(kustomizev1.KustomizationKind)))
+	go healthTracker.RecordDiscovery(ctx, discoveryClient, time.Minute)
+	// The health probe server is started here instead of by the manager,
+	// to serve the health report along with the liveness and readiness probes.
+	if healthAddr != "" && healthAddr != "0" {
+		healthListener, err := net.Listen("tcp", healthAddr)
+		if err != nil {
+			setupLog.Error(err, "unable to listen on the health probe address")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&manager.Server{
+			Name: "health probe",
+			Server: &http.Server{
+				Handler:           healthTracker.ProbeHandler(),
+				ReadHeaderTimeout: 32 * time.Second,
+			},
+			Listener: healthListener,
+		}); err != nil {
+			setupLog.Error(err, "unable to create health probe server")
+			os.Exit(1)
+		}
+	}
+
+	if enableInventoryBrowser {
+		objectCache, err := browser.NewObjectCache(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to create inventory browser cache")
+			os.Exit(1)
+		}
+		if err := mgr.Add(objectCache); err != nil {
+			setupLog.Error(err, "unable to add inventory browser cache")
+			os.Exit(1)
+		}
+		inventoryBrowser := &browser.Handler{
+			Client:         mgr.GetClient(),
+			Objects:        objectCache,
+			APIReader:      mgr.GetAPIReader(),
+			ControllerName: controllerName,
+		}
+		if err := mgr.AddMetricsServerExtraHandler(browser.Path, inventoryBrowser); err != nil {
+			setupLog.Error(err, "unable to create inventory browser endpoint")
+			os.Exit(1)
+		}
+	}
+
+	featureStates := make(map[string]bool, len(features.FeatureGates()))
+	for name := range features.FeatureGates() {
+		enabled, err := features.Enabled(name)
+		if err != nil {
+			setupLog.Error(err, "unable to check feature gate "+name)
+			os.Exit(1)
+		}
+		featureStates[name] = enabled
+	}
+	capabilitiesHandler := &capabilities.Handler{
+		Capabilities: capabilities.Capabilities{
+			FeatureGates:        featureStates,
+			DecryptionProviders: decryptor.ProviderNames(),
+			ReconcileModes:      capabilities.ReconcileModes(),
+			BuildOptions: capabilities.BuildOptions{
+				RemoteBases:  !noRemoteBases,
+				AlphaPlugins: allowKustomizeAlphaPlugins,
+				Helm:         kustomizeHelmCommand != "",
+			},
+			Concurrency: capabilities.Concurrency{
+				Reconciles:        concurrent,
+				ServerSideApplies: concurrentSSA,
+			},
+		},
+	}
+	if err := mgr.AddMetricsServerExtraHandler(capabilities.Path, capabilitiesHandler); err != nil {
+		setupLog.Error(err, "unable to create capabilities endpoint")
+		os.Exit(1)
+	}
 
 	var eventRecorder *events.Recorder
 	if eventRecorder, err = events.NewRecorder(mgr, ctrl.Log, eventsAddr, controllerName); err != nil {
@@ -311,6 +539,64 @@ func main() {
 		os.Exit(1)
 	}
 
+	skipHPAReplicasDrift, err := features.Enabled(features.SkipHPAReplicasDrift)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.SkipHPAReplicasDrift)
+		os.Exit(1)
+	}
+
+	cacheBuildOnManualReconcile, err := features.Enabled(features.CacheBuildOnManualReconcile)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.CacheBuildOnManualReconcile)
+		os.Exit(1)
+	}
+
+	applyJournal, err := features.Enabled(features.ApplyJournal)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.ApplyJournal)
+		os.Exit(1)
+	}
+
+	cacheApplyReads, err := features.Enabled(features.CacheApplyReads)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.CacheApplyReads)
+		os.Exit(1)
+	}
+
+	watchInventoryKinds, err := features.Enabled(features.WatchInventoryKinds)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.WatchInventoryKinds)
+		os.Exit(1)
+	}
+
+	refreshRemoteTokens, err := features.Enabled(features.RefreshRemoteTokens)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.RefreshRemoteTokens)
+		os.Exit(1)
+	}
+
+	skipUnchangedConfigRenders, err := features.Enabled(features.SkipUnchangedConfigRenders)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.SkipUnchangedConfigRenders)
+		os.Exit(1)
+	}
+
+	clusterFactsSubstitution, err := features.Enabled(features.ClusterFactsSubstitution)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.ClusterFactsSubstitution)
+		os.Exit(1)
+	}
+	inventoryObjectRevisions, err := features.Enabled(features.InventoryObjectRevisions)
+	if err != nil {
+		setupLog.Error(err, "unable to check feature gate "+features.InventoryObjectRevisions)
+		os.Exit(1)
+	}
+
+	var clusterFacts *controller.ClusterFacts
+	if clusterFactsSubstitution {
+		clusterFacts = controller.NewClusterFacts(mgr.GetAPIReader(), discoveryClient)
+	}
+
 	var tokenCache *pkgcache.TokenCache
 	if tokenCacheOptions.MaxSize > 0 {
 		var err error
@@ -346,34 +632,78 @@ func main() {
 		os.Exit(1)
 	}
 
+	readCacheKinds, err := ssautils.ParseGroupKindSet(applyReadCacheKinds)
+	if err != nil {
+		setupLog.Error(err, "unable to parse --apply-read-cache-kinds")
+		os.Exit(1)
+	}
+	var applyReadCache ctrlclient.Reader
+	if cacheApplyReads {
+		applyReadCache = mgr.GetCache()
+	}
+
 	if err = (&controller.KustomizationReconciler{
-		AdditiveCELDependencyCheck: additiveCELDependencyCheck,
-		AllowExternalArtifact:      allowExternalArtifact,
-		APIReader:                  mgr.GetAPIReader(),
-		ArtifactFetchRetries:       httpRetry,
-		Client:                     mgr.GetClient(),
-		ClusterReader:              clusterReader,
-		ConcurrentSSA:              concurrentSSA,
-		ControllerName:             controllerName,
-		DefaultServiceAccount:      defaultServiceAccount,
-		DependencyRequeueInterval:  requeueDependency,
-		DirectSourceFetch:          directSourceFetch,
-		DisallowedFieldManagers:    disallowedFieldManagers,
-		EventRecorder:              eventRecorder,
-		FailFast:                   failFast,
-		GroupChangeLog:             groupChangeLog,
-		KubeConfigOpts:             kubeConfigOpts,
-		Mapper:                     restMapper,
-		Metrics:                    metricsH,
-		MigrateAPIVersion:          migrateAPIVersion,
-		NoCrossNamespaceRefs:       aclOptions.NoCrossNamespaceRefs,
-		NoRemoteBases:              noRemoteBases,
-		SOPSAgeSecret:              sopsAgeSecret,
-		SOPSVaultConfigMap:         sopsVaultConfigMap,
-		StatusManager:              fmt.Sprintf("gotk-%s", controllerName),
-		StrictSubstitutions:        strictSubstitutions,
-		TokenCache:                 tokenCache,
-		CustomStageKinds:           customStageKinds,
+		AdditiveCELDependencyCheck:   additiveCELDependencyCheck,
+		AllowExternalArtifact:        allowExternalArtifact,
+		AllowKustomizeAlphaPlugins:   allowKustomizeAlphaPlugins,
+		APIReader:                    mgr.GetAPIReader(),
+		ApplyJournal:                 applyJournal,
+		ApplyReadCache:               applyReadCache,
+		ApplyReadCacheKinds:          readCacheKinds,
+		ApplyReadCacheMaxStaleness:   applyReadCacheMaxStaleness,
+		ApplyRetries:                 applyRetries,
+		ArtifactFetchRetries:         httpRetry,
+		Client:                       mgr.GetClient(),
+		CacheApplyReads:              cacheApplyReads,
+		CacheBuildOnManualReconcile:  cacheBuildOnManualReconcile,
+		ClusterFacts:                 clusterFacts,
+		ClusterReader:                clusterReader,
+		ConcurrentSSA:                concurrentSSA,
+		ControllerName:               controllerName,
+		CRDServiceAccount:            crdServiceAccount,
+		CRDServiceAccountNamespaces:  crdServiceAccountNamespaces,
+		DecryptionKeyExpiryWarning:   decryptionKeyExpiryWarning,
+		DecryptionKeysCacheTTL:       decryptionKeysCacheTTL,
+		DecryptionMaxBinaryFileSize:  decryptionMaxBinaryFileSize,
+		DefaultServiceAccount:        defaultServiceAccount,
+		DefaultSubstituteFrom:        defaultSubstituteFrom,
+		DependencyRequeueInterval:    requeueDependency,
+		DependencyWaitTimeout:        dependencyWaitTimeout,
+		DirectSourceFetch:            directSourceFetch,
+		DisallowedFieldManagers:      disallowedFieldManagers,
+		EphemeralObjectLabels:        ephemeralObjectLabels,
+		NamespaceLabels:              namespaceLabels,
+		EventRecorder:                eventRecorder,
+		Health:                       healthTracker,
+		FailFast:                     failFast,
+		GroupChangeLog:               groupChangeLog,
+		KubeConfigBurst:              kubeConfigBurst,
+		KubeConfigClientTTL:          kubeConfigClientTTL,
+		InventoryObjectRevisions:     inventoryObjectRevisions,
+		KubeConfigOpts:               kubeConfigOpts,
+		KubeConfigQPS:                kubeConfigQPS,
+		KustomizeHelmCommand:         kustomizeHelmCommand,
+		LockNamespace:                os.Getenv(runtimeCtrl.EnvRuntimeNamespace),
+		Mapper:                       restMapper,
+		Metrics:                      metricsH,
+		MigrateAPIVersion:            migrateAPIVersion,
+		NoCrossNamespaceRefs:         aclOptions.NoCrossNamespaceRefs,
+		NoRemoteBases:                noRemoteBases,
+		RefreshRemoteTokens:          refreshRemoteTokens,
+		RemoteUnreachableGracePeriod: remoteUnreachableGracePeriod,
+		SOPSAgeSecret:                sopsAgeSecret,
+		SOPSVaultConfigMap:           sopsVaultConfigMap,
+		ShutdownDrainTimeout:         shutdownDrainTimeout,
+		SkipHPAReplicasDrift:         skipHPAReplicasDrift,
+		SkipUnchangedConfigRenders:   skipUnchangedConfigRenders,
+		SpecValidationRules:          specValidationRules,
+		StatusManager:                fmt.Sprintf("gotk-%s", controllerName),
+		StrictSubstitutions:          strictSubstitutions,
+		TenancyProfiles:              tenancyProfiles,
+		TokenCache:                   tokenCache,
+		UpgradeFreezeLease:           upgradeFreezeLease,
+		WatchInventoryKinds:          watchInventoryKinds,
+		CustomStageKinds:             customStageKinds,
 	}).SetupWithManager(ctx, mgr, controller.KustomizationReconcilerOptions{
 		RateLimiter:                runtimeCtrl.GetRateLimiter(rateLimiterOptions),
 		WatchConfigs:               watchConfigs,
@@ -384,6 +714,23 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", controllerName)
 		os.Exit(1)
 	}
+	if enableWebhook {
+		kustomizationWebhook := &webhook.KustomizationWebhook{
+			Client:               mgr.GetClient(),
+			MinInterval:          webhookMinInterval,
+			NoCrossNamespaceRefs: aclOptions.NoCrossNamespaceRefs,
+		}
+		if specValidationRules != "" {
+			kustomizationWebhook.SpecValidationRules = types.NamespacedName{
+				Namespace: os.Getenv(runtimeCtrl.EnvRuntimeNamespace),
+				Name:      specValidationRules,
+			}
+		}
+		if err = kustomizationWebhook.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", kustomizev1.KustomizationKind)
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -392,3 +739,32 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// setSecureMetricsServing configures the metrics server to serve HTTPS and
+// to authenticate and authorize every request against the Kubernetes API
+// server with a TokenReview and a SubjectAccessReview.
+func setSecureMetricsServing(opts *metricsserver.Options, certDir string) {
+	opts.SecureServing = true
+	opts.CertDir = certDir
+	opts.FilterProvider = filters.WithAuthenticationAndAuthorization
+}
+
+// setDebugHandlers registers the pprof and expvar handlers with the metrics
+// server. As they expose the internals of the controller, they are only
+// registered when the metrics server authenticates and authorizes the
+// requests, otherwise an error is returned.
+func setDebugHandlers(opts *metricsserver.Options, enablePprof, enableExpvar bool) error {
+	if !enablePprof && !enableExpvar {
+		return nil
+	}
+	if !opts.SecureServing || opts.FilterProvider == nil {
+		return fmt.Errorf("--enable-pprof and --enable-expvar require --metrics-secure")
+	}
+	if enablePprof {
+		maps.Copy(opts.ExtraHandlers, pprof.GetHandlers())
+	}
+	if enableExpvar {
+		opts.ExtraHandlers["/debug/vars"] = expvar.Handler()
+	}
+	return nil
+}
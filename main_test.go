@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/rest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// newReviewServer returns an API server authenticating the 'valid' token as
+// 'reader', which is only allowed to get /metrics.
+func newReviewServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apis/authentication.k8s.io/v1/tokenreviews", func(w http.ResponseWriter, r *http.Request) {
+		review := &authenticationv1.TokenReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			t.Error(err)
+		}
+		review.APIVersion, review.Kind = "authentication.k8s.io/v1", "TokenReview"
+		if review.Spec.Token == "valid" {
+			review.Status.Authenticated = true
+			review.Status.User.Username = "reader"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(review)
+	})
+	mux.HandleFunc("/apis/authorization.k8s.io/v1/subjectaccessreviews", func(w http.ResponseWriter, r *http.Request) {
+		review := &authorizationv1.SubjectAccessReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			t.Error(err)
+		}
+		review.APIVersion, review.Kind = "authorization.k8s.io/v1", "SubjectAccessReview"
+		attrs := review.Spec.NonResourceAttributes
+		review.Status.Allowed = review.Spec.User == "reader" &&
+			attrs != nil && attrs.Path == "/metrics" && attrs.Verb == "get"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(review)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSetSecureMetricsServing(t *testing.T) {
+	g := NewWithT(t)
+
+	opts := metricsserver.Options{BindAddress: ":8080"}
+	setSecureMetricsServing(&opts, "/certs")
+	g.Expect(opts.SecureServing).To(BeTrue())
+	g.Expect(opts.CertDir).To(Equal("/certs"))
+	g.Expect(opts.FilterProvider).ToNot(BeNil())
+
+	apiServer := newReviewServer(t)
+	filter, err := opts.FilterProvider(&rest.Config{Host: apiServer.URL}, apiServer.Client())
+	g.Expect(err).ToNot(HaveOccurred())
+	handler, err := filter(testr.New(t), http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{name: "anonymous", path: "/metrics", want: http.StatusUnauthorized},
+		// The delegating authenticator surfaces a rejected token as an error,
+		// which the controller-runtime filter answers with a server error.
+		{name: "invalid token", path: "/metrics", token: "invalid", want: http.StatusInternalServerError},
+		{name: "authorized", path: "/metrics", token: "valid", want: http.StatusOK},
+		{name: "forbidden path", path: "/debug/vars", token: "valid", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			g.Expect(rec.Code).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSetDebugHandlers(t *testing.T) {
+	g := NewWithT(t)
+
+	opts := metricsserver.Options{ExtraHandlers: make(map[string]http.Handler)}
+	g.Expect(setDebugHandlers(&opts, false, false)).To(Succeed())
+	g.Expect(opts.ExtraHandlers).To(BeEmpty())
+
+	err := setDebugHandlers(&opts, true, false)
+	g.Expect(err).To(MatchError(ContainSubstring("require --metrics-secure")))
+	g.Expect(setDebugHandlers(&opts, false, true)).ToNot(Succeed())
+	g.Expect(opts.ExtraHandlers).To(BeEmpty())
+
+	setSecureMetricsServing(&opts, "")
+	g.Expect(setDebugHandlers(&opts, true, true)).To(Succeed())
+	g.Expect(opts.ExtraHandlers).To(HaveKey("/debug/vars"))
+	g.Expect(opts.ExtraHandlers).To(HaveKey("/debug/pprof/"))
+}